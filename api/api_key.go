@@ -0,0 +1,177 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+//CreateAPIKeyHandler is the handler responsible for creating new API keys
+func CreateAPIKeyHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "CreateAPIKey")
+		start := time.Now()
+		gameID := c.Param("gameID")
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "CreateAPIKeyHandler"),
+			zap.String("operation", "createAPIKey"),
+			zap.String("gameID", gameID),
+		)
+
+		var payload CreateAPIKeyPayload
+
+		err := WithSegment("payload", c, func() error {
+			if err := LoadJSONPayload(&payload, c, l); err != nil {
+				log.E(l, "Failed to parse json payload.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusBadRequest, err.Error(), c)
+		}
+
+		_, err = models.GetGameByPublicID(db, gameID)
+		if err != nil {
+			log.W(l, "Failed to retrieve game.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWithError(err, c)
+		}
+
+		var apiKey *models.APIKey
+		var key string
+		err = WithSegment("api-key-create", c, func() error {
+			log.D(l, "Creating API key...")
+			apiKey, key, err = models.CreateAPIKey(db, gameID, payload.Name, payload.Role)
+			if err != nil {
+				log.E(l, "Failed to create the API key.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		log.I(l, "Created API key successfully.", func(cm log.CM) {
+			cm.Write(
+				zap.String("apiKeyPublicID", apiKey.PublicID),
+				zap.String("role", apiKey.Role),
+				zap.Duration("duration", time.Now().Sub(start)),
+			)
+		})
+		result := apiKey.Serialize()
+		result["key"] = key
+		return SucceedWith(result, c)
+	}
+}
+
+//ListAPIKeysHandler is the handler responsible for listing the API keys of a game
+func ListAPIKeysHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "ListAPIKeys")
+		start := time.Now()
+		gameID := c.Param("gameID")
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "ListAPIKeysHandler"),
+			zap.String("operation", "listAPIKeys"),
+			zap.String("gameID", gameID),
+		)
+
+		var apiKeys []*models.APIKey
+		var err error
+		err = WithSegment("api-key-list", c, func() error {
+			log.D(l, "Retrieving API keys...")
+			apiKeys, err = models.GetAPIKeysByGameID(db, gameID)
+			if err != nil {
+				log.E(l, "Failed to retrieve API keys.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		serializedAPIKeys := make([]map[string]interface{}, len(apiKeys))
+		for i, apiKey := range apiKeys {
+			serializedAPIKeys[i] = apiKey.Serialize()
+		}
+
+		log.I(l, "Retrieved API keys successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{
+			"apiKeys": serializedAPIKeys,
+		}, c)
+	}
+}
+
+//DeleteAPIKeyHandler is the handler responsible for revoking API keys
+func DeleteAPIKeyHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "DeleteAPIKey")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		publicID := c.Param("publicID")
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "DeleteAPIKeyHandler"),
+			zap.String("operation", "deleteAPIKey"),
+			zap.String("gameID", gameID),
+			zap.String("apiKeyPublicID", publicID),
+		)
+
+		var apiKey *models.APIKey
+		var err error
+		err = WithSegment("api-key-delete", c, func() error {
+			log.D(l, "Deleting API key...")
+			apiKey, err = models.DeleteAPIKey(db, gameID, publicID)
+			if err != nil {
+				log.W(l, "Failed to delete API key.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		app.InvalidateAPIKey(apiKey)
+
+		log.I(l, "API key deleted successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{}, c)
+	}
+}
@@ -0,0 +1,281 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("API Key API Handler", func() {
+	var testDb models.DB
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Create API Key Handler", func() {
+		It("Should create API key", func() {
+			a := GetDefaultTestApp()
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := testDb.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"name": "game-server",
+				"role": models.APIKeyRoleServer,
+			}
+			status, body := PostJSON(a, GetGameRoute(game.PublicID, "/api-keys"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["publicID"]).NotTo(BeEquivalentTo(""))
+			Expect(result["name"]).To(Equal("game-server"))
+			Expect(result["role"]).To(Equal(models.APIKeyRoleServer))
+			Expect(result["key"]).NotTo(BeEquivalentTo(""))
+
+			dbAPIKey, err := models.GetAPIKeyByKey(testDb, result["key"].(string))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbAPIKey.GameID).To(Equal(game.PublicID))
+			Expect(dbAPIKey.PublicID).To(Equal(result["publicID"]))
+			Expect(body).NotTo(ContainSubstring(dbAPIKey.KeyHash))
+		})
+
+		It("Should not create API key with invalid role", func() {
+			a := GetDefaultTestApp()
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := testDb.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"name": "game-server",
+				"role": "superuser",
+			}
+			status, body := PostJSON(a, GetGameRoute(game.PublicID, "/api-keys"), payload)
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["code"]).To(Equal(api.InvalidAPIKeyRoleErrorCode))
+		})
+
+		It("Should not create API key for unexistent game", func() {
+			a := GetDefaultTestApp()
+			payload := map[string]interface{}{
+				"role": models.APIKeyRoleReadOnly,
+			}
+			status, body := PostJSON(a, GetGameRoute("unexistent-game", "/api-keys"), payload)
+
+			Expect(status).To(Equal(http.StatusNotFound))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+		})
+	})
+
+	Describe("List API Keys Handler", func() {
+		It("Should list the API keys of the game without their keys", func() {
+			a := GetDefaultTestApp()
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := testDb.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, key, err := models.CreateAPIKey(testDb, game.PublicID, "admin", models.APIKeyRoleAdmin)
+			Expect(err).NotTo(HaveOccurred())
+			_, _, err = models.CreateAPIKey(testDb, game.PublicID, "dashboard", models.APIKeyRoleReadOnly)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, GetGameRoute(game.PublicID, "/api-keys"))
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).NotTo(ContainSubstring(key))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			apiKeys := result["apiKeys"].([]interface{})
+			Expect(apiKeys).To(HaveLen(2))
+			Expect(apiKeys[0].(map[string]interface{})["name"]).To(Equal("admin"))
+			Expect(apiKeys[1].(map[string]interface{})["role"]).To(Equal(models.APIKeyRoleReadOnly))
+		})
+	})
+
+	Describe("Delete API Key Handler", func() {
+		It("Should delete API key", func() {
+			a := GetDefaultTestApp()
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := testDb.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			apiKey, key, err := models.CreateAPIKey(testDb, game.PublicID, "", models.APIKeyRoleServer)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Delete(a, GetGameRoute(game.PublicID, fmt.Sprintf("/api-keys/%s", apiKey.PublicID)))
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			_, err = models.GetAPIKeyByKey(testDb, key)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should not delete API key of another game", func() {
+			a := GetDefaultTestApp()
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := testDb.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			apiKey, _, err := models.CreateAPIKey(testDb, game.PublicID, "", models.APIKeyRoleServer)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, _ := Delete(a, GetGameRoute("other-game", fmt.Sprintf("/api-keys/%s", apiKey.PublicID)))
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+})
+
+var _ = Describe("API Key Auth Middleware", func() {
+	var testDb models.DB
+	var a *api.App
+	var game *models.Game
+
+	basicAuth := fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte("basicauthuser:basicauthpass")))
+
+	createKey := func(gameID, role string) string {
+		_, key, err := models.CreateAPIKey(testDb, gameID, "", role)
+		Expect(err).NotTo(HaveOccurred())
+		return fmt.Sprintf("Bearer %s", key)
+	}
+
+	request := func(method, url, authorization string, body interface{}) int {
+		status, _, _ := DoRequestWithHeaders(a, method, url, body, map[string]string{
+			"Authorization": authorization,
+		})
+		return status
+	}
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+
+		a = GetTestAppWithAPIKeys("basicauthuser", "basicauthpass")
+		game = models.GameFactory.MustCreate().(*models.Game)
+		err = testDb.Insert(game)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should reject requests without credentials", func() {
+		status, _ := Get(a, GetGameRoute(game.PublicID, "/clans"))
+		Expect(status).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("Should not require credentials in the healthcheck", func() {
+		status, _ := Get(a, "/healthcheck")
+		Expect(status).To(Equal(http.StatusOK))
+	})
+
+	It("Should accept the basic auth credentials in every route", func() {
+		Expect(request("GET", "/games", basicAuth, nil)).To(Equal(http.StatusOK))
+		Expect(request("GET", GetGameRoute(game.PublicID, "/api-keys"), basicAuth, nil)).To(Equal(http.StatusOK))
+	})
+
+	It("Should reject invalid basic auth credentials", func() {
+		wrongAuth := fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte("basicauthuser:wrong")))
+		Expect(request("GET", "/games", wrongAuth, nil)).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("Should reject unknown API keys", func() {
+		Expect(request("GET", GetGameRoute(game.PublicID, "/clans"), "Bearer unknown", nil)).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("Should reject API keys of another game", func() {
+		otherGame := models.GameFactory.MustCreate().(*models.Game)
+		err := testDb.Insert(otherGame)
+		Expect(err).NotTo(HaveOccurred())
+
+		key := createKey(otherGame.PublicID, models.APIKeyRoleAdmin)
+		Expect(request("GET", GetGameRoute(game.PublicID, "/clans"), key, nil)).To(Equal(http.StatusForbidden))
+	})
+
+	It("Should reject API keys in routes that are not of a game", func() {
+		key := createKey(game.PublicID, models.APIKeyRoleAdmin)
+		Expect(request("GET", "/games", key, nil)).To(Equal(http.StatusForbidden))
+	})
+
+	It("Should only allow read-only API keys to retrieve data", func() {
+		key := createKey(game.PublicID, models.APIKeyRoleReadOnly)
+		Expect(request("GET", fmt.Sprintf("/games/%s", game.PublicID), key, nil)).To(Equal(http.StatusOK))
+		Expect(request("GET", GetGameRoute(game.PublicID, "/clans"), key, nil)).To(Equal(http.StatusOK))
+
+		payload := map[string]interface{}{
+			"publicID": "player-id",
+			"name":     "player",
+		}
+		Expect(request("POST", GetGameRoute(game.PublicID, "/players"), key, payload)).To(Equal(http.StatusForbidden))
+		Expect(request("GET", GetGameRoute(game.PublicID, "/hooks"), key, nil)).To(Equal(http.StatusForbidden))
+	})
+
+	It("Should allow server API keys to change players and clans but not the game", func() {
+		key := createKey(game.PublicID, models.APIKeyRoleServer)
+
+		payload := map[string]interface{}{
+			"publicID": "player-id",
+			"name":     "player",
+		}
+		Expect(request("POST", GetGameRoute(game.PublicID, "/players"), key, payload)).To(Equal(http.StatusOK))
+		Expect(request("GET", GetGameRoute(game.PublicID, "/players/player-id"), key, nil)).To(Equal(http.StatusOK))
+
+		Expect(request("GET", GetGameRoute(game.PublicID, "/api-keys"), key, nil)).To(Equal(http.StatusForbidden))
+		Expect(request("POST", GetGameRoute(game.PublicID, "/archive"), key, nil)).To(Equal(http.StatusForbidden))
+		Expect(request("PATCH", fmt.Sprintf("/games/%s", game.PublicID), key, map[string]interface{}{"name": "new"})).To(Equal(http.StatusForbidden))
+	})
+
+	It("Should only allow admin API keys to delete clans as an admin", func() {
+		_, clan, _, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+		Expect(err).NotTo(HaveOccurred())
+		route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s?admin=true", clan.PublicID))
+
+		serverKey := createKey(clan.GameID, models.APIKeyRoleServer)
+		Expect(request("DELETE", route, serverKey, nil)).To(Equal(http.StatusForbidden))
+
+		adminKey := createKey(clan.GameID, models.APIKeyRoleAdmin)
+		Expect(request("DELETE", route, adminKey, nil)).To(Equal(http.StatusOK))
+	})
+
+	It("Should allow admin API keys to manage the API keys of the game", func() {
+		key := createKey(game.PublicID, models.APIKeyRoleAdmin)
+		Expect(request("GET", GetGameRoute(game.PublicID, "/api-keys"), key, nil)).To(Equal(http.StatusOK))
+		Expect(request("GET", GetGameRoute(game.PublicID, "/hooks"), key, nil)).To(Equal(http.StatusOK))
+	})
+
+	It("Should reject API keys after they are deleted", func() {
+		apiKey, key, err := models.CreateAPIKey(testDb, game.PublicID, "", models.APIKeyRoleServer)
+		Expect(err).NotTo(HaveOccurred())
+		authorization := fmt.Sprintf("Bearer %s", key)
+		Expect(request("GET", GetGameRoute(game.PublicID, "/clans"), authorization, nil)).To(Equal(http.StatusOK))
+
+		route := GetGameRoute(game.PublicID, fmt.Sprintf("/api-keys/%s", apiKey.PublicID))
+		Expect(request("DELETE", route, basicAuth, nil)).To(Equal(http.StatusOK))
+
+		Expect(request("GET", GetGameRoute(game.PublicID, "/clans"), authorization, nil)).To(Equal(http.StatusUnauthorized))
+	})
+})
@@ -16,9 +16,11 @@ import (
 	"os/signal"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/garyburd/redigo/redis"
 	"github.com/getsentry/raven-go"
 	"github.com/jrallison/go-workers"
 	"github.com/labstack/echo"
@@ -65,6 +67,7 @@ type App struct {
 	Dispatcher     *Dispatcher
 	ESWorker       *models.ESWorker
 	MongoWorker    *models.MongoWorker
+	RankingWorker  *RankingWorker
 	Logger         zap.Logger
 	ESClient       *es.Client
 	MongoDB        interfaces.MongoDB
@@ -74,8 +77,14 @@ type App struct {
 	DDStatsD       *extnethttpmiddleware.DogStatsD
 
 	getGameCache        *gocache.Cache
+	getGameHooksCache   *gocache.Cache
+	getAPIKeyCache      *gocache.Cache
+	archivedGamesCache  *gocache.Cache
 	clansSummariesCache *caches.ClansSummaries
 	db                  gorp.Database
+
+	cacheInvalidationMutex sync.Mutex
+	stopCacheInvalidation  chan struct{}
 }
 
 // GetApp returns a new Khan API Application
@@ -112,13 +121,31 @@ func (app *App) Configure() {
 	app.initDispatcher()
 	app.initESWorker()
 	app.initMongoWorker()
+	app.initRankingWorker()
 	app.configureGoWorkers()
 	app.configureCaches()
 }
 
 func (app *App) configureCaches() {
 	app.configureGetGameCache()
+	app.configureGetGameHooksCache()
+	app.configureGetAPIKeyCache()
+	app.configureArchivedGamesCache()
 	app.configureClansSummariesCache()
+	setCacheInvalidationDefaults(app.Config)
+}
+
+// Config keys of the redis channels used to remove entries from the caches of every Khan process
+const (
+	GameHooksInvalidationChannelKey     = "caches.getGameHooks.invalidationChannel"
+	APIKeysInvalidationChannelKey       = "caches.getAPIKey.invalidationChannel"
+	ArchivedGamesInvalidationChannelKey = "caches.archivedGames.invalidationChannel"
+)
+
+func setCacheInvalidationDefaults(config *viper.Viper) {
+	config.SetDefault(GameHooksInvalidationChannelKey, "khan:hooks:invalidate")
+	config.SetDefault(APIKeysInvalidationChannelKey, "khan:apikeys:invalidate")
+	config.SetDefault(ArchivedGamesInvalidationChannelKey, "khan:archivedgames:invalidate")
 }
 
 func (app *App) configureGetGameCache() {
@@ -138,6 +165,61 @@ func (app *App) configureGetGameCache() {
 	app.getGameCache = gocache.New(ttl, cleanupInterval)
 }
 
+func (app *App) configureGetGameHooksCache() {
+	// TTL
+	ttlKey := "caches.getGameHooks.ttl"
+	app.Config.SetDefault(ttlKey, 5*time.Minute)
+	ttl := app.Config.GetDuration(ttlKey)
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+
+	// cleanup
+	cleanupIntervalKey := "caches.getGameHooks.cleanupInterval"
+	app.Config.SetDefault(cleanupIntervalKey, time.Minute)
+	cleanupInterval := app.Config.GetDuration(cleanupIntervalKey)
+
+	app.getGameHooksCache = gocache.New(ttl, cleanupInterval)
+}
+
+func (app *App) configureGetAPIKeyCache() {
+	// TTL
+	ttlKey := "caches.getAPIKey.ttl"
+	app.Config.SetDefault(ttlKey, time.Minute)
+	ttl := app.Config.GetDuration(ttlKey)
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	// TTL of unknown keys, so invalid tokens don't query the database on every request
+	notFoundTTLKey := "caches.getAPIKey.notFoundTTL"
+	app.Config.SetDefault(notFoundTTLKey, 10*time.Second)
+
+	// cleanup
+	cleanupIntervalKey := "caches.getAPIKey.cleanupInterval"
+	app.Config.SetDefault(cleanupIntervalKey, time.Minute)
+	cleanupInterval := app.Config.GetDuration(cleanupIntervalKey)
+
+	app.getAPIKeyCache = gocache.New(ttl, cleanupInterval)
+}
+
+func (app *App) configureArchivedGamesCache() {
+	// TTL, kept short since archived games must stop accepting changes right away
+	ttlKey := "caches.archivedGames.ttl"
+	app.Config.SetDefault(ttlKey, 5*time.Second)
+	ttl := app.Config.GetDuration(ttlKey)
+	if ttl <= 0 {
+		ttl = 5 * time.Second
+	}
+
+	// cleanup
+	cleanupIntervalKey := "caches.archivedGames.cleanupInterval"
+	app.Config.SetDefault(cleanupIntervalKey, time.Minute)
+	cleanupInterval := app.Config.GetDuration(cleanupIntervalKey)
+
+	app.archivedGamesCache = gocache.New(ttl, cleanupInterval)
+}
+
 func (app *App) configureClansSummariesCache() {
 	// TTL
 	ttlKey := "caches.clansSummaries.ttl"
@@ -280,6 +362,11 @@ func (app *App) setConfigurationDefaults() {
 	app.Config.SetDefault("webhooks.timeout", 500)
 	app.Config.SetDefault("webhooks.maxIdleConnsPerHost", http.DefaultMaxIdleConnsPerHost)
 	app.Config.SetDefault("webhooks.maxIdleConns", 100)
+	app.Config.SetDefault("webhooks.maxRetries", 5)
+	app.Config.SetDefault("webhooks.backoff.initial", 1000)
+	app.Config.SetDefault("webhooks.backoff.max", 60000)
+	app.Config.SetDefault("webhooks.pollInterval", 15)
+	app.Config.SetDefault("webhooks.deliveryLog.maxLimit", 100)
 	app.Config.SetDefault("elasticsearch.host", "localhost")
 	app.Config.SetDefault("elasticsearch.port", 9234)
 	app.Config.SetDefault("elasticsearch.sniff", true)
@@ -290,6 +377,8 @@ func (app *App) setConfigurationDefaults() {
 	app.Config.SetDefault("khan.defaultCooldownBeforeApply", -1)
 	app.Config.SetDefault("jaeger.disabled", true)
 	app.Config.SetDefault("jaeger.samplingProbability", 0.001)
+	app.Config.SetDefault(IdempotencyKeyTTLKey, 86400)
+	app.Config.SetDefault(APIKeysEnabledKey, false)
 
 	app.setHandlersConfigurationDefaults()
 
@@ -298,12 +387,52 @@ func (app *App) setConfigurationDefaults() {
 
 func (app *App) setHandlersConfigurationDefaults() {
 	app.setRetrieveClanHandlerConfigurationDefaults()
+	app.setListClansHandlerConfigurationDefaults()
+	app.setSearchClansHandlerConfigurationDefaults()
+	app.setRetrieveClanMembersHandlerConfigurationDefaults()
+	app.setRetrievePlayerMembershipsHandlerConfigurationDefaults()
+	app.setBatchMembershipsHandlerConfigurationDefaults()
+	app.setTopClansHandlerConfigurationDefaults()
+	app.setAuditHandlerConfigurationDefaults()
+	app.setListGamesHandlerConfigurationDefaults()
 }
 
 func (app *App) setRetrieveClanHandlerConfigurationDefaults() {
 	SetRetrieveClanHandlerConfigurationDefaults(app.Config)
 }
 
+func (app *App) setListClansHandlerConfigurationDefaults() {
+	SetListClansHandlerConfigurationDefaults(app.Config)
+}
+
+func (app *App) setSearchClansHandlerConfigurationDefaults() {
+	SetSearchClansHandlerConfigurationDefaults(app.Config)
+}
+
+func (app *App) setRetrieveClanMembersHandlerConfigurationDefaults() {
+	SetRetrieveClanMembersHandlerConfigurationDefaults(app.Config)
+}
+
+func (app *App) setRetrievePlayerMembershipsHandlerConfigurationDefaults() {
+	SetRetrievePlayerMembershipsHandlerConfigurationDefaults(app.Config)
+}
+
+func (app *App) setBatchMembershipsHandlerConfigurationDefaults() {
+	SetBatchMembershipsHandlerConfigurationDefaults(app.Config)
+}
+
+func (app *App) setTopClansHandlerConfigurationDefaults() {
+	SetTopClansHandlerConfigurationDefaults(app.Config)
+}
+
+func (app *App) setAuditHandlerConfigurationDefaults() {
+	SetAuditHandlerConfigurationDefaults(app.Config)
+}
+
+func (app *App) setListGamesHandlerConfigurationDefaults() {
+	SetListGamesHandlerConfigurationDefaults(app.Config)
+}
+
 func (app *App) loadConfiguration() {
 	l := app.Logger.With(
 		zap.String("source", "app"),
@@ -394,7 +523,9 @@ func (app *App) configureApplication() {
 	a.SetLogOutput(w)
 
 	basicAuthUser := app.Config.GetString("basicauth.username")
-	if basicAuthUser != "" {
+	if app.Config.GetBool(APIKeysEnabledKey) {
+		a.Use(NewAPIKeyAuthMiddleware(app).Serve)
+	} else if basicAuthUser != "" {
 		basicAuthPass := app.Config.GetString("basicauth.password")
 
 		a.Use(middleware.BasicAuthWithConfig(middleware.BasicAuthConfig{
@@ -413,35 +544,61 @@ func (app *App) configureApplication() {
 	a.Use(NewRecoveryMiddleware(app.onErrorHandler).Serve)
 	a.Use(extechomiddleware.NewResponseTimeMetricsMiddleware(app.DDStatsD).Serve)
 	a.Use(NewVersionMiddleware().Serve)
+	a.Use(NewRequestIDMiddleware().Serve)
 	a.Use(NewSentryMiddleware(app).Serve)
 	a.Use(NewLoggerMiddleware(app.Logger).Serve)
 	a.Use(NewBodyExtractionMiddleware().Serve)
+	a.Use(NewArchivedGameMiddleware(app).Serve)
+	a.Use(NewIdempotencyMiddleware(app).Serve)
 
 	a.Get("/healthcheck", HealthCheckHandler(app))
 	a.Get("/status", StatusHandler(app))
 
 	// Game Routes
+	a.Get("/games", ListGamesHandler(app))
 	a.Post("/games", CreateGameHandler(app))
+	a.Get("/games/:gameID", RetrieveGameHandler(app))
 	a.Put("/games/:gameID", UpdateGameHandler(app))
+	a.Patch("/games/:gameID", PatchGameHandler(app))
+	a.Post(ArchiveGameRoute, ArchiveGameHandler(app, true))
+	a.Delete(ArchiveGameRoute, ArchiveGameHandler(app, false))
+	a.Get("/games/:gameID/audit", AuditEntriesHandler(app))
 
 	// Hook Routes
+	a.Get("/games/:gameID/hooks", ListHooksHandler(app))
 	a.Post("/games/:gameID/hooks", CreateHookHandler(app))
+	a.Get("/games/:gameID/hooks/:publicID", RetrieveHookHandler(app))
+	a.Put("/games/:gameID/hooks/:publicID", UpdateHookHandler(app))
 	a.Delete("/games/:gameID/hooks/:publicID", RemoveHookHandler(app))
+	a.Post("/games/:gameID/hooks/:publicID/test", TestHookHandler(app))
+	a.Get("/games/:gameID/hooks/:publicID/deliveries", RetrieveHookDeliveriesHandler(app))
+
+	// API Key Routes
+	a.Get("/games/:gameID/api-keys", ListAPIKeysHandler(app))
+	a.Post("/games/:gameID/api-keys", CreateAPIKeyHandler(app))
+	a.Delete("/games/:gameID/api-keys/:publicID", DeleteAPIKeyHandler(app))
 
 	// Player Routes
 	a.Post("/games/:gameID/players", CreatePlayerHandler(app))
 	a.Put("/games/:gameID/players/:playerPublicID", UpdatePlayerHandler(app))
+	a.Patch("/games/:gameID/players/:playerPublicID", PatchPlayerHandler(app))
 	a.Get("/games/:gameID/players/:playerPublicID", RetrievePlayerHandler(app))
+	a.Get("/games/:gameID/players/:playerPublicID/memberships", RetrievePlayerMembershipsHandler(app))
+	a.Get("/games/:gameID/players/:playerPublicID/audit", AuditEntriesHandler(app))
 
 	// Clan Routes
 	a.Get("/games/:gameID/clans/search", SearchClansHandler(app))
 	a.Get("/games/:gameID/clans", ListClansHandler(app))
 	a.Post("/games/:gameID/clans", CreateClanHandler(app))
 	a.Get("/games/:gameID/clans-summary", RetrieveClansSummariesHandler(app))
+	a.Get("/games/:gameID/clans-top/:dimension", TopClansHandler(app))
 	a.Get("/games/:gameID/clans/:clanPublicID", RetrieveClanHandler(app))
 	a.Get("/games/:gameID/clans/:clanPublicID/members", RetrieveClanMembersHandler(app))
 	a.Get("/games/:gameID/clans/:clanPublicID/summary", RetrieveClanSummaryHandler(app))
+	a.Get("/games/:gameID/clans/:clanPublicID/audit", AuditEntriesHandler(app))
 	a.Put("/games/:gameID/clans/:clanPublicID", UpdateClanHandler(app))
+	a.Patch("/games/:gameID/clans/:clanPublicID", PatchClanHandler(app))
+	a.Delete("/games/:gameID/clans/:clanPublicID", DeleteClanHandler(app))
 	a.Post("/games/:gameID/clans/:clanPublicID/leave", LeaveClanHandler(app))
 	a.Post("/games/:gameID/clans/:clanPublicID/transfer-ownership", TransferOwnershipHandler(app))
 
@@ -453,6 +610,9 @@ func (app *App) configureApplication() {
 	a.Post("/games/:gameID/clans/:clanPublicID/memberships/delete", DeleteMembershipHandler(app))
 	a.Post("/games/:gameID/clans/:clanPublicID/memberships/promote", PromoteOrDemoteMembershipHandler(app, "promote"))
 	a.Post("/games/:gameID/clans/:clanPublicID/memberships/demote", PromoteOrDemoteMembershipHandler(app, "demote"))
+	a.Post("/games/:gameID/clans/:clanPublicID/memberships/ban", BanOrUnbanMembershipHandler(app, "ban"))
+	a.Post("/games/:gameID/clans/:clanPublicID/memberships/unban", BanOrUnbanMembershipHandler(app, "unban"))
+	a.Post("/games/:gameID/clans/:clanPublicID/memberships/batch", BatchMembershipsHandler(app))
 
 	// pprof
 	pprofHandlers := map[string]func(http.ResponseWriter, *http.Request){
@@ -479,38 +639,272 @@ func (app *App) addError() {
 	app.Errors.Update(1)
 }
 
-//GetHooks returns all available hooks
-func (app *App) GetHooks(ctx context.Context) map[string]map[int][]*models.Hook {
+//GetGameHooks returns the hooks of a game by event type. Hooks subscribed to
+//several event types are listed under each of them. Hooks are cached until
+//they are invalidated with InvalidateGameHooks or until the cache TTL expires
+func (app *App) GetGameHooks(ctx context.Context, gameID string) (map[int][]*models.Hook, error) {
 	l := app.Logger.With(
 		zap.String("source", "app"),
-		zap.String("operation", "GetHooks"),
+		zap.String("operation", "GetGameHooks"),
+		zap.String("gameID", gameID),
 	)
 
+	value, present := app.getGameHooksCache.Get(gameID)
+	if present {
+		return value.(map[int][]*models.Hook), nil
+	}
+
 	start := time.Now()
-	log.D(l, "Retrieving hooks...")
-	dbHooks, err := models.GetAllHooks(app.Db(ctx))
+	log.D(l, "Retrieving game hooks...")
+
+	dbHooks, err := models.GetHooksByGameID(app.Db(ctx), gameID)
 	if err != nil {
-		log.E(l, "Retrieve hooks failed.", func(cm log.CM) {
-			cm.Write(zap.String("error", err.Error()))
+		log.E(l, "Retrieve game hooks failed.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
 		})
-		return nil
+		return nil, err
 	}
-	log.D(l, "Hooks retrieved successfully.", func(cm log.CM) {
+
+	log.D(l, "Game hooks retrieved successfully.", func(cm log.CM) {
 		cm.Write(zap.Duration("hookRetrievalDuration", time.Now().Sub(start)))
 	})
 
-	hooks := make(map[string]map[int][]*models.Hook)
+	hooks := make(map[int][]*models.Hook)
 	for _, hook := range dbHooks {
-		if hooks[hook.GameID] == nil {
-			hooks[hook.GameID] = make(map[int][]*models.Hook)
+		for _, eventType := range hook.GetEventTypes() {
+			hooks[eventType] = append(hooks[eventType], hook)
 		}
-		hooks[hook.GameID][hook.EventType] = append(
-			hooks[hook.GameID][hook.EventType],
-			hook,
-		)
 	}
+	app.getGameHooksCache.Set(gameID, hooks, gocache.DefaultExpiration)
+	return hooks, nil
+}
 
-	return hooks
+//InvalidateGameHooks removes the hooks of a game from the cache of this
+//and every other Khan process listening to the invalidation channel
+func (app *App) InvalidateGameHooks(gameID string) {
+	app.getGameHooksCache.Delete(gameID)
+	app.publishInvalidation(GameHooksInvalidationChannelKey, gameID)
+}
+
+// publishInvalidation tells the other Khan processes to remove key from the cache
+// invalidated through the channel in channelKey
+func (app *App) publishInvalidation(channelKey, key string) {
+	err := PublishCacheInvalidation(app.Config, channelKey, key)
+	if err != nil {
+		log.E(app.Logger, "Could not publish invalidation.", func(cm log.CM) {
+			cm.Write(
+				zap.String("source", "app"),
+				zap.String("operation", "publishInvalidation"),
+				zap.String("channelKey", channelKey),
+				zap.Error(err),
+			)
+		})
+	}
+}
+
+//PublishCacheInvalidation tells every Khan process listening to the channel in channelKey to
+//remove keys from the cache it invalidates. Commands that change data cached by the running
+//apps use it with their own config
+func PublishCacheInvalidation(config *viper.Viper, channelKey string, keys ...string) error {
+	setCacheInvalidationDefaults(config)
+	conn, err := dialRedis(config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	channel := config.GetString(channelKey)
+	for _, key := range keys {
+		if _, err := conn.Do("PUBLISH", channel, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dialRedis(config *viper.Viper) (redis.Conn, error) {
+	options := []redis.DialOption{
+		redis.DialDatabase(config.GetInt("redis.database")),
+		redis.DialConnectTimeout(time.Second),
+	}
+	if redisPass := config.GetString("redis.password"); redisPass != "" {
+		options = append(options, redis.DialPassword(redisPass))
+	}
+	return redis.Dial(
+		"tcp",
+		fmt.Sprintf("%s:%d", config.GetString("redis.host"), config.GetInt("redis.port")),
+		options...,
+	)
+}
+
+//StartCacheInvalidation makes this app remove from its caches the entries invalidated by other
+//Khan processes, until StopCacheInvalidation is called. Apps that serve requests or run workers
+//must call it, otherwise they keep stale entries until the cache TTL expires
+func (app *App) StartCacheInvalidation() {
+	app.cacheInvalidationMutex.Lock()
+	defer app.cacheInvalidationMutex.Unlock()
+
+	if app.stopCacheInvalidation != nil {
+		return
+	}
+	app.stopCacheInvalidation = make(chan struct{})
+	go app.listenCacheInvalidation(app.stopCacheInvalidation)
+}
+
+//StopCacheInvalidation stops listening to invalidations and closes the redis subscription
+func (app *App) StopCacheInvalidation() {
+	app.cacheInvalidationMutex.Lock()
+	defer app.cacheInvalidationMutex.Unlock()
+
+	if app.stopCacheInvalidation == nil {
+		return
+	}
+	close(app.stopCacheInvalidation)
+	app.stopCacheInvalidation = nil
+}
+
+// getInvalidatedCaches returns the caches invalidated through redis by their channel
+func (app *App) getInvalidatedCaches() map[string]*gocache.Cache {
+	return map[string]*gocache.Cache{
+		app.Config.GetString(GameHooksInvalidationChannelKey):     app.getGameHooksCache,
+		app.Config.GetString(APIKeysInvalidationChannelKey):       app.getAPIKeyCache,
+		app.Config.GetString(ArchivedGamesInvalidationChannelKey): app.archivedGamesCache,
+	}
+}
+
+// listenCacheInvalidation removes entries from the caches whenever another process
+// publishes an invalidation, until stop is closed. It reconnects on failures and,
+// since invalidations may have been missed meanwhile, flushes the whole caches
+func (app *App) listenCacheInvalidation(stop chan struct{}) {
+	l := app.Logger.With(
+		zap.String("source", "app"),
+		zap.String("operation", "listenCacheInvalidation"),
+	)
+	invalidatedCaches := app.getInvalidatedCaches()
+	channels := []interface{}{}
+	for channel := range invalidatedCaches {
+		channels = append(channels, channel)
+	}
+	flush := func() {
+		for _, cache := range invalidatedCaches {
+			cache.Flush()
+		}
+	}
+
+	for {
+		conn, err := dialRedis(app.Config)
+		if err == nil {
+			psc := redis.PubSubConn{Conn: conn}
+			err = psc.Subscribe(channels...)
+			if err == nil {
+				flush()
+				log.D(l, "Listening to cache invalidation.")
+			}
+
+			// closing the connection makes the pending Receive return an error
+			received := make(chan struct{})
+			go func() {
+				select {
+				case <-stop:
+					conn.Close()
+				case <-received:
+				}
+			}()
+
+			for err == nil {
+				switch msg := psc.Receive().(type) {
+				case redis.Message:
+					if cache, ok := invalidatedCaches[msg.Channel]; ok {
+						cache.Delete(string(msg.Data))
+					}
+				case error:
+					err = msg
+				}
+			}
+			close(received)
+			conn.Close()
+		}
+
+		select {
+		case <-stop:
+			log.D(l, "Stopped listening to cache invalidation.")
+			return
+		default:
+		}
+
+		log.W(l, "Cache invalidation listener failed. Reconnecting...", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		flush()
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+//GetAPIKey returns the API key that matches key. Keys are cached until they are revoked
+//with InvalidateAPIKey or the cache TTL expires, and unknown keys for a shorter TTL
+func (app *App) GetAPIKey(ctx context.Context, key string) (*models.APIKey, error) {
+	l := app.Logger.With(
+		zap.String("source", "app"),
+		zap.String("operation", "GetAPIKey"),
+	)
+
+	keyHash := models.HashAPIKey(key)
+	value, present := app.getAPIKeyCache.Get(keyHash)
+	if present {
+		if value == nil {
+			return nil, &models.ModelNotFoundError{Type: "APIKey", ID: "<redacted>"}
+		}
+		return value.(*models.APIKey), nil
+	}
+
+	log.D(l, "Retrieving API key...")
+	apiKey, err := models.GetAPIKeyByKey(app.Db(ctx), key)
+	if err != nil {
+		// keys are random, so an unknown key never becomes valid
+		notFoundTTL := app.Config.GetDuration("caches.getAPIKey.notFoundTTL")
+		if _, ok := err.(*models.ModelNotFoundError); ok && notFoundTTL > 0 {
+			app.getAPIKeyCache.Set(keyHash, nil, notFoundTTL)
+		}
+		return nil, err
+	}
+
+	app.getAPIKeyCache.Set(keyHash, apiKey, gocache.DefaultExpiration)
+	return apiKey, nil
+}
+
+//InvalidateAPIKey removes a revoked API key from the cache of this and every other Khan
+//process listening to the invalidation channel
+func (app *App) InvalidateAPIKey(apiKey *models.APIKey) {
+	app.getAPIKeyCache.Delete(apiKey.KeyHash)
+	app.publishInvalidation(APIKeysInvalidationChannelKey, apiKey.KeyHash)
+}
+
+//IsGameArchived returns whether a game was archived. The result is cached for a short TTL and
+//invalidated in every Khan process with InvalidateArchivedGame when the game is (un)archived
+func (app *App) IsGameArchived(ctx context.Context, gameID string) (bool, error) {
+	value, present := app.archivedGamesCache.Get(gameID)
+	if present {
+		return value.(bool), nil
+	}
+
+	archived, err := models.IsGameArchived(app.Db(ctx), gameID)
+	if err != nil {
+		return false, err
+	}
+	app.archivedGamesCache.Set(gameID, archived, gocache.DefaultExpiration)
+	return archived, nil
+}
+
+//InvalidateArchivedGame removes whether a game is archived from the cache of this and every
+//other Khan process listening to the invalidation channel
+func (app *App) InvalidateArchivedGame(gameID string) {
+	app.archivedGamesCache.Delete(gameID)
+	app.publishInvalidation(ArchivedGamesInvalidationChannelKey, gameID)
 }
 
 //GetGame returns a game by Public ID
@@ -578,6 +972,8 @@ func (app *App) configureGoWorkers() {
 		"pool": strconv.Itoa(redisPool),
 		// unique process id
 		"process": uuid.NewV4().String(),
+		// seconds between checks for scheduled jobs, such as webhook retries
+		"poll_interval": strconv.Itoa(app.Config.GetInt("webhooks.pollInterval")),
 	}
 	redisPass := app.Config.GetString("redis.password")
 	if redisPass != "" {
@@ -602,6 +998,7 @@ func (app *App) configureGoWorkers() {
 	workers.Process(queues.KhanQueue, app.Dispatcher.PerformDispatchHook, workerCount)
 	workers.Process(queues.KhanESQueue, app.ESWorker.PerformUpdateES, workerCount)
 	workers.Process(queues.KhanMongoQueue, app.MongoWorker.PerformUpdateMongo, workerCount)
+	workers.Process(queues.KhanRankingQueue, app.RankingWorker.PerformRebuildClanScores, workerCount)
 	l.Info("Worker configured.")
 }
 
@@ -613,6 +1010,8 @@ func (app *App) StartWorkers() {
 	)
 
 	log.D(l, "Starting workers...")
+	app.StartCacheInvalidation()
+	defer app.StopCacheInvalidation()
 	if app.Config.GetBool("webhooks.runStats") {
 		jobsStatsPort := app.Config.GetInt("webhooks.statsPort")
 		go workers.StatsServer(jobsStatsPort)
@@ -649,6 +1048,17 @@ func (app *App) initMongoWorker() {
 	app.MongoWorker = mongoWorker
 }
 
+func (app *App) initRankingWorker() {
+	l := app.Logger.With(
+		zap.String("source", "app"),
+		zap.String("operation", "initRankingWorker"),
+	)
+
+	log.D(l, "Initializing ranking worker...")
+	app.RankingWorker = NewRankingWorker(app)
+	log.I(l, "Ranking Worker initialized successfully")
+}
+
 func (app *App) initDispatcher() {
 	l := app.Logger.With(
 		zap.String("source", "app"),
@@ -755,7 +1165,8 @@ func (app *App) Db(ctx context.Context) gorp.Database {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return app.db.WithContext(ctx).(gorp.Database)
+	db := app.db.WithContext(ctx).(gorp.Database)
+	return models.WithRequestID(db, getRequestIDFromContext(ctx))
 }
 
 // Start starts listening for web requests at specified host and port
@@ -766,6 +1177,8 @@ func (app *App) Start() {
 	)
 
 	defer app.finalizeApp()
+	app.StartCacheInvalidation()
+	defer app.StopCacheInvalidation()
 	log.I(l, "app started", func(cm log.CM) {
 		cm.Write(zap.String("host", app.Host), zap.Int("port", app.Port))
 	})
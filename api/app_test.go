@@ -13,14 +13,21 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/garyburd/redigo/redis"
+	workers "github.com/jrallison/go-workers"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/satori/go.uuid"
 	. "github.com/topfreegames/khan/api"
 	"github.com/topfreegames/khan/models"
+	"github.com/topfreegames/khan/queues"
 	kt "github.com/topfreegames/khan/testing"
+	"github.com/topfreegames/khan/util"
 )
 
 func startRouteHandler(routes []string, port int) *[]map[string]interface{} {
@@ -102,19 +109,115 @@ var _ = Describe("API Application", func() {
 		})
 	})
 
-	Describe("App Load Hooks", func() {
-		It("should load all hooks", func() {
+	Describe("App Game Hooks", func() {
+		It("should load the hooks of a game", func() {
 			gameID := uuid.NewV4().String()
 			_, err := models.GetTestHooks(testDb, gameID, 2)
 			Expect(err).NotTo(HaveOccurred())
 
 			app := GetDefaultTestApp()
-			app.NonblockingStartWorkers()
 
-			hooks := app.GetHooks(context.Background())
-			Expect(len(hooks[gameID])).To(Equal(2))
-			Expect(len(hooks[gameID][0])).To(Equal(2))
-			Expect(len(hooks[gameID][1])).To(Equal(2))
+			hooks, err := app.GetGameHooks(context.Background(), gameID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(hooks)).To(Equal(2))
+			Expect(len(hooks[0])).To(Equal(2))
+			Expect(len(hooks[1])).To(Equal(2))
+		})
+
+		It("should cache the hooks of a game until they are invalidated", func() {
+			hook, err := models.CreateHookFactory(testDb, "", models.GameUpdatedHook, "http://test/cached")
+			Expect(err).NotTo(HaveOccurred())
+
+			app := GetDefaultTestApp()
+
+			hooks, err := app.GetGameHooks(context.Background(), hook.GameID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(hooks[models.GameUpdatedHook])).To(Equal(1))
+
+			_, err = models.CreateHook(testDb, hook.GameID, models.GameUpdatedHook, nil, nil, "http://test/cached2", "", "", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			hooks, err = app.GetGameHooks(context.Background(), hook.GameID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(hooks[models.GameUpdatedHook])).To(Equal(1))
+
+			app.InvalidateGameHooks(hook.GameID)
+
+			hooks, err = app.GetGameHooks(context.Background(), hook.GameID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(hooks[models.GameUpdatedHook])).To(Equal(2))
+		})
+
+		It("should invalidate the hooks of a game in other apps", func() {
+			hook, err := models.CreateHookFactory(testDb, "", models.GameUpdatedHook, "http://test/cached")
+			Expect(err).NotTo(HaveOccurred())
+
+			app := GetDefaultTestApp()
+			otherApp := GetDefaultTestApp()
+			otherApp.StartCacheInvalidation()
+			defer otherApp.StopCacheInvalidation()
+
+			hooks, err := otherApp.GetGameHooks(context.Background(), hook.GameID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(hooks[models.GameUpdatedHook])).To(Equal(1))
+
+			status, _ := PostJSON(app, GetGameRoute(hook.GameID, "/hooks"), map[string]interface{}{
+				"type":    models.GameUpdatedHook,
+				"hookURL": "http://test/cached2",
+			})
+			Expect(status).To(Equal(http.StatusOK))
+
+			Eventually(func() int {
+				hooks, err := otherApp.GetGameHooks(context.Background(), hook.GameID)
+				Expect(err).NotTo(HaveOccurred())
+				return len(hooks[models.GameUpdatedHook])
+			}).Should(Equal(2))
+		})
+	})
+
+	Describe("App API Keys", func() {
+		It("should cache unknown API keys", func() {
+			key := uuid.NewV4().String()
+			app := GetDefaultTestApp()
+
+			_, err := app.GetAPIKey(context.Background(), key)
+			Expect(err).To(BeAssignableToTypeOf(&models.ModelNotFoundError{}))
+
+			// the key is not read from the database again while it is cached
+			err = testDb.Insert(&models.APIKey{
+				GameID:   uuid.NewV4().String(),
+				PublicID: uuid.NewV4().String(),
+				Role:     models.APIKeyRoleServer,
+				KeyHash:  models.HashAPIKey(key),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = app.GetAPIKey(context.Background(), key)
+			Expect(err).To(BeAssignableToTypeOf(&models.ModelNotFoundError{}))
+		})
+
+		It("should invalidate revoked API keys in other apps", func() {
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := testDb.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+			apiKey, key, err := models.CreateAPIKey(testDb, game.PublicID, "", models.APIKeyRoleServer)
+			Expect(err).NotTo(HaveOccurred())
+
+			app := GetDefaultTestApp()
+			otherApp := GetDefaultTestApp()
+			otherApp.StartCacheInvalidation()
+			defer otherApp.StopCacheInvalidation()
+
+			_, err = otherApp.GetAPIKey(context.Background(), key)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, _ := Delete(app, GetGameRoute(game.PublicID, fmt.Sprintf("/api-keys/%s", apiKey.PublicID)))
+			Expect(status).To(Equal(http.StatusOK))
+
+			Eventually(func() error {
+				_, err := otherApp.GetAPIKey(context.Background(), key)
+				return err
+			}).Should(HaveOccurred())
 		})
 	})
 
@@ -256,5 +359,257 @@ var _ = Describe("API Application", func() {
 				return len(*responses)
 			}, 50*time.Millisecond, 10*time.Millisecond).Should(Equal(0))
 		})
+
+		It("should sign hooks that have a secret", func() {
+			gameID := uuid.NewV4().String()
+			hook, err := models.CreateHookFactory(testDb, gameID, models.GameUpdatedHook, "http://localhost:52525/signed")
+			Expect(err).NotTo(HaveOccurred())
+			hook.Secret = "my-secret"
+			_, err = testDb.Update(hook)
+			Expect(err).NotTo(HaveOccurred())
+			startRouteHandler([]string{}, 52525)
+
+			var verifyErr error
+			verified := false
+			http.HandleFunc("/signed", func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				timestamp, _ := strconv.ParseInt(r.Header.Get(util.HookTimestampHeader), 10, 64)
+				verifyErr = util.VerifyHookSignature(
+					"my-secret", r.Header.Get(util.HookSignatureHeader),
+					timestamp, body, util.DefaultHookSignatureTolerance,
+				)
+				verified = true
+			})
+
+			app := GetDefaultTestApp()
+			app.NonblockingStartWorkers()
+
+			err = app.DispatchHooks(gameID, models.GameUpdatedHook, map[string]interface{}{
+				"success":  true,
+				"publicID": gameID,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() bool {
+				return verified
+			}).Should(BeTrue())
+			Expect(verifyErr).NotTo(HaveOccurred())
+		})
+
+		It("should only dispatch hooks whose filter matches the payload", func() {
+			gameID := uuid.NewV4().String()
+			_, err := models.CreateHookFactory(testDb, gameID, models.MembershipPromotedHook, nil, nil, "http://localhost:52525/filtered-out")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = models.CreateHook(
+				testDb, gameID, models.MembershipPromotedHook, nil, nil, "http://localhost:52525/filtered-out",
+				"", `clan.metadata.region == "us"`, nil,
+			)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = models.CreateHook(
+				testDb, gameID, models.MembershipPromotedHook, nil, nil, "http://localhost:52525/filtered-in",
+				"", `clan.metadata.region == "eu" && level >= 2`, nil,
+			)
+			Expect(err).NotTo(HaveOccurred())
+			responses := startRouteHandler([]string{"/filtered-in", "/filtered-out"}, 52525)
+
+			app := GetDefaultTestApp()
+			app.NonblockingStartWorkers()
+
+			err = app.DispatchHooks(gameID, models.MembershipPromotedHook, map[string]interface{}{
+				"success": true,
+				"level":   2,
+				"clan": map[string]interface{}{
+					"metadata": map[string]interface{}{"region": "eu"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				return len(*responses)
+			}).Should(Equal(1))
+			Consistently(func() int {
+				return len(*responses)
+			}, 100*time.Millisecond, 10*time.Millisecond).Should(Equal(1))
+			req := (*responses)[0]["request"].(*http.Request)
+			Expect(req.URL.Path).To(Equal("/filtered-in"))
+		})
+
+		It("should dispatch hooks subscribed through wildcards once per event", func() {
+			gameID := uuid.NewV4().String()
+			_, err := models.CreateHookFactory(testDb, gameID, models.GameUpdatedHook, "http://localhost:52525/wildcard")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = models.CreateHook(
+				testDb, gameID, models.GameUpdatedHook,
+				[]int{models.MembershipApprovedHook}, []string{"membership.*"},
+				"http://localhost:52525/wildcard", "", "", nil,
+			)
+			Expect(err).NotTo(HaveOccurred())
+			responses := startRouteHandler([]string{"/wildcard"}, 52525)
+
+			app := GetDefaultTestApp()
+			app.NonblockingStartWorkers()
+
+			err = app.DispatchHooks(gameID, models.MembershipApprovedHook, map[string]interface{}{
+				"success": true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			err = app.DispatchHooks(gameID, models.ClanCreatedHook, map[string]interface{}{
+				"success": true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				return len(*responses)
+			}).Should(Equal(1))
+			Consistently(func() int {
+				return len(*responses)
+			}, 100*time.Millisecond, 10*time.Millisecond).Should(Equal(1))
+			payload := (*responses)[0]["payload"].(map[string]interface{})
+			Expect(payload["type"]).To(BeEquivalentTo(models.MembershipApprovedHook))
+		})
+
+		It("should render the hook body template", func() {
+			gameID := uuid.NewV4().String()
+			_, err := models.CreateHookFactory(testDb, gameID, models.ClanCreatedHook, "http://localhost:52525/templated")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = models.CreateHook(
+				testDb, gameID, models.ClanCreatedHook, nil, nil, "http://localhost:52525/templated",
+				"", "", map[string]interface{}{
+					"text":  "Clan {{name}} was created",
+					"clan":  "{{publicID}}",
+					"extra": map[string]interface{}{"members": "{{membershipCount}}"},
+				},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			responses := startRouteHandler([]string{"/templated"}, 52525)
+
+			app := GetDefaultTestApp()
+			app.NonblockingStartWorkers()
+
+			err = app.DispatchHooks(gameID, models.ClanCreatedHook, map[string]interface{}{
+				"success":         true,
+				"publicID":        "clan-id",
+				"name":            "My Clan",
+				"membershipCount": 1,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				return len(*responses)
+			}).Should(Equal(1))
+			payload := (*responses)[0]["payload"].(map[string]interface{})
+			Expect(payload).To(HaveLen(3))
+			Expect(payload["text"]).To(Equal("Clan My Clan was created"))
+			Expect(payload["clan"]).To(Equal("clan-id"))
+			Expect(payload["extra"]).To(Equal(map[string]interface{}{"members": float64(1)}))
+		})
+
+		It("should retry hooks that fail", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52525/retried",
+			}, models.GameUpdatedHook)
+			Expect(err).NotTo(HaveOccurred())
+			startRouteHandler([]string{}, 52525)
+
+			var requests int32
+			http.HandleFunc("/retried", func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			})
+
+			app := GetDefaultTestApp()
+			app.NonblockingStartWorkers()
+
+			resultingPayload := map[string]interface{}{
+				"success":  true,
+				"publicID": hooks[0].GameID,
+			}
+			err = app.DispatchHooks(hooks[0].GameID, models.GameUpdatedHook, resultingPayload)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int32 {
+				return atomic.LoadInt32(&requests)
+			}, 5*time.Second).Should(Equal(int32(2)))
+		})
+
+		It("should retry hooks at their current URL", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52525/moved-from",
+			}, models.GameUpdatedHook)
+			Expect(err).NotTo(HaveOccurred())
+			hook := hooks[0]
+			startRouteHandler([]string{}, 52525)
+
+			app := GetDefaultTestApp()
+
+			var movedToRequests int32
+			http.HandleFunc("/moved-to", func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&movedToRequests, 1)
+			})
+			http.HandleFunc("/moved-from", func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				hook.URL = "http://localhost:52525/moved-to"
+				_, err := testDb.Update(hook)
+				Expect(err).NotTo(HaveOccurred())
+				app.InvalidateGameHooks(hook.GameID)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			})
+
+			app.NonblockingStartWorkers()
+
+			resultingPayload := map[string]interface{}{
+				"success":  true,
+				"publicID": hook.GameID,
+			}
+			err = app.DispatchHooks(hook.GameID, models.GameUpdatedHook, resultingPayload)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int32 {
+				return atomic.LoadInt32(&movedToRequests)
+			}, 5*time.Second).Should(Equal(int32(1)))
+		})
+
+		It("should send hooks that ran out of retries to the dead-letter queue", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52525/dead",
+				"http://localhost:52525/alive",
+			}, models.GameUpdatedHook)
+			Expect(err).NotTo(HaveOccurred())
+			responses := startRouteHandler([]string{"/alive"}, 52525)
+
+			http.HandleFunc("/dead", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+
+			app := GetDefaultTestApp()
+			app.NonblockingStartWorkers()
+
+			resultingPayload := map[string]interface{}{
+				"success":  true,
+				"publicID": hooks[0].GameID,
+			}
+			err = app.DispatchHooks(hooks[0].GameID, models.GameUpdatedHook, resultingPayload)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				return len(*responses)
+			}).Should(Equal(1))
+
+			Eventually(func() bool {
+				conn := workers.Config.Pool.Get()
+				defer conn.Close()
+				jobs, err := redis.Strings(conn.Do(
+					"lrange", fmt.Sprintf("%squeue:%s", workers.Config.Namespace, queues.KhanDeadLetterQueue), 0, -1,
+				))
+				Expect(err).NotTo(HaveOccurred())
+				for _, job := range jobs {
+					if strings.Contains(job, hooks[0].PublicID) {
+						return true
+					}
+				}
+				return false
+			}, 5*time.Second).Should(BeTrue())
+		})
 	})
 })
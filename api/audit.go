@@ -0,0 +1,122 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+func getAuditEntriesOptions(app *App, c echo.Context) (*models.AuditEntriesOptions, error) {
+	options := &models.AuditEntriesOptions{
+		Limit:          app.Config.GetInt(models.AuditEntriesDefaultLimitKey),
+		Cursor:         c.QueryParam("cursor"),
+		ClanPublicID:   c.Param("clanPublicID"),
+		PlayerPublicID: c.Param("playerPublicID"),
+	}
+	maxLimit := app.Config.GetInt(models.AuditEntriesMaxLimitKey)
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		parsedLimit, err := strconv.ParseUint(limit, 10, 16)
+		if err != nil || parsedLimit == 0 {
+			return nil, fmt.Errorf("Limit must be a positive integer.")
+		}
+		if int(parsedLimit) > maxLimit {
+			return nil, fmt.Errorf("Limit above allowed (%v).", maxLimit)
+		}
+		options.Limit = int(parsedLimit)
+	}
+
+	for _, param := range []string{"since", "until"} {
+		value := c.QueryParam(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("%s must be a timestamp in milliseconds.", param)
+		}
+		if param == "since" {
+			options.Since = parsed
+		} else {
+			options.Until = parsed
+		}
+	}
+
+	return options, nil
+}
+
+// AuditEntriesHandler is the handler responsible for returning the audit trail of a game,
+// optionally restricted to a clan or a player
+func AuditEntriesHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "AuditEntries")
+		start := time.Now()
+		gameID := c.Param("gameID")
+
+		l := app.Logger.With(
+			zap.String("source", "auditHandler"),
+			zap.String("operation", "auditEntries"),
+			zap.String("gameID", gameID),
+			zap.String("clanPublicID", c.Param("clanPublicID")),
+			zap.String("playerPublicID", c.Param("playerPublicID")),
+		)
+
+		options, err := getAuditEntriesOptions(app, c)
+		if err != nil {
+			return FailWith(400, err.Error(), c)
+		}
+
+		log.D(l, "Getting DB connection...")
+		db, err := app.GetCtxDB(c)
+		if err != nil {
+			log.E(l, "Failed to connect to DB.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWith(500, err.Error(), c)
+		}
+		log.D(l, "DB Connection successful.")
+
+		var entries []*models.AuditEntry
+		var nextCursor string
+		err = WithSegment("audit-entries-retrieve", c, func() error {
+			log.D(l, "Retrieving audit entries...")
+			entries, nextCursor, err = models.GetAuditEntries(db, gameID, options)
+			if err != nil {
+				log.E(l, "Audit entries retrieval failed.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		serializedEntries := make([]map[string]interface{}, len(entries))
+		for i, entry := range entries {
+			serializedEntries[i] = entry.Serialize()
+		}
+
+		log.D(l, "Audit entries retrieved successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+
+		return SucceedWith(map[string]interface{}{
+			"entries":    serializedEntries,
+			"nextCursor": nextCursor,
+		}, c)
+	}
+}
@@ -0,0 +1,143 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Audit API Handler", func() {
+	var testDb models.DB
+	var a *api.App
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+		a = GetDefaultTestApp()
+	})
+
+	Describe("Audit Entries Handler", func() {
+		It("Should record the actions of a request with its request ID", func() {
+			_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID))
+			status, body, headers := DoRequestWithHeaders(a, "PUT", route, map[string]interface{}{
+				"name":             "audited clan",
+				"ownerPublicID":    owner.PublicID,
+				"metadata":         clan.Metadata,
+				"allowApplication": clan.AllowApplication,
+				"autoJoin":         clan.AutoJoin,
+			}, map[string]string{api.RequestIDHeader: "update-request"})
+			Expect(status).To(Equal(http.StatusOK), body)
+			Expect(headers.Get(api.RequestIDHeader)).To(Equal("update-request"))
+
+			for _, route := range []string{
+				GetGameRoute(clan.GameID, "/audit"),
+				GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s/audit", clan.PublicID)),
+				GetGameRoute(clan.GameID, fmt.Sprintf("/players/%s/audit", owner.PublicID)),
+			} {
+				status, body = Get(a, route)
+				Expect(status).To(Equal(http.StatusOK), body)
+				var result map[string]interface{}
+				json.Unmarshal([]byte(body), &result)
+				Expect(result["success"]).To(BeTrue())
+				Expect(result["nextCursor"]).To(Equal(""))
+
+				entries := result["entries"].([]interface{})
+				Expect(entries).To(HaveLen(1))
+				entry := entries[0].(map[string]interface{})
+				Expect(entry["action"]).To(Equal(models.ClanUpdatedAction))
+				Expect(entry["actorPublicID"]).To(Equal(owner.PublicID))
+				Expect(entry["clanPublicID"]).To(Equal(clan.PublicID))
+				Expect(entry["requestID"]).To(Equal("update-request"))
+				Expect(entry["before"].(map[string]interface{})["name"]).To(Equal(clan.Name))
+				Expect(entry["after"].(map[string]interface{})["name"]).To(Equal("audited clan"))
+			}
+		})
+
+		It("Should truncate long request IDs without splitting characters", func() {
+			requestID := strings.Repeat("é", 200)
+			status, _, headers := DoRequestWithHeaders(a, "GET", "/games", nil, map[string]string{
+				api.RequestIDHeader: requestID,
+			})
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(headers.Get(api.RequestIDHeader)).To(Equal(strings.Repeat("é", 127)))
+		})
+
+		It("Should generate a request ID if none is sent", func() {
+			_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID))
+			status, body, headers := DoRequestWithHeaders(a, "PATCH", route, map[string]interface{}{
+				"ownerPublicID": owner.PublicID,
+				"name":          "audited clan",
+			}, nil)
+			Expect(status).To(Equal(http.StatusOK), body)
+			requestID := headers.Get(api.RequestIDHeader)
+			Expect(requestID).NotTo(BeEmpty())
+
+			entries, _, err := models.GetAuditEntries(testDb, clan.GameID, &models.AuditEntriesOptions{
+				Limit: 10, ClanPublicID: clan.PublicID,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].RequestID).To(Equal(requestID))
+		})
+
+		It("Should return entries of players in other clans only in the game trail", func() {
+			_, clan, _, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+			_, otherClan, otherOwner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, clan.GameID, "", true)
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", otherClan.PublicID))
+			status, body := PatchJSON(a, route, map[string]interface{}{
+				"ownerPublicID": otherOwner.PublicID,
+				"name":          "audited clan",
+			})
+			Expect(status).To(Equal(http.StatusOK), body)
+
+			status, body = Get(a, GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s/audit", clan.PublicID)))
+			Expect(status).To(Equal(http.StatusOK), body)
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["entries"]).To(BeEmpty())
+
+			status, body = Get(a, GetGameRoute(clan.GameID, "/audit"))
+			Expect(status).To(Equal(http.StatusOK), body)
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["entries"]).To(HaveLen(1))
+		})
+
+		It("Should fail if the query parameters are invalid", func() {
+			for _, query := range []string{"limit=0", "limit=1001", "since=yesterday", "until=-1"} {
+				status, body := Get(a, GetGameRoute("some-game", "/audit?"+query))
+				Expect(status).To(Equal(http.StatusBadRequest), query)
+				var result map[string]interface{}
+				json.Unmarshal([]byte(body), &result)
+				Expect(result["success"]).To(BeFalse())
+			}
+		})
+
+		It("Should fail if the cursor is invalid", func() {
+			status, body := Get(a, GetGameRoute("some-game", "/audit?cursor=invalid"))
+			Expect(status).To(Equal(http.StatusBadRequest), body)
+		})
+	})
+})
@@ -9,6 +9,7 @@ package api
 
 import (
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -126,6 +127,7 @@ func CreateClanHandler(app *App) func(c echo.Context) error {
 			log.D(l, "Creating clan...")
 			clan, err = models.CreateClan(
 				tx,
+				game,
 				gameID,
 				payload.PublicID,
 				payload.Name,
@@ -268,10 +270,27 @@ func UpdateClanHandler(app *App) func(c echo.Context) error {
 				return err
 			}
 
+			expectedVersion, ok := getExpectedVersion(c, beforeUpdateClan.Version)
+			if !ok {
+				err = &models.VersionConflictError{Type: "Clan", ID: publicID, Version: beforeUpdateClan.Version}
+				log.D(l, "Clan version does not match the request preconditions.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+
+			// the clan and its audit entry are written in the same transaction
+			var tx interfaces.Transaction
+			tx, err = app.BeginTrans(c.StdContext(), l)
+			if err != nil {
+				return err
+			}
+
 			err = WithSegment("clan-update-query", c, func() error {
 				log.D(l, "Updating clan...")
-				clan, err = models.UpdateClan(
-					db,
+				clan, err = models.UpdateClanWithVersion(
+					tx,
+					game,
 					gameID,
 					publicID,
 					payload.Name,
@@ -279,18 +298,25 @@ func UpdateClanHandler(app *App) func(c echo.Context) error {
 					payload.Metadata,
 					payload.AllowApplication,
 					payload.AutoJoin,
+					expectedVersion,
 				)
 				return err
 			})
 			if err != nil {
-				log.E(l, "Updating clan failed.", func(cm log.CM) {
-					cm.Write(zap.Error(err))
-				})
+				txErr := app.Rollback(tx, "Updating clan failed", c, l, err)
+				if txErr == nil {
+					log.E(l, "Updating clan failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
 				return err
 			}
-			return nil
+			return app.Commit(tx, "Update clan", c, l)
 		})
 		if err != nil {
+			if conflict, ok := err.(*models.VersionConflictError); ok {
+				return FailWithVersionConflict(conflict.Error(), conflict.Version, c)
+			}
 			return FailWithError(err, c)
 		}
 
@@ -330,7 +356,166 @@ func UpdateClanHandler(app *App) func(c echo.Context) error {
 		log.D(l, "Clan updated successfully.", func(cm log.CM) {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
-		return SucceedWith(map[string]interface{}{}, c)
+		c.Response().Header().Set(ETagHeader, getVersionETag(clan.Version))
+		return SucceedWith(map[string]interface{}{
+			"version": clan.Version,
+		}, c)
+	}
+}
+
+// PatchClanHandler is the handler responsible for partially updating existing clans
+func PatchClanHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "PatchClan")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		publicID := c.Param("clanPublicID")
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "clanHandler"),
+			zap.String("operation", "patchClan"),
+			zap.String("gameID", gameID),
+			zap.String("clanPublicID", publicID),
+		)
+
+		var payload PatchClanPayload
+		err := WithSegment("payload", c, func() error {
+			return LoadJSONPayload(&payload, c, l)
+		})
+		if err != nil {
+			return FailWith(400, err.Error(), c)
+		}
+
+		var clan, beforePatchClan *models.Clan
+		var game *models.Game
+
+		err = WithSegment("clan-patch", c, func() error {
+			err = WithSegment("game-retrieve", c, func() error {
+				log.D(l, "Retrieving game...")
+				game, err = models.GetGameByPublicID(db, gameID)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			err = WithSegment("clan-retrieve", c, func() error {
+				log.D(l, "Retrieving clan...")
+				beforePatchClan, err = models.GetClanByPublicID(db, gameID, publicID)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			expectedVersion, ok := getExpectedVersion(c, beforePatchClan.Version)
+			if !ok {
+				return &models.VersionConflictError{Type: "Clan", ID: publicID, Version: beforePatchClan.Version}
+			}
+
+			// the clan and its audit entry are written in the same transaction
+			var tx interfaces.Transaction
+			tx, err = app.BeginTrans(c.StdContext(), l)
+			if err != nil {
+				return err
+			}
+
+			err = WithSegment("clan-patch-query", c, func() error {
+				log.D(l, "Patching clan...")
+				clan, err = models.PatchClan(
+					tx,
+					game,
+					gameID,
+					publicID,
+					payload.OwnerPublicID,
+					&models.ClanPatch{
+						Name:             payload.Name,
+						AllowApplication: payload.AllowApplication,
+						AutoJoin:         payload.AutoJoin,
+						Metadata:         getMetadataPatch(payload.Metadata, payload.IncrementMetadata),
+					},
+					expectedVersion,
+				)
+				return err
+			})
+			if err != nil {
+				app.Rollback(tx, "Patching clan failed", c, l, err)
+				return err
+			}
+			return app.Commit(tx, "Patch clan", c, l)
+		})
+		if err != nil {
+			log.W(l, "Patching clan failed.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			if conflict, ok := err.(*models.VersionConflictError); ok {
+				return FailWithVersionConflict(conflict.Error(), conflict.Version, c)
+			}
+			return FailWithError(err, c)
+		}
+
+		// the clan is only indexed once the patch is committed, so the
+		// search indexes never see a patch that was rolled back
+		err = WithSegment("clan-index", c, func() error {
+			err = clan.UpdateClanIntoElasticSearch()
+			if err != nil {
+				return err
+			}
+			return clan.UpdateClanIntoMongoDB()
+		})
+		if err != nil {
+			log.E(l, "Indexing patched clan failed.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+		}
+
+		clanJSON := map[string]interface{}{
+			"publicID":         clan.PublicID,
+			"name":             clan.Name,
+			"membershipCount":  clan.MembershipCount,
+			"ownerPublicID":    payload.OwnerPublicID,
+			"metadata":         clan.Metadata,
+			"allowApplication": clan.AllowApplication,
+			"autoJoin":         clan.AutoJoin,
+		}
+
+		result := map[string]interface{}{
+			"gameID": gameID,
+			"clan":   clanJSON,
+		}
+
+		err = WithSegment("hook-dispatch", c, func() error {
+			shouldDispatch := validateUpdateClanDispatch(game, beforePatchClan, clan, clan.Metadata, l)
+			if shouldDispatch {
+				log.D(l, "Dispatching clan update hooks...")
+				err = app.DispatchHooks(gameID, models.ClanUpdatedHook, result)
+				if err != nil {
+					log.E(l, "Clan updated hook dispatch failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(500, err.Error(), c)
+		}
+
+		log.D(l, "Clan patched successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		c.Response().Header().Set(ETagHeader, getVersionETag(clan.Version))
+		return SucceedWith(map[string]interface{}{
+			"publicID":         clan.PublicID,
+			"name":             clan.Name,
+			"metadata":         clan.Metadata,
+			"allowApplication": clan.AllowApplication,
+			"autoJoin":         clan.AutoJoin,
+			"version":          clan.Version,
+		}, c)
 	}
 }
 
@@ -352,10 +537,24 @@ func LeaveClanHandler(app *App) func(c echo.Context) error {
 			zap.String("clanPublicID", publicID),
 		)
 
+		var game *models.Game
+		var err error
+
+		err = WithSegment("game-retrieve", c, func() error {
+			game, err = app.GetGame(c.StdContext(), gameID)
+			if err != nil {
+				log.W(l, "Could not find game.")
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(404, err.Error(), c)
+		}
+
 		var tx interfaces.Transaction
 		var clan *models.Clan
 		var previousOwner, newOwner *models.Player
-		var err error
 
 		//rollback function
 		rb := func(err error) error {
@@ -381,6 +580,7 @@ func LeaveClanHandler(app *App) func(c echo.Context) error {
 				log.D(l, "Leaving clan...")
 				clan, previousOwner, newOwner, err = models.LeaveClan(
 					tx,
+					game,
 					gameID,
 					publicID,
 				)
@@ -466,6 +666,121 @@ func LeaveClanHandler(app *App) func(c echo.Context) error {
 	}
 }
 
+// DeleteClanHandler is the handler responsible for deleting a clan and ending all of its memberships
+func DeleteClanHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "DeleteClan")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		publicID := c.Param("clanPublicID")
+		requestorPublicID := c.QueryParam("requestorPublicID")
+		admin := c.QueryParam("admin") == "true"
+
+		l := app.Logger.With(
+			zap.String("source", "clanHandler"),
+			zap.String("operation", "deleteClan"),
+			zap.String("gameID", gameID),
+			zap.String("clanPublicID", publicID),
+			zap.String("requestorPublicID", requestorPublicID),
+			zap.Bool("admin", admin),
+		)
+
+		if requestorPublicID == "" && !admin {
+			return FailWith(http.StatusBadRequest, "Either requestorPublicID or admin=true must be sent.", c)
+		}
+		if requestorPublicID != "" && admin {
+			return FailWith(http.StatusBadRequest, "requestorPublicID and admin=true can't be sent together.", c)
+		}
+		// requests authenticated with the basic auth credentials carry no API key and can delete as an admin
+		if apiKey, ok := c.Get(apiKeyContextKey).(*models.APIKey); ok && admin && apiKey.Role != models.APIKeyRoleAdmin {
+			return FailWith(http.StatusForbidden, "Only admin API keys can delete clans as an admin.", c)
+		}
+
+		var tx interfaces.Transaction
+		var clan *models.Clan
+		var owner *models.Player
+		var err error
+
+		//rollback function
+		rb := func(err error) error {
+			txErr := app.Rollback(tx, "Deleting clan failed", c, l, err)
+			if txErr != nil {
+				return txErr
+			}
+
+			return nil
+		}
+
+		err = WithSegment("clan-delete", c, func() error {
+			err = WithSegment("tx-begin", c, func() error {
+				tx, err = app.BeginTrans(c.StdContext(), l)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			log.D(l, "DB Tx begun successful.")
+
+			err = WithSegment("clan-delete-query", c, func() error {
+				log.D(l, "Deleting clan...")
+				if admin {
+					clan, owner, err = models.DeleteClanAsAdmin(tx, gameID, publicID, getAdminAuditActor(c))
+					return err
+				}
+				clan, owner, err = models.DeleteClan(
+					tx,
+					gameID,
+					publicID,
+					requestorPublicID,
+				)
+				return err
+			})
+
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Clan delete failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		err = WithSegment("hook-dispatch", c, func() error {
+			err = dispatchClanDeletedHook(app, clan, owner, requestorPublicID)
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Clan deleted hook dispatch failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(500, err.Error(), c)
+		}
+
+		err = app.Commit(tx, "Deleted clan", c, l)
+		if err != nil {
+			return FailWith(500, err.Error(), c)
+		}
+
+		log.I(l, "Deleted clan successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+
+		return SucceedWith(map[string]interface{}{}, c)
+	}
+}
+
 // TransferOwnershipHandler is the handler responsible for transferring the clan ownership to another clan member
 func TransferOwnershipHandler(app *App) func(c echo.Context) error {
 	return func(c echo.Context) error {
@@ -538,6 +853,7 @@ func TransferOwnershipHandler(app *App) func(c echo.Context) error {
 				log.D(l, "Transferring clan ownership...")
 				clan, previousOwner, newOwner, err = models.TransferClanOwnership(
 					tx,
+					game,
 					gameID,
 					publicID,
 					payload.PlayerPublicID,
@@ -612,7 +928,7 @@ func TransferOwnershipHandler(app *App) func(c echo.Context) error {
 	}
 }
 
-// ListClansHandler is the handler responsible for returning a list of all clans
+// ListClansHandler is the handler responsible for returning all the clans of a game or a page of them
 func ListClansHandler(app *App) func(c echo.Context) error {
 	return func(c echo.Context) error {
 		c.Set("route", "ListClans")
@@ -625,6 +941,11 @@ func ListClansHandler(app *App) func(c echo.Context) error {
 			zap.String("gameID", gameID),
 		)
 
+		options, err := getListClansOptions(app, c)
+		if err != nil {
+			return FailWith(400, err.Error(), c)
+		}
+
 		log.D(l, "Getting DB connection...")
 		db, err := app.GetCtxDB(c)
 		if err != nil {
@@ -636,15 +957,22 @@ func ListClansHandler(app *App) func(c echo.Context) error {
 		log.D(l, "DB Connection successful.")
 
 		var clans []models.Clan
-		err = WithSegment("clan-get-all", c, func() error {
-			log.D(l, "Retrieving all clans...")
-			clans, err = models.GetAllClans(
-				db,
-				gameID,
-			)
+		var nextCursor string
+		err = WithSegment("clan-list", c, func() error {
+			log.D(l, "Listing clans...")
+			// clients that do not ask for a page keep getting every clan
+			if options == nil {
+				clans, err = models.GetAllClans(db, gameID)
+			} else {
+				clans, nextCursor, err = models.ListClans(
+					db,
+					gameID,
+					options,
+				)
+			}
 
 			if err != nil {
-				log.E(l, "Retrieve all clans failed.", func(cm log.CM) {
+				log.E(l, "List clans failed.", func(cm log.CM) {
 					cm.Write(zap.Error(err))
 				})
 				return err
@@ -652,7 +980,7 @@ func ListClansHandler(app *App) func(c echo.Context) error {
 			return nil
 		})
 		if err != nil {
-			return FailWith(500, err.Error(), c)
+			return FailWithError(err, c)
 		}
 
 		var serializedClans []map[string]interface{}
@@ -661,13 +989,17 @@ func ListClansHandler(app *App) func(c echo.Context) error {
 			return nil
 		})
 
-		log.D(l, "Retrieve all clans completed successfully.", func(cm log.CM) {
+		log.D(l, "List clans completed successfully.", func(cm log.CM) {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
 
-		return SucceedWith(map[string]interface{}{
+		result := map[string]interface{}{
 			"clans": serializedClans,
-		}, c)
+		}
+		if options != nil {
+			result["nextCursor"] = nextCursor
+		}
+		return SucceedWith(result, c)
 	}
 }
 
@@ -678,7 +1010,6 @@ func SearchClansHandler(app *App) func(c echo.Context) error {
 		start := time.Now()
 		gameID := c.Param("gameID")
 		term := c.QueryParam("term")
-		pageSize := app.Config.GetInt64("search.pageSize")
 
 		l := app.Logger.With(
 			zap.String("source", "clanHandler"),
@@ -692,6 +1023,11 @@ func SearchClansHandler(app *App) func(c echo.Context) error {
 			return FailWith(400, (&models.EmptySearchTermError{}).Error(), c)
 		}
 
+		pageSize, err := getSearchClansPageSize(app, c)
+		if err != nil {
+			return FailWith(400, err.Error(), c)
+		}
+
 		log.D(l, "Getting DB connection...")
 		db, err := app.GetCtxDB(c)
 		if err != nil {
@@ -864,6 +1200,16 @@ func RetrieveClanHandler(app *App) func(c echo.Context) error {
 		log.D(l, "Clan details retrieved successfully.", func(cm log.CM) {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
+
+		version := clanResult["version"].(int64)
+		switch checkReadPreconditions(c, version) {
+		case http.StatusNotModified:
+			return c.NoContent(http.StatusNotModified)
+		case http.StatusConflict:
+			err = &models.VersionConflictError{Type: "Clan", ID: clan.PublicID, Version: version}
+			return FailWithVersionConflict(err.Error(), version, c)
+		}
+
 		return SucceedWith(clanResult, c)
 	}
 }
@@ -883,6 +1229,11 @@ func RetrieveClanMembersHandler(app *App) func(c echo.Context) error {
 			zap.String("clanPublicID", publicID),
 		)
 
+		options, err := getClanMembersOptions(app, c)
+		if err != nil {
+			return FailWith(400, err.Error(), c)
+		}
+
 		log.D(l, "Getting DB connection...")
 		db, err := app.GetCtxDB(c)
 		if err != nil {
@@ -894,23 +1245,61 @@ func RetrieveClanMembersHandler(app *App) func(c echo.Context) error {
 		log.D(l, "DB Connection successful.")
 
 		var result map[string]interface{}
-		err = WithSegment("clan-get-playerids", c, func() error {
-			log.D(l, "Retrieving clan players...")
-			result, err = models.GetClanMembers(
-				db,
-				gameID,
-				publicID,
-			)
+		if options == nil {
+			err = WithSegment("clan-get-playerids", c, func() error {
+				log.D(l, "Retrieving clan players...")
+				result, err = models.GetClanMembers(
+					db,
+					gameID,
+					publicID,
+				)
+				if err != nil {
+					log.E(l, "Clan playerids retrieval failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+					return err
+				}
+				return nil
+			})
 			if err != nil {
-				log.E(l, "Clan playerids retrieval failed.", func(cm log.CM) {
-					cm.Write(zap.Error(err))
-				})
-				return err
+				return FailWith(500, err.Error(), c)
+			}
+		} else {
+			var game *models.Game
+			err = WithSegment("game-retrieve", c, func() error {
+				game, err = app.GetGame(c.StdContext(), gameID)
+				if err != nil {
+					log.W(l, "Could not find game.")
+					return err
+				}
+				return nil
+			})
+			if err != nil {
+				return FailWith(404, err.Error(), c)
+			}
+			if _, ok := game.MembershipLevels[options.Level]; options.Level != "" && !ok {
+				return FailWith(400, (&models.InvalidLevelForGameError{GameID: gameID, Level: options.Level}).Error(), c)
+			}
+
+			err = WithSegment("clan-get-members-page", c, func() error {
+				log.D(l, "Retrieving page of clan members...")
+				result, err = models.GetClanMembersPage(
+					db,
+					game,
+					publicID,
+					options,
+				)
+				if err != nil {
+					log.E(l, "Clan members page retrieval failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+					return err
+				}
+				return nil
+			})
+			if err != nil {
+				return FailWithError(err, c)
 			}
-			return nil
-		})
-		if err != nil {
-			return FailWith(500, err.Error(), c)
 		}
 
 		log.D(l, "Clan playerids retrieved successfully.", func(cm log.CM) {
@@ -1057,3 +1446,96 @@ func RetrieveClansSummariesHandler(app *App) func(c echo.Context) error {
 		return SucceedWith(clansResponse, c)
 	}
 }
+
+// TopClansHandler is the handler responsible for returning the top clans of a game in a ranking dimension
+func TopClansHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "TopClans")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		dimension := c.Param("dimension")
+		clanPublicID := c.QueryParam("clanPublicID")
+
+		l := app.Logger.With(
+			zap.String("source", "clanHandler"),
+			zap.String("operation", "TopClans"),
+			zap.String("gameID", gameID),
+			zap.String("dimension", dimension),
+			zap.String("clanPublicID", clanPublicID),
+		)
+
+		limit, err := getTopClansLimit(app, c)
+		if err != nil {
+			return FailWith(400, err.Error(), c)
+		}
+
+		var game *models.Game
+		err = WithSegment("game-retrieve", c, func() error {
+			game, err = app.GetGame(c.StdContext(), gameID)
+			if err != nil {
+				log.W(l, "Could not find game.")
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(404, err.Error(), c)
+		}
+
+		log.D(l, "Getting DB connection...")
+		db, err := app.GetCtxDB(c)
+		if err != nil {
+			log.E(l, "Failed to connect to DB.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWith(500, err.Error(), c)
+		}
+		log.D(l, "DB Connection successful.")
+
+		var ranks []*models.ClanRank
+		var clanRank *models.ClanRank
+		err = WithSegment("clan-top", c, func() error {
+			log.D(l, "Retrieving top clans...")
+			ranks, err = models.GetTopClans(db, game, dimension, limit)
+			if err != nil {
+				log.W(l, "Top clans retrieval failed.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+
+			if clanPublicID != "" {
+				log.D(l, "Retrieving clan rank...")
+				clanRank, err = models.GetClanRank(db, game, dimension, clanPublicID)
+				if err != nil {
+					log.W(l, "Clan rank retrieval failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		serializedClans := make([]map[string]interface{}, len(ranks))
+		for i, rank := range ranks {
+			serializedClans[i] = serializeClanRank(rank)
+		}
+		result := map[string]interface{}{
+			"dimension": dimension,
+			"clans":     serializedClans,
+		}
+		if clanRank != nil {
+			result["clan"] = serializeClanRank(clanRank)
+		}
+
+		log.D(l, "Top clans retrieved successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+
+		return SucceedWith(result, c)
+	}
+}
@@ -8,8 +8,11 @@
 package api
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/labstack/echo"
 	"github.com/topfreegames/khan/log"
 	"github.com/topfreegames/khan/models"
 	"github.com/uber-go/zap"
@@ -59,6 +62,224 @@ func dispatchClanOwnershipChangeHook(app *App, hookType int, clan *models.Clan,
 	return nil
 }
 
+func dispatchClanDeletedHook(app *App, clan *models.Clan, owner *models.Player, requestorPublicID string) error {
+	l := app.Logger.With(
+		zap.String("source", "clanHandler"),
+		zap.String("operation", "dispatchClanDeletedHook"),
+		zap.String("gameID", clan.GameID),
+		zap.String("clanPublicID", clan.PublicID),
+		zap.String("requestorPublicID", requestorPublicID),
+	)
+
+	ownerJSON := owner.Serialize()
+	delete(ownerJSON, "gameID")
+
+	clanJSON := clan.Serialize()
+	delete(clanJSON, "gameID")
+
+	result := map[string]interface{}{
+		"gameID":            clan.GameID,
+		"clan":              clanJSON,
+		"owner":             ownerJSON,
+		"requestorPublicID": requestorPublicID,
+		"deletedAt":         clan.DeletedAt,
+	}
+
+	log.D(l, "Dispatching hook...")
+	err := app.DispatchHooks(clan.GameID, models.ClanDeletedHook, result)
+	if err != nil {
+		return err
+	}
+	log.D(l, "Hook dispatch succeeded.")
+
+	return nil
+}
+
+// listClansParams are the query parameters that make ListClansHandler return a single page of clans
+var listClansParams = map[string]bool{
+	"limit": true, "cursor": true, "orderBy": true, "order": true, "allowApplication": true,
+	"autoJoin": true, "minMembershipCount": true, "maxMembershipCount": true,
+}
+
+// getListClansOptions reads the pagination, sorting and filtering options of ListClansHandler from the query string.
+// Metadata filters are sent as metadata.<field>=<value>. It returns nil options if the client did not ask for a page
+func getListClansOptions(app *App, c echo.Context) (*models.ListClansOptions, error) {
+	paged := false
+	for param := range c.QueryParams() {
+		if listClansParams[param] || strings.HasPrefix(param, "metadata.") {
+			paged = true
+		}
+	}
+	if !paged {
+		return nil, nil
+	}
+
+	options := models.NewDefaultListClansOptions(app.Config)
+	maxLimit := app.Config.GetInt(models.ListClansMaxLimitKey)
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		parsedLimit, err := strconv.ParseUint(limit, 10, 16)
+		if err != nil || parsedLimit == 0 {
+			return nil, fmt.Errorf("Limit must be a positive integer.")
+		}
+		if int(parsedLimit) > maxLimit {
+			return nil, fmt.Errorf("Limit above allowed (%v).", maxLimit)
+		}
+		options.Limit = int(parsedLimit)
+	}
+
+	options.Cursor = c.QueryParam("cursor")
+
+	if orderBy := c.QueryParam("orderBy"); orderBy != "" {
+		if !models.IsValidClanListOrderBy(orderBy) {
+			return nil, fmt.Errorf("Order by is invalid (valid fields are name, membershipCount or createdAt).")
+		}
+		options.OrderBy = orderBy
+	}
+
+	if order := c.QueryParam("order"); order != "" {
+		if order != models.Ascending && order != models.Descending {
+			return nil, fmt.Errorf("Order is invalid (valid orders are %s or %s).", models.Ascending, models.Descending)
+		}
+		options.Order = order
+	}
+
+	for _, param := range []string{"allowApplication", "autoJoin"} {
+		value := c.QueryParam(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a boolean.", param)
+		}
+		if param == "allowApplication" {
+			options.AllowApplication = &parsed
+		} else {
+			options.AutoJoin = &parsed
+		}
+	}
+
+	for _, param := range []string{"minMembershipCount", "maxMembershipCount"} {
+		value := c.QueryParam(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseUint(value, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a non negative integer.", param)
+		}
+		count := int(parsed)
+		if param == "minMembershipCount" {
+			options.MinMembershipCount = &count
+		} else {
+			options.MaxMembershipCount = &count
+		}
+	}
+
+	for param, values := range c.QueryParams() {
+		if strings.HasPrefix(param, "metadata.") && len(values) > 0 {
+			options.Metadata[strings.TrimPrefix(param, "metadata.")] = values[0]
+		}
+	}
+
+	return options, nil
+}
+
+// getClanMembersOptions reads the pagination, sorting and filtering options of RetrieveClanMembersHandler
+// from the query string. It returns nil options if the client did not ask for a page
+func getClanMembersOptions(app *App, c echo.Context) (*models.GetClanMembersOptions, error) {
+	paged := false
+	for _, param := range []string{"limit", "cursor", "level", "orderBy", "order"} {
+		if c.QueryParam(param) != "" {
+			paged = true
+		}
+	}
+	if !paged {
+		return nil, nil
+	}
+
+	options := models.NewDefaultGetClanMembersOptions(app.Config)
+	maxLimit := app.Config.GetInt(models.ClanMembersMaxLimitKey)
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		parsedLimit, err := strconv.ParseUint(limit, 10, 16)
+		if err != nil || parsedLimit == 0 {
+			return nil, fmt.Errorf("Limit must be a positive integer.")
+		}
+		if int(parsedLimit) > maxLimit {
+			return nil, fmt.Errorf("Limit above allowed (%v).", maxLimit)
+		}
+		options.Limit = int(parsedLimit)
+	}
+
+	options.Cursor = c.QueryParam("cursor")
+	options.Level = c.QueryParam("level")
+
+	if orderBy := c.QueryParam("orderBy"); orderBy != "" {
+		if !models.IsValidClanMembersOrderBy(orderBy) {
+			return nil, fmt.Errorf("Order by is invalid (valid fields are approvedAt or level).")
+		}
+		options.OrderBy = orderBy
+	}
+
+	if order := c.QueryParam("order"); order != "" {
+		if order != models.Ascending && order != models.Descending {
+			return nil, fmt.Errorf("Order is invalid (valid orders are %s or %s).", models.Ascending, models.Descending)
+		}
+		options.Order = order
+	}
+
+	return options, nil
+}
+
+// getTopClansLimit reads the number of clans TopClansHandler should return from the query string
+func getTopClansLimit(app *App, c echo.Context) (int, error) {
+	maxLimit := app.Config.GetInt(models.TopClansMaxLimitKey)
+	limit := c.QueryParam("limit")
+	if limit == "" {
+		return app.Config.GetInt(models.TopClansDefaultLimitKey), nil
+	}
+
+	parsedLimit, err := strconv.ParseUint(limit, 10, 16)
+	if err != nil || parsedLimit == 0 {
+		return 0, fmt.Errorf("Limit must be a positive integer.")
+	}
+	if int(parsedLimit) > maxLimit {
+		return 0, fmt.Errorf("Limit above allowed (%v).", maxLimit)
+	}
+	return int(parsedLimit), nil
+}
+
+// getSearchClansPageSize reads the number of clans SearchClansHandler should return from the query string
+func getSearchClansPageSize(app *App, c echo.Context) (int64, error) {
+	maxPageSize := app.Config.GetInt64(models.SearchClansMaxPageSizeKey)
+	pageSize := c.QueryParam("pageSize")
+	if pageSize == "" {
+		return app.Config.GetInt64(models.SearchClansPageSizeKey), nil
+	}
+
+	parsedPageSize, err := strconv.ParseUint(pageSize, 10, 16)
+	if err != nil || parsedPageSize == 0 {
+		return 0, fmt.Errorf("Page size must be a positive integer.")
+	}
+	if int64(parsedPageSize) > maxPageSize {
+		return 0, fmt.Errorf("Page size above allowed (%v).", maxPageSize)
+	}
+	return int64(parsedPageSize), nil
+}
+
+func serializeClanRank(rank *models.ClanRank) map[string]interface{} {
+	return map[string]interface{}{
+		"rank":            rank.Rank,
+		"score":           rank.Score,
+		"publicID":        rank.PublicID,
+		"name":            rank.Name,
+		"membershipCount": rank.MembershipCount,
+		"metadata":        rank.Metadata,
+	}
+}
+
 func serializeClans(clans []models.Clan, includePublicID bool) []map[string]interface{} {
 	serializedClans := make([]map[string]interface{}, len(clans))
 	for i, clan := range clans {
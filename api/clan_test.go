@@ -261,7 +261,11 @@ var _ = Describe("Clan API Handler", func() {
 		})
 
 		It("Should not leave a clan if invalid clan", func() {
-			route := GetGameRoute("game-id", fmt.Sprintf("clans/%s/leave", "random-id"))
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := testDb.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(game.PublicID, fmt.Sprintf("clans/%s/leave", "random-id"))
 			status, body := Post(a, route, "")
 
 			Expect(status).To(Equal(http.StatusNotFound))
@@ -272,6 +276,97 @@ var _ = Describe("Clan API Handler", func() {
 		})
 	})
 
+	Describe("Delete Clan Handler", func() {
+		It("Should delete a clan if requestor is the owner", func() {
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("clans/%s?requestorPublicID=%s", clan.PublicID, owner.PublicID))
+			status, body := Delete(a, route)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			_, err = models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).To(HaveOccurred())
+
+			dbPlayer, err := models.GetPlayerByID(db, players[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbPlayer.MembershipCount).To(Equal(0))
+		})
+
+		It("Should not delete a clan without requestor", func() {
+			_, clan, _, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("clans/%s", clan.PublicID))
+			status, body := Delete(a, route)
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+
+			dbClan, err := models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.DeletedAt).To(Equal(int64(0)))
+		})
+
+		It("Should delete a clan as an admin", func() {
+			_, clan, _, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("clans/%s?admin=true", clan.PublicID))
+			status, body := Delete(a, route)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			route = GetGameRoute(clan.GameID, fmt.Sprintf("clans/%s", clan.PublicID))
+			status, _ = Get(a, route)
+			Expect(status).To(Equal(http.StatusNotFound))
+
+			entries, _, err := models.GetAuditEntries(testDb, clan.GameID, &models.AuditEntriesOptions{
+				Limit: 1, ClanPublicID: clan.PublicID,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries[0].Action).To(Equal(models.ClanDeletedAction))
+			Expect(entries[0].ActorPublicID).To(Equal(models.AdminAuditActor))
+		})
+
+		It("Should not delete a clan if requestor is not the owner", func() {
+			_, clan, _, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("clans/%s?requestorPublicID=%s", clan.PublicID, players[0].PublicID))
+			status, body := Delete(a, route)
+
+			Expect(status).To(Equal(http.StatusForbidden))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+
+			dbClan, err := models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.DeletedAt).To(Equal(int64(0)))
+		})
+
+		It("Should not delete a clan if invalid clan", func() {
+			route := GetGameRoute("game-id", fmt.Sprintf("clans/%s", "random-id"))
+			status, body := Delete(a, route)
+
+			Expect(status).To(Equal(http.StatusNotFound))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal("Clan was not found with id: random-id"))
+		})
+	})
+
 	Describe("Transfer Clan Ownership Handler", func() {
 		It("Should transfer a clan ownership", func() {
 			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
@@ -556,6 +651,191 @@ var _ = Describe("Clan API Handler", func() {
 			Expect(result["success"]).To(BeFalse())
 			Expect(result["reason"]).To(Equal("pq: value too long for type character varying(255)"))
 		})
+
+		It("Should update clan if If-Match matches the current version", func() {
+			_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"name":             clan.Name,
+				"ownerPublicID":    owner.PublicID,
+				"metadata":         map[string]interface{}{"new": "metadata"},
+				"allowApplication": clan.AllowApplication,
+				"autoJoin":         clan.AutoJoin,
+			}
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID))
+			status, body, headers := DoRequestWithHeaders(a, "PUT", route, payload, map[string]string{
+				"If-Match": fmt.Sprintf("\"%d\"", clan.Version),
+			})
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["version"]).To(BeEquivalentTo(clan.Version + 1))
+			Expect(headers.Get("ETag")).To(Equal(fmt.Sprintf("\"%d\"", clan.Version+1)))
+
+			dbClan, err := models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.Metadata).To(Equal(map[string]interface{}{"new": "metadata"}))
+			Expect(dbClan.Version).To(Equal(clan.Version + 1))
+		})
+
+		It("Should not update clan if If-Match does not match the current version", func() {
+			_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"name":             clan.Name,
+				"ownerPublicID":    owner.PublicID,
+				"metadata":         map[string]interface{}{"first": "update"},
+				"allowApplication": clan.AllowApplication,
+				"autoJoin":         clan.AutoJoin,
+			}
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID))
+			ifMatch := map[string]string{"If-Match": fmt.Sprintf("\"%d\"", clan.Version)}
+			status, _, _ := DoRequestWithHeaders(a, "PUT", route, payload, ifMatch)
+			Expect(status).To(Equal(http.StatusOK))
+
+			payload["metadata"] = map[string]interface{}{"second": "update"}
+			status, body, headers := DoRequestWithHeaders(a, "PUT", route, payload, ifMatch)
+
+			Expect(status).To(Equal(http.StatusConflict))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal(fmt.Sprintf(
+				"Clan %s does not match the expected version. Current version is %d.", clan.PublicID, clan.Version+1,
+			)))
+			Expect(result["version"]).To(BeEquivalentTo(clan.Version + 1))
+			Expect(headers.Get("ETag")).To(Equal(fmt.Sprintf("\"%d\"", clan.Version+1)))
+
+			dbClan, err := models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.Metadata).To(Equal(map[string]interface{}{"first": "update"}))
+		})
+	})
+
+	Describe("Patch Clan Handler", func() {
+		It("Should merge the patch into the clan", func() {
+			game, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+			clan, err = models.UpdateClan(
+				testDb, game, clan.GameID, clan.PublicID, clan.Name, owner.PublicID,
+				map[string]interface{}{"x": "a", "stats": map[string]interface{}{"wins": 1, "losses": 2}},
+				clan.AllowApplication, clan.AutoJoin,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"ownerPublicID":     owner.PublicID,
+				"autoJoin":          !clan.AutoJoin,
+				"metadata":          map[string]interface{}{"x": nil, "stats": map[string]interface{}{"losses": 3}},
+				"incrementMetadata": map[string]interface{}{"score": 10},
+			}
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID))
+			status, body, headers := DoRequestWithHeaders(a, "PATCH", route, payload, map[string]string{
+				"If-Match": fmt.Sprintf("\"%d\"", clan.Version),
+			})
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["version"]).To(BeEquivalentTo(clan.Version + 1))
+			Expect(headers.Get("ETag")).To(Equal(fmt.Sprintf("\"%d\"", clan.Version+1)))
+
+			dbClan, err := models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.Name).To(Equal(clan.Name))
+			Expect(dbClan.AllowApplication).To(Equal(clan.AllowApplication))
+			Expect(dbClan.AutoJoin).To(Equal(!clan.AutoJoin))
+			Expect(dbClan.Metadata).To(Equal(map[string]interface{}{
+				"stats": map[string]interface{}{"wins": float64(1), "losses": float64(3)},
+				"score": float64(10),
+			}))
+		})
+
+		It("Should update Mongo once the clan patch is committed", func() {
+			mongo, err := GetTestMongo()
+			Expect(err).NotTo(HaveOccurred())
+			_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			col, sess := mongo.C(fmt.Sprintf("clans_%s", clan.GameID))
+			defer sess.Close()
+
+			payload := map[string]interface{}{
+				"ownerPublicID": owner.PublicID,
+				"name":          "patched name",
+			}
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID))
+			status, _, _ := DoRequestWithHeaders(a, "PATCH", route, payload, map[string]string{})
+			Expect(status).To(Equal(http.StatusOK))
+
+			Eventually(func() string {
+				var res *models.Clan
+				col.FindId(clan.PublicID).One(&res)
+				if res == nil {
+					return ""
+				}
+				return res.Name
+			}, 5).Should(Equal("patched name"))
+		})
+
+		It("Should not patch clan if If-Match does not match the current version", func() {
+			_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"ownerPublicID": owner.PublicID,
+				"name":          "new name",
+			}
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID))
+			status, body, _ := DoRequestWithHeaders(a, "PATCH", route, payload, map[string]string{
+				"If-Match": fmt.Sprintf("\"%d\"", clan.Version+1),
+			})
+
+			Expect(status).To(Equal(http.StatusConflict))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["version"]).To(BeEquivalentTo(clan.Version))
+			Expect(result["code"]).To(Equal(api.VersionConflictErrorCode))
+
+			dbClan, err := models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.Name).To(Equal(clan.Name))
+		})
+
+		It("Should not patch clan if player is not the clan owner", func() {
+			_, clan, _, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"ownerPublicID": players[0].PublicID,
+				"name":          "new name",
+			}
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID))
+			status, _ := PatchJSON(a, route, payload)
+			Expect(status).To(Equal(http.StatusForbidden))
+
+			dbClan, err := models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.Name).To(Equal(clan.Name))
+		})
+
+		It("Should not patch clan if ownerPublicID is missing", func() {
+			_, clan, _, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID))
+			status, body := PatchJSON(a, route, map[string]interface{}{"name": "new name"})
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["reason"]).To(Equal("ownerPublicID is required"))
+		})
 	})
 
 	Describe("List All Clans Handler", func() {
@@ -571,6 +851,8 @@ var _ = Describe("Clan API Handler", func() {
 			json.Unmarshal([]byte(body), &result)
 
 			Expect(result["success"]).To(BeTrue())
+			Expect(result["clans"]).To(HaveLen(10))
+			Expect(result).NotTo(HaveKey("nextCursor"))
 			for index, clanObj := range result["clans"].([]interface{}) {
 				clan := clanObj.(map[string]interface{}) // Can't be map[string]interface{}
 				Expect(clan["name"]).To(Equal(expectedClans[index].Name))
@@ -595,6 +877,67 @@ var _ = Describe("Clan API Handler", func() {
 			Expect(result["success"]).To(BeTrue())
 			Expect(len(result["clans"].([]interface{}))).To(Equal(0))
 		})
+
+		It("Should get clans one page at a time", func() {
+			player, expectedClans, err := models.GetTestClans(testDb, "", "", 3)
+			Expect(err).NotTo(HaveOccurred())
+			sort.Sort(models.ClanByName(expectedClans))
+
+			status, body := Get(a, GetGameRoute(player.GameID, "/clans?limit=2"))
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			clans := result["clans"].([]interface{})
+			Expect(clans).To(HaveLen(2))
+			Expect(clans[0].(map[string]interface{})["publicID"]).To(Equal(expectedClans[0].PublicID))
+			Expect(clans[1].(map[string]interface{})["publicID"]).To(Equal(expectedClans[1].PublicID))
+			nextCursor := result["nextCursor"].(string)
+			Expect(nextCursor).NotTo(BeEmpty())
+
+			status, body = Get(a, GetGameRoute(player.GameID, fmt.Sprintf("/clans?limit=2&cursor=%s", nextCursor)))
+
+			Expect(status).To(Equal(http.StatusOK))
+			result = map[string]interface{}{}
+			json.Unmarshal([]byte(body), &result)
+			clans = result["clans"].([]interface{})
+			Expect(clans).To(HaveLen(1))
+			Expect(clans[0].(map[string]interface{})["publicID"]).To(Equal(expectedClans[2].PublicID))
+			Expect(result["nextCursor"]).To(Equal(""))
+		})
+
+		It("Should filter clans by metadata and membership count", func() {
+			player, clans, err := models.GetTestClans(testDb, "", "", 3)
+			Expect(err).NotTo(HaveOccurred())
+			for i, clan := range clans {
+				clan.MembershipCount = i
+				clan.Metadata = map[string]interface{}{"region": "us"}
+				_, err = testDb.Update(clan)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			status, body := Get(a, GetGameRoute(player.GameID, "/clans?minMembershipCount=1&orderBy=membershipCount&order=desc&metadata.region=us"))
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			filtered := result["clans"].([]interface{})
+			Expect(filtered).To(HaveLen(2))
+			Expect(filtered[0].(map[string]interface{})["publicID"]).To(Equal(clans[2].PublicID))
+			Expect(filtered[1].(map[string]interface{})["publicID"]).To(Equal(clans[1].PublicID))
+		})
+
+		It("Should fail with 400 if query parameters are invalid", func() {
+			for _, query := range []string{"limit=0", "limit=100000", "orderBy=owner", "order=up", "autoJoin=maybe", "minMembershipCount=-1", "cursor=invalid"} {
+				status, body := Get(a, GetGameRoute("game-id", "/clans?"+query))
+
+				Expect(status).To(Equal(http.StatusBadRequest))
+				var result map[string]interface{}
+				json.Unmarshal([]byte(body), &result)
+				Expect(result["success"]).To(BeFalse())
+			}
+		})
 	})
 
 	Describe("Retrieve Clan Handler", func() {
@@ -839,6 +1182,34 @@ var _ = Describe("Clan API Handler", func() {
 			Expect(status).To(Equal(http.StatusOK))
 			validateRetrieveClanResponse(memberships, body, "<", maxPending, true)
 		})
+
+		It("Should return the clan version as ETag", func() {
+			_, clan, _, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID))
+			status, body, headers := DoRequestWithHeaders(a, "GET", route, nil, nil)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["version"]).To(BeEquivalentTo(clan.Version))
+			Expect(headers.Get("ETag")).To(Equal(fmt.Sprintf("\"%d\"", clan.Version)))
+
+			status, body, _ = DoRequestWithHeaders(a, "GET", route, nil, map[string]string{
+				"If-None-Match": headers.Get("ETag"),
+			})
+			Expect(status).To(Equal(http.StatusNotModified))
+			Expect(body).To(BeEmpty())
+
+			status, body, _ = DoRequestWithHeaders(a, "GET", route, nil, map[string]string{
+				"If-Match": fmt.Sprintf("\"%d\"", clan.Version+1),
+			})
+			Expect(status).To(Equal(http.StatusConflict))
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["version"]).To(BeEquivalentTo(clan.Version))
+		})
 	})
 
 	Describe("Retrieve Clan Members Handler", func() {
@@ -861,6 +1232,44 @@ var _ = Describe("Clan API Handler", func() {
 				Expect(result["members"].([]interface{})).To(ContainElement(p.PublicID))
 			}
 		})
+
+		It("Should get a page of clan members", func() {
+			_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 3, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s/members?limit=2", clan.PublicID)))
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["members"].([]interface{})).To(HaveLen(2))
+			Expect(result["owner"].(map[string]interface{})["publicID"]).To(Equal(owner.PublicID))
+			nextCursor := result["nextCursor"].(string)
+			Expect(nextCursor).NotTo(BeEmpty())
+
+			status, body = Get(a, GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s/members?limit=2&cursor=%s", clan.PublicID, nextCursor)))
+
+			Expect(status).To(Equal(http.StatusOK))
+			result = map[string]interface{}{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["members"].([]interface{})).To(HaveLen(1))
+			Expect(result["nextCursor"]).To(Equal(""))
+		})
+
+		It("Should fail with 400 if page parameters are invalid", func() {
+			_, clan, _, _, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, query := range []string{"limit=0", "orderBy=name", "order=up", "level=Overlord", "cursor=invalid"} {
+				status, body := Get(a, GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s/members?%s", clan.PublicID, query)))
+
+				Expect(status).To(Equal(http.StatusBadRequest))
+				var result map[string]interface{}
+				json.Unmarshal([]byte(body), &result)
+				Expect(result["success"]).To(BeFalse())
+			}
+		})
 	})
 
 	Describe("Retrieve Clans Handler", func() {
@@ -1133,6 +1542,124 @@ var _ = Describe("Clan API Handler", func() {
 				Expect(clan["allowApplication"]).To(Equal(expectedClan.AllowApplication))
 			}
 		})
+
+		It("Should limit the results to the page size", func() {
+			gameID := uuid.NewV4().String()
+			player, _, err := models.GetTestClans(
+				testDb, gameID, "clan-apisearch-clan", 10,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = testing.CreateClanNameTextIndexInMongo(GetTestMongo, gameID)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, GetGameRoute(player.GameID, "clans/search?term=APISEARCH&pageSize=3"))
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["clans"]).To(HaveLen(3))
+		})
+
+		It("Should fail if the page size is invalid", func() {
+			for _, pageSize := range []string{"0", "-1", "abc", "101"} {
+				status, body := Get(a, GetGameRoute("some-game", "clans/search?term=APISEARCH&pageSize="+pageSize))
+				Expect(status).To(Equal(http.StatusBadRequest), pageSize)
+				var result map[string]interface{}
+				json.Unmarshal([]byte(body), &result)
+				Expect(result["success"]).To(BeFalse())
+			}
+		})
+	})
+
+	Describe("Top Clans Handler", func() {
+		It("Should return the top clans and the rank of a clan", func() {
+			player, clans, err := models.GetTestClans(testDb, "", "clan-apitop-clan", 3)
+			Expect(err).NotTo(HaveOccurred())
+			game, err := models.GetGameByPublicID(testDb, player.GameID)
+			Expect(err).NotTo(HaveOccurred())
+			for i, clan := range clans {
+				_, err = models.UpdateClan(
+					testDb, game, clan.GameID, clan.PublicID, clan.Name, player.PublicID,
+					map[string]interface{}{"trophies": (i + 1) * 10}, clan.AllowApplication, clan.AutoJoin,
+				)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			status, body := PatchJSON(a, fmt.Sprintf("/games/%s", player.GameID), map[string]interface{}{
+				"metadata": map[string]interface{}{
+					models.ClanRankingDimensionsKey: map[string]interface{}{
+						"trophies": map[string]interface{}{"type": "clanMetadata", "field": "trophies"},
+					},
+				},
+			})
+			Expect(status).To(Equal(http.StatusOK), body)
+
+			// the clan scores are rebuilt by the ranking worker
+			route := GetGameRoute(player.GameID, fmt.Sprintf("/clans-top/trophies?limit=2&clanPublicID=%s", clans[0].PublicID))
+			var result map[string]interface{}
+			Eventually(func() int {
+				status, body = Get(a, route)
+				Expect(status).To(Equal(http.StatusOK), body)
+				result = map[string]interface{}{}
+				json.Unmarshal([]byte(body), &result)
+				return len(result["clans"].([]interface{}))
+			}, 5*time.Second).Should(Equal(2))
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["dimension"]).To(Equal("trophies"))
+
+			topClans := result["clans"].([]interface{})
+			first := topClans[0].(map[string]interface{})
+			Expect(first["rank"]).To(BeEquivalentTo(1))
+			Expect(first["score"]).To(BeEquivalentTo(30))
+			Expect(first["publicID"]).To(Equal(clans[2].PublicID))
+			Expect(first["name"]).To(Equal(clans[2].Name))
+			Expect(first["metadata"].(map[string]interface{})["trophies"]).To(BeEquivalentTo(30))
+			Expect(topClans[1].(map[string]interface{})["publicID"]).To(Equal(clans[1].PublicID))
+
+			clanRank := result["clan"].(map[string]interface{})
+			Expect(clanRank["publicID"]).To(Equal(clans[0].PublicID))
+			Expect(clanRank["rank"]).To(BeEquivalentTo(3))
+			Expect(clanRank["score"]).To(BeEquivalentTo(10))
+		})
+
+		It("Should fail if the dimension is not declared", func() {
+			_, clans, err := models.GetTestClans(testDb, "", "", 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, GetGameRoute(clans[0].GameID, "/clans-top/trophies"))
+			Expect(status).To(Equal(http.StatusNotFound))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal("ClanRankingDimension was not found with id: trophies"))
+		})
+
+		It("Should not shadow the routes of a clan whose public ID is top", func() {
+			_, clan, _, _, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "top")
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, GetGameRoute(clan.GameID, "/clans/top/members"))
+			Expect(status).To(Equal(http.StatusOK), body)
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["members"]).To(HaveLen(2))
+		})
+
+		It("Should fail if limit is above allowed", func() {
+			_, clans, err := models.GetTestClans(testDb, "", "", 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, GetGameRoute(clans[0].GameID, "/clans-top/trophies?limit=1001"))
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal("Limit above allowed (100)."))
+		})
 	})
 
 	Describe("Clan Hooks", func() {
@@ -1280,6 +1807,49 @@ var _ = Describe("Clan API Handler", func() {
 					}
 				})
 
+				It("Should call update clan hook if patched field in whitelist", func() {
+					hooks, err := models.GetHooksForRoutes(testDb, []string{
+						"http://localhost:52525/clanpatchedhookwhitelist",
+					}, models.ClanUpdatedHook)
+					Expect(err).NotTo(HaveOccurred())
+					responses := startRouteHandler([]string{"/clanpatchedhookwhitelist"}, 52525)
+
+					_, err = testDb.Exec(
+						"UPDATE games SET clan_metadata_fields_whitelist='score' WHERE public_id=$1",
+						hooks[0].GameID,
+					)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, hooks[0].GameID, "", true)
+					Expect(err).NotTo(HaveOccurred())
+					route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID))
+
+					status, _ := PatchJSON(a, route, map[string]interface{}{
+						"ownerPublicID": owner.PublicID,
+						"metadata":      map[string]interface{}{"other": "field"},
+					})
+					Expect(status).To(Equal(http.StatusOK))
+					Consistently(func() int {
+						return len(*responses)
+					}, 100*time.Millisecond, time.Millisecond).Should(Equal(0))
+
+					status, _ = PatchJSON(a, route, map[string]interface{}{
+						"ownerPublicID":     owner.PublicID,
+						"incrementMetadata": map[string]interface{}{"score": 3},
+					})
+					Expect(status).To(Equal(http.StatusOK))
+					Eventually(func() int {
+						return len(*responses)
+					}).Should(Equal(1))
+
+					hookRes := (*responses)[0]["payload"].(map[string]interface{})
+					rClan := hookRes["clan"].(map[string]interface{})
+					Expect(rClan["publicID"]).To(Equal(clan.PublicID))
+					clanMetadata := rClan["metadata"].(map[string]interface{})
+					Expect(clanMetadata["other"]).To(Equal("field"))
+					Expect(clanMetadata["score"]).To(BeEquivalentTo(3))
+				})
+
 				It("Should call update clan hook if field in whitelist is new", func() {
 					hooks, err := models.GetHooksForRoutes(testDb, []string{
 						"http://localhost:52525/clanupdatedhookwhitelist3",
@@ -1612,6 +2182,40 @@ var _ = Describe("Clan API Handler", func() {
 			Expect(rClan["newOwner"]).To(BeNil())
 		})
 
+		It("Should call delete clan hook", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52525/clandeleted",
+			}, models.ClanDeletedHook)
+			Expect(err).NotTo(HaveOccurred())
+			responses := startRouteHandler([]string{"/clandeleted"}, 52525)
+
+			_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, hooks[0].GameID, "", true)
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s?requestorPublicID=%s", clan.PublicID, owner.PublicID))
+			status, _ := Delete(a, route)
+			Expect(status).To(Equal(http.StatusOK))
+
+			Eventually(func() int {
+				return len(*responses)
+			}).Should(Equal(1))
+
+			rClan := (*responses)[0]["payload"].(map[string]interface{})
+			Expect(rClan["gameID"]).To(Equal(hooks[0].GameID))
+			Expect(rClan["type"].(float64)).To(BeEquivalentTo(models.ClanDeletedHook))
+			Expect(rClan["requestorPublicID"]).To(Equal(owner.PublicID))
+			Expect(rClan["deletedAt"]).NotTo(BeEquivalentTo(0))
+
+			clanDetails := rClan["clan"].(map[string]interface{})
+			Expect(clanDetails["publicID"]).To(Equal(clan.PublicID))
+			Expect(clanDetails["name"]).To(Equal(clan.Name))
+			Expect(clanDetails["membershipCount"]).To(BeEquivalentTo(0))
+
+			ownerDetails := rClan["owner"].(map[string]interface{})
+			Expect(ownerDetails["publicID"]).To(Equal(owner.PublicID))
+			Expect(ownerDetails["ownershipCount"]).To(BeEquivalentTo(0))
+		})
+
 		It("Should call transfer ownership hook", func() {
 			hooks, err := models.GetHooksForRoutes(testDb, []string{
 				"http://localhost:52525/clantransfer",
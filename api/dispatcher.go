@@ -15,9 +15,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -28,7 +30,9 @@ import (
 	ehttp "github.com/topfreegames/extensions/http"
 	"github.com/topfreegames/extensions/tracing"
 	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
 	"github.com/topfreegames/khan/queues"
+	"github.com/topfreegames/khan/util"
 	"github.com/uber-go/zap"
 	"github.com/valyala/fasttemplate"
 )
@@ -38,6 +42,11 @@ var httpClient *http.Client
 
 const hookInternalFailures = "hook_internal_failures"
 const requestingHookMilliseconds = "requesting_hook_milliseconds"
+const hookDeliveryRetries = "hook_delivery_retries"
+const hookDeadLetters = "hook_dead_letters"
+
+// deliverHookJobClass identifies jobs that deliver an event to a single hook
+const deliverHookJobClass = "Deliver"
 
 //Dispatcher is responsible for sending web hooks to workers
 type Dispatcher struct {
@@ -114,6 +123,46 @@ func (d *Dispatcher) DispatchHook(gameID string, eventType int, payload map[stri
 	})
 }
 
+//DispatchTestHook sends a synthetic event straight to the given hook. Hooks
+//subscribed to several event types get an event of the first of them
+func (d *Dispatcher) DispatchTestHook(hook *models.Hook) (string, error) {
+	eventType := hook.EventType
+	if eventTypes := hook.GetEventTypes(); len(eventTypes) > 0 {
+		eventType = eventTypes[0]
+	}
+
+	eventID := uuid.NewV4().String()
+	payload := map[string]interface{}{
+		"success":   true,
+		"test":      true,
+		"gameID":    hook.GameID,
+		"type":      eventType,
+		"id":        eventID,
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+
+	log.D(d.app.Logger, "Pushing test hook delivery into dispatch queue.", func(cm log.CM) {
+		cm.Write(
+			zap.String("source", "dispatcher"),
+			zap.String("operation", "DispatchTestHook"),
+			zap.String("hookID", hook.PublicID),
+		)
+	})
+
+	_, err := workers.Enqueue(queues.KhanQueue, deliverHookJobClass, map[string]interface{}{
+		"gameID":    hook.GameID,
+		"eventType": eventType,
+		"hookID":    hook.PublicID,
+		"url":       hook.URL,
+		"payload":   payload,
+		"attempt":   0,
+	})
+	if err != nil {
+		return "", err
+	}
+	return eventID, nil
+}
+
 func (d *Dispatcher) interpolateURL(sourceURL string, payload map[string]interface{}) (string, error) {
 	t, err := fasttemplate.NewTemplate(sourceURL, "{{", "}}")
 	if err != nil {
@@ -157,168 +206,375 @@ func basicAuth(username, password string) string {
 	return base64.StdEncoding.EncodeToString([]byte(auth))
 }
 
-// PerformDispatchHook dispatches web hooks for a specific game and event type
+// getRetryBackoff returns how long to wait before retrying a delivery that
+// already failed attempt times. The delay grows exponentially from initial up
+// to max and half of it is randomized so retries from many deliveries spread out.
+func getRetryBackoff(attempt int, initial, max time.Duration) time.Duration {
+	delay := initial
+	for i := 0; i < attempt && delay < max; i++ {
+		delay = delay * 2
+	}
+	if delay > max {
+		delay = max
+	}
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+func isRetryableStatus(statusCode int) bool {
+	return statusCode >= 500 ||
+		statusCode == http.StatusRequestTimeout ||
+		statusCode == http.StatusTooManyRequests
+}
+
+// PerformDispatchHook processes jobs from the webhooks queue. Event jobs are
+// fanned out into one delivery job per registered hook, and delivery jobs are
+// sent to their hook URL
 func (d *Dispatcher) PerformDispatchHook(m *workers.Msg) {
 	jtags := opentracing.Tags{"component": "go-workers"}
 	span := opentracing.StartSpan("PerformDispatchHook", jtags)
 	defer span.Finish()
 	defer tracing.LogPanic(span)
 	ctx := opentracing.ContextWithSpan(context.Background(), span)
-	app := d.app
-	statsd := app.DDStatsD
 
 	item := m.Args()
 	data := item.MustMap()
 
+	if m.Get("class").MustString() == deliverHookJobClass {
+		d.performDeliverHook(ctx, data)
+		return
+	}
+	d.performFanOutHook(ctx, data)
+}
+
+func (d *Dispatcher) performFanOutHook(ctx context.Context, data map[string]interface{}) {
+	app := d.app
+
 	gameID := data["gameID"].(string)
 	eventType, _ := data["eventType"].(json.Number).Int64()
 	payload := data["payload"].(map[string]interface{})
 
 	l := d.app.Logger.With(
 		zap.String("source", "dispatcher"),
-		zap.String("operation", "PerformDispatchHook"),
+		zap.String("operation", "performFanOutHook"),
 		zap.String("gameID", gameID),
 		zap.Int64("eventType", eventType),
 	)
 
-	hooks := app.GetHooks(ctx)
-	if _, ok := hooks[gameID]; !ok {
-		log.D(l, "No hooks found for game.")
+	hooks, err := app.GetGameHooks(ctx, gameID)
+	if err != nil {
+		app.addError()
+		log.E(l, "Could not retrieve game hooks.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
 		return
 	}
-	if _, ok := hooks[gameID][int(eventType)]; !ok {
+	if _, ok := hooks[int(eventType)]; !ok {
 		log.D(l, "No hooks found for event in specified game.")
 		return
 	}
 
-	for _, hook := range hooks[gameID][int(eventType)] {
-		log.D(app.Logger, "Sending webhook...", func(cm log.CM) {
-			cm.Write(zap.String("url", hook.URL))
-		})
-
-		requestURL, err := d.interpolateURL(hook.URL, payload)
+	for _, hook := range hooks[int(eventType)] {
+		matches, err := hook.Matches(payload)
 		if err != nil {
 			app.addError()
-			tags := []string{
-				"error:true",
-				fmt.Sprintf("url:%s", hook.URL),
-				fmt.Sprintf("game:%s", gameID),
-			}
-			statsd.Increment(hookInternalFailures, tags...)
-
-			log.E(l, "Could not interpolate webhook.", func(cm log.CM) {
-				cm.Write(
-					zap.String("requestURL", hook.URL),
-					zap.Error(err),
-				)
+			log.E(l, "Could not evaluate hook filter.", func(cm log.CM) {
+				cm.Write(zap.String("hookID", hook.PublicID), zap.Error(err))
+			})
+			continue
+		}
+		if !matches {
+			log.D(l, "Hook filter does not match event.", func(cm log.CM) {
+				cm.Write(zap.String("hookID", hook.PublicID))
 			})
 			continue
 		}
 
-		payloadJSON, _ := json.Marshal(payload)
-
-		log.D(l, "Requesting Hook URL...", func(cm log.CM) {
-			cm.Write(zap.String("requestURL", requestURL))
+		log.D(l, "Pushing hook delivery into dispatch queue.", func(cm log.CM) {
+			cm.Write(zap.String("hookID", hook.PublicID), zap.String("url", hook.URL))
 		})
 
-		req, err := http.NewRequest("POST", requestURL, bytes.NewBuffer(payloadJSON))
+		_, err := workers.Enqueue(queues.KhanQueue, deliverHookJobClass, map[string]interface{}{
+			"gameID":    gameID,
+			"eventType": eventType,
+			"hookID":    hook.PublicID,
+			"url":       hook.URL,
+			"payload":   payload,
+			"attempt":   0,
+		})
 		if err != nil {
-			log.E(l, "failed to create webhook request", func(cm log.CM) {
-				cm.Write(
-					zap.String("requestURL", hook.URL),
-					zap.Error(err),
-				)
+			app.addError()
+			log.E(l, "Could not enqueue hook delivery.", func(cm log.CM) {
+				cm.Write(zap.String("hookID", hook.PublicID), zap.Error(err))
 			})
-			continue
 		}
-		req.Header.Set("Content-Type", "application/json")
-		req = req.WithContext(ctx)
+	}
+}
 
-		parsedURL, err := url.Parse(requestURL)
-		if err != nil {
-			app.addError()
-			tags := []string{
-				"error:true",
-				fmt.Sprintf("url:%s", hook.URL),
-				fmt.Sprintf("game:%s", gameID),
-			}
-			statsd.Increment(hookInternalFailures, tags...)
+func (d *Dispatcher) performDeliverHook(ctx context.Context, data map[string]interface{}) {
+	app := d.app
+
+	gameID := data["gameID"].(string)
+	eventType, _ := data["eventType"].(json.Number).Int64()
+	hookID := data["hookID"].(string)
+	payload := data["payload"].(map[string]interface{})
+	attempt, _ := data["attempt"].(json.Number).Int64()
+
+	l := d.app.Logger.With(
+		zap.String("source", "dispatcher"),
+		zap.String("operation", "performDeliverHook"),
+		zap.String("gameID", gameID),
+		zap.Int64("eventType", eventType),
+		zap.String("hookID", hookID),
+		zap.Int64("attempt", attempt),
+	)
+
+	hook, err := d.getHook(ctx, gameID, int(eventType), hookID)
+	if err != nil {
+		d.retryOrDeadLetter(l, data, attempt, true, err)
+		return
+	}
+	if hook == nil {
+		log.W(l, "Hook was removed before it could be delivered.")
+		return
+	}
+	// the hook URL may have changed since the delivery was enqueued
+	data["url"] = hook.URL
+
+	delivery := &models.HookDelivery{
+		HookID:    hook.ID,
+		GameID:    gameID,
+		EventID:   fmt.Sprintf("%v", payload["id"]),
+		EventType: int(eventType),
+		Attempt:   int(attempt) + 1,
+	}
+	body := hook.RenderBody(payload)
+	retryable, err := d.requestHook(ctx, l, gameID, hook.URL, hook.Secret, payload, body, delivery)
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	if logErr := models.CreateHookDelivery(app.Db(ctx), delivery); logErr != nil {
+		log.E(l, "Could not store hook delivery.", func(cm log.CM) {
+			cm.Write(zap.Error(logErr))
+		})
+	}
+	if err != nil {
+		d.retryOrDeadLetter(l, data, attempt, retryable, err)
+	}
+}
+
+// retryOrDeadLetter schedules a failed delivery to be retried with backoff
+// or, if it is not retryable or ran out of retries, pushes it to the
+// dead-letter queue
+func (d *Dispatcher) retryOrDeadLetter(
+	l zap.Logger, data map[string]interface{},
+	attempt int64, retryable bool, err error,
+) {
+	app := d.app
+	statsd := app.DDStatsD
 
-			log.E(l, "Could not parse request requestURL.", func(cm log.CM) {
-				cm.Write(
-					zap.String(requestURL, hook.URL),
-					zap.Error(err),
-				)
+	tags := []string{
+		fmt.Sprintf("url:%s", data["url"]),
+		fmt.Sprintf("game:%s", data["gameID"]),
+	}
+	maxRetries := int64(app.Config.GetInt("webhooks.maxRetries"))
+	if retryable && attempt < maxRetries {
+		backoff := getRetryBackoff(
+			int(attempt),
+			time.Duration(app.Config.GetInt("webhooks.backoff.initial"))*time.Millisecond,
+			time.Duration(app.Config.GetInt("webhooks.backoff.max"))*time.Millisecond,
+		)
+		data["attempt"] = attempt + 1
+		_, enqueueErr := workers.EnqueueIn(queues.KhanQueue, deliverHookJobClass, backoff.Seconds(), data)
+		if enqueueErr == nil {
+			statsd.Increment(hookDeliveryRetries, tags...)
+			log.W(l, "Hook delivery failed. Retrying later.", func(cm log.CM) {
+				cm.Write(zap.Duration("backoff", backoff), zap.Error(err))
 			})
+			return
 		}
-		if parsedURL.User != nil {
-			username := parsedURL.User.Username()
-			password, setten := parsedURL.User.Password()
-			if setten == false {
-				password = ""
-			}
-			requestURL = fmt.Sprintf("%s://%s%s", parsedURL.Scheme, parsedURL.Host, parsedURL.RequestURI())
-			req.SetBasicAuth(username, password)
+		log.E(l, "Could not schedule hook delivery retry.", func(cm log.CM) {
+			cm.Write(zap.Error(enqueueErr))
+		})
+	}
+
+	data["reason"] = err.Error()
+	data["failedAt"] = time.Now().Format(time.RFC3339)
+	statsd.Increment(hookDeadLetters, tags...)
+	log.E(l, "Hook delivery failed permanently. Sending it to the dead-letter queue.", func(cm log.CM) {
+		cm.Write(zap.Error(err))
+	})
+	if _, dlqErr := workers.Enqueue(queues.KhanDeadLetterQueue, deliverHookJobClass, data); dlqErr != nil {
+		log.E(l, "Could not enqueue hook delivery into the dead-letter queue.", func(cm log.CM) {
+			cm.Write(zap.Error(dlqErr))
+		})
+	}
+}
+
+// getHook returns the hook with publicID registered for eventType in gameID,
+// or nil if it does not exist anymore
+func (d *Dispatcher) getHook(ctx context.Context, gameID string, eventType int, publicID string) (*models.Hook, error) {
+	hooks, err := d.app.GetGameHooks(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range hooks[eventType] {
+		if hook.PublicID == publicID {
+			return hook, nil
 		}
+	}
+	return nil, nil
+}
 
-		start := time.Now()
-		resp, err := d.httpClient.Do(req)
-		if err != nil {
-			app.addError()
-			tags := []string{
-				"error:true",
-				fmt.Sprintf("url:%s", hook.URL),
-				fmt.Sprintf("game:%s", gameID),
-				fmt.Sprintf("status:500"),
-			}
-			elapsed := time.Since(start)
-			statsd.Timing(requestingHookMilliseconds, elapsed, tags...)
-			statsd.Increment(hookInternalFailures, tags...)
+// requestHook posts body to hookURL interpolated with payload, signing it if
+// secret is not empty, and fills delivery with the response details. It
+// returns whether the delivery is worth retrying when it fails
+func (d *Dispatcher) requestHook(
+	ctx context.Context, l zap.Logger,
+	gameID, hookURL, secret string, payload map[string]interface{}, body interface{},
+	delivery *models.HookDelivery,
+) (bool, error) {
+	app := d.app
+	statsd := app.DDStatsD
 
-			log.E(l, "Could not request webhook.", func(cm log.CM) {
-				cm.Write(zap.String("requestURL", hook.URL), zap.Error(err))
-			})
-			continue
+	log.D(l, "Sending webhook...", func(cm log.CM) {
+		cm.Write(zap.String("url", hookURL))
+	})
+
+	requestURL, err := d.interpolateURL(hookURL, payload)
+	if err != nil {
+		app.addError()
+		tags := []string{
+			"error:true",
+			fmt.Sprintf("url:%s", hookURL),
+			fmt.Sprintf("game:%s", gameID),
 		}
-		defer resp.Body.Close()
+		statsd.Increment(hookInternalFailures, tags...)
 
-		body, respErr := ioutil.ReadAll(resp.Body)
-		if respErr != nil {
-			log.E(l, "failed to read webhook response", func(cm log.CM) {
-				cm.Write(zap.String("requestURL", hook.URL), zap.Error(respErr))
-			})
-			continue
+		log.E(l, "Could not interpolate webhook.", func(cm log.CM) {
+			cm.Write(
+				zap.String("requestURL", hookURL),
+				zap.Error(err),
+			)
+		})
+		return false, err
+	}
+
+	payloadJSON, _ := json.Marshal(body)
+
+	log.D(l, "Requesting Hook URL...", func(cm log.CM) {
+		cm.Write(zap.String("requestURL", requestURL))
+	})
+
+	req, err := http.NewRequest("POST", requestURL, bytes.NewBuffer(payloadJSON))
+	if err != nil {
+		log.E(l, "failed to create webhook request", func(cm log.CM) {
+			cm.Write(
+				zap.String("requestURL", hookURL),
+				zap.Error(err),
+			)
+		})
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(util.HookTimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(util.HookSignatureHeader, util.SignHookPayload(secret, timestamp, payloadJSON))
+	}
+	req = req.WithContext(ctx)
+
+	parsedURL, err := url.Parse(requestURL)
+	if err != nil {
+		app.addError()
+		tags := []string{
+			"error:true",
+			fmt.Sprintf("url:%s", hookURL),
+			fmt.Sprintf("game:%s", gameID),
+		}
+		statsd.Increment(hookInternalFailures, tags...)
+
+		log.E(l, "Could not parse request requestURL.", func(cm log.CM) {
+			cm.Write(
+				zap.String(requestURL, hookURL),
+				zap.Error(err),
+			)
+		})
+		return false, err
+	}
+	if parsedURL.User != nil {
+		username := parsedURL.User.Username()
+		password, setten := parsedURL.User.Password()
+		if setten == false {
+			password = ""
 		}
+		requestURL = fmt.Sprintf("%s://%s%s", parsedURL.Scheme, parsedURL.Host, parsedURL.RequestURI())
+		req.SetBasicAuth(username, password)
+	}
 
+	start := time.Now()
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		app.addError()
 		tags := []string{
-			fmt.Sprintf("error:%t", resp.StatusCode > 399),
-			fmt.Sprintf("url:%s", hook.URL),
+			"error:true",
+			fmt.Sprintf("url:%s", hookURL),
 			fmt.Sprintf("game:%s", gameID),
-			fmt.Sprintf("status:%d", resp.StatusCode),
+			fmt.Sprintf("status:500"),
 		}
 		elapsed := time.Since(start)
+		delivery.LatencyMs = int64(elapsed / time.Millisecond)
 		statsd.Timing(requestingHookMilliseconds, elapsed, tags...)
+		statsd.Increment(hookInternalFailures, tags...)
 
-		if resp.StatusCode > 399 {
-			app.addError()
-			log.E(l, "Could not request webhook.", func(cm log.CM) {
-				cm.Write(
-					zap.String("requestURL", hook.URL),
-					zap.Int("statusCode", resp.StatusCode),
-					zap.String("body", string(body)),
-				)
-			})
-			continue
-		}
+		log.E(l, "Could not request webhook.", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hookURL), zap.Error(err))
+		})
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	respBody, respErr := ioutil.ReadAll(resp.Body)
+	if respErr != nil {
+		log.E(l, "failed to read webhook response", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hookURL), zap.Error(respErr))
+		})
+		return true, respErr
+	}
 
-		log.D(l, "Webhook requested successfully.", func(cm log.CM) {
+	tags := []string{
+		fmt.Sprintf("error:%t", resp.StatusCode > 399),
+		fmt.Sprintf("url:%s", hookURL),
+		fmt.Sprintf("game:%s", gameID),
+		fmt.Sprintf("status:%d", resp.StatusCode),
+	}
+	elapsed := time.Since(start)
+	statsd.Timing(requestingHookMilliseconds, elapsed, tags...)
+	delivery.StatusCode = resp.StatusCode
+	delivery.LatencyMs = int64(elapsed / time.Millisecond)
+	delivery.ResponseBody = string(respBody)
+
+	if resp.StatusCode > 399 {
+		app.addError()
+		log.E(l, "Could not request webhook.", func(cm log.CM) {
 			cm.Write(
+				zap.String("requestURL", hookURL),
 				zap.Int("statusCode", resp.StatusCode),
-				zap.String("requestURL", requestURL),
-				zap.String("body", string(body)),
+				zap.String("body", string(respBody)),
 			)
 		})
+		return isRetryableStatus(resp.StatusCode), fmt.Errorf("webhook responded with status %d", resp.StatusCode)
 	}
 
-	return
+	log.D(l, "Webhook requested successfully.", func(cm log.CM) {
+		cm.Write(
+			zap.Int("statusCode", resp.StatusCode),
+			zap.String("requestURL", requestURL),
+			zap.String("body", string(respBody)),
+		)
+	})
+	return false, nil
 }
@@ -6,3 +6,131 @@
 // Copyright © 2016 Top Free Games <backend@tfgco.com>
 
 package api
+
+import (
+	"reflect"
+
+	"github.com/topfreegames/khan/models"
+)
+
+// Error codes are sent in the code field of failure payloads, so clients can tell failures apart
+// without parsing the reason. They are part of the API contract and must never change
+const (
+	InternalErrorCode                            = "INTERNAL_ERROR"
+	ModelNotFoundErrorCode                       = "MODEL_NOT_FOUND"
+	EmptyGameIDErrorCode                         = "EMPTY_GAME_ID"
+	ClanReachedMaxMembersErrorCode               = "CLAN_REACHED_MAX_MEMBERS"
+	PlayerReachedMaxClansErrorCode               = "PLAYER_REACHED_MAX_CLANS"
+	PlayerReachedMaxInvitesErrorCode             = "PLAYER_REACHED_MAX_INVITES"
+	PlayerCannotCreateMembershipErrorCode        = "PLAYER_CANNOT_CREATE_MEMBERSHIP"
+	PlayerCannotPerformMembershipActionErrorCode = "PLAYER_CANNOT_PERFORM_MEMBERSHIP_ACTION"
+	MembershipAlreadyProcessedErrorCode          = "MEMBERSHIP_ALREADY_PROCESSED"
+	CannotPromoteOrDemoteInvalidMemberErrorCode  = "CANNOT_PROMOTE_OR_DEMOTE_INVALID_MEMBER"
+	CannotPromoteOrDemoteMemberLevelErrorCode    = "CANNOT_PROMOTE_OR_DEMOTE_MEMBER_LEVEL"
+	InvalidMembershipActionErrorCode             = "INVALID_MEMBERSHIP_ACTION"
+	InvalidLevelForGameErrorCode                 = "INVALID_LEVEL_FOR_GAME"
+	ClanHasNoMembersErrorCode                    = "CLAN_HAS_NO_MEMBERS"
+	EmptySearchTermErrorCode                     = "EMPTY_SEARCH_TERM"
+	AlreadyHasValidMembershipErrorCode           = "ALREADY_HAS_VALID_MEMBERSHIP"
+	MustWaitMembershipCooldownErrorCode          = "MUST_WAIT_MEMBERSHIP_COOLDOWN"
+	PlayerBannedFromClanErrorCode                = "PLAYER_BANNED_FROM_CLAN"
+	CouldNotFindAllClansErrorCode                = "COULD_NOT_FIND_ALL_CLANS"
+	ForbiddenErrorCode                           = "FORBIDDEN"
+	InvalidHookFilterErrorCode                   = "INVALID_HOOK_FILTER"
+	InvalidHookEventsErrorCode                   = "INVALID_HOOK_EVENTS"
+	HookSecretConflictErrorCode                  = "HOOK_SECRET_CONFLICT"
+	HookOptionConflictErrorCode                  = "HOOK_OPTION_CONFLICT"
+	InvalidCursorErrorCode                       = "INVALID_CURSOR"
+	InvalidImportRecordErrorCode                 = "INVALID_IMPORT_RECORD"
+	VersionConflictErrorCode                     = "VERSION_CONFLICT"
+	InvalidMetadataIncrementErrorCode            = "INVALID_METADATA_INCREMENT"
+	GameArchivedErrorCode                        = "GAME_ARCHIVED"
+	GameNotArchivedErrorCode                     = "GAME_NOT_ARCHIVED"
+	InvalidAPIKeyRoleErrorCode                   = "INVALID_API_KEY_ROLE"
+)
+
+// errorCodes maps the model errors to the codes that identify them. Errors that are not listed
+// here are reported with InternalErrorCode
+var errorCodes = map[string]string{
+	"*models.ModelNotFoundError":                                 ModelNotFoundErrorCode,
+	"*models.EmptyGameIDError":                                   EmptyGameIDErrorCode,
+	"*models.ClanReachedMaxMembersError":                         ClanReachedMaxMembersErrorCode,
+	"*models.PlayerReachedMaxClansError":                         PlayerReachedMaxClansErrorCode,
+	"*models.PlayerReachedMaxInvitesError":                       PlayerReachedMaxInvitesErrorCode,
+	"*models.PlayerCannotCreateMembershipError":                  PlayerCannotCreateMembershipErrorCode,
+	"*models.PlayerCannotPerformMembershipActionError":           PlayerCannotPerformMembershipActionErrorCode,
+	"*models.CannotApproveOrDenyMembershipAlreadyProcessedError": MembershipAlreadyProcessedErrorCode,
+	"*models.CannotPromoteOrDemoteInvalidMemberError":            CannotPromoteOrDemoteInvalidMemberErrorCode,
+	"*models.CannotPromoteOrDemoteMemberLevelError":              CannotPromoteOrDemoteMemberLevelErrorCode,
+	"*models.InvalidMembershipActionError":                       InvalidMembershipActionErrorCode,
+	"*models.InvalidLevelForGameError":                           InvalidLevelForGameErrorCode,
+	"*models.ClanHasNoMembersError":                              ClanHasNoMembersErrorCode,
+	"*models.EmptySearchTermError":                               EmptySearchTermErrorCode,
+	"*models.AlreadyHasValidMembershipError":                     AlreadyHasValidMembershipErrorCode,
+	"*models.MustWaitMembershipCooldownError":                    MustWaitMembershipCooldownErrorCode,
+	"*models.PlayerBannedFromClanError":                          PlayerBannedFromClanErrorCode,
+	"*models.CouldNotFindAllClansError":                          CouldNotFindAllClansErrorCode,
+	"*models.ForbiddenError":                                     ForbiddenErrorCode,
+	"*models.InvalidHookFilterError":                             InvalidHookFilterErrorCode,
+	"*models.InvalidHookEventsError":                             InvalidHookEventsErrorCode,
+	"*models.HookSecretConflictError":                            HookSecretConflictErrorCode,
+	"*models.HookOptionConflictError":                            HookOptionConflictErrorCode,
+	"*models.InvalidCursorError":                                 InvalidCursorErrorCode,
+	"*models.InvalidImportRecordError":                           InvalidImportRecordErrorCode,
+	"*models.VersionConflictError":                               VersionConflictErrorCode,
+	"*models.InvalidMetadataIncrementError":                      InvalidMetadataIncrementErrorCode,
+	"*models.GameArchivedError":                                  GameArchivedErrorCode,
+	"*models.GameNotArchivedError":                               GameNotArchivedErrorCode,
+	"*models.InvalidAPIKeyRoleError":                             InvalidAPIKeyRoleErrorCode,
+}
+
+// getErrorCode returns the error code that identifies the specified error
+func getErrorCode(err error) string {
+	code, ok := errorCodes[reflect.TypeOf(err).String()]
+	if !ok {
+		code = InternalErrorCode
+	}
+	return code
+}
+
+// getErrorDetails returns the fields of the specified error that clients may act upon,
+// e.g. the number of seconds left before a membership can be created again
+func getErrorDetails(err error) map[string]interface{} {
+	switch e := err.(type) {
+	case *models.ModelNotFoundError:
+		return map[string]interface{}{"type": e.Type, "id": e.ID}
+	case *models.ClanReachedMaxMembersError:
+		return map[string]interface{}{"clanPublicID": e.ID}
+	case *models.PlayerReachedMaxClansError:
+		return map[string]interface{}{"playerPublicID": e.ID}
+	case *models.PlayerReachedMaxInvitesError:
+		return map[string]interface{}{"playerPublicID": e.ID}
+	case *models.CannotPromoteOrDemoteMemberLevelError:
+		return map[string]interface{}{"action": e.Action, "level": e.Level}
+	case *models.InvalidLevelForGameError:
+		return map[string]interface{}{"level": e.Level}
+	case *models.AlreadyHasValidMembershipError:
+		return map[string]interface{}{"playerPublicID": e.PlayerID, "clanPublicID": e.ClanID}
+	case *models.MustWaitMembershipCooldownError:
+		return map[string]interface{}{"playerPublicID": e.PlayerID, "clanPublicID": e.ClanID, "time": e.Time}
+	case *models.PlayerBannedFromClanError:
+		return map[string]interface{}{"playerPublicID": e.PlayerID, "clanPublicID": e.ClanID, "expiresAt": e.ExpiresAt}
+	case *models.CouldNotFindAllClansError:
+		return map[string]interface{}{"clanPublicIDs": e.ClanIDs}
+	case *models.HookSecretConflictError:
+		return map[string]interface{}{"hookPublicID": e.PublicID}
+	case *models.HookOptionConflictError:
+		return map[string]interface{}{"hookPublicID": e.PublicID, "option": e.Option}
+	case *models.VersionConflictError:
+		return map[string]interface{}{"type": e.Type, "id": e.ID, "version": e.Version}
+	case *models.InvalidMetadataIncrementError:
+		return map[string]interface{}{"fields": e.Fields}
+	case *models.GameArchivedError:
+		return map[string]interface{}{"gameID": e.PublicID}
+	case *models.GameNotArchivedError:
+		return map[string]interface{}{"gameID": e.PublicID}
+	case *models.InvalidAPIKeyRoleError:
+		return map[string]interface{}{"role": e.Role}
+	}
+	return nil
+}
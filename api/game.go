@@ -155,9 +155,22 @@ func UpdateGameHandler(app *App) func(c echo.Context) error {
 			return FailWith(status, err.Error(), c)
 		}
 
+		var previous, game *models.Game
+		err = WithSegment("game-retrieve", c, func() error {
+			previous, err = models.GetGameByPublicID(db, gameID)
+			if _, ok := err.(*models.ModelNotFoundError); ok {
+				// the game is created by the update
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return FailWith(500, err.Error(), c)
+		}
+
 		err = WithSegment("game-update", c, func() error {
 			log.D(l, "Updating game...")
-			_, err = models.UpdateGame(
+			game, err = models.UpdateGame(
 				db,
 				gameID,
 				payload.Name,
@@ -179,7 +192,10 @@ func UpdateGameHandler(app *App) func(c echo.Context) error {
 				optional.clanUpdateMetadataFieldsHookTriggerWhitelist,
 				optional.playerUpdateMetadataFieldsHookTriggerWhitelist,
 			)
-			return err
+			if err != nil {
+				return err
+			}
+			return app.RankingWorker.EnqueueClanScoresRebuild(previous, game)
 		})
 
 		if err != nil {
@@ -230,3 +246,246 @@ func UpdateGameHandler(app *App) func(c echo.Context) error {
 		return SucceedWith(map[string]interface{}{}, c)
 	}
 }
+
+// PatchGameHandler is the handler responsible for partially updating existing games
+func PatchGameHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "PatchGame")
+		start := time.Now()
+		gameID := c.Param("gameID")
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "gameHandler"),
+			zap.String("operation", "patchGame"),
+			zap.String("gameID", gameID),
+		)
+
+		var payload PatchGamePayload
+		err := WithSegment("payload", c, func() error {
+			return LoadJSONPayload(&payload, c, l)
+		})
+		if err != nil {
+			return FailWith(400, err.Error(), c)
+		}
+
+		var previous, game *models.Game
+		err = WithSegment("game-retrieve", c, func() error {
+			previous, err = models.GetGameByPublicID(db, gameID)
+			return err
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		err = WithSegment("game-patch", c, func() error {
+			log.D(l, "Patching game...")
+			game, err = models.PatchGame(db, gameID, &models.GamePatch{
+				Name:     payload.Name,
+				Metadata: getMetadataPatch(payload.Metadata, payload.IncrementMetadata),
+			})
+			if err != nil {
+				return err
+			}
+			return app.RankingWorker.EnqueueClanScoresRebuild(previous, game)
+		})
+		if err != nil {
+			log.W(l, "Game patch failed.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWithError(err, c)
+		}
+
+		successPayload := map[string]interface{}{
+			"publicID":                      game.PublicID,
+			"name":                          game.Name,
+			"membershipLevels":              game.MembershipLevels,
+			"metadata":                      game.Metadata,
+			"minLevelToAcceptApplication":   game.MinLevelToAcceptApplication,
+			"minLevelToCreateInvitation":    game.MinLevelToCreateInvitation,
+			"minLevelToRemoveMember":        game.MinLevelToRemoveMember,
+			"minLevelOffsetToRemoveMember":  game.MinLevelOffsetToRemoveMember,
+			"minLevelOffsetToPromoteMember": game.MinLevelOffsetToPromoteMember,
+			"minLevelOffsetToDemoteMember":  game.MinLevelOffsetToDemoteMember,
+			"maxMembers":                    game.MaxMembers,
+			"maxClansPerPlayer":             game.MaxClansPerPlayer,
+			"cooldownAfterDeny":             game.CooldownAfterDeny,
+			"cooldownAfterDelete":           game.CooldownAfterDelete,
+			"cooldownBeforeApply":           game.CooldownBeforeApply,
+			"cooldownBeforeInvite":          game.CooldownBeforeInvite,
+			"maxPendingInvites":             game.MaxPendingInvites,
+		}
+
+		err = WithSegment("hook-dispatch", c, func() error {
+			dErr := app.DispatchHooks(gameID, models.GameUpdatedHook, successPayload)
+			if dErr != nil {
+				log.E(l, "Game update hook dispatch failed.", func(cm log.CM) {
+					cm.Write(zap.Error(dErr))
+				})
+				return dErr
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(500, err.Error(), c)
+		}
+
+		log.I(l, "Game patched succesfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+
+		return SucceedWith(map[string]interface{}{
+			"name":     game.Name,
+			"metadata": game.Metadata,
+		}, c)
+	}
+}
+
+// ArchiveGameRoute is the route that archives and unarchives games, the only one allowed to change archived games
+const ArchiveGameRoute = "/games/:gameID/archive"
+
+// RetrieveGameHandler is the handler responsible for returning a game's details
+func RetrieveGameHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "RetrieveGame")
+		start := time.Now()
+		gameID := c.Param("gameID")
+
+		l := app.Logger.With(
+			zap.String("source", "gameHandler"),
+			zap.String("operation", "retrieveGame"),
+			zap.String("gameID", gameID),
+		)
+
+		db, err := app.GetCtxDB(c)
+		if err != nil {
+			log.E(l, "Failed to connect to DB.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWith(500, err.Error(), c)
+		}
+
+		var game *models.Game
+		err = WithSegment("game-retrieve", c, func() error {
+			log.D(l, "Retrieving game...")
+			game, err = models.GetGameByPublicID(db, gameID)
+			return err
+		})
+		if err != nil {
+			log.W(l, "Retrieve game failed.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWithError(err, c)
+		}
+
+		log.D(l, "Game retrieved successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(game.Serialize(), c)
+	}
+}
+
+// ListGamesHandler is the handler responsible for listing games
+func ListGamesHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "ListGames")
+		start := time.Now()
+
+		l := app.Logger.With(
+			zap.String("source", "gameHandler"),
+			zap.String("operation", "listGames"),
+		)
+
+		options, err := getListGamesOptions(app, c)
+		if err != nil {
+			return FailWith(400, err.Error(), c)
+		}
+
+		db, err := app.GetCtxDB(c)
+		if err != nil {
+			log.E(l, "Failed to connect to DB.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWith(500, err.Error(), c)
+		}
+
+		var games []*models.Game
+		var nextCursor string
+		err = WithSegment("game-list", c, func() error {
+			log.D(l, "Listing games...")
+			games, nextCursor, err = models.ListGames(db, options)
+			return err
+		})
+		if err != nil {
+			log.E(l, "List games failed.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWithError(err, c)
+		}
+
+		serializedGames := make([]map[string]interface{}, len(games))
+		for i, game := range games {
+			serializedGames[i] = game.Serialize()
+		}
+
+		log.D(l, "List games completed successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{
+			"games":      serializedGames,
+			"nextCursor": nextCursor,
+		}, c)
+	}
+}
+
+// ArchiveGameHandler is the handler responsible for archiving a game, disabling changes to its data,
+// or unarchiving it
+func ArchiveGameHandler(app *App, archive bool) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		route, operation := "ArchiveGame", "archiveGame"
+		if !archive {
+			route, operation = "UnarchiveGame", "unarchiveGame"
+		}
+		c.Set("route", route)
+		start := time.Now()
+		gameID := c.Param("gameID")
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "gameHandler"),
+			zap.String("operation", operation),
+			zap.String("gameID", gameID),
+		)
+
+		var game *models.Game
+		err := WithSegment("game-archive", c, func() error {
+			var err error
+			if archive {
+				log.D(l, "Archiving game...")
+				game, err = models.ArchiveGame(db, gameID)
+			} else {
+				log.D(l, "Unarchiving game...")
+				game, err = models.UnarchiveGame(db, gameID)
+			}
+			return err
+		})
+		if err != nil {
+			log.W(l, "Game archival failed.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWithError(err, c)
+		}
+		app.InvalidateArchivedGame(gameID)
+
+		log.I(l, "Game archival updated successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{
+			"publicID":   game.PublicID,
+			"archived":   game.ArchivedAt > 0,
+			"archivedAt": game.ArchivedAt,
+		}, c)
+	}
+}
@@ -9,8 +9,11 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
 
 	"github.com/labstack/echo"
+	"github.com/topfreegames/khan/models"
 	"github.com/uber-go/zap"
 )
 
@@ -90,3 +93,32 @@ func getCreateGamePayload(app *App, c echo.Context, l zap.Logger) (*CreateGamePa
 
 	return &payload, optional, nil
 }
+
+func getListGamesOptions(app *App, c echo.Context) (*models.ListGamesOptions, error) {
+	options := &models.ListGamesOptions{
+		Limit:  app.Config.GetInt(models.ListGamesDefaultLimitKey),
+		Cursor: c.QueryParam("cursor"),
+	}
+	maxLimit := app.Config.GetInt(models.ListGamesMaxLimitKey)
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		parsedLimit, err := strconv.ParseUint(limit, 10, 16)
+		if err != nil || parsedLimit == 0 {
+			return nil, fmt.Errorf("Limit must be a positive integer.")
+		}
+		if int(parsedLimit) > maxLimit {
+			return nil, fmt.Errorf("Limit above allowed (%v).", maxLimit)
+		}
+		options.Limit = int(parsedLimit)
+	}
+
+	if archived := c.QueryParam("archived"); archived != "" {
+		parsed, err := strconv.ParseBool(archived)
+		if err != nil {
+			return nil, fmt.Errorf("archived must be a boolean.")
+		}
+		options.Archived = &parsed
+	}
+
+	return options, nil
+}
@@ -293,6 +293,132 @@ var _ = Describe("Game API Handler", func() {
 		})
 	})
 
+	Describe("Patch Game Handler", func() {
+		It("Should merge the patch into the game metadata", func() {
+			game := models.GameFactory.MustCreateWithOption(map[string]interface{}{
+				"Metadata": map[string]interface{}{"x": "a", "y": map[string]interface{}{"z": "b", "w": "c"}},
+			}).(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"metadata": map[string]interface{}{"x": nil, "y": map[string]interface{}{"w": "d"}},
+			}
+			status, body := PatchJSON(a, fmt.Sprintf("/games/%s", game.PublicID), payload)
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			expected := map[string]interface{}{"y": map[string]interface{}{"z": "b", "w": "d"}}
+			Expect(result["metadata"]).To(Equal(expected))
+
+			dbGame, err := models.GetGameByPublicID(db, game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbGame.Name).To(Equal(game.Name))
+			Expect(dbGame.Metadata).To(Equal(expected))
+			Expect(dbGame.MaxMembers).To(Equal(game.MaxMembers))
+		})
+
+		It("Should return 404 if game does not exist", func() {
+			payload := map[string]interface{}{"name": "game"}
+			status, body := PatchJSON(a, fmt.Sprintf("/games/%s", uuid.NewV4().String()), payload)
+			Expect(status).To(Equal(http.StatusNotFound))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+		})
+	})
+
+	Describe("Retrieve Game Handler", func() {
+		It("Should return the game details", func() {
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, fmt.Sprintf("/games/%s", game.PublicID))
+			Expect(status).To(Equal(http.StatusOK), body)
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["publicID"]).To(Equal(game.PublicID))
+			Expect(result["name"]).To(Equal(game.Name))
+			Expect(result["maxMembers"]).To(BeEquivalentTo(game.MaxMembers))
+			Expect(result["archived"]).To(BeFalse())
+		})
+
+		It("Should return 404 if game does not exist", func() {
+			status, _ := Get(a, fmt.Sprintf("/games/%s", uuid.NewV4().String()))
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("List Games Handler", func() {
+		It("Should list games one page at a time", func() {
+			status, body := Get(a, "/games?limit=1")
+			Expect(status).To(Equal(http.StatusOK), body)
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["games"]).To(HaveLen(1))
+			Expect(result["nextCursor"]).NotTo(BeEmpty())
+
+			status, body = Get(a, fmt.Sprintf("/games?limit=1&cursor=%s", result["nextCursor"]))
+			Expect(status).To(Equal(http.StatusOK), body)
+			var next map[string]interface{}
+			json.Unmarshal([]byte(body), &next)
+			Expect(next["games"]).To(HaveLen(1))
+			Expect(next["games"]).NotTo(Equal(result["games"]))
+		})
+
+		It("Should fail if the query parameters are invalid", func() {
+			for _, query := range []string{"limit=0", "limit=1001", "archived=maybe", "cursor=invalid"} {
+				status, _ := Get(a, "/games?"+query)
+				Expect(status).To(Equal(http.StatusBadRequest), query)
+			}
+		})
+	})
+
+	Describe("Archive Game Handler", func() {
+		It("Should reject changes to an archived game until it is unarchived", func() {
+			_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := PostJSON(a, GetGameRoute(clan.GameID, "/archive"), map[string]interface{}{})
+			Expect(status).To(Equal(http.StatusOK), body)
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["archived"]).To(BeTrue())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID))
+			payload := map[string]interface{}{"ownerPublicID": owner.PublicID, "name": "archived clan"}
+			status, body = PatchJSON(a, route, payload)
+			Expect(status).To(Equal(http.StatusConflict), body)
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal(fmt.Sprintf("Game %s is archived and can't be changed.", clan.GameID)))
+
+			status, body = PatchJSON(a, fmt.Sprintf("/games/%s", clan.GameID), map[string]interface{}{"name": "game"})
+			Expect(status).To(Equal(http.StatusConflict), body)
+
+			status, body = Get(a, route)
+			Expect(status).To(Equal(http.StatusOK), body)
+
+			status, body = Delete(a, GetGameRoute(clan.GameID, "/archive"))
+			Expect(status).To(Equal(http.StatusOK), body)
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["archived"]).To(BeFalse())
+
+			status, body = PatchJSON(a, route, payload)
+			Expect(status).To(Equal(http.StatusOK), body)
+		})
+
+		It("Should return 404 if game does not exist", func() {
+			status, _ := PostJSON(a, GetGameRoute(uuid.NewV4().String(), "/archive"), map[string]interface{}{})
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
 	Describe("Game Hooks", func() {
 		Describe("Update Game Hook", func() {
 			It("Should call update game hook", func() {
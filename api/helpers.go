@@ -27,6 +27,15 @@ import (
 	"github.com/uber-go/zap"
 )
 
+// ETagHeader is the response header with the current version of clans and players
+const ETagHeader = "ETag"
+
+// IfMatchHeader is the request header with the versions a request is conditioned on
+const IfMatchHeader = "If-Match"
+
+// IfNoneMatchHeader is the request header with the versions a request must not match
+const IfNoneMatchHeader = "If-None-Match"
+
 //EasyJSONUnmarshaler describes a struct able to unmarshal json
 type EasyJSONUnmarshaler interface {
 	UnmarshalEasyJSON(l *jlexer.Lexer)
@@ -46,24 +55,47 @@ func FailWith(status int, message string, c echo.Context) error {
 	return c.JSON(status, payload)
 }
 
-// FailWithError fails with the specified error
+// FailWithError fails with the specified error, along with its error code and details
 func FailWithError(err error, c echo.Context) error {
+	payload := map[string]interface{}{
+		"success": false,
+		"reason":  err.Error(),
+		"code":    getErrorCode(err),
+	}
+	if details := getErrorDetails(err); details != nil {
+		payload["details"] = details
+	}
+	return c.JSON(getErrorStatus(err), payload)
+}
+
+// getErrorStatus returns the HTTP status that represents the specified error
+func getErrorStatus(err error) int {
 	t := reflect.TypeOf(err)
 	status, ok := map[string]int{
 		"*models.ModelNotFoundError":                                 http.StatusNotFound,
 		"*models.PlayerReachedMaxInvitesError":                       http.StatusBadRequest,
 		"*models.ForbiddenError":                                     http.StatusForbidden,
+		"*models.PlayerBannedFromClanError":                          http.StatusForbidden,
 		"*models.PlayerCannotPerformMembershipActionError":           http.StatusForbidden,
 		"*models.AlreadyHasValidMembershipError":                     http.StatusConflict,
 		"*models.CannotApproveOrDenyMembershipAlreadyProcessedError": http.StatusConflict,
 		"*models.CannotPromoteOrDemoteMemberLevelError":              http.StatusConflict,
+		"*models.InvalidHookFilterError":                             http.StatusBadRequest,
+		"*models.InvalidHookEventsError":                             http.StatusBadRequest,
+		"*models.HookSecretConflictError":                            http.StatusConflict,
+		"*models.HookOptionConflictError":                            http.StatusConflict,
+		"*models.InvalidCursorError":                                 http.StatusBadRequest,
+		"*models.VersionConflictError":                               http.StatusConflict,
+		"*models.InvalidMetadataIncrementError":                      http.StatusUnprocessableEntity,
+		"*models.GameArchivedError":                                  http.StatusConflict,
+		"*models.GameNotArchivedError":                               http.StatusConflict,
+		"*models.InvalidAPIKeyRoleError":                             http.StatusBadRequest,
 	}[t.String()]
 
 	if !ok {
 		status = http.StatusInternalServerError
 	}
-
-	return FailWith(status, err.Error(), c)
+	return status
 }
 
 // SucceedWith sends payload to user with status 200
@@ -81,6 +113,81 @@ func SucceedWith(payload map[string]interface{}, c echo.Context) error {
 	return f()
 }
 
+// FailWithVersionConflict fails with status 409 and the current version of the entity
+func FailWithVersionConflict(message string, version int64, c echo.Context) error {
+	if version > 0 {
+		c.Response().Header().Set(ETagHeader, getVersionETag(version))
+	}
+	payload := map[string]interface{}{
+		"success": false,
+		"reason":  message,
+		"code":    VersionConflictErrorCode,
+		"version": version,
+	}
+	return c.JSON(http.StatusConflict, payload)
+}
+
+// getVersionETag returns the entity tag of the given entity version
+func getVersionETag(version int64) string {
+	return fmt.Sprintf("\"%d\"", version)
+}
+
+// etagMatches returns whether an If-Match or If-None-Match header value matches the given entity version
+func etagMatches(header string, version int64) bool {
+	etag := getVersionETag(version)
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// getExpectedVersion evaluates the If-Match and If-None-Match headers of a write request against the
+// current version of the entity (0 if it does not exist). It returns the version the write must be
+// conditioned on (0 for unconditional writes) and false if the preconditions are not met
+func getExpectedVersion(c echo.Context, version int64) (int64, bool) {
+	var expected int64
+	if ifMatch := c.Request().Header().Get(IfMatchHeader); ifMatch != "" {
+		if version == 0 || !etagMatches(ifMatch, version) {
+			return 0, false
+		}
+		expected = version
+	}
+	if ifNoneMatch := c.Request().Header().Get(IfNoneMatchHeader); ifNoneMatch != "" {
+		if version != 0 && etagMatches(ifNoneMatch, version) {
+			return 0, false
+		}
+	}
+	return expected, true
+}
+
+// checkReadPreconditions evaluates the If-Match and If-None-Match headers of a read request against the
+// current version of the entity, setting its ETag in the response. It returns the status to respond
+// with if the preconditions are not met or 0 otherwise
+func checkReadPreconditions(c echo.Context, version int64) int {
+	c.Response().Header().Set(ETagHeader, getVersionETag(version))
+	if ifMatch := c.Request().Header().Get(IfMatchHeader); ifMatch != "" && !etagMatches(ifMatch, version) {
+		return http.StatusConflict
+	}
+	if ifNoneMatch := c.Request().Header().Get(IfNoneMatchHeader); ifNoneMatch != "" && etagMatches(ifNoneMatch, version) {
+		return http.StatusNotModified
+	}
+	return 0
+}
+
+// getMetadataPatch returns the metadata patch of a PATCH payload or nil if the metadata is not changed
+func getMetadataPatch(metadata map[string]interface{}, increment map[string]float64) *models.MetadataPatch {
+	if len(metadata) == 0 && len(increment) == 0 {
+		return nil
+	}
+	return &models.MetadataPatch{
+		Merge:     metadata,
+		Increment: increment,
+	}
+}
+
 //LoadJSONPayload loads the JSON payload to the given struct validating all fields are not null
 func LoadJSONPayload(payloadStruct interface{}, c echo.Context, l zap.Logger) error {
 	log.D(l, "Loading payload...")
@@ -157,6 +264,17 @@ func GetRequestJSON(payloadStruct interface{}, c echo.Context) error {
 	return nil
 }
 
+// hasPayloadField returns whether the JSON payload of the request has the field, which can't be
+// told from its zero value once the payload is loaded
+func hasPayloadField(c echo.Context, field string) bool {
+	var fields map[string]json.RawMessage
+	if err := GetRequestJSON(&fields, c); err != nil {
+		return false
+	}
+	_, ok := fields[field]
+	return ok
+}
+
 //GetTX returns new relic transaction
 func GetTX(c echo.Context) newrelic.Transaction {
 	tx := c.Get("txn")
@@ -185,3 +303,50 @@ func SetRetrieveClanHandlerConfigurationDefaults(config *viper.Viper) {
 	config.SetDefault(models.PendingApplicationsOrderKey, models.Newest)
 	config.SetDefault(models.PendingInvitesOrderKey, models.Newest)
 }
+
+// SetListClansHandlerConfigurationDefaults sets the default configs for ListClansHandler
+func SetListClansHandlerConfigurationDefaults(config *viper.Viper) {
+	config.SetDefault(models.ListClansDefaultLimitKey, 100)
+	config.SetDefault(models.ListClansMaxLimitKey, 1000)
+}
+
+// SetSearchClansHandlerConfigurationDefaults sets the default configs for SearchClansHandler
+func SetSearchClansHandlerConfigurationDefaults(config *viper.Viper) {
+	config.SetDefault(models.SearchClansPageSizeKey, 50)
+	config.SetDefault(models.SearchClansMaxPageSizeKey, 100)
+}
+
+// SetListGamesHandlerConfigurationDefaults sets the default configs for ListGamesHandler
+func SetListGamesHandlerConfigurationDefaults(config *viper.Viper) {
+	config.SetDefault(models.ListGamesDefaultLimitKey, 100)
+	config.SetDefault(models.ListGamesMaxLimitKey, 1000)
+}
+
+// SetRetrieveClanMembersHandlerConfigurationDefaults sets the default configs for RetrieveClanMembersHandler
+func SetRetrieveClanMembersHandlerConfigurationDefaults(config *viper.Viper) {
+	config.SetDefault(models.ClanMembersDefaultLimitKey, 100)
+	config.SetDefault(models.ClanMembersMaxLimitKey, 1000)
+}
+
+// SetTopClansHandlerConfigurationDefaults sets the default configs for TopClansHandler
+func SetTopClansHandlerConfigurationDefaults(config *viper.Viper) {
+	config.SetDefault(models.TopClansDefaultLimitKey, 10)
+	config.SetDefault(models.TopClansMaxLimitKey, 100)
+}
+
+// SetAuditHandlerConfigurationDefaults sets the default configs for AuditEntriesHandler
+func SetAuditHandlerConfigurationDefaults(config *viper.Viper) {
+	config.SetDefault(models.AuditEntriesDefaultLimitKey, 100)
+	config.SetDefault(models.AuditEntriesMaxLimitKey, 1000)
+}
+
+// SetBatchMembershipsHandlerConfigurationDefaults sets the default configs for BatchMembershipsHandler
+func SetBatchMembershipsHandlerConfigurationDefaults(config *viper.Viper) {
+	config.SetDefault(BatchMembershipsMaxOperationsKey, 100)
+}
+
+// SetRetrievePlayerMembershipsHandlerConfigurationDefaults sets the default configs for RetrievePlayerMembershipsHandler
+func SetRetrievePlayerMembershipsHandlerConfigurationDefaults(config *viper.Viper) {
+	config.SetDefault(models.PlayerMembershipsDefaultLimitKey, 20)
+	config.SetDefault(models.PlayerMembershipsMaxLimitKey, 100)
+}
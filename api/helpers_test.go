@@ -83,6 +83,17 @@ func GetTestAppWithBasicAuth(username, password string) *api.App {
 	return app
 }
 
+// GetTestAppWithAPIKeys returns a new Khan API application bound to 0.0.0.0:8888 for test with API key authentication
+func GetTestAppWithAPIKeys(username, password string) *api.App {
+	l := kt.NewMockLogger()
+	app := api.GetApp("0.0.0.0", 8888, "../config/test.yaml", true, l, false, true)
+	app.Config.Set("basicauth.username", username)
+	app.Config.Set("basicauth.password", password)
+	app.Config.Set(api.APIKeysEnabledKey, true)
+	app.Configure()
+	return app
+}
+
 //Get from server
 func Get(app *api.App, url string) (int, string) {
 	return doRequest(app, "GET", url, "")
@@ -116,6 +127,15 @@ func PutJSON(app *api.App, url string, body interface{}) (int, string) {
 	return Put(app, url, string(result))
 }
 
+//PatchJSON to server
+func PatchJSON(app *api.App, url string, body interface{}) (int, string) {
+	result, err := json.Marshal(body)
+	if err != nil {
+		return 510, "Failed to marshal specified body to JSON format"
+	}
+	return doRequest(app, "PATCH", url, string(result))
+}
+
 //Delete from server
 func Delete(app *api.App, url string) (int, string) {
 	return doRequest(app, "DELETE", url, "")
@@ -172,6 +192,33 @@ func doRequest(app *api.App, method, url, body string) (int, string) {
 	return PerformRequest(ts, req)
 }
 
+//DoRequestWithHeaders sends a request with the specified headers to server and returns the response headers as well
+func DoRequestWithHeaders(app *api.App, method, url string, body interface{}, headers map[string]string) (int, string, http.Header) {
+	var payload string
+	if body != nil {
+		result, err := json.Marshal(body)
+		Expect(err).NotTo(HaveOccurred())
+		payload = string(result)
+	}
+
+	ts := InitializeTestServer(app)
+	defer transport.CloseIdleConnections()
+	defer ts.Close()
+
+	req := GetRequest(app, ts, method, url, payload)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	res, err := client.Do(req)
+	Expect(err).NotTo(HaveOccurred())
+
+	b, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	Expect(err).NotTo(HaveOccurred())
+
+	return res.StatusCode, string(b), res.Header
+}
+
 // GetGameRoute returns a clan route for the given game id.
 func GetGameRoute(gameID, route string) string {
 	return fmt.Sprintf("/games/%s/%s", gameID, strings.TrimPrefix(route, "/"))
@@ -8,7 +8,9 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo"
@@ -55,7 +57,12 @@ func CreateHookHandler(app *App) func(c echo.Context) error {
 				db,
 				gameID,
 				payload.Type,
+				payload.Types,
+				payload.Wildcards,
 				payload.HookURL,
+				payload.Secret,
+				payload.Filter,
+				payload.BodyTemplate,
 			)
 
 			if err != nil {
@@ -68,9 +75,11 @@ func CreateHookHandler(app *App) func(c echo.Context) error {
 			return nil
 		})
 		if err != nil {
-			return FailWith(http.StatusInternalServerError, err.Error(), c)
+			return FailWithError(err, c)
 		}
 
+		app.InvalidateGameHooks(gameID)
+
 		log.I(l, "Created hook successfully.", func(cm log.CM) {
 			cm.Write(
 				zap.String("hookPublicID", hook.PublicID),
@@ -121,9 +130,297 @@ func RemoveHookHandler(app *App) func(c echo.Context) error {
 			return FailWith(http.StatusInternalServerError, err.Error(), c)
 		}
 
+		app.InvalidateGameHooks(gameID)
+
 		log.I(l, "Hook removed successfully.", func(cm log.CM) {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
 		return SucceedWith(map[string]interface{}{}, c)
 	}
 }
+
+// ListHooksHandler is the handler responsible for listing the hooks of a game
+func ListHooksHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "ListHooks")
+		start := time.Now()
+		gameID := c.Param("gameID")
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "ListHooksHandler"),
+			zap.String("operation", "listHooks"),
+			zap.String("gameID", gameID),
+		)
+
+		var hooks []*models.Hook
+		var err error
+		err = WithSegment("hook-list", c, func() error {
+			log.D(l, "Listing hooks...")
+			hooks, err = models.GetHooksByGameID(db, gameID)
+			if err != nil {
+				log.E(l, "Failed to list hooks.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		serializedHooks := make([]map[string]interface{}, len(hooks))
+		for i, hook := range hooks {
+			serializedHooks[i] = hook.Serialize()
+		}
+
+		log.I(l, "Listed hooks successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{
+			"hooks": serializedHooks,
+		}, c)
+	}
+}
+
+// RetrieveHookHandler is the handler responsible for returning details of a hook
+func RetrieveHookHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "RetrieveHook")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		publicID := c.Param("publicID")
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "RetrieveHookHandler"),
+			zap.String("operation", "retrieveHook"),
+			zap.String("gameID", gameID),
+			zap.String("hookPublicID", publicID),
+		)
+
+		var hook *models.Hook
+		var err error
+		err = WithSegment("hook-retrieve", c, func() error {
+			log.D(l, "Retrieving hook...")
+			hook, err = models.GetHookByPublicID(db, gameID, publicID)
+			if err != nil {
+				log.W(l, "Failed to retrieve hook.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		log.I(l, "Retrieved hook successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(hook.Serialize(), c)
+	}
+}
+
+// UpdateHookHandler is the handler responsible for updating existing hooks
+func UpdateHookHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "UpdateHook")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		publicID := c.Param("publicID")
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "UpdateHookHandler"),
+			zap.String("operation", "updateHook"),
+			zap.String("gameID", gameID),
+			zap.String("hookPublicID", publicID),
+		)
+
+		var payload HookPayload
+
+		err := WithSegment("payload", c, func() error {
+			if err := LoadJSONPayload(&payload, c, l); err != nil {
+				log.E(l, "Failed to parse json payload.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusBadRequest, err.Error(), c)
+		}
+
+		// the hook keeps its event subscriptions unless the payload sends new ones
+		if len(payload.Types) == 0 && len(payload.Wildcards) == 0 && !hasPayloadField(c, "type") {
+			current, err := models.GetHookByPublicID(db, gameID, publicID)
+			if err != nil {
+				log.W(l, "Failed to retrieve hook.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return FailWithError(err, c)
+			}
+			payload.Type = current.EventType
+			payload.Types = current.EventTypes
+			payload.Wildcards = current.EventWildcards
+		}
+
+		var hook *models.Hook
+		err = WithSegment("hook-update", c, func() error {
+			log.D(l, "Updating hook...")
+			hook, err = models.UpdateHook(
+				db,
+				gameID,
+				publicID,
+				payload.Type,
+				payload.Types,
+				payload.Wildcards,
+				payload.HookURL,
+				payload.Secret,
+				payload.Filter,
+				payload.BodyTemplate,
+			)
+
+			if err != nil {
+				log.E(l, "Failed to update the hook.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		app.InvalidateGameHooks(gameID)
+
+		log.I(l, "Updated hook successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(hook.Serialize(), c)
+	}
+}
+
+// TestHookHandler is the handler responsible for sending a synthetic event to a hook
+func TestHookHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "TestHook")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		publicID := c.Param("publicID")
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "TestHookHandler"),
+			zap.String("operation", "testHook"),
+			zap.String("gameID", gameID),
+			zap.String("hookPublicID", publicID),
+		)
+
+		hook, err := models.GetHookByPublicID(db, gameID, publicID)
+		if err != nil {
+			log.W(l, "Failed to retrieve hook.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWithError(err, c)
+		}
+
+		var eventID string
+		err = WithSegment("hook-test", c, func() error {
+			log.D(l, "Dispatching test event...")
+			eventID, err = app.Dispatcher.DispatchTestHook(hook)
+			if err != nil {
+				log.E(l, "Failed to dispatch test event.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		log.I(l, "Dispatched test event successfully.", func(cm log.CM) {
+			cm.Write(
+				zap.String("eventID", eventID),
+				zap.Duration("duration", time.Now().Sub(start)),
+			)
+		})
+		return SucceedWith(map[string]interface{}{
+			"eventID": eventID,
+		}, c)
+	}
+}
+
+// RetrieveHookDeliveriesHandler is the handler responsible for returning the latest deliveries of a hook
+func RetrieveHookDeliveriesHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "RetrieveHookDeliveries")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		publicID := c.Param("publicID")
+
+		maxLimit := app.Config.GetInt("webhooks.deliveryLog.maxLimit")
+		limit := maxLimit
+		if limitParam := c.QueryParam("limit"); limitParam != "" {
+			parsedLimit, err := strconv.ParseUint(limitParam, 10, 16)
+			if err != nil {
+				return FailWith(http.StatusBadRequest, err.Error(), c)
+			}
+			if int(parsedLimit) > maxLimit {
+				return FailWith(http.StatusBadRequest, fmt.Sprintf("Limit above allowed (%v).", maxLimit), c)
+			}
+			limit = int(parsedLimit)
+		}
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "RetrieveHookDeliveriesHandler"),
+			zap.String("operation", "retrieveHookDeliveries"),
+			zap.String("gameID", gameID),
+			zap.String("hookPublicID", publicID),
+		)
+
+		var deliveries []*models.HookDelivery
+		var err error
+		err = WithSegment("hook-deliveries", c, func() error {
+			log.D(l, "Retrieving hook deliveries...")
+			deliveries, err = models.GetHookDeliveries(db, gameID, publicID, limit)
+			if err != nil {
+				log.W(l, "Failed to retrieve hook deliveries.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		serializedDeliveries := make([]map[string]interface{}, len(deliveries))
+		for i, delivery := range deliveries {
+			serializedDeliveries[i] = delivery.Serialize()
+		}
+
+		log.I(l, "Retrieved hook deliveries successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{
+			"deliveries": serializedDeliveries,
+		}, c)
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
 	"github.com/topfreegames/khan/models"
 )
 
@@ -56,6 +57,169 @@ var _ = Describe("Hook API Handler", func() {
 			Expect(dbHook.URL).To(Equal(payload["hookURL"]))
 		})
 
+		It("Should create hook with secret without returning it", func() {
+			a := GetDefaultTestApp()
+			db := a.Db(nil)
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"type":    models.GameUpdatedHook,
+				"hookURL": "http://test/create-signed",
+				"secret":  "my-secret",
+			}
+			status, body := PostJSON(a, GetGameRoute(game.PublicID, "/hooks"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).NotTo(ContainSubstring("my-secret"))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			dbHook, err := models.GetHookByPublicID(
+				db, game.PublicID, result["publicID"].(string),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbHook.Secret).To(Equal("my-secret"))
+		})
+
+		It("Should not create an existing hook again with another secret", func() {
+			a := GetDefaultTestApp()
+			db := a.Db(nil)
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+			hook, err := models.CreateHook(db, game.PublicID, models.GameUpdatedHook, nil, nil, "http://test/resigned", "my-secret", "", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"type":    models.GameUpdatedHook,
+				"hookURL": "http://test/resigned",
+				"secret":  "other-secret",
+			}
+			status, body := PostJSON(a, GetGameRoute(game.PublicID, "/hooks"), payload)
+
+			Expect(status).To(Equal(http.StatusConflict))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["code"]).To(Equal("HOOK_SECRET_CONFLICT"))
+			Expect(result["details"]).To(Equal(map[string]interface{}{"hookPublicID": hook.PublicID}))
+		})
+
+		It("Should not create an existing hook again with another filter", func() {
+			a := GetDefaultTestApp()
+			db := a.Db(nil)
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+			hook, err := models.CreateHook(db, game.PublicID, models.GameUpdatedHook, nil, nil, "http://test/refiltered", "", `metadata.region == "eu"`, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"type":    models.GameUpdatedHook,
+				"hookURL": "http://test/refiltered",
+				"filter":  `metadata.region == "us"`,
+			}
+			status, body := PostJSON(a, GetGameRoute(game.PublicID, "/hooks"), payload)
+
+			Expect(status).To(Equal(http.StatusConflict))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["code"]).To(Equal("HOOK_OPTION_CONFLICT"))
+			Expect(result["details"]).To(Equal(map[string]interface{}{"hookPublicID": hook.PublicID, "option": "filter"}))
+		})
+
+		It("Should create hook with filter and body template", func() {
+			a := GetDefaultTestApp()
+			db := a.Db(nil)
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"type":         models.ClanCreatedHook,
+				"hookURL":      "http://test/create-filtered",
+				"filter":       `metadata.region == "eu"`,
+				"bodyTemplate": map[string]interface{}{"text": "{{name}} created"},
+			}
+			status, body := PostJSON(a, GetGameRoute(game.PublicID, "/hooks"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+
+			dbHook, err := models.GetHookByPublicID(
+				db, game.PublicID, result["publicID"].(string),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbHook.Filter).To(Equal(payload["filter"]))
+			Expect(dbHook.BodyTemplate).To(Equal(payload["bodyTemplate"]))
+		})
+
+		It("Should not create hook if filter is invalid", func() {
+			a := GetDefaultTestApp()
+			route := GetGameRoute("game-id", "/hooks")
+			status, body := PostJSON(a, route, map[string]interface{}{
+				"type":    models.ClanCreatedHook,
+				"hookURL": "http://test/create-filtered",
+				"filter":  `metadata.region == `,
+			})
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(ContainSubstring("invalid filter"))
+		})
+
+		It("Should create hook subscribed to event types and wildcards", func() {
+			a := GetDefaultTestApp()
+			db := a.Db(nil)
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"types":     []int{models.PlayerCreatedHook},
+				"wildcards": []string{"clan.*"},
+				"hookURL":   "http://test/create-multiple",
+			}
+			status, body := PostJSON(a, GetGameRoute(game.PublicID, "/hooks"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			dbHook, err := models.GetHookByPublicID(
+				db, game.PublicID, result["publicID"].(string),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbHook.EventType).To(Equal(models.MultipleEventsHook))
+			Expect(dbHook.GetEventTypes()).To(Equal([]int{
+				models.PlayerCreatedHook, models.ClanCreatedHook, models.ClanUpdatedHook,
+				models.ClanLeftHook, models.ClanOwnershipTransferredHook, models.ClanDeletedHook,
+			}))
+		})
+
+		It("Should not create hook if wildcard is invalid", func() {
+			a := GetDefaultTestApp()
+			payload := map[string]interface{}{
+				"wildcards": []string{"guild.*"},
+				"hookURL":   "http://test/create-invalid",
+			}
+			status, body := PostJSON(a, GetGameRoute("game-id", "/hooks"), payload)
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal("Hook events are invalid: guild.* is not a valid event wildcard"))
+		})
+
 		It("Should not create hook if missing parameters", func() {
 			a := GetDefaultTestApp()
 			route := GetGameRoute("game-id", "/hooks")
@@ -101,4 +265,196 @@ var _ = Describe("Hook API Handler", func() {
 			Expect(number == 0).To(BeTrue())
 		})
 	})
+
+	Describe("List Hooks Handler", func() {
+		It("Should list the hooks of a game without their secrets", func() {
+			a := GetDefaultTestApp()
+
+			hook, err := models.CreateHookFactory(testDb, "", models.GameUpdatedHook, "http://test/list")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = models.CreateHook(testDb, hook.GameID, models.ClanCreatedHook, nil, nil, "http://test/list2", "my-secret", "", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, GetGameRoute(hook.GameID, "/hooks"))
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).NotTo(ContainSubstring("my-secret"))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			hooks := result["hooks"].([]interface{})
+			Expect(hooks).To(HaveLen(2))
+			first := hooks[0].(map[string]interface{})
+			Expect(first["publicID"]).To(Equal(hook.PublicID))
+			Expect(first["hookURL"]).To(Equal("http://test/list"))
+			Expect(first["signed"]).To(BeFalse())
+			Expect(hooks[1].(map[string]interface{})["signed"]).To(BeTrue())
+		})
+	})
+
+	Describe("Retrieve Hook Handler", func() {
+		It("Should retrieve hook", func() {
+			a := GetDefaultTestApp()
+
+			hook, err := models.CreateHookFactory(testDb, "", models.GameUpdatedHook, "http://test/retrieve")
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, GetGameRoute(hook.GameID, fmt.Sprintf("/hooks/%s", hook.PublicID)))
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["publicID"]).To(Equal(hook.PublicID))
+			Expect(result["type"]).To(BeEquivalentTo(models.GameUpdatedHook))
+			Expect(result["hookURL"]).To(Equal("http://test/retrieve"))
+		})
+
+		It("Should return 404 if hook does not exist", func() {
+			a := GetDefaultTestApp()
+
+			status, body := Get(a, GetGameRoute("game-id", "/hooks/invalid-hook"))
+
+			Expect(status).To(Equal(http.StatusNotFound))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal("Hook was not found with id: invalid-hook"))
+		})
+	})
+
+	Describe("Update Hook Handler", func() {
+		It("Should update hook", func() {
+			a := GetDefaultTestApp()
+
+			hook, err := models.CreateHookFactory(testDb, "", models.GameUpdatedHook, "http://test/update")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"type":    models.ClanUpdatedHook,
+				"hookURL": "http://test/updated",
+			}
+			status, body := PutJSON(a, GetGameRoute(hook.GameID, fmt.Sprintf("/hooks/%s", hook.PublicID)), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			dbHook, err := models.GetHookByPublicID(testDb, hook.GameID, hook.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbHook.EventType).To(Equal(models.ClanUpdatedHook))
+			Expect(dbHook.URL).To(Equal("http://test/updated"))
+		})
+
+		It("Should keep the event type if it is not sent", func() {
+			a := GetDefaultTestApp()
+
+			hook, err := models.CreateHookFactory(testDb, "", models.ClanCreatedHook, "http://test/update")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"hookURL": "http://test/updated",
+			}
+			status, _ := PutJSON(a, GetGameRoute(hook.GameID, fmt.Sprintf("/hooks/%s", hook.PublicID)), payload)
+			Expect(status).To(Equal(http.StatusOK))
+
+			dbHook, err := models.GetHookByPublicID(testDb, hook.GameID, hook.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbHook.EventType).To(Equal(models.ClanCreatedHook))
+			Expect(dbHook.URL).To(Equal("http://test/updated"))
+		})
+
+		It("Should keep the event types and wildcards if they are not sent", func() {
+			a := GetDefaultTestApp()
+			gameID := uuid.NewV4().String()
+
+			hook, err := models.CreateHook(
+				testDb, gameID, models.GameUpdatedHook, []int{models.ClanCreatedHook}, []string{"player.*"},
+				"http://test/update-multiple", "", "", nil,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"hookURL": "http://test/updated-multiple",
+			}
+			status, _ := PutJSON(a, GetGameRoute(gameID, fmt.Sprintf("/hooks/%s", hook.PublicID)), payload)
+			Expect(status).To(Equal(http.StatusOK))
+
+			dbHook, err := models.GetHookByPublicID(testDb, gameID, hook.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbHook.EventType).To(Equal(models.MultipleEventsHook))
+			Expect(dbHook.EventTypes).To(Equal([]int{models.ClanCreatedHook}))
+			Expect(dbHook.EventWildcards).To(Equal([]string{"player.*"}))
+		})
+
+		It("Should return 404 if hook does not exist", func() {
+			a := GetDefaultTestApp()
+
+			payload := map[string]interface{}{
+				"type":    models.ClanUpdatedHook,
+				"hookURL": "http://test/updated",
+			}
+			status, _ := PutJSON(a, GetGameRoute("game-id", "/hooks/invalid-hook"), payload)
+
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Test Hook Handler", func() {
+		It("Should send a test event and log its delivery", func() {
+			hook, err := models.CreateHookFactory(testDb, "", models.GameUpdatedHook, "http://localhost:52525/testfire")
+			Expect(err).NotTo(HaveOccurred())
+			responses := startRouteHandler([]string{"/testfire"}, 52525)
+
+			a := GetDefaultTestApp()
+			a.NonblockingStartWorkers()
+
+			status, body := PostJSON(a, GetGameRoute(hook.GameID, fmt.Sprintf("/hooks/%s/test", hook.PublicID)), map[string]interface{}{})
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["eventID"]).NotTo(BeEquivalentTo(""))
+
+			Eventually(func() int {
+				return len(*responses)
+			}).Should(Equal(1))
+			payload := (*responses)[0]["payload"].(map[string]interface{})
+			Expect(payload["test"]).To(BeTrue())
+			Expect(payload["id"]).To(Equal(result["eventID"]))
+
+			var deliveries []interface{}
+			Eventually(func() int {
+				_, body := Get(a, GetGameRoute(hook.GameID, fmt.Sprintf("/hooks/%s/deliveries", hook.PublicID)))
+				var deliveriesResult map[string]interface{}
+				json.Unmarshal([]byte(body), &deliveriesResult)
+				deliveries = deliveriesResult["deliveries"].([]interface{})
+				return len(deliveries)
+			}).Should(Equal(1))
+
+			delivery := deliveries[0].(map[string]interface{})
+			Expect(delivery["eventID"]).To(Equal(result["eventID"]))
+			Expect(delivery["statusCode"]).To(BeEquivalentTo(http.StatusOK))
+			Expect(delivery["attempt"]).To(BeEquivalentTo(1))
+		})
+	})
+
+	Describe("Retrieve Hook Deliveries Handler", func() {
+		It("Should fail if limit is above allowed", func() {
+			a := GetDefaultTestApp()
+
+			hook, err := models.CreateHookFactory(testDb, "", models.GameUpdatedHook, "http://test/deliveries")
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, GetGameRoute(hook.GameID, fmt.Sprintf("/hooks/%s/deliveries?limit=1000", hook.PublicID)))
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["reason"]).To(Equal("Limit above allowed (100)."))
+		})
+	})
 })
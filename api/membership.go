@@ -8,6 +8,8 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -725,9 +727,15 @@ func PromoteOrDemoteMembershipHandler(app *App, action string) func(c echo.Conte
 
 		err = WithSegment("membership-promote-demote", c, func() error {
 			err = WithSegment("membership-promote-demote-query", c, func() error {
+				// the membership and its audit entry are written in the same transaction
+				tx, err := app.BeginTrans(c.StdContext(), l)
+				if err != nil {
+					return err
+				}
+
 				log.D(l, "Promoting/Demoting member...")
 				membership, err = models.PromoteOrDemoteMember(
-					db,
+					tx,
 					game,
 					game.PublicID,
 					payload.PlayerPublicID,
@@ -737,13 +745,16 @@ func PromoteOrDemoteMembershipHandler(app *App, action string) func(c echo.Conte
 				)
 
 				if err != nil {
-					log.E(l, "Member promotion/demotion failed.", func(cm log.CM) {
-						cm.Write(zap.Error(err))
-					})
+					txErr := app.Rollback(tx, "Member promotion/demotion failed", c, l, err)
+					if txErr == nil {
+						log.E(l, "Member promotion/demotion failed.", func(cm log.CM) {
+							cm.Write(zap.Error(err))
+						})
+					}
 					return err
 				}
 				log.D(l, "Member promoted/demoted successful.")
-				return nil
+				return app.Commit(tx, "Member promotion/demotion", c, l)
 			})
 
 			if err != nil {
@@ -801,3 +812,319 @@ func PromoteOrDemoteMembershipHandler(app *App, action string) func(c echo.Conte
 		}, c)
 	}
 }
+
+// BanOrUnbanMembershipHandler is the handler responsible for banning or unbanning a player from a clan
+func BanOrUnbanMembershipHandler(app *App, action string) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		var err error
+		var status int
+		var duration int
+		var payload *BasePayloadWithRequestorAndPlayerPublicIDs
+		var game *models.Game
+		var membership *models.Membership
+		var tx interfaces.Transaction
+
+		c.Set("route", "BanOrUnbanMember")
+		start := time.Now()
+		clanPublicID := c.Param("clanPublicID")
+
+		l := app.Logger.With(
+			zap.String("source", "membershipHandler"),
+			zap.String("operation", "banOrUnbanMembership"),
+			zap.String("clanPublicID", clanPublicID),
+			zap.String("action", action),
+		)
+
+		err = WithSegment("payload", c, func() error {
+			payload, game, status, err = getPayloadAndGame(app, c, l)
+			if err != nil {
+				return err
+			}
+			if action == "ban" {
+				duration, err = getBanDuration(c)
+				if err != nil {
+					status = http.StatusBadRequest
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(status, err.Error(), c)
+		}
+
+		l = l.With(
+			zap.String("gameID", game.PublicID),
+			zap.String("playerPublicID", payload.PlayerPublicID),
+			zap.String("requestorPublicID", payload.RequestorPublicID),
+		)
+
+		rb := func(err error) error {
+			txErr := app.Rollback(tx, "Banning/Unbanning member failed", c, l, err)
+			if txErr != nil {
+				return txErr
+			}
+
+			return nil
+		}
+
+		err = WithSegment("membership-ban-unban", c, func() error {
+			err = WithSegment("tx-begin", c, func() error {
+				tx, err = app.BeginTrans(c.StdContext(), l)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			log.D(l, "DB Tx began successfully.")
+
+			log.D(l, "Banning/Unbanning member...")
+			if action == "ban" {
+				membership, err = models.BanMember(
+					tx,
+					game,
+					game.PublicID,
+					payload.PlayerPublicID,
+					clanPublicID,
+					payload.RequestorPublicID,
+					duration,
+				)
+			} else {
+				membership, err = models.UnbanMember(
+					tx,
+					game,
+					game.PublicID,
+					payload.PlayerPublicID,
+					clanPublicID,
+					payload.RequestorPublicID,
+				)
+			}
+
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Member ban/unban failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		err = WithSegment("hook-dispatch", c, func() error {
+			hookType := models.MembershipBannedHook
+			if action == "unban" {
+				hookType = models.MembershipUnbannedHook
+			}
+
+			err = dispatchMembershipBanHook(app, tx, hookType, membership, payload.RequestorPublicID)
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Member ban/unban hook dispatch failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		err = app.Commit(tx, "Member ban/unban", c, l)
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		log.I(l, "Member banned/unbanned successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+
+		res := map[string]interface{}{}
+		if action == "ban" {
+			res["banExpiresAt"] = membership.BanExpiresAt
+		}
+		return SucceedWith(res, c)
+	}
+}
+
+// BatchMembershipsHandler is the handler responsible for running several membership operations in a single transaction
+func BatchMembershipsHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		var payload BatchMembershipPayload
+		var game *models.Game
+		var err error
+		var tx interfaces.Transaction
+
+		c.Set("route", "BatchMemberships")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		clanPublicID := c.Param("clanPublicID")
+
+		l := app.Logger.With(
+			zap.String("source", "membershipHandler"),
+			zap.String("operation", "batchMemberships"),
+			zap.String("gameID", gameID),
+			zap.String("clanPublicID", clanPublicID),
+		)
+
+		err = WithSegment("payload", c, func() error {
+			if err = LoadJSONPayload(&payload, c, l); err != nil {
+				return err
+			}
+			maxOperations := app.Config.GetInt(BatchMembershipsMaxOperationsKey)
+			if len(payload.Operations) > maxOperations {
+				return fmt.Errorf("A batch can have at most %d operations", maxOperations)
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusBadRequest, err.Error(), c)
+		}
+
+		mode := payload.Mode
+		if mode == "" {
+			mode = BatchModeAtomic
+		}
+		l = l.With(
+			zap.String("mode", mode),
+			zap.Int("operations", len(payload.Operations)),
+		)
+
+		err = WithSegment("game-retrieve", c, func() error {
+			game, err = app.GetGame(c.StdContext(), gameID)
+			if err != nil {
+				log.W(l, "Could not find game.")
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusNotFound, err.Error(), c)
+		}
+
+		err = WithSegment("tx-begin", c, func() error {
+			tx, err = app.BeginTrans(c.StdContext(), l)
+			return err
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		results := []map[string]interface{}{}
+		hooks := []func(models.DB) error{}
+		failed := 0
+		for i := range payload.Operations {
+			op := &payload.Operations[i]
+			var result map[string]interface{}
+			var hook func(models.DB) error
+
+			err = WithSegment("membership-batch-operation", c, func() error {
+				if mode == BatchModeBestEffort {
+					if _, spErr := tx.Exec("SAVEPOINT batch_membership_operation"); spErr != nil {
+						return spErr
+					}
+				}
+				var opErr error
+				result, hook, opErr = runBatchMembershipOperation(app, tx, game, clanPublicID, op)
+				if mode == BatchModeBestEffort {
+					query := "RELEASE SAVEPOINT batch_membership_operation"
+					if opErr != nil {
+						query = "ROLLBACK TO SAVEPOINT batch_membership_operation"
+					}
+					if _, spErr := tx.Exec(query); spErr != nil {
+						return spErr
+					}
+				}
+				if opErr != nil {
+					result = map[string]interface{}{
+						"success": false,
+						"status":  getErrorStatus(opErr),
+						"code":    getErrorCode(opErr),
+						"reason":  opErr.Error(),
+					}
+					if details := getErrorDetails(opErr); details != nil {
+						result["details"] = details
+					}
+					return nil
+				}
+				result["success"] = true
+				hooks = append(hooks, hook)
+				return nil
+			})
+			if err != nil {
+				txErr := app.Rollback(tx, "Membership batch failed", c, l, err)
+				if txErr != nil {
+					return FailWith(http.StatusInternalServerError, txErr.Error(), c)
+				}
+				log.E(l, "Membership batch failed.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return FailWith(http.StatusInternalServerError, err.Error(), c)
+			}
+
+			result["index"] = i
+			result["action"] = op.Action
+			result["playerPublicID"] = op.PlayerPublicID
+			results = append(results, result)
+
+			if !result["success"].(bool) {
+				failed++
+				if mode == BatchModeAtomic {
+					reason := result["reason"].(string)
+					txErr := app.Rollback(tx, "Membership batch operation failed", c, l, errors.New(reason))
+					if txErr != nil {
+						return FailWith(http.StatusInternalServerError, txErr.Error(), c)
+					}
+					log.W(l, "Membership batch operation failed, batch rolled back.", func(cm log.CM) {
+						cm.Write(zap.Int("index", i), zap.String("reason", reason))
+					})
+					return c.JSON(result["status"].(int), map[string]interface{}{
+						"success":     false,
+						"reason":      reason,
+						"code":        result["code"],
+						"failedIndex": i,
+						"results":     results,
+					})
+				}
+			}
+		}
+
+		err = app.Commit(tx, "Membership batch", c, l)
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		// hooks are only dispatched after commit, so rolled back operations never notify
+		WithSegment("hook-dispatch", c, func() error {
+			db := app.Db(c.StdContext())
+			for _, hook := range hooks {
+				if hErr := hook(db); hErr != nil {
+					log.E(l, "Membership batch hook dispatch failed.", func(cm log.CM) {
+						cm.Write(zap.Error(hErr))
+					})
+				}
+			}
+			return nil
+		})
+
+		log.I(l, "Membership batch processed successfully.", func(cm log.CM) {
+			cm.Write(
+				zap.Int("failed", failed),
+				zap.Duration("duration", time.Now().Sub(start)),
+			)
+		})
+
+		return SucceedWith(map[string]interface{}{
+			"results":   results,
+			"succeeded": len(results) - failed,
+			"failed":    failed,
+		}, c)
+	}
+}
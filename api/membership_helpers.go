@@ -9,6 +9,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/labstack/echo"
 	"github.com/topfreegames/khan/log"
@@ -177,6 +178,68 @@ func dispatchApproveDenyMembershipHook(app *App, db models.DB, hookType int, gam
 	return nil
 }
 
+func getBanDuration(c echo.Context) (int, error) {
+	data, err := GetRequestBody(c)
+	if err != nil {
+		return 0, err
+	}
+
+	var jsonPayload map[string]interface{}
+	err = json.Unmarshal([]byte(data), &jsonPayload)
+	if err != nil {
+		return 0, err
+	}
+
+	duration := 0
+	if val, ok := jsonPayload["duration"]; ok {
+		floatDuration, ok := val.(float64)
+		if !ok || floatDuration < 0 {
+			return 0, fmt.Errorf("duration must be a positive number of seconds")
+		}
+		duration = int(floatDuration)
+	}
+	return duration, nil
+}
+
+func dispatchMembershipBanHook(app *App, db models.DB, hookType int, membership *models.Membership, requestorPublicID string) error {
+	clan, err := models.GetClanByID(db, membership.ClanID)
+	if err != nil {
+		return err
+	}
+
+	player, err := models.GetPlayerByID(db, membership.PlayerID)
+	if err != nil {
+		return err
+	}
+
+	requestor, err := models.GetPlayerByPublicID(db, membership.GameID, requestorPublicID)
+	if err != nil {
+		return err
+	}
+
+	clanJSON := clan.Serialize()
+	delete(clanJSON, "gameID")
+
+	playerJSON := player.Serialize()
+	playerJSON["membershipLevel"] = membership.Level
+	delete(playerJSON, "gameID")
+
+	requestorJSON := requestor.Serialize()
+	delete(requestorJSON, "gameID")
+
+	result := map[string]interface{}{
+		"gameID":    membership.GameID,
+		"clan":      clanJSON,
+		"player":    playerJSON,
+		"requestor": requestorJSON,
+	}
+
+	if hookType == models.MembershipBannedHook {
+		result["banExpiresAt"] = membership.BanExpiresAt
+	}
+	return app.DispatchHooks(membership.GameID, hookType, result)
+}
+
 func getPayloadAndGame(app *App, c echo.Context, l zap.Logger) (*BasePayloadWithRequestorAndPlayerPublicIDs, *models.Game, int, error) {
 	gameID := c.Param("gameID")
 
@@ -193,3 +256,108 @@ func getPayloadAndGame(app *App, c echo.Context, l zap.Logger) (*BasePayloadWith
 
 	return &payload, game, 200, nil
 }
+
+// Batch membership modes
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "bestEffort"
+)
+
+// BatchMembershipsMaxOperationsKey is the config key for the max number of operations in a batch
+const BatchMembershipsMaxOperationsKey = "batchMemberships.maxOperations"
+
+var batchMembershipActions = map[string]bool{
+	"approve": true,
+	"deny":    true,
+	"invite":  true,
+	"kick":    true,
+	"promote": true,
+	"demote":  true,
+}
+
+// runBatchMembershipOperation runs a single batch operation and returns its result fields
+// and a function that dispatches its hook once the batch is committed
+func runBatchMembershipOperation(
+	app *App, db models.DB, game *models.Game, clanPublicID string, op *BatchMembershipOperation,
+) (map[string]interface{}, func(models.DB) error, error) {
+	gameID := game.PublicID
+
+	switch op.Action {
+	case "approve", "deny":
+		membership, err := models.ApproveOrDenyMembershipApplication(
+			db, game, gameID, op.PlayerPublicID, clanPublicID, op.RequestorPublicID, op.Action,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		requestor, err := models.GetPlayerByPublicID(db, gameID, op.RequestorPublicID)
+		if err != nil {
+			return nil, nil, err
+		}
+		hookType := models.MembershipApprovedHook
+		if op.Action == "deny" {
+			hookType = models.MembershipDeniedHook
+		}
+		return map[string]interface{}{}, func(hookDB models.DB) error {
+			return dispatchApproveDenyMembershipHookByID(
+				app, hookDB, hookType,
+				membership.GameID, membership.ClanID, membership.PlayerID,
+				requestor.ID, membership.RequestorID, membership.Message, membership.Level,
+			)
+		}, nil
+
+	case "invite":
+		membership, err := models.CreateMembership(
+			db, game, gameID, op.Level, op.PlayerPublicID, clanPublicID, op.RequestorPublicID, op.Message,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		return map[string]interface{}{"approved": membership.Approved}, func(hookDB models.DB) error {
+			return dispatchMembershipHookByID(
+				app, hookDB, models.MembershipApplicationCreatedHook,
+				membership.GameID, membership.ClanID, membership.PlayerID,
+				membership.RequestorID, membership.Message, membership.Level,
+			)
+		}, nil
+
+	case "kick":
+		membership, err := models.DeleteMembership(
+			db, game, gameID, op.PlayerPublicID, clanPublicID, op.RequestorPublicID,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		return map[string]interface{}{}, func(hookDB models.DB) error {
+			return dispatchMembershipHookByPublicID(
+				app, hookDB, models.MembershipLeftHook,
+				gameID, clanPublicID, op.PlayerPublicID, op.RequestorPublicID, membership.Level,
+			)
+		}, nil
+
+	case "promote", "demote":
+		membership, err := models.PromoteOrDemoteMember(
+			db, game, gameID, op.PlayerPublicID, clanPublicID, op.RequestorPublicID, op.Action,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		requestor, err := models.GetPlayerByPublicID(db, gameID, op.RequestorPublicID)
+		if err != nil {
+			return nil, nil, err
+		}
+		hookType := models.MembershipPromotedHook
+		if op.Action == "demote" {
+			hookType = models.MembershipDemotedHook
+		}
+		return map[string]interface{}{"level": membership.Level}, func(hookDB models.DB) error {
+			return dispatchMembershipHookByID(
+				app, hookDB, hookType,
+				membership.GameID, membership.ClanID, membership.PlayerID,
+				requestor.ID, membership.Message, membership.Level,
+			)
+		}, nil
+	}
+
+	return nil, nil, fmt.Errorf("Action %s is not a valid batch membership action", op.Action)
+}
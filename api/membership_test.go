@@ -17,6 +17,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/topfreegames/khan/api"
 	"github.com/topfreegames/khan/models"
+	"github.com/topfreegames/khan/util"
 )
 
 var _ = Describe("Membership API Handler", func() {
@@ -152,6 +153,37 @@ var _ = Describe("Membership API Handler", func() {
 			status, body = PostJSON(a, CreateMembershipRoute(gameID, clanPublicID, "application"), payload)
 
 			Expect(status).To(Equal(http.StatusConflict))
+			result = map[string]interface{}{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["code"]).To(Equal(api.AlreadyHasValidMembershipErrorCode))
+			details := result["details"].(map[string]interface{})
+			Expect(details["playerPublicID"]).To(Equal(players[0].PublicID))
+			Expect(details["clanPublicID"]).To(Equal(clanPublicID))
+		})
+
+		It("Should return the remaining cooldown when applying again too soon", func() {
+			_, clan, _, players, memberships, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 1, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			memberships[0].RequestorID = memberships[0].PlayerID
+			_, err = testDb.Update(memberships[0])
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"level":          "Member",
+				"playerPublicID": players[0].PublicID,
+			}
+			_, body := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "application"), payload)
+
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["code"]).To(Equal(api.MustWaitMembershipCooldownErrorCode))
+			details := result["details"].(map[string]interface{})
+			Expect(details["playerPublicID"]).To(Equal(players[0].PublicID))
+			Expect(details["clanPublicID"]).To(Equal(clan.PublicID))
+			Expect(details["time"]).To(BeNumerically(">", 0))
+			Expect(details["time"]).To(BeNumerically("<=", 3600))
 		})
 
 		It("Should create membership application sending a message after player left clan", func() {
@@ -271,6 +303,8 @@ var _ = Describe("Membership API Handler", func() {
 			json.Unmarshal([]byte(body), &result)
 			Expect(result["success"]).To(BeFalse())
 			Expect(result["reason"]).To(Equal(fmt.Sprintf("Player was not found with id: %s", playerPublicID)))
+			Expect(result["code"]).To(Equal(api.ModelNotFoundErrorCode))
+			Expect(result["details"]).To(Equal(map[string]interface{}{"type": "Player", "id": playerPublicID}))
 		})
 
 		It("Should not create membership application if invalid data", func() {
@@ -893,6 +927,328 @@ var _ = Describe("Membership API Handler", func() {
 		})
 	})
 
+	Describe("Ban And Unban Member Handler", func() {
+		It("Should ban member", func() {
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			gameID := clan.GameID
+			clanPublicID := clan.PublicID
+
+			payload := map[string]interface{}{
+				"playerPublicID":    players[0].PublicID,
+				"requestorPublicID": owner.PublicID,
+				"duration":          60,
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(gameID, clanPublicID, "ban"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["banExpiresAt"]).To(BeNumerically(">", util.NowMilli()))
+
+			_, err = models.GetValidMembershipByClanAndPlayerPublicID(db, gameID, clanPublicID, players[0].PublicID)
+			Expect(err).To(HaveOccurred())
+
+			dbMembership, err := models.GetMembershipByClanAndPlayerPublicID(db, gameID, clanPublicID, players[0].PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbMembership.Banned).To(BeTrue())
+		})
+
+		It("Should not ban member if invalid duration", func() {
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"playerPublicID":    players[0].PublicID,
+				"requestorPublicID": owner.PublicID,
+				"duration":          -1,
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "ban"), payload)
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal("duration must be a positive number of seconds"))
+		})
+
+		It("Should not let a banned player apply", func() {
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			clan.AllowApplication = true
+			_, err = testDb.Update(clan)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"playerPublicID":    players[0].PublicID,
+				"requestorPublicID": owner.PublicID,
+			}
+			status, _ := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "ban"), payload)
+			Expect(status).To(Equal(http.StatusOK))
+
+			payload = map[string]interface{}{
+				"level":          "Member",
+				"playerPublicID": players[0].PublicID,
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "application"), payload)
+
+			Expect(status).To(Equal(http.StatusForbidden))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal(fmt.Sprintf("Player %s is banned from clan %s.", players[0].PublicID, clan.PublicID)))
+			Expect(result["code"]).To(Equal(api.PlayerBannedFromClanErrorCode))
+		})
+
+		It("Should unban member", func() {
+			game, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = models.BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID, 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"playerPublicID":    players[0].PublicID,
+				"requestorPublicID": owner.PublicID,
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "unban"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			dbMembership, err := models.GetMembershipByClanAndPlayerPublicID(db, clan.GameID, clan.PublicID, players[0].PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbMembership.Banned).To(BeFalse())
+		})
+
+		It("Should not unban member if player is not banned", func() {
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"playerPublicID":    players[0].PublicID,
+				"requestorPublicID": owner.PublicID,
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "unban"), payload)
+
+			Expect(status).To(Equal(http.StatusNotFound))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal(fmt.Sprintf("Ban was not found with id: %s", players[0].PublicID)))
+		})
+	})
+
+	Describe("Batch Memberships Handler", func() {
+		It("Should run all operations in a single transaction", func() {
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 2, "", "", false, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"operations": []map[string]interface{}{
+					{"action": "approve", "playerPublicID": players[1].PublicID, "requestorPublicID": owner.PublicID},
+					{"action": "deny", "playerPublicID": players[2].PublicID, "requestorPublicID": owner.PublicID},
+					{"action": "promote", "playerPublicID": players[0].PublicID, "requestorPublicID": owner.PublicID},
+				},
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "batch"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["succeeded"]).To(BeEquivalentTo(3))
+			Expect(result["failed"]).To(BeEquivalentTo(0))
+
+			results := result["results"].([]interface{})
+			Expect(results).To(HaveLen(3))
+			for i, item := range results {
+				itemResult := item.(map[string]interface{})
+				Expect(itemResult["success"]).To(BeTrue())
+				Expect(itemResult["index"]).To(BeEquivalentTo(i))
+			}
+			Expect(results[2].(map[string]interface{})["level"]).To(Equal("Elder"))
+
+			dbMembership, err := models.GetValidMembershipByClanAndPlayerPublicID(db, clan.GameID, clan.PublicID, players[1].PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbMembership.Approved).To(BeTrue())
+
+			dbMembership, err = models.GetMembershipByClanAndPlayerPublicID(db, clan.GameID, clan.PublicID, players[2].PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbMembership.Denied).To(BeTrue())
+
+			dbMembership, err = models.GetValidMembershipByClanAndPlayerPublicID(db, clan.GameID, clan.PublicID, players[0].PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbMembership.Level).To(Equal("Elder"))
+		})
+
+		It("Should roll back every operation in atomic mode if one fails", func() {
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 1, "", "", false, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"mode": "atomic",
+				"operations": []map[string]interface{}{
+					{"action": "approve", "playerPublicID": players[0].PublicID, "requestorPublicID": owner.PublicID},
+					{"action": "approve", "playerPublicID": "invalid-player", "requestorPublicID": owner.PublicID},
+				},
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "batch"), payload)
+
+			Expect(status).To(Equal(http.StatusNotFound))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["failedIndex"]).To(BeEquivalentTo(1))
+			Expect(result["reason"]).To(Equal("Membership was not found with id: invalid-player"))
+			Expect(result["code"]).To(Equal(api.ModelNotFoundErrorCode))
+			Expect(result["results"]).To(HaveLen(2))
+
+			dbMembership, err := models.GetValidMembershipByClanAndPlayerPublicID(db, clan.GameID, clan.PublicID, players[0].PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbMembership.Approved).To(BeFalse())
+		})
+
+		It("Should commit the successful operations in best effort mode", func() {
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 2, "", "", false, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"mode": "bestEffort",
+				"operations": []map[string]interface{}{
+					{"action": "approve", "playerPublicID": players[0].PublicID, "requestorPublicID": owner.PublicID},
+					{"action": "kick", "playerPublicID": "invalid-player", "requestorPublicID": owner.PublicID},
+					{"action": "approve", "playerPublicID": players[1].PublicID, "requestorPublicID": owner.PublicID},
+				},
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "batch"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["succeeded"]).To(BeEquivalentTo(2))
+			Expect(result["failed"]).To(BeEquivalentTo(1))
+
+			failedResult := result["results"].([]interface{})[1].(map[string]interface{})
+			Expect(failedResult["success"]).To(BeFalse())
+			Expect(failedResult["status"]).To(BeEquivalentTo(http.StatusNotFound))
+			Expect(failedResult["code"]).To(Equal(api.ModelNotFoundErrorCode))
+			Expect(failedResult["action"]).To(Equal("kick"))
+
+			for _, player := range players {
+				dbMembership, err := models.GetValidMembershipByClanAndPlayerPublicID(db, clan.GameID, clan.PublicID, player.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbMembership.Approved).To(BeTrue())
+			}
+
+			dbClan, err := models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.MembershipCount).To(Equal(3))
+		})
+
+		It("Should invite and kick players", func() {
+			game, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			player := models.PlayerFactory.MustCreateWithOption(map[string]interface{}{
+				"GameID": game.PublicID,
+			}).(*models.Player)
+			err = testDb.Insert(player)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"operations": []map[string]interface{}{
+					{"action": "invite", "playerPublicID": player.PublicID, "requestorPublicID": owner.PublicID, "level": "Member", "message": "join us"},
+					{"action": "kick", "playerPublicID": players[0].PublicID, "requestorPublicID": owner.PublicID},
+				},
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "batch"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["succeeded"]).To(BeEquivalentTo(2))
+
+			dbMembership, err := models.GetValidMembershipByClanAndPlayerPublicID(db, clan.GameID, clan.PublicID, player.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbMembership.Approved).To(BeFalse())
+			Expect(dbMembership.Message).To(Equal("join us"))
+
+			_, err = models.GetValidMembershipByClanAndPlayerPublicID(db, clan.GameID, clan.PublicID, players[0].PublicID)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should fail if an operation is invalid", func() {
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 1, "", "", false, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"operations": []map[string]interface{}{
+					{"action": "approve", "playerPublicID": players[0].PublicID, "requestorPublicID": owner.PublicID},
+					{"action": "invite", "playerPublicID": players[0].PublicID, "requestorPublicID": owner.PublicID},
+					{"action": "explode", "playerPublicID": players[0].PublicID},
+				},
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "batch"), payload)
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal(
+				"operations[1].level is required, " +
+					"operations[2].action must be one of approve, deny, invite, kick, promote or demote, " +
+					"operations[2].requestorPublicID is required",
+			))
+		})
+
+		It("Should fail if mode is invalid", func() {
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 1, "", "", false, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"mode": "whatever",
+				"operations": []map[string]interface{}{
+					{"action": "approve", "playerPublicID": players[0].PublicID, "requestorPublicID": owner.PublicID},
+				},
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "batch"), payload)
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["reason"]).To(Equal("mode must be one of atomic or bestEffort"))
+		})
+
+		It("Should fail if the batch has too many operations", func() {
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 2, "", "", false, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			a.Config.Set(api.BatchMembershipsMaxOperationsKey, 1)
+			defer a.Config.Set(api.BatchMembershipsMaxOperationsKey, 100)
+
+			payload := map[string]interface{}{
+				"operations": []map[string]interface{}{
+					{"action": "approve", "playerPublicID": players[0].PublicID, "requestorPublicID": owner.PublicID},
+					{"action": "approve", "playerPublicID": players[1].PublicID, "requestorPublicID": owner.PublicID},
+				},
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "batch"), payload)
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["reason"]).To(Equal("A batch can have at most 1 operations"))
+		})
+	})
+
 	Describe("Membership Hooks", func() {
 		It("Apply should call membership application created hook with non empty message", func() {
 			hooks, err := models.GetHooksForRoutes(testDb, []string{
@@ -1252,5 +1608,74 @@ var _ = Describe("Membership API Handler", func() {
 			response := (*responses)[0]["payload"].(map[string]interface{})
 			validateMembershipHookResponse(response, gameID, clan, players[0], owner)
 		})
+
+		It("should call membership banned hook", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52525/membershipbanned",
+			}, models.MembershipBannedHook)
+			Expect(err).NotTo(HaveOccurred())
+			responses := startRouteHandler([]string{"/membershipbanned"}, 52525)
+
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, hooks[0].GameID, "", true)
+			Expect(err).NotTo(HaveOccurred())
+
+			gameID := hooks[0].GameID
+			clanPublicID := clan.PublicID
+
+			payload := map[string]interface{}{
+				"playerPublicID":    players[0].PublicID,
+				"requestorPublicID": owner.PublicID,
+				"duration":          60,
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(gameID, clanPublicID, "ban"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			Eventually(func() int {
+				return len(*responses)
+			}).Should(Equal(1))
+
+			response := (*responses)[0]["payload"].(map[string]interface{})
+			validateMembershipHookResponse(response, gameID, clan, players[0], owner)
+			Expect(response["banExpiresAt"]).To(Equal(result["banExpiresAt"]))
+		})
+
+		It("should call membership unbanned hook", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52525/membershipunbanned",
+			}, models.MembershipUnbannedHook)
+			Expect(err).NotTo(HaveOccurred())
+			responses := startRouteHandler([]string{"/membershipunbanned"}, 52525)
+
+			game, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, hooks[0].GameID, "", true)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = models.BanMember(testDb, game, game.PublicID, players[0].PublicID, clan.PublicID, owner.PublicID, 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			gameID := hooks[0].GameID
+			clanPublicID := clan.PublicID
+
+			payload := map[string]interface{}{
+				"playerPublicID":    players[0].PublicID,
+				"requestorPublicID": owner.PublicID,
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(gameID, clanPublicID, "unban"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			Eventually(func() int {
+				return len(*responses)
+			}).Should(Equal(1))
+
+			response := (*responses)[0]["payload"].(map[string]interface{})
+			validateMembershipHookResponse(response, gameID, clan, players[0], owner)
+		})
 	})
 })
@@ -9,15 +9,24 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/getsentry/raven-go"
 	"github.com/labstack/echo"
+	uuid "github.com/satori/go.uuid"
 	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
 	"github.com/topfreegames/khan/util"
 	"github.com/uber-go/zap"
 )
@@ -75,6 +84,46 @@ func (v *VersionMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
+// RequestIDHeader identifies a request, so the audit entries it writes can be correlated with it
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength is the size of the request id column in the audit trail
+const maxRequestIDLength = 255
+
+type requestIDContextKey struct{}
+
+// getRequestIDFromContext returns the ID of the request being served with ctx, if any
+func getRequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+//NewRequestIDMiddleware returns a new request id middleware
+func NewRequestIDMiddleware() *RequestIDMiddleware {
+	return &RequestIDMiddleware{}
+}
+
+//RequestIDMiddleware reads the request id from the X-Request-ID header, generating one if it is missing,
+//and echoes it in the response
+type RequestIDMiddleware struct{}
+
+// Serve serves the middleware
+func (r *RequestIDMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		requestID := c.Request().Header().Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewV4().String()
+		}
+		requestID = util.TruncateString(requestID, maxRequestIDLength)
+		c.Response().Header().Set(RequestIDHeader, requestID)
+		c.SetStdContext(context.WithValue(c.StdContext(), requestIDContextKey{}, requestID))
+		return next(c)
+	}
+}
+
 //NewSentryMiddleware returns a new sentry middleware
 func NewSentryMiddleware(app *App) *SentryMiddleware {
 	return &SentryMiddleware{
@@ -293,3 +342,266 @@ func (nr *NewRelicMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
 		return nil
 	}
 }
+
+//NewArchivedGameMiddleware returns a new archived game middleware
+func NewArchivedGameMiddleware(app *App) *ArchivedGameMiddleware {
+	return &ArchivedGameMiddleware{
+		App: app,
+	}
+}
+
+//ArchivedGameMiddleware rejects mutating requests to archived games, except the ones unarchiving them
+type ArchivedGameMiddleware struct {
+	App *App
+}
+
+// Serve serves the middleware
+func (a *ArchivedGameMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		gameID := c.Param("gameID")
+		method := c.Request().Method()
+		if gameID == "" || c.Path() == ArchiveGameRoute || method == echo.GET || method == echo.HEAD || method == echo.OPTIONS {
+			return next(c)
+		}
+
+		// the game cache can't be used here, since writes must be disabled as soon as the
+		// game is archived, so a short lived cache invalidated on archival is used instead
+		var archived bool
+		err := WithSegment("middleware-archived-game", c, func() error {
+			var err error
+			archived, err = a.App.IsGameArchived(c.StdContext(), gameID)
+			return err
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+		if archived {
+			return FailWithError(&models.GameArchivedError{PublicID: gameID}, c)
+		}
+		return next(c)
+	}
+}
+
+// IdempotencyKeyHeader is the header clients send to safely retry mutating requests
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyReplayedHeader is set in responses replayed from a previous request with the same key
+const IdempotencyReplayedHeader = "Idempotency-Replayed"
+
+// IdempotencyKeyTTLKey is the config key for the number of seconds responses are kept for replaying
+const IdempotencyKeyTTLKey = "idempotency.ttl"
+
+// maxIdempotencyKeyLength is the size of the key column in the database
+const maxIdempotencyKeyLength = 255
+
+func getRequestHash(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte(" "))
+	h.Write([]byte(uri))
+	h.Write([]byte("\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+//NewIdempotencyMiddleware returns a new idempotency middleware
+func NewIdempotencyMiddleware(app *App) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		App: app,
+	}
+}
+
+//IdempotencyMiddleware stores the response of mutating requests sent with an Idempotency-Key header
+//and replays it when the request is retried with the same key
+type IdempotencyMiddleware struct {
+	App *App
+}
+
+// Serve serves the middleware
+func (i *IdempotencyMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header().Get(IdempotencyKeyHeader)
+		gameID := c.Param("gameID")
+		method := c.Request().Method()
+		if key == "" || gameID == "" || method == echo.GET || method == echo.HEAD || method == echo.OPTIONS {
+			return next(c)
+		}
+
+		return WithSegment("middleware-idempotency", c, func() error {
+			l := i.App.Logger.With(
+				zap.String("source", "idempotencyMiddleware"),
+				zap.String("operation", "Serve"),
+				zap.String("gameID", gameID),
+				zap.String("idempotencyKey", key),
+			)
+
+			if len(key) > maxIdempotencyKeyLength {
+				return FailWith(
+					http.StatusBadRequest,
+					fmt.Sprintf("%s must have at most %d characters.", IdempotencyKeyHeader, maxIdempotencyKeyLength),
+					c,
+				)
+			}
+
+			body, err := GetRequestBody(c)
+			if err != nil {
+				return FailWith(http.StatusBadRequest, err.Error(), c)
+			}
+			requestHash := getRequestHash(method, c.Request().URI(), body)
+
+			db := i.App.Db(c.StdContext())
+			ttl := i.App.Config.GetInt(IdempotencyKeyTTLKey)
+			existing, err := models.ReserveIdempotencyKey(db, gameID, key, requestHash, ttl)
+			if err != nil {
+				log.E(l, "Failed to reserve idempotency key.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return FailWith(http.StatusInternalServerError, err.Error(), c)
+			}
+
+			if existing != nil {
+				if existing.RequestHash != requestHash {
+					return FailWith(
+						http.StatusConflict,
+						fmt.Sprintf("%s %s was already used with a different request.", IdempotencyKeyHeader, key),
+						c,
+					)
+				}
+				if !existing.IsCompleted() {
+					return FailWith(
+						http.StatusConflict,
+						fmt.Sprintf("A request with %s %s is still being processed.", IdempotencyKeyHeader, key),
+						c,
+					)
+				}
+
+				log.D(l, "Replaying stored response.")
+				res := c.Response()
+				res.Header().Set(IdempotencyReplayedHeader, "true")
+				res.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+				res.WriteHeader(existing.Status)
+				_, err = res.Write([]byte(existing.Response))
+				return err
+			}
+
+			response, err := getBodyFromNext(c, next)
+			status := c.Response().Status()
+			if err != nil || status >= http.StatusInternalServerError {
+				// server errors are not stored, so the request can be retried with the same key
+				if rErr := models.ReleaseIdempotencyKey(db, gameID, key); rErr != nil {
+					log.E(l, "Failed to release idempotency key.", func(cm log.CM) {
+						cm.Write(zap.Error(rErr))
+					})
+				}
+				return err
+			}
+
+			if cErr := models.CompleteIdempotencyKey(db, gameID, key, status, response); cErr != nil {
+				log.E(l, "Failed to store idempotent response.", func(cm log.CM) {
+					cm.Write(zap.Error(cErr))
+				})
+			}
+			return nil
+		})
+	}
+}
+
+// APIKeysEnabledKey is the config key that enables API key authentication
+const APIKeysEnabledKey = "apiKeys.enabled"
+
+// apiKeyContextKey is the context key of the API key that authenticated the request
+const apiKeyContextKey = "apiKey"
+
+// getAdminAuditActor returns the actor recorded in the audit trail for the admin actions of the request
+func getAdminAuditActor(c echo.Context) string {
+	if apiKey, ok := c.Get(apiKeyContextKey).(*models.APIKey); ok {
+		return apiKey.AuditActor()
+	}
+	return models.AdminAuditActor
+}
+
+// apiKeyRoleRanks orders the API key roles, each one being allowed everything the lower ones are
+var apiKeyRoleRanks = map[string]int{
+	models.APIKeyRoleReadOnly: 1,
+	models.APIKeyRoleServer:   2,
+	models.APIKeyRoleAdmin:    3,
+}
+
+// getRequiredAPIKeyRole returns the lowest API key role allowed to call a route,
+// or an empty string if the route can only be called with the basic auth credentials
+func getRequiredAPIKeyRole(method, path string) string {
+	if !strings.HasPrefix(path, "/games/:gameID") {
+		return ""
+	}
+	readOnly := method == echo.GET || method == echo.HEAD || method == echo.OPTIONS
+	switch {
+	case strings.HasPrefix(path, "/games/:gameID/api-keys"), strings.HasPrefix(path, "/games/:gameID/hooks"):
+		return models.APIKeyRoleAdmin
+	case (path == "/games/:gameID" || path == ArchiveGameRoute) && !readOnly:
+		return models.APIKeyRoleAdmin
+	case readOnly:
+		return models.APIKeyRoleReadOnly
+	}
+	return models.APIKeyRoleServer
+}
+
+func isValidBasicAuth(header, user, pass string) bool {
+	if user == "" || !strings.HasPrefix(header, "Basic ") {
+		return false
+	}
+	credentials, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(header, "Basic "))
+	if err != nil {
+		return false
+	}
+	expected := []byte(fmt.Sprintf("%s:%s", user, pass))
+	return subtle.ConstantTimeCompare(credentials, expected) == 1
+}
+
+//NewAPIKeyAuthMiddleware returns a new API key auth middleware
+func NewAPIKeyAuthMiddleware(app *App) *APIKeyAuthMiddleware {
+	return &APIKeyAuthMiddleware{
+		App: app,
+	}
+}
+
+//APIKeyAuthMiddleware authenticates requests with an API key sent as a bearer token, which can only
+//call the routes of its game allowed by its role, or with the basic auth credentials, which can call every route
+type APIKeyAuthMiddleware struct {
+	App *App
+}
+
+// Serve serves the middleware
+func (a *APIKeyAuthMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Path() == "/healthcheck" {
+			return next(c)
+		}
+
+		header := c.Request().Header().Get(echo.HeaderAuthorization)
+		if !strings.HasPrefix(header, "Bearer ") {
+			user := a.App.Config.GetString("basicauth.username")
+			pass := a.App.Config.GetString("basicauth.password")
+			if isValidBasicAuth(header, user, pass) {
+				return next(c)
+			}
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Basic realm=Restricted")
+			return FailWith(http.StatusUnauthorized, "Unauthorized.", c)
+		}
+
+		apiKey, err := a.App.GetAPIKey(c.StdContext(), strings.TrimPrefix(header, "Bearer "))
+		if err != nil {
+			if _, ok := err.(*models.ModelNotFoundError); ok {
+				return FailWith(http.StatusUnauthorized, "Unauthorized.", c)
+			}
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		role := getRequiredAPIKeyRole(c.Request().Method(), c.Path())
+		if role == "" || apiKey.GameID != c.Param("gameID") || apiKeyRoleRanks[apiKey.Role] < apiKeyRoleRanks[role] {
+			return FailWith(http.StatusForbidden, "API key is not allowed to perform this request.", c)
+		}
+
+		c.Set(apiKeyContextKey, apiKey)
+		return next(c)
+	}
+}
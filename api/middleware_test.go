@@ -0,0 +1,165 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Pallinder/go-randomdata"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Idempotency Middleware", func() {
+	var testDb models.DB
+	var a *api.App
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+
+		a = GetDefaultTestApp()
+		a.NonblockingStartWorkers()
+	})
+
+	AfterEach(func() {
+		a.Config.Set(api.IdempotencyKeyTTLKey, 86400)
+	})
+
+	It("Should replay the response of a retried clan creation", func() {
+		_, player, err := models.CreatePlayerFactory(testDb, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		clanPublicID := uuid.NewV4().String()
+		payload := map[string]interface{}{
+			"publicID":         clanPublicID,
+			"name":             randomdata.FullName(randomdata.RandomGender),
+			"ownerPublicID":    player.PublicID,
+			"metadata":         map[string]interface{}{"x": "a"},
+			"allowApplication": true,
+			"autoJoin":         true,
+		}
+		key := uuid.NewV4().String()
+
+		status, body, headers := DoRequestWithHeaders(a, "POST", GetGameRoute(player.GameID, "/clans"), payload, map[string]string{api.IdempotencyKeyHeader: key})
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(headers.Get(api.IdempotencyReplayedHeader)).To(BeEmpty())
+
+		replayedStatus, replayedBody, headers := DoRequestWithHeaders(a, "POST", GetGameRoute(player.GameID, "/clans"), payload, map[string]string{api.IdempotencyKeyHeader: key})
+		Expect(replayedStatus).To(Equal(http.StatusOK))
+		Expect(replayedBody).To(Equal(body))
+		Expect(headers.Get(api.IdempotencyReplayedHeader)).To(Equal("true"))
+
+		count, err := testDb.SelectInt("SELECT COUNT(*) FROM clans WHERE game_id=$1 AND public_id=$2", player.GameID, clanPublicID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(BeEquivalentTo(1))
+	})
+
+	It("Should replay a membership application instead of failing with a conflict", func() {
+		_, clan, _, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+		Expect(err).NotTo(HaveOccurred())
+		clan.AllowApplication = true
+		_, err = testDb.Update(clan)
+		Expect(err).NotTo(HaveOccurred())
+
+		player := models.PlayerFactory.MustCreateWithOption(map[string]interface{}{
+			"GameID": clan.GameID,
+		}).(*models.Player)
+		err = testDb.Insert(player)
+		Expect(err).NotTo(HaveOccurred())
+
+		payload := map[string]interface{}{
+			"level":          "Member",
+			"playerPublicID": player.PublicID,
+		}
+		route := CreateMembershipRoute(clan.GameID, clan.PublicID, "application")
+		key := uuid.NewV4().String()
+
+		status, body, _ := DoRequestWithHeaders(a, "POST", route, payload, map[string]string{api.IdempotencyKeyHeader: key})
+		Expect(status).To(Equal(http.StatusOK))
+
+		status, replayedBody, _ := DoRequestWithHeaders(a, "POST", route, payload, map[string]string{api.IdempotencyKeyHeader: key})
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(replayedBody).To(Equal(body))
+
+		status, _ = PostJSON(a, route, payload)
+		Expect(status).To(Equal(http.StatusConflict))
+	})
+
+	It("Should fail if the key is reused with a different body", func() {
+		_, player, err := models.CreatePlayerFactory(testDb, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		payload := map[string]interface{}{
+			"publicID":      uuid.NewV4().String(),
+			"name":          "clan",
+			"ownerPublicID": player.PublicID,
+			"metadata":      map[string]interface{}{},
+		}
+		key := uuid.NewV4().String()
+
+		status, _, _ := DoRequestWithHeaders(a, "POST", GetGameRoute(player.GameID, "/clans"), payload, map[string]string{api.IdempotencyKeyHeader: key})
+		Expect(status).To(Equal(http.StatusOK))
+
+		payload["name"] = "another clan"
+		status, body, _ := DoRequestWithHeaders(a, "POST", GetGameRoute(player.GameID, "/clans"), payload, map[string]string{api.IdempotencyKeyHeader: key})
+		Expect(status).To(Equal(http.StatusConflict))
+		var result map[string]interface{}
+		json.Unmarshal([]byte(body), &result)
+		Expect(result["success"]).To(BeFalse())
+		Expect(result["reason"]).To(Equal(fmt.Sprintf("Idempotency-Key %s was already used with a different request.", key)))
+	})
+
+	It("Should not replay responses after the key expires", func() {
+		_, player, err := models.CreatePlayerFactory(testDb, "")
+		Expect(err).NotTo(HaveOccurred())
+		a.Config.Set(api.IdempotencyKeyTTLKey, 0)
+
+		payload := map[string]interface{}{
+			"publicID":      uuid.NewV4().String(),
+			"name":          "clan",
+			"ownerPublicID": player.PublicID,
+			"metadata":      map[string]interface{}{},
+		}
+		key := uuid.NewV4().String()
+
+		status, _, _ := DoRequestWithHeaders(a, "POST", GetGameRoute(player.GameID, "/clans"), payload, map[string]string{api.IdempotencyKeyHeader: key})
+		Expect(status).To(Equal(http.StatusOK))
+
+		payload["publicID"] = uuid.NewV4().String()
+		status, _, headers := DoRequestWithHeaders(a, "POST", GetGameRoute(player.GameID, "/clans"), payload, map[string]string{api.IdempotencyKeyHeader: key})
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(headers.Get(api.IdempotencyReplayedHeader)).To(BeEmpty())
+	})
+
+	It("Should scope keys by game", func() {
+		_, player, err := models.CreatePlayerFactory(testDb, "")
+		Expect(err).NotTo(HaveOccurred())
+		_, otherPlayer, err := models.CreatePlayerFactory(testDb, "")
+		Expect(err).NotTo(HaveOccurred())
+		key := uuid.NewV4().String()
+
+		for _, p := range []*models.Player{player, otherPlayer} {
+			payload := map[string]interface{}{
+				"publicID":      uuid.NewV4().String(),
+				"name":          "clan",
+				"ownerPublicID": p.PublicID,
+				"metadata":      map[string]interface{}{},
+			}
+			status, _, headers := DoRequestWithHeaders(a, "POST", GetGameRoute(p.GameID, "/clans"), payload, map[string]string{api.IdempotencyKeyHeader: key})
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(headers.Get(api.IdempotencyReplayedHeader)).To(BeEmpty())
+		}
+	})
+})
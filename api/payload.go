@@ -11,6 +11,7 @@ package api
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/topfreegames/khan/log"
 	"github.com/topfreegames/khan/util"
@@ -70,6 +71,23 @@ func (v *Validation) validateCustom(name string, valFunc func() []string) {
 	}
 }
 
+func (v *Validation) validateMetadataPatch(name *string, metadata map[string]interface{}, increment map[string]float64) {
+	if name != nil && *name == "" {
+		v.errors = append(v.errors, "name cannot be empty")
+	}
+
+	fields := []string{}
+	for field := range increment {
+		if _, ok := metadata[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		v.errors = append(v.errors, fmt.Sprintf("incrementMetadata.%s cannot also be patched in metadata", field))
+	}
+}
+
 //Errors in validation
 func (v *Validation) Errors() []string {
 	return v.errors
@@ -123,6 +141,24 @@ func (ucp *UpdateClanPayload) Validate() []string {
 	return v.Errors()
 }
 
+//PatchClanPayload maps the payload for the Patch Clan route
+type PatchClanPayload struct {
+	OwnerPublicID     string                 `json:"ownerPublicID"`
+	Name              *string                `json:"name"`
+	Metadata          map[string]interface{} `json:"metadata"`
+	IncrementMetadata map[string]float64     `json:"incrementMetadata"`
+	AllowApplication  *bool                  `json:"allowApplication"`
+	AutoJoin          *bool                  `json:"autoJoin"`
+}
+
+//Validate all the required fields for patching a clan
+func (pcp *PatchClanPayload) Validate() []string {
+	v := NewValidation()
+	v.validateRequiredString("ownerPublicID", pcp.OwnerPublicID)
+	v.validateMetadataPatch(pcp.Name, pcp.Metadata, pcp.IncrementMetadata)
+	return v.Errors()
+}
+
 //TransferClanOwnershipPayload maps the payload for the Transfer Clan Ownership route
 type TransferClanOwnershipPayload struct {
 	PlayerPublicID string `json:"playerPublicID"`
@@ -165,6 +201,20 @@ func (upp *UpdatePlayerPayload) Validate() []string {
 	return v.Errors()
 }
 
+//PatchPlayerPayload maps the payload for the Patch Player route
+type PatchPlayerPayload struct {
+	Name              *string                `json:"name"`
+	Metadata          map[string]interface{} `json:"metadata"`
+	IncrementMetadata map[string]float64     `json:"incrementMetadata"`
+}
+
+//Validate all the fields for patching a player
+func (ppp *PatchPlayerPayload) Validate() []string {
+	v := NewValidation()
+	v.validateMetadataPatch(ppp.Name, ppp.Metadata, ppp.IncrementMetadata)
+	return v.Errors()
+}
+
 //UpdateGamePayload maps the payload required for the Update game route
 type UpdateGamePayload struct {
 	Name                          string                 `json:"name"`
@@ -220,6 +270,20 @@ func (p *UpdateGamePayload) Validate() []string {
 	return v.Errors()
 }
 
+//PatchGamePayload maps the payload for the Patch Game route
+type PatchGamePayload struct {
+	Name              *string                `json:"name"`
+	Metadata          map[string]interface{} `json:"metadata"`
+	IncrementMetadata map[string]float64     `json:"incrementMetadata"`
+}
+
+//Validate all the fields for patching a game
+func (p *PatchGamePayload) Validate() []string {
+	v := NewValidation()
+	v.validateMetadataPatch(p.Name, p.Metadata, p.IncrementMetadata)
+	return v.Errors()
+}
+
 //CreateGamePayload maps the payload required for the Create game route
 type CreateGamePayload struct {
 	PublicID                      string                 `json:"publicID"`
@@ -333,13 +397,88 @@ func (admip *ApproveOrDenyMembershipInvitationPayload) Validate() []string {
 
 //HookPayload maps the payload required to create or update hooks
 type HookPayload struct {
-	Type    int    `json:"type"`
-	HookURL string `json:"hookURL"`
+	Type         int                    `json:"type"`
+	Types        []int                  `json:"types"`
+	Wildcards    []string               `json:"wildcards"`
+	HookURL      string                 `json:"hookURL"`
+	Secret       string                 `json:"secret"`
+	Filter       string                 `json:"filter"`
+	BodyTemplate map[string]interface{} `json:"bodyTemplate"`
 }
 
 //Validate all the required fields
 func (hp *HookPayload) Validate() []string {
 	v := NewValidation()
 	v.validateRequiredString("hookURL", hp.HookURL)
+	v.validateCustom("filter", func() []string {
+		if hp.Filter == "" {
+			return []string{}
+		}
+		if _, err := util.CompileHookFilter(hp.Filter); err != nil {
+			return []string{err.Error()}
+		}
+		return []string{}
+	})
+	return v.Errors()
+}
+
+//CreateAPIKeyPayload maps the payload required to create API keys
+type CreateAPIKeyPayload struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+//Validate all the required fields
+func (akp *CreateAPIKeyPayload) Validate() []string {
+	v := NewValidation()
+	v.validateRequiredString("role", akp.Role)
+	return v.Errors()
+}
+
+//BatchMembershipOperation maps a single operation of the Batch Memberships route
+type BatchMembershipOperation struct {
+	Action            string `json:"action"`
+	PlayerPublicID    string `json:"playerPublicID"`
+	RequestorPublicID string `json:"requestorPublicID"`
+	Level             string `json:"level"`
+	Message           string `json:"message"`
+}
+
+//BatchMembershipPayload maps the payload required for the Batch Memberships route
+type BatchMembershipPayload struct {
+	Mode       string                     `json:"mode"`
+	Operations []BatchMembershipOperation `json:"operations"`
+}
+
+//Validate all the required fields
+func (bmp *BatchMembershipPayload) Validate() []string {
+	v := NewValidation()
+	v.validateCustom("mode", func() []string {
+		if bmp.Mode != "" && bmp.Mode != BatchModeAtomic && bmp.Mode != BatchModeBestEffort {
+			return []string{fmt.Sprintf("mode must be one of %s or %s", BatchModeAtomic, BatchModeBestEffort)}
+		}
+		return []string{}
+	})
+	v.validateCustom("operations", func() []string {
+		if len(bmp.Operations) == 0 {
+			return []string{"operations is required"}
+		}
+		errors := []string{}
+		for i, op := range bmp.Operations {
+			if _, ok := batchMembershipActions[op.Action]; !ok {
+				errors = append(errors, fmt.Sprintf("operations[%d].action must be one of approve, deny, invite, kick, promote or demote", i))
+			}
+			if op.PlayerPublicID == "" {
+				errors = append(errors, fmt.Sprintf("operations[%d].playerPublicID is required", i))
+			}
+			if op.RequestorPublicID == "" {
+				errors = append(errors, fmt.Sprintf("operations[%d].requestorPublicID is required", i))
+			}
+			if op.Action == "invite" && op.Level == "" {
+				errors = append(errors, fmt.Sprintf("operations[%d].level is required", i))
+			}
+		}
+		return errors
+	})
 	return v.Errors()
 }
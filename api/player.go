@@ -49,6 +49,7 @@ func CreatePlayerHandler(app *App) func(c echo.Context) error {
 			log.D(l, "Creating player...")
 			player, err = models.CreatePlayer(
 				db,
+				nil,
 				gameID,
 				payload.PublicID,
 				payload.Name,
@@ -153,15 +154,30 @@ func UpdatePlayerHandler(app *App) func(c echo.Context) error {
 			return FailWith(http.StatusBadRequest, err.Error(), c)
 		}
 
+		var currentVersion int64
+		if beforeUpdatePlayer != nil {
+			currentVersion = beforeUpdatePlayer.Version
+		}
+		expectedVersion, ok := getExpectedVersion(c, currentVersion)
+		if !ok {
+			err = &models.VersionConflictError{Type: "Player", ID: playerPublicID, Version: currentVersion}
+			log.D(l, "Player version does not match the request preconditions.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWithVersionConflict(err.Error(), currentVersion, c)
+		}
+
 		err = WithSegment("player-update", c, func() error {
 			err = WithSegment("player-update-query", c, func() error {
 				log.D(l, "Updating player...")
-				player, err = models.UpdatePlayer(
+				player, err = models.UpdatePlayerWithVersion(
 					db,
+					game,
 					gameID,
 					playerPublicID,
 					payload.Name,
 					payload.Metadata,
+					expectedVersion,
 				)
 				return err
 			})
@@ -175,6 +191,9 @@ func UpdatePlayerHandler(app *App) func(c echo.Context) error {
 			return nil
 		})
 		if err != nil {
+			if conflict, ok := err.(*models.VersionConflictError); ok {
+				return FailWithVersionConflict(conflict.Error(), conflict.Version, c)
+			}
 			return FailWith(http.StatusInternalServerError, err.Error(), c)
 		}
 
@@ -199,7 +218,120 @@ func UpdatePlayerHandler(app *App) func(c echo.Context) error {
 		log.D(l, "Player updated successfully.", func(cm log.CM) {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
-		return SucceedWith(map[string]interface{}{}, c)
+		c.Response().Header().Set(ETagHeader, getVersionETag(player.Version))
+		return SucceedWith(map[string]interface{}{
+			"version": player.Version,
+		}, c)
+	}
+}
+
+// PatchPlayerHandler is the handler responsible for partially updating existing players
+func PatchPlayerHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "PatchPlayer")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		playerPublicID := c.Param("playerPublicID")
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "playerHandler"),
+			zap.String("operation", "patchPlayer"),
+			zap.String("gameID", gameID),
+			zap.String("playerPublicID", playerPublicID),
+		)
+
+		var payload PatchPlayerPayload
+		err := WithSegment("payload", c, func() error {
+			return LoadJSONPayload(&payload, c, l)
+		})
+		if err != nil {
+			return FailWith(http.StatusBadRequest, err.Error(), c)
+		}
+
+		var player, beforePatchPlayer *models.Player
+		var game *models.Game
+
+		err = WithSegment("game-retrieve", c, func() error {
+			log.D(l, "Retrieving game...")
+			game, err = models.GetGameByPublicID(db, gameID)
+			return err
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		err = WithSegment("player-retrieve", c, func() error {
+			log.D(l, "Retrieving player...")
+			beforePatchPlayer, err = models.GetPlayerByPublicID(db, gameID, playerPublicID)
+			return err
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		expectedVersion, ok := getExpectedVersion(c, beforePatchPlayer.Version)
+		if !ok {
+			err = &models.VersionConflictError{Type: "Player", ID: playerPublicID, Version: beforePatchPlayer.Version}
+			log.D(l, "Player version does not match the request preconditions.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWithVersionConflict(err.Error(), beforePatchPlayer.Version, c)
+		}
+
+		err = WithSegment("player-patch", c, func() error {
+			log.D(l, "Patching player...")
+			player, err = models.PatchPlayer(
+				db,
+				game,
+				gameID,
+				playerPublicID,
+				&models.PlayerPatch{
+					Name:     payload.Name,
+					Metadata: getMetadataPatch(payload.Metadata, payload.IncrementMetadata),
+				},
+				expectedVersion,
+			)
+			return err
+		})
+		if err != nil {
+			log.W(l, "Patching player failed.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			if conflict, ok := err.(*models.VersionConflictError); ok {
+				return FailWithVersionConflict(conflict.Error(), conflict.Version, c)
+			}
+			return FailWithError(err, c)
+		}
+
+		err = WithSegment("hook-dispatch", c, func() error {
+			shouldDispatch := validateUpdatePlayerDispatch(game, beforePatchPlayer, player, player.Metadata, l)
+			if shouldDispatch {
+				log.D(l, "Dispatching player update hooks...")
+				err = app.DispatchHooks(gameID, models.PlayerUpdatedHook, player.Serialize())
+				if err != nil {
+					log.E(l, "Update player hook dispatch failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		log.D(l, "Player patched successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		c.Response().Header().Set(ETagHeader, getVersionETag(player.Version))
+		return SucceedWith(map[string]interface{}{
+			"name":     player.Name,
+			"metadata": player.Metadata,
+			"version":  player.Version,
+		}, c)
 	}
 }
 
@@ -257,6 +389,72 @@ func RetrievePlayerHandler(app *App) func(c echo.Context) error {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
 
+		version := player["version"].(int64)
+		switch checkReadPreconditions(c, version) {
+		case http.StatusNotModified:
+			return c.NoContent(http.StatusNotModified)
+		case http.StatusConflict:
+			err = &models.VersionConflictError{Type: "Player", ID: publicID, Version: version}
+			return FailWithVersionConflict(err.Error(), version, c)
+		}
+
 		return SucceedWith(player, c)
 	}
 }
+
+// RetrievePlayerMembershipsHandler is the handler responsible for returning a page of the memberships of a player
+func RetrievePlayerMembershipsHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "RetrievePlayerMemberships")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		publicID := c.Param("playerPublicID")
+
+		l := app.Logger.With(
+			zap.String("source", "playerHandler"),
+			zap.String("operation", "retrievePlayerMemberships"),
+			zap.String("gameID", gameID),
+			zap.String("playerPublicID", publicID),
+		)
+
+		options, err := getPlayerMembershipsOptions(app, c)
+		if err != nil {
+			return FailWith(http.StatusBadRequest, err.Error(), c)
+		}
+
+		log.D(l, "Getting DB connection...")
+		db, err := app.GetCtxDB(c)
+		if err != nil {
+			log.E(l, "Failed to connect to DB.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+		log.D(l, "DB Connection successful.")
+
+		var result map[string]interface{}
+		err = WithSegment("player-get-memberships", c, func() error {
+			log.D(l, "Retrieving player memberships...")
+			result, err = models.GetPlayerMembershipsPage(
+				db,
+				gameID,
+				publicID,
+				options,
+			)
+			return err
+		})
+
+		if err != nil {
+			log.W(l, "Retrieve player memberships failed.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWithError(err, c)
+		}
+
+		log.D(l, "Player memberships retrieved successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+
+		return SucceedWith(result, c)
+	}
+}
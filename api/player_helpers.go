@@ -8,8 +8,11 @@
 package api
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/labstack/echo"
 	"github.com/topfreegames/khan/log"
 	"github.com/topfreegames/khan/models"
 	"github.com/uber-go/zap"
@@ -69,3 +72,42 @@ func validateUpdatePlayerDispatch(game *models.Game, sourcePlayer *models.Player
 
 	return false
 }
+
+// getPlayerMembershipsOptions reads the pagination and filtering options of RetrievePlayerMembershipsHandler
+// from the query string. Statuses are sent as a comma separated list
+func getPlayerMembershipsOptions(app *App, c echo.Context) (*models.GetPlayerMembershipsOptions, error) {
+	options := models.NewDefaultGetPlayerMembershipsOptions(app.Config)
+	maxLimit := app.Config.GetInt(models.PlayerMembershipsMaxLimitKey)
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		parsedLimit, err := strconv.ParseUint(limit, 10, 16)
+		if err != nil || parsedLimit == 0 {
+			return nil, fmt.Errorf("Limit must be a positive integer.")
+		}
+		if int(parsedLimit) > maxLimit {
+			return nil, fmt.Errorf("Limit above allowed (%v).", maxLimit)
+		}
+		options.Limit = int(parsedLimit)
+	}
+
+	options.Cursor = c.QueryParam("cursor")
+	options.ClanPublicID = c.QueryParam("clanPublicID")
+
+	if status := c.QueryParam("status"); status != "" {
+		for _, s := range strings.Split(status, ",") {
+			if !models.IsValidMembershipStatus(s) {
+				return nil, fmt.Errorf("Status %s is invalid (valid statuses are approved, pendingApplication, pendingInvite, expired, denied, banned or deleted).", s)
+			}
+			options.Statuses = append(options.Statuses, s)
+		}
+	}
+
+	if order := c.QueryParam("order"); order != "" {
+		if order != models.Ascending && order != models.Descending {
+			return nil, fmt.Errorf("Order is invalid (valid orders are %s or %s).", models.Ascending, models.Descending)
+		}
+		options.Order = order
+	}
+
+	return options, nil
+}
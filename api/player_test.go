@@ -173,6 +173,159 @@ var _ = Describe("Player API Handler", func() {
 			Expect(result["success"]).To(BeFalse())
 			Expect(result["reason"]).To(Equal("pq: value too long for type character varying(255)"))
 		})
+
+		It("Should not update player if If-Match does not match the current version", func() {
+			_, player, err := models.CreatePlayerFactory(db, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"name":     player.Name,
+				"metadata": map[string]interface{}{"y": 10},
+			}
+			route := GetGameRoute(player.GameID, fmt.Sprintf("/players/%s", player.PublicID))
+			ifMatch := map[string]string{"If-Match": fmt.Sprintf("\"%d\"", player.Version)}
+			status, body, headers := DoRequestWithHeaders(a, "PUT", route, payload, ifMatch)
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["version"]).To(BeEquivalentTo(player.Version + 1))
+			Expect(headers.Get("ETag")).To(Equal(fmt.Sprintf("\"%d\"", player.Version+1)))
+
+			payload["metadata"] = map[string]interface{}{"y": 20}
+			status, body, _ = DoRequestWithHeaders(a, "PUT", route, payload, ifMatch)
+			Expect(status).To(Equal(http.StatusConflict))
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal(fmt.Sprintf(
+				"Player %s does not match the expected version. Current version is %d.", player.PublicID, player.Version+1,
+			)))
+			Expect(result["version"]).To(BeEquivalentTo(player.Version + 1))
+
+			dbPlayer, err := models.GetPlayerByPublicID(db, player.GameID, player.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbPlayer.Metadata["y"]).To(BeEquivalentTo(10))
+		})
+
+		It("Should only create player with If-None-Match if it does not exist", func() {
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"name":     "player",
+				"metadata": map[string]interface{}{},
+			}
+			route := GetGameRoute(game.PublicID, fmt.Sprintf("/players/%s", uuid.NewV4().String()))
+			ifNoneMatch := map[string]string{"If-None-Match": "*"}
+			status, _, _ := DoRequestWithHeaders(a, "PUT", route, payload, ifNoneMatch)
+			Expect(status).To(Equal(http.StatusOK))
+
+			status, _, _ = DoRequestWithHeaders(a, "PUT", route, payload, ifNoneMatch)
+			Expect(status).To(Equal(http.StatusConflict))
+		})
+	})
+
+	Describe("Patch Player Handler", func() {
+		It("Should merge the patch into the player metadata", func() {
+			game, player, err := models.CreatePlayerFactory(db, "")
+			Expect(err).NotTo(HaveOccurred())
+			player, err = models.UpdatePlayer(db, game, player.GameID, player.PublicID, player.Name, map[string]interface{}{
+				"a": 1,
+				"b": map[string]interface{}{"c": 1, "d": 2},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"b": map[string]interface{}{"c": nil, "e": 3},
+					"f": "x",
+				},
+			}
+			route := GetGameRoute(player.GameID, fmt.Sprintf("/players/%s", player.PublicID))
+			status, body := PatchJSON(a, route, payload)
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["version"]).To(BeEquivalentTo(player.Version + 1))
+
+			dbPlayer, err := models.GetPlayerByPublicID(db, player.GameID, player.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbPlayer.Name).To(Equal(player.Name))
+			Expect(dbPlayer.Metadata).To(Equal(map[string]interface{}{
+				"a": float64(1),
+				"b": map[string]interface{}{"d": float64(2), "e": float64(3)},
+				"f": "x",
+			}))
+		})
+
+		It("Should increment numeric metadata fields", func() {
+			_, player, err := models.CreatePlayerFactory(db, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"name":              "new name",
+				"incrementMetadata": map[string]interface{}{"score": 5, "wins": 1},
+			}
+			route := GetGameRoute(player.GameID, fmt.Sprintf("/players/%s", player.PublicID))
+			for i := 0; i < 2; i++ {
+				status, _ := PatchJSON(a, route, payload)
+				Expect(status).To(Equal(http.StatusOK))
+			}
+
+			dbPlayer, err := models.GetPlayerByPublicID(db, player.GameID, player.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbPlayer.Name).To(Equal("new name"))
+			Expect(dbPlayer.Metadata["score"]).To(BeEquivalentTo(10))
+			Expect(dbPlayer.Metadata["wins"]).To(BeEquivalentTo(2))
+		})
+
+		It("Should not increment metadata fields that are not numbers", func() {
+			game, player, err := models.CreatePlayerFactory(db, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = models.UpdatePlayer(db, game, player.GameID, player.PublicID, player.Name, map[string]interface{}{"score": "high"})
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"incrementMetadata": map[string]interface{}{"score": 5},
+			}
+			route := GetGameRoute(player.GameID, fmt.Sprintf("/players/%s", player.PublicID))
+			status, body := PatchJSON(a, route, payload)
+			Expect(status).To(Equal(422))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal(fmt.Sprintf(
+				"Could not increment metadata of Player %s: fields score must be numbers.", player.PublicID,
+			)))
+
+			dbPlayer, err := models.GetPlayerByPublicID(db, player.GameID, player.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbPlayer.Metadata["score"]).To(Equal("high"))
+		})
+
+		It("Should not patch player if a field is both merged and incremented", func() {
+			route := GetGameRoute("game-id", "/players/player-id")
+			status, body := PatchJSON(a, route, map[string]interface{}{
+				"metadata":          map[string]interface{}{"score": 1},
+				"incrementMetadata": map[string]interface{}{"score": 1},
+			})
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal("incrementMetadata.score cannot also be patched in metadata"))
+		})
+
+		It("Should return 404 if player does not exist", func() {
+			_, player, err := models.CreatePlayerFactory(db, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(player.GameID, fmt.Sprintf("/players/%s", uuid.NewV4().String()))
+			status, _ := PatchJSON(a, route, map[string]interface{}{"name": "new name"})
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
 	})
 
 	Describe("Retrieve Player", func() {
@@ -225,6 +378,91 @@ var _ = Describe("Player API Handler", func() {
 			Expect(playerDetails["success"]).To(BeFalse())
 			Expect(playerDetails["reason"]).To(Equal("Player was not found with id: invalid-player"))
 		})
+
+		It("Should return the player version as ETag", func() {
+			_, player, err := models.CreatePlayerFactory(db, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(player.GameID, fmt.Sprintf("/players/%s", player.PublicID))
+			status, body, headers := DoRequestWithHeaders(a, "GET", route, nil, nil)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var playerDetails map[string]interface{}
+			json.Unmarshal([]byte(body), &playerDetails)
+			Expect(playerDetails["version"]).To(BeEquivalentTo(player.Version))
+			Expect(headers.Get("ETag")).To(Equal(fmt.Sprintf("\"%d\"", player.Version)))
+
+			status, body, _ = DoRequestWithHeaders(a, "GET", route, nil, map[string]string{
+				"If-None-Match": fmt.Sprintf("W/\"%d\", \"%d\"", player.Version+1, player.Version),
+			})
+			Expect(status).To(Equal(http.StatusNotModified))
+			Expect(body).To(BeEmpty())
+		})
+	})
+
+	Describe("Retrieve Player Memberships", func() {
+		It("Should retrieve a page of player memberships", func() {
+			player, err := models.GetTestPlayerWithMemberships(testDb, "", 2, 1, 1, 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(player.GameID, fmt.Sprintf("/players/%s/memberships?limit=5", player.PublicID))
+			status, body := Get(a, route)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["memberships"].([]interface{})).To(HaveLen(5))
+			nextCursor := result["nextCursor"].(string)
+			Expect(nextCursor).NotTo(BeEmpty())
+
+			route = GetGameRoute(player.GameID, fmt.Sprintf("/players/%s/memberships?limit=5&cursor=%s", player.PublicID, nextCursor))
+			status, body = Get(a, route)
+
+			Expect(status).To(Equal(http.StatusOK))
+			result = map[string]interface{}{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["memberships"].([]interface{})).To(HaveLen(2))
+			Expect(result["nextCursor"]).To(Equal(""))
+		})
+
+		It("Should filter player memberships by status", func() {
+			player, err := models.GetTestPlayerWithMemberships(testDb, "", 2, 1, 1, 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(player.GameID, fmt.Sprintf("/players/%s/memberships?status=pendingInvite,banned", player.PublicID))
+			status, body := Get(a, route)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			memberships := result["memberships"].([]interface{})
+			Expect(memberships).To(HaveLen(4))
+			for _, membership := range memberships {
+				Expect(membership.(map[string]interface{})["status"]).To(SatisfyAny(Equal("pendingInvite"), Equal("banned")))
+			}
+		})
+
+		It("Should return 400 for invalid status", func() {
+			route := GetGameRoute("some-game", "/players/some-player/memberships?status=kicked")
+			status, body := Get(a, route)
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+		})
+
+		It("Should return 404 for invalid player", func() {
+			route := GetGameRoute("some-game", "/players/invalid-player/memberships")
+			status, body := Get(a, route)
+
+			Expect(status).To(Equal(http.StatusNotFound))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal("Player was not found with id: invalid-player"))
+		})
 	})
 
 	Describe("Player Hooks", func() {
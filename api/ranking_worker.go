@@ -0,0 +1,86 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"context"
+
+	workers "github.com/jrallison/go-workers"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/topfreegames/extensions/tracing"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/topfreegames/khan/queues"
+	"github.com/uber-go/zap"
+)
+
+//RankingWorker rebuilds the clan rankings of the games whose ranking dimensions changed
+type RankingWorker struct {
+	app *App
+}
+
+//NewRankingWorker creates a new ranking worker available to our app
+func NewRankingWorker(app *App) *RankingWorker {
+	return &RankingWorker{app: app}
+}
+
+//EnqueueClanScoresRebuild enqueues the rebuild of the clan scores of a game if its ranking dimensions
+//are not the ones of the previous game, which is nil if the game did not exist
+func (w *RankingWorker) EnqueueClanScoresRebuild(previous, game *models.Game) error {
+	if !models.ClanRankingDimensionsChanged(previous, game) {
+		return nil
+	}
+
+	log.D(w.app.Logger, "Pushing clan scores rebuild into ranking queue.", func(cm log.CM) {
+		cm.Write(
+			zap.String("source", "rankingWorker"),
+			zap.String("operation", "EnqueueClanScoresRebuild"),
+			zap.String("gameID", game.PublicID),
+		)
+	})
+	_, err := workers.Enqueue(queues.KhanRankingQueue, "Add", map[string]interface{}{
+		"gameID": game.PublicID,
+	})
+	return err
+}
+
+//PerformRebuildClanScores rebuilds the clan scores of a game with its current ranking dimensions
+func (w *RankingWorker) PerformRebuildClanScores(m *workers.Msg) {
+	tags := opentracing.Tags{"component": "go-workers"}
+	span := opentracing.StartSpan("PerformRebuildClanScores", tags)
+	defer span.Finish()
+	defer tracing.LogPanic(span)
+	ctx := opentracing.ContextWithSpan(context.Background(), span)
+
+	data := m.Args().MustMap()
+	gameID := data["gameID"].(string)
+
+	l := w.app.Logger.With(
+		zap.String("source", "rankingWorker"),
+		zap.String("operation", "PerformRebuildClanScores"),
+		zap.String("gameID", gameID),
+	)
+
+	db := w.app.Db(ctx)
+	game, err := models.GetGameByPublicID(db, gameID)
+	if err != nil {
+		if _, ok := err.(*models.ModelNotFoundError); ok {
+			log.W(l, "Game was not found. Skipping clan scores rebuild.")
+			return
+		}
+		// the job is retried by the workers
+		panic(err)
+	}
+
+	log.D(l, "Rebuilding clan scores...")
+	err = models.RebuildClanScores(db, game)
+	if err != nil {
+		panic(err)
+	}
+	log.I(l, "Clan scores rebuilt successfully.")
+}
@@ -0,0 +1,105 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+var apiKeyGameID string
+var apiKeyName string
+var apiKeyRole string
+var apiKeyDebug bool
+var apiKeyQuiet bool
+
+// CreateGameAPIKey creates an API key with the given role for a game and returns it along with the key,
+// which can't be retrieved again
+func CreateGameAPIKey(gameID, name, role string, debug, quiet bool) (*models.APIKey, string, error) {
+	InitConfig()
+	l := getTransferLogger(debug, quiet)
+	cmdL := l.With(
+		zap.String("source", "createAPIKeyCmd"),
+		zap.String("operation", "Run"),
+		zap.String("gameID", gameID),
+		zap.String("role", role),
+	)
+
+	db, err := getTransferDatabase(cmdL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	_, err = models.GetGameByPublicID(db, gameID)
+	if err != nil {
+		log.E(cmdL, "Failed to retrieve game.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, "", err
+	}
+
+	log.I(cmdL, "Creating API key...")
+	apiKey, key, err := models.CreateAPIKey(db, gameID, name, role)
+	if err != nil {
+		log.E(cmdL, "Failed to create API key.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, "", err
+	}
+
+	log.I(cmdL, "API key created successfully.", func(cm log.CM) {
+		cm.Write(zap.String("apiKeyPublicID", apiKey.PublicID))
+	})
+	return apiKey, key, nil
+}
+
+// createAPIKeyCmd represents the create-api-key command
+var createAPIKeyCmd = &cobra.Command{
+	Use:   "create-api-key",
+	Short: "Creates an API key for a game",
+	Long: `This command creates an API key for a game and prints it to stdout.
+
+API keys are only accepted when apiKeys.enabled is true. They are sent as a bearer token and can
+only call the routes of their game allowed by their role:
+
+  admin     manages the game, its hooks and API keys, besides everything the server role does
+  server    runs every player, clan and membership operation of the game
+  readonly  only retrieves the data of the game
+
+Only the hash of the key is stored, so it can't be retrieved again. Use it to create the first
+admin key of a game, since the API key routes require admin credentials.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if apiKeyGameID == "" {
+			fmt.Fprintln(os.Stderr, "The game id is required.")
+			os.Exit(1)
+		}
+		apiKey, key, err := CreateGameAPIKey(apiKeyGameID, apiKeyName, apiKeyRole, apiKeyDebug, apiKeyQuiet)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "API key %s (%s) created for game %s:\n", apiKey.PublicID, apiKey.Role, apiKeyGameID)
+		fmt.Println(key)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(createAPIKeyCmd)
+
+	createAPIKeyCmd.Flags().StringVarP(&apiKeyGameID, "game", "g", "", "Public ID of the game of the API key")
+	createAPIKeyCmd.Flags().StringVarP(&apiKeyName, "name", "n", "", "Name of the API key, e.g. the service that uses it")
+	createAPIKeyCmd.Flags().StringVarP(&apiKeyRole, "role", "r", models.APIKeyRoleServer, "Role of the API key (admin, server or readonly)")
+	createAPIKeyCmd.Flags().BoolVarP(&apiKeyDebug, "debug", "d", false, "Debug mode")
+	createAPIKeyCmd.Flags().BoolVarP(&apiKeyQuiet, "quiet", "q", false, "Quiet mode (log level error)")
+}
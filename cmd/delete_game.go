@@ -0,0 +1,282 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/es"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/topfreegames/khan/mongo"
+	"github.com/uber-go/zap"
+	"gopkg.in/olivere/elastic.v5"
+)
+
+var deleteGameID string
+var deleteGameFile string
+var deleteGameSkipExport bool
+var deleteGameBatchSize int
+var deleteGameDebug bool
+var deleteGameQuiet bool
+
+func exportGameBeforeDeletion(db models.DB, gameID, file string, batchSize int, l zap.Logger) error {
+	var w io.Writer = os.Stdout
+	if file != "-" {
+		// an existing export is never overwritten, since it may be the only
+		// copy of data already deleted by a previous run
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+
+	// the export is the only record of the game left, so deleted clans and memberships are kept
+	stats, err := models.ExportGame(db, gameID, bw, batchSize, true)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		return err
+	}
+
+	log.I(l, "Game data exported successfully.", func(cm log.CM) {
+		cm.Write(
+			zap.Int("Players", stats.Players),
+			zap.Int("Clans", stats.Clans),
+			zap.Int("Memberships", stats.Memberships),
+		)
+	})
+	return nil
+}
+
+func deleteGameElasticSearchIndex(gameID string, debug bool, l zap.Logger) error {
+	if !viper.GetBool("elasticsearch.enabled") {
+		return nil
+	}
+
+	client := es.GetClient(
+		viper.GetString("elasticsearch.host"),
+		viper.GetInt("elasticsearch.port"),
+		viper.GetString("elasticsearch.index"),
+		viper.GetBool("elasticsearch.sniff"),
+		l,
+		debug,
+		nil,
+	)
+	index := client.GetIndexName(gameID)
+	_, err := client.Client.DeleteIndex(index).Do(context.Background())
+	if err != nil && !elastic.IsNotFound(err) {
+		return err
+	}
+
+	log.I(l, "Game elasticsearch index deleted successfully.", func(cm log.CM) {
+		cm.Write(zap.String("index", index))
+	})
+	return nil
+}
+
+func dropGameMongoCollection(gameID string, l zap.Logger) error {
+	if !viper.GetBool("mongodb.enabled") {
+		return nil
+	}
+
+	mongoDB, err := newMongo(viper.GetViper())
+	if err != nil {
+		return err
+	}
+	var res struct {
+		OK int `bson:"ok"`
+	}
+	err = mongoDB.Run(mongo.GetDropClansCollectionCommand(gameID), &res)
+	// the collection does not exist if the game never had clans
+	if err != nil && !strings.Contains(err.Error(), "ns not found") {
+		return err
+	}
+
+	log.I(l, "Game mongo collection dropped successfully.")
+	return nil
+}
+
+// invalidateGameCaches tells the running Khan processes to remove keys from the cache invalidated
+// through the channel in channelKey. Failures are only logged, since the caches expire on their own
+func invalidateGameCaches(channelKey string, keys []string, l zap.Logger) {
+	err := api.PublishCacheInvalidation(viper.GetViper(), channelKey, keys...)
+	if err != nil {
+		log.W(l, "Failed to invalidate game caches. They will expire with their TTL.", func(cm log.CM) {
+			cm.Write(zap.String("channelKey", channelKey), zap.Error(err))
+		})
+	}
+}
+
+// DeleteGameData archives a game, so its data can't be changed anymore, exports it to a JSON Lines file
+// ("-" for stdout, "" to skip the export) and then deletes it from elasticsearch, mongo and the database.
+// If the deletion fails, running it again resumes from where it stopped
+func DeleteGameData(gameID, file string, batchSize int, debug, quiet bool) (*models.GameDeletionStats, error) {
+	InitConfig()
+	l := getTransferLogger(debug, quiet)
+	cmdL := l.With(
+		zap.String("source", "deleteGameCmd"),
+		zap.String("operation", "Run"),
+		zap.String("gameID", gameID),
+		zap.String("file", file),
+	)
+
+	db, err := getTransferDatabase(cmdL)
+	if err != nil {
+		return nil, err
+	}
+
+	log.I(cmdL, "Archiving game...")
+	_, err = models.ArchiveGame(db, gameID)
+	if err != nil {
+		log.E(cmdL, "Failed to archive game.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, err
+	}
+	invalidateGameCaches(api.ArchivedGamesInvalidationChannelKey, []string{gameID}, cmdL)
+
+	// the cached API keys are found by their hashes, which are gone after the deletion
+	apiKeys, err := models.GetAPIKeysByGameID(db, gameID)
+	if err != nil {
+		log.E(cmdL, "Failed to retrieve game API keys.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, err
+	}
+	apiKeyHashes := make([]string, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		apiKeyHashes[i] = apiKey.KeyHash
+	}
+
+	if file != "" {
+		log.I(cmdL, "Exporting game data...")
+		err = exportGameBeforeDeletion(db, gameID, file, batchSize, cmdL)
+		if err != nil {
+			log.E(cmdL, "Failed to export game data.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return nil, err
+		}
+	}
+
+	log.I(cmdL, "Deleting game elasticsearch index...")
+	err = deleteGameElasticSearchIndex(gameID, debug, cmdL)
+	if err != nil {
+		log.E(cmdL, "Failed to delete game elasticsearch index.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, err
+	}
+
+	log.I(cmdL, "Dropping game mongo collection...")
+	err = dropGameMongoCollection(gameID, cmdL)
+	if err != nil {
+		log.E(cmdL, "Failed to drop game mongo collection.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, err
+	}
+
+	log.I(cmdL, "Deleting game data...")
+	stats, err := models.DeleteGame(db, gameID, batchSize, func(stats *models.GameDeletionStats) {
+		log.I(cmdL, "Deleting game data...", func(cm log.CM) {
+			cm.Write(
+				zap.Int("Memberships", stats.Memberships),
+				zap.Int("Clans", stats.Clans),
+				zap.Int("Players", stats.Players),
+				zap.Int("Hooks", stats.Hooks),
+				zap.Int("IdempotencyKeys", stats.IdempotencyKeys),
+				zap.Int("AuditEntries", stats.AuditEntries),
+				zap.Int("APIKeys", stats.APIKeys),
+			)
+		})
+	})
+	if err != nil {
+		log.E(cmdL, "Failed to delete game data.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, err
+	}
+
+	log.I(cmdL, "Invalidating game caches...")
+	invalidateGameCaches(api.GameHooksInvalidationChannelKey, []string{gameID}, cmdL)
+	invalidateGameCaches(api.APIKeysInvalidationChannelKey, apiKeyHashes, cmdL)
+	invalidateGameCaches(api.ArchivedGamesInvalidationChannelKey, []string{gameID}, cmdL)
+
+	log.I(cmdL, "Game deleted successfully.", func(cm log.CM) {
+		cm.Write(
+			zap.Int("Memberships", stats.Memberships),
+			zap.Int("Clans", stats.Clans),
+			zap.Int("Players", stats.Players),
+			zap.Int("Hooks", stats.Hooks),
+			zap.Int("IdempotencyKeys", stats.IdempotencyKeys),
+			zap.Int("AuditEntries", stats.AuditEntries),
+			zap.Int("APIKeys", stats.APIKeys),
+		)
+	})
+	return stats, nil
+}
+
+// deleteGameCmd represents the delete-game command
+var deleteGameCmd = &cobra.Command{
+	Use:   "delete-game",
+	Short: "Archives, exports and deletes a game",
+	Long: `This command permanently deletes a game and all of its data.
+
+The game is archived first, so the API rejects any further changes to it. Its players, clans and
+memberships are then exported as JSON Lines, in the same format as the export command, before the
+game elasticsearch index, mongo collection and database rows are deleted. Rows are deleted in
+batches and the progress is logged after each batch.
+
+If the command fails after the game data was exported, run it again with --skip-export to resume
+the deletion. The export file is never overwritten.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if deleteGameID == "" {
+			fmt.Fprintln(os.Stderr, "The game id is required.")
+			os.Exit(1)
+		}
+		if deleteGameFile == "" && !deleteGameSkipExport {
+			fmt.Fprintln(os.Stderr, "The export file is required (use --skip-export to resume a deletion).")
+			os.Exit(1)
+		}
+		if deleteGameSkipExport {
+			deleteGameFile = ""
+		}
+		stats, err := DeleteGameData(deleteGameID, deleteGameFile, deleteGameBatchSize, deleteGameDebug, deleteGameQuiet)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Game %s deleted:\n%s", deleteGameID, stats.GetStats())
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(deleteGameCmd)
+
+	deleteGameCmd.Flags().StringVarP(&deleteGameID, "game", "g", "", "Public ID of the game to delete")
+	deleteGameCmd.Flags().StringVarP(&deleteGameFile, "file", "f", "", "JSON Lines file to export the game data to (- for stdout)")
+	deleteGameCmd.Flags().BoolVar(&deleteGameSkipExport, "skip-export", false, "Do not export the game data, to resume a failed deletion")
+	deleteGameCmd.Flags().IntVarP(&deleteGameBatchSize, "batch", "b", models.DefaultGameDeletionBatchSize, "Number of rows read or deleted per query")
+	deleteGameCmd.Flags().BoolVarP(&deleteGameDebug, "debug", "d", false, "Debug mode")
+	deleteGameCmd.Flags().BoolVarP(&deleteGameQuiet, "quiet", "q", false, "Quiet mode (log level error)")
+}
@@ -0,0 +1,125 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	"github.com/spf13/viper"
+	. "github.com/topfreegames/khan/cmd"
+	"github.com/topfreegames/khan/models"
+	"github.com/topfreegames/khan/util"
+)
+
+var _ = Describe("Delete Game Command", func() {
+	var db models.DB
+	var err error
+	var dir string
+
+	BeforeEach(func() {
+		ConfigFile = "../config/test.yaml"
+		InitConfig()
+
+		host := viper.GetString("postgres.host")
+		user := viper.GetString("postgres.user")
+		dbName := viper.GetString("postgres.dbname")
+		password := viper.GetString("postgres.password")
+		port := viper.GetInt("postgres.port")
+		sslMode := viper.GetString("postgres.sslMode")
+
+		db, err = models.GetDB(host, user, port, sslMode, dbName, password)
+		Expect(err).NotTo(HaveOccurred())
+
+		dir, err = ioutil.TempDir("", "khan-delete-game")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("Should export and delete a game", func() {
+		game, _, _, _, _, err := models.GetClanWithMemberships(db, 2, 0, 0, 1, "", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		file := filepath.Join(dir, "export.jsonl")
+		stats, err := DeleteGameData(game.PublicID, file, 2, false, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.Players).To(Equal(4))
+		Expect(stats.Clans).To(Equal(1))
+		Expect(stats.Memberships).To(Equal(3))
+
+		data, err := ioutil.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		Expect(lines).To(HaveLen(8))
+
+		_, err = models.GetGameByPublicID(db, game.PublicID)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should export deleted memberships, which are not imported again", func() {
+		game, _, owner, _, memberships, err := models.GetClanWithMemberships(db, 2, 0, 0, 0, "", "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec(
+			"UPDATE memberships SET deleted_at=$2, deleted_by=$3 WHERE id=$1",
+			memberships[0].ID, util.NowMilli(), owner.ID,
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		file := filepath.Join(dir, "export.jsonl")
+		_, err = DeleteGameData(game.PublicID, file, 2, false, true)
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := ioutil.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Count(string(data), `"deletedAt"`)).To(Equal(1))
+		Expect(string(data)).To(ContainSubstring(`"deletedByPublicID":"` + owner.PublicID + `"`))
+
+		newGame := models.GameFactory.MustCreateWithOption(map[string]interface{}{
+			"PublicID":          uuid.NewV4().String(),
+			"MaxClansPerPlayer": 10,
+		}).(*models.Game)
+		err = db.Insert(newGame)
+		Expect(err).NotTo(HaveOccurred())
+
+		stats, err := ImportGameData(newGame.PublicID, file, "", 2, false, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.Memberships).To(Equal(1))
+	})
+
+	It("Should not overwrite an existing export", func() {
+		game, _, _, _, _, err := models.GetClanWithMemberships(db, 1, 0, 0, 0, "", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		file := filepath.Join(dir, "export.jsonl")
+		err = ioutil.WriteFile(file, []byte("previous export\n"), 0644)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = DeleteGameData(game.PublicID, file, 2, false, true)
+		Expect(err).To(HaveOccurred())
+
+		data, err := ioutil.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("previous export\n"))
+
+		dbGame, err := models.GetGameByPublicID(db, game.PublicID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dbGame.ArchivedAt).To(BeNumerically(">", 0))
+
+		stats, err := DeleteGameData(game.PublicID, "", 2, false, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.Players).To(Equal(2))
+	})
+})
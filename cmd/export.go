@@ -0,0 +1,118 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+var exportGameID string
+var exportFile string
+var exportBatchSize int
+var exportDebug bool
+var exportQuiet bool
+
+// ExportGameData exports players, clans and memberships of a game to a JSON Lines file ("-" for stdout)
+func ExportGameData(gameID, file string, batchSize int, debug, quiet bool) (*models.TransferStats, error) {
+	InitConfig()
+	l := getTransferLogger(debug, quiet)
+	cmdL := l.With(
+		zap.String("source", "exportCmd"),
+		zap.String("operation", "Run"),
+		zap.String("gameID", gameID),
+		zap.String("file", file),
+	)
+
+	db, err := getTransferDatabase(cmdL)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = models.GetGameByPublicID(db, gameID)
+	if err != nil {
+		log.E(cmdL, "Failed to load game.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, err
+	}
+
+	var w io.Writer = os.Stdout
+	if file != "" && file != "-" {
+		f, err := os.Create(file)
+		if err != nil {
+			log.E(cmdL, "Failed to create export file.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return nil, err
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+
+	stats, err := models.ExportGame(db, gameID, bw, batchSize, false)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		log.E(cmdL, "Failed to export game data.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, err
+	}
+
+	log.I(cmdL, "Game data exported successfully.", func(cm log.CM) {
+		cm.Write(
+			zap.Int("Players", stats.Players),
+			zap.Int("Clans", stats.Clans),
+			zap.Int("Memberships", stats.Memberships),
+		)
+	})
+	return stats, nil
+}
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports players, clans and memberships of a game",
+	Long: `This command exports players, clans and memberships of a game as JSON Lines.
+
+Each line is a JSON object with a "type" of player, clan or membership. Players are written first,
+followed by clans and then memberships, so the output can be fed directly to the import command.
+Deleted clans and memberships are not exported.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if exportGameID == "" {
+			fmt.Fprintln(os.Stderr, "The game id is required.")
+			os.Exit(1)
+		}
+		_, err := ExportGameData(exportGameID, exportFile, exportBatchSize, exportDebug, exportQuiet)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVarP(&exportGameID, "game", "g", "", "Public ID of the game to export")
+	exportCmd.Flags().StringVarP(&exportFile, "file", "f", "-", "JSON Lines file to write (- for stdout)")
+	exportCmd.Flags().IntVarP(&exportBatchSize, "batch", "b", models.DefaultTransferBatchSize, "Number of records read per query")
+	exportCmd.Flags().BoolVarP(&exportDebug, "debug", "d", false, "Debug mode")
+	exportCmd.Flags().BoolVarP(&exportQuiet, "quiet", "q", false, "Quiet mode (log level error)")
+}
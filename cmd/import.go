@@ -0,0 +1,200 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/topfreegames/extensions/gorp/interfaces"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+var importGameID string
+var importFile string
+var importCheckpointFile string
+var importBatchSize int
+var importDebug bool
+var importQuiet bool
+
+func getTransferLogger(debug, quiet bool) zap.Logger {
+	ll := zap.InfoLevel
+	if debug {
+		ll = zap.DebugLevel
+	}
+	if quiet {
+		ll = zap.ErrorLevel
+	}
+	// logs go to stderr so they don't get mixed with data streamed through stdout
+	return zap.New(
+		zap.NewJSONEncoder(), // drop timestamps in tests
+		ll,
+		zap.Output(zap.AddSync(os.Stderr)),
+	)
+}
+
+func getTransferDatabase(l zap.Logger) (interfaces.Database, error) {
+	host := viper.GetString("postgres.host")
+	user := viper.GetString("postgres.user")
+	dbName := viper.GetString("postgres.dbname")
+	password := viper.GetString("postgres.password")
+	port := viper.GetInt("postgres.port")
+	sslMode := viper.GetString("postgres.sslMode")
+
+	db, err := models.GetDB(host, user, port, sslMode, dbName, password)
+	if err != nil {
+		log.E(l, "Failed to connect to DB.", func(cm log.CM) {
+			cm.Write(
+				zap.Error(err),
+				zap.String("host", host),
+				zap.String("user", user),
+				zap.Int("port", port),
+				zap.String("sslMode", sslMode),
+				zap.String("dbName", dbName),
+			)
+		})
+		return nil, err
+	}
+	return db, nil
+}
+
+func readImportCheckpoint(checkpointFile string) (int, error) {
+	if checkpointFile == "" {
+		return 0, nil
+	}
+	data, err := ioutil.ReadFile(checkpointFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// ImportGameData imports players, clans and memberships of a game from a JSON Lines file ("-" for stdin).
+// If checkpointFile is given, the number of committed lines is saved to it and a new run resumes from there
+func ImportGameData(gameID, file, checkpointFile string, batchSize int, debug, quiet bool) (*models.TransferStats, error) {
+	InitConfig()
+	l := getTransferLogger(debug, quiet)
+	cmdL := l.With(
+		zap.String("source", "importCmd"),
+		zap.String("operation", "Run"),
+		zap.String("gameID", gameID),
+		zap.String("file", file),
+	)
+
+	db, err := getTransferDatabase(cmdL)
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := models.GetGameByPublicID(db, gameID)
+	if err != nil {
+		log.E(cmdL, "Failed to load game.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, err
+	}
+
+	checkpoint, err := readImportCheckpoint(checkpointFile)
+	if err != nil {
+		log.E(cmdL, "Failed to read checkpoint.", func(cm log.CM) {
+			cm.Write(zap.Error(err), zap.String("checkpointFile", checkpointFile))
+		})
+		return nil, err
+	}
+	if checkpoint > 0 {
+		log.I(cmdL, fmt.Sprintf("Resuming import after line %d.", checkpoint))
+	}
+
+	var r io.Reader = os.Stdin
+	if file != "" && file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			log.E(cmdL, "Failed to open import file.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	options := &models.ImportOptions{
+		BatchSize:  batchSize,
+		Checkpoint: checkpoint,
+	}
+	if checkpointFile != "" {
+		options.OnCheckpoint = func(lines int) error {
+			return ioutil.WriteFile(checkpointFile, []byte(strconv.Itoa(lines)), 0644)
+		}
+	}
+
+	stats, err := models.ImportGame(db, game, r, options, l)
+	if err != nil {
+		log.E(cmdL, "Failed to import game data.", func(cm log.CM) {
+			cm.Write(zap.Error(err), zap.Int("committedLines", stats.Lines))
+		})
+		return stats, err
+	}
+
+	log.I(cmdL, "Game data imported successfully.", func(cm log.CM) {
+		cm.Write(
+			zap.Int("Players", stats.Players),
+			zap.Int("Clans", stats.Clans),
+			zap.Int("Memberships", stats.Memberships),
+			zap.Int("Lines", stats.Lines),
+		)
+	})
+	return stats, nil
+}
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Imports players, clans and memberships of a game",
+	Long: `This command imports players, clans and memberships of a game from a JSON Lines file,
+usually generated by the export command.
+
+Records are validated against the game's membership levels and limits and are written in batched
+transactions. Existing players, clans and memberships with the same public ids are updated.
+When a checkpoint file is given, the import can be resumed from the last committed batch.
+Membership and ownership counts are recomputed once all records are imported.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if importGameID == "" {
+			fmt.Fprintln(os.Stderr, "The game id is required.")
+			os.Exit(1)
+		}
+		_, err := ImportGameData(importGameID, importFile, importCheckpointFile, importBatchSize, importDebug, importQuiet)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVarP(&importGameID, "game", "g", "", "Public ID of the game to import into")
+	importCmd.Flags().StringVarP(&importFile, "file", "f", "-", "JSON Lines file to import (- for stdin)")
+	importCmd.Flags().StringVar(&importCheckpointFile, "checkpoint", "", "File used to save and resume the import progress")
+	importCmd.Flags().IntVarP(&importBatchSize, "batch", "b", models.DefaultTransferBatchSize, "Number of records per transaction")
+	importCmd.Flags().BoolVarP(&importDebug, "debug", "d", false, "Debug mode")
+	importCmd.Flags().BoolVarP(&importQuiet, "quiet", "q", false, "Quiet mode (log level error)")
+}
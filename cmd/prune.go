@@ -49,6 +49,7 @@ func PruneStaleData(debug, quiet bool) (*models.PruneStats, error) {
 
 func executePruning(debug, quiet bool) (*models.PruneStats, error) {
 	InitConfig()
+	viper.SetDefault("webhooks.deliveryLog.retention", 7*24*3600)
 	ll := zap.InfoLevel
 	if debug {
 		ll = zap.DebugLevel
@@ -112,6 +113,50 @@ func executePruning(debug, quiet bool) (*models.PruneStats, error) {
 		})
 		log.D(cmdL, "Pruning stale data...")
 
+		// the audit trail is pruned even if the game does not prune memberships
+		auditEntriesRetention := viper.GetInt("audit.retention")
+		if retention, ok := game.Metadata["auditEntriesRetention"].(float64); ok {
+			auditEntriesRetention = int(retention)
+		}
+		if auditEntriesRetention > 0 {
+			auditEntriesPruned, err := models.PruneAuditEntries(db, game.PublicID, auditEntriesRetention)
+			if err != nil {
+				log.E(cmdL, "Failed to prune audit entries for game.", func(cm log.CM) {
+					cm.Write(zap.Error(err), zap.String("gameID", game.PublicID))
+				})
+				return nil, err
+			}
+			log.I(cmdL, "Audit entries for game pruned successfully.", func(cm log.CM) {
+				cm.Write(
+					zap.Int("AuditEntriesPruned", auditEntriesPruned),
+					zap.String("GameID", game.PublicID),
+				)
+			})
+			totals.AuditEntriesPruned += auditEntriesPruned
+		}
+
+		// so are the hook deliveries, which are written for every attempt
+		hookDeliveriesRetention := viper.GetInt("webhooks.deliveryLog.retention")
+		if retention, ok := game.Metadata["hookDeliveriesRetention"].(float64); ok {
+			hookDeliveriesRetention = int(retention)
+		}
+		if hookDeliveriesRetention > 0 {
+			hookDeliveriesPruned, err := models.PruneHookDeliveries(db, game.PublicID, hookDeliveriesRetention)
+			if err != nil {
+				log.E(cmdL, "Failed to prune hook deliveries for game.", func(cm log.CM) {
+					cm.Write(zap.Error(err), zap.String("gameID", game.PublicID))
+				})
+				return nil, err
+			}
+			log.I(cmdL, "Hook deliveries for game pruned successfully.", func(cm log.CM) {
+				cm.Write(
+					zap.Int("HookDeliveriesPruned", hookDeliveriesPruned),
+					zap.String("GameID", game.PublicID),
+				)
+			})
+			totals.HookDeliveriesPruned += hookDeliveriesPruned
+		}
+
 		pendingApplicationsExpiration := game.Metadata["pendingApplicationsExpiration"]
 		pendingInvitesExpiration := game.Metadata["pendingInvitesExpiration"]
 		deniedMembershipsExpiration := game.Metadata["deniedMembershipsExpiration"]
@@ -168,12 +213,25 @@ func executePruning(debug, quiet bool) (*models.PruneStats, error) {
 		totals.DeletedMembershipsPruned += stats.DeletedMembershipsPruned
 		totals.DeniedMembershipsPruned += stats.DeniedMembershipsPruned
 	}
+
+	log.D(cmdL, "Pruning expired idempotency keys...")
+	totals.IdempotencyKeysPruned, err = models.PruneExpiredIdempotencyKeys(db)
+	if err != nil {
+		log.E(cmdL, "Failed to prune expired idempotency keys.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, err
+	}
+
 	log.I(cmdL, "Stale data pruned successfully.", func(cm log.CM) {
 		cm.Write(
 			zap.Int("PendingApplicationsPruned", totals.PendingApplicationsPruned),
 			zap.Int("PendingInvitesPruned", totals.PendingInvitesPruned),
 			zap.Int("DeniedMembershipsPruned", totals.DeniedMembershipsPruned),
 			zap.Int("DeletedMembershipsPruned", totals.DeletedMembershipsPruned),
+			zap.Int("IdempotencyKeysPruned", totals.IdempotencyKeysPruned),
+			zap.Int("AuditEntriesPruned", totals.AuditEntriesPruned),
+			zap.Int("HookDeliveriesPruned", totals.HookDeliveriesPruned),
 		)
 	})
 	return totals, nil
@@ -9,12 +9,14 @@ package cmd_test
 
 import (
 	"math/rand"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/spf13/viper"
 	. "github.com/topfreegames/khan/cmd"
 	"github.com/topfreegames/khan/models"
+	"github.com/topfreegames/khan/util"
 )
 
 var _ = Describe("Prune Command", func() {
@@ -111,5 +113,50 @@ var _ = Describe("Prune Command", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(int(count)).To(Equal((totalApps + totalInvites + totalDenies + totalDeletes) * 3))
 		})
+
+		It("Should prune old hook deliveries", func() {
+			_, err := db.Exec("TRUNCATE TABLE hook_deliveries")
+			Expect(err).NotTo(HaveOccurred())
+
+			hook, err := models.CreateHookFactory(db, "", models.GameUpdatedHook, "http://test/deliveries")
+			Expect(err).NotTo(HaveOccurred())
+			retainedHook, err := models.CreateHookFactory(db, "", models.GameUpdatedHook, "http://test/deliveries")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = db.Exec(
+				`UPDATE games SET metadata='{"hookDeliveriesRetention": 3600}' WHERE public_id=$1`,
+				retainedHook.GameID,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			// the default retention is a week and the retained hook's game keeps deliveries for an hour
+			createDelivery := func(hook *models.Hook, age time.Duration) {
+				delivery := &models.HookDelivery{
+					HookID:    hook.ID,
+					GameID:    hook.GameID,
+					EventID:   "event-id",
+					EventType: hook.EventType,
+					Attempt:   1,
+				}
+				err := models.CreateHookDelivery(db, delivery)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = db.Exec(
+					"UPDATE hook_deliveries SET created_at=$1 WHERE id=$2",
+					util.NowMilli()-int64(age/time.Millisecond), delivery.ID,
+				)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			createDelivery(hook, 8*24*time.Hour)
+			createDelivery(hook, 2*time.Hour)
+			createDelivery(retainedHook, 2*time.Hour)
+			createDelivery(retainedHook, time.Minute)
+
+			stats, err := PruneStaleData(false, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.HookDeliveriesPruned).To(Equal(2))
+
+			count, err := db.SelectInt("select count(*) from hook_deliveries")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(int(count)).To(Equal(2))
+		})
 	})
 })
@@ -0,0 +1,249 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package cmd_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	"github.com/spf13/viper"
+	. "github.com/topfreegames/khan/cmd"
+	"github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Import and Export Commands", func() {
+	var db models.DB
+	var err error
+	var dir string
+
+	createGame := func() *models.Game {
+		game := models.GameFactory.MustCreateWithOption(map[string]interface{}{
+			"PublicID":          uuid.NewV4().String(),
+			"MaxClansPerPlayer": 10,
+		}).(*models.Game)
+		err := db.Insert(game)
+		Expect(err).NotTo(HaveOccurred())
+		return game
+	}
+
+	writeLines := func(lines ...string) string {
+		file := filepath.Join(dir, fmt.Sprintf("%s.jsonl", uuid.NewV4().String()))
+		err := ioutil.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+		Expect(err).NotTo(HaveOccurred())
+		return file
+	}
+
+	BeforeEach(func() {
+		ConfigFile = "../config/test.yaml"
+		InitConfig()
+
+		host := viper.GetString("postgres.host")
+		user := viper.GetString("postgres.user")
+		dbName := viper.GetString("postgres.dbname")
+		password := viper.GetString("postgres.password")
+		port := viper.GetInt("postgres.port")
+		sslMode := viper.GetString("postgres.sslMode")
+
+		db, err = models.GetDB(host, user, port, sslMode, dbName, password)
+		Expect(err).NotTo(HaveOccurred())
+
+		dir, err = ioutil.TempDir("", "khan-transfer")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Describe("Export Cmd", func() {
+		It("Should export players, clans and memberships of a game", func() {
+			game, clan, _, _, _, err := models.GetClanWithMemberships(db, 2, 1, 1, 1, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			file := filepath.Join(dir, "export.jsonl")
+			stats, err := ExportGameData(game.PublicID, file, 2, false, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.Players).To(Equal(6))
+			Expect(stats.Clans).To(Equal(1))
+			Expect(stats.Memberships).To(Equal(5))
+
+			data, err := ioutil.ReadFile(file)
+			Expect(err).NotTo(HaveOccurred())
+			lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+			Expect(lines).To(HaveLen(12))
+			Expect(lines[0]).To(ContainSubstring(`"type":"player"`))
+			Expect(lines[6]).To(ContainSubstring(`"type":"clan"`))
+			Expect(lines[6]).To(ContainSubstring(clan.PublicID))
+			Expect(lines[11]).To(ContainSubstring(`"type":"membership"`))
+		})
+	})
+
+	Describe("Import Cmd", func() {
+		It("Should import an exported game into another game", func() {
+			sourceGame, clan, owner, _, _, err := models.GetClanWithMemberships(db, 2, 1, 1, 1, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			file := filepath.Join(dir, "export.jsonl")
+			_, err = ExportGameData(sourceGame.PublicID, file, 0, false, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			game := createGame()
+			stats, err := ImportGameData(game.PublicID, file, "", 2, false, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.Players).To(Equal(6))
+			Expect(stats.Clans).To(Equal(1))
+			Expect(stats.Memberships).To(Equal(5))
+			Expect(stats.Lines).To(Equal(12))
+
+			dbClan, err := models.GetClanByPublicID(db, game.PublicID, clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.Name).To(Equal(clan.Name))
+			Expect(dbClan.MembershipCount).To(Equal(3))
+
+			dbOwner, err := models.GetPlayerByPublicID(db, game.PublicID, owner.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbOwner.ID).To(Equal(dbClan.OwnerID))
+			Expect(dbOwner.OwnershipCount).To(Equal(1))
+
+			count, err := db.SelectInt("SELECT COUNT(*) FROM memberships WHERE game_id=$1", game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeEquivalentTo(5))
+
+			count, err = db.SelectInt("SELECT COUNT(*) FROM players WHERE game_id=$1 AND membership_count=1", game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeEquivalentTo(2))
+		})
+
+		It("Should be idempotent when importing the same file twice", func() {
+			sourceGame, _, _, _, _, err := models.GetClanWithMemberships(db, 2, 0, 0, 1, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			file := filepath.Join(dir, "export.jsonl")
+			_, err = ExportGameData(sourceGame.PublicID, file, 0, false, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			game := createGame()
+			_, err = ImportGameData(game.PublicID, file, "", 0, false, true)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = ImportGameData(game.PublicID, file, "", 0, false, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			count, err := db.SelectInt("SELECT COUNT(*) FROM players WHERE game_id=$1", game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeEquivalentTo(4))
+
+			count, err = db.SelectInt("SELECT COUNT(*) FROM memberships WHERE game_id=$1", game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeEquivalentTo(3))
+		})
+
+		It("Should resume an import from its checkpoint", func() {
+			game := createGame()
+			file := writeLines(
+				`{"type":"player","publicID":"player-1","name":"Player 1"}`,
+				`{"type":"player","publicID":"player-2","name":"Player 2"}`,
+				`{"type":"clan","publicID":"clan-1","name":"Clan 1","ownerPublicID":"player-1"}`,
+				`{"type":"membership","playerPublicID":"player-2","clanPublicID":"clan-1","level":"Member","approved":true}`,
+			)
+			checkpointFile := filepath.Join(dir, "checkpoint")
+			err := ioutil.WriteFile(checkpointFile, []byte("1"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			stats, err := ImportGameData(game.PublicID, file, checkpointFile, 1, false, true)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Could not import line 3: Player was not found with id: player-1"))
+			Expect(stats.Lines).To(Equal(2))
+			Expect(stats.Players).To(Equal(1))
+			Expect(stats.Clans).To(Equal(0))
+
+			data, err := ioutil.ReadFile(checkpointFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal("2"))
+
+			_, err = models.CreatePlayer(db, game, game.PublicID, "player-1", "Player 1", map[string]interface{}{}, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			stats, err = ImportGameData(game.PublicID, file, checkpointFile, 1, false, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.Players).To(Equal(0))
+			Expect(stats.Clans).To(Equal(1))
+			Expect(stats.Memberships).To(Equal(1))
+			Expect(stats.Lines).To(Equal(4))
+
+			dbClan, err := models.GetClanByPublicID(db, game.PublicID, "clan-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.MembershipCount).To(Equal(2))
+		})
+
+		It("Should not commit a batch with an invalid membership level", func() {
+			game := createGame()
+			file := writeLines(
+				`{"type":"player","publicID":"player-1","name":"Player 1"}`,
+				`{"type":"player","publicID":"player-2","name":"Player 2"}`,
+				`{"type":"clan","publicID":"clan-1","name":"Clan 1","ownerPublicID":"player-1"}`,
+				`{"type":"membership","playerPublicID":"player-2","clanPublicID":"clan-1","level":"King","approved":true}`,
+			)
+
+			stats, err := ImportGameData(game.PublicID, file, "", 10, false, true)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Could not import line 4:"))
+			Expect(stats.Lines).To(Equal(0))
+			Expect(stats.Players).To(Equal(0))
+			Expect(stats.Clans).To(Equal(0))
+			Expect(stats.Memberships).To(Equal(0))
+
+			count, err := db.SelectInt("SELECT COUNT(*) FROM players WHERE game_id=$1", game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeEquivalentTo(0))
+		})
+
+		It("Should fail if a clan would exceed the game max members", func() {
+			game := models.GameFactory.MustCreateWithOption(map[string]interface{}{
+				"PublicID":   uuid.NewV4().String(),
+				"MaxMembers": 2,
+			}).(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			file := writeLines(
+				`{"type":"player","publicID":"player-1","name":"Player 1"}`,
+				`{"type":"player","publicID":"player-2","name":"Player 2"}`,
+				`{"type":"player","publicID":"player-3","name":"Player 3"}`,
+				`{"type":"clan","publicID":"clan-1","name":"Clan 1","ownerPublicID":"player-1"}`,
+				`{"type":"membership","playerPublicID":"player-2","clanPublicID":"clan-1","level":"Member","approved":true}`,
+				`{"type":"membership","playerPublicID":"player-3","clanPublicID":"clan-1","level":"Member","approved":true}`,
+			)
+
+			_, err = ImportGameData(game.PublicID, file, "", 10, false, true)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Could not import line 6: Clan clan-1 reached max members"))
+		})
+
+		It("Should not import into an archived game", func() {
+			game := createGame()
+			_, err := models.ArchiveGame(db, game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			file := writeLines(`{"type":"player","publicID":"player-1","name":"Player 1"}`)
+
+			stats, err := ImportGameData(game.PublicID, file, "", 10, false, true)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&models.GameArchivedError{}))
+			Expect(stats.Players).To(Equal(0))
+
+			count, err := db.SelectInt("SELECT COUNT(*) FROM players WHERE game_id=$1", game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeEquivalentTo(0))
+		})
+	})
+})
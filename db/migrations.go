@@ -18,6 +18,21 @@
 // migrations/20160729184159_CreateCooldownAfterInviteField.sql
 // migrations/20160819145352_CreateHookTriggerFieldsMetadata.sql
 // migrations/20180517112014_ChangeIDSequenceType.sql
+// migrations/20261018100000_CreateHookSecretField.sql
+// migrations/20261018110000_CreateHookDeliveriesTable.sql
+// migrations/20261018120000_CreateHookFilterAndBodyTemplateFields.sql
+// migrations/20261018130000_CreateHookEventTypesFields.sql
+// migrations/20261018140000_CreateMembershipBanFields.sql
+// migrations/20261018150000_CreateMembershipExpiresAtField.sql
+// migrations/20261018160000_CreateIdempotencyKeysTable.sql
+// migrations/20261018170000_CreateClanAndPlayerVersionFields.sql
+// migrations/20261018180000_CreateJSONBMergePatchFunction.sql
+// migrations/20261018190000_CreateClanScoresTable.sql
+// migrations/20261018200000_CreateAuditEntriesTable.sql
+// migrations/20261018210000_CreateGameArchivedAtField.sql
+// migrations/20261018220000_CreateAPIKeysTable.sql
+// migrations/20261018230000_BackfillMembershipExpiresAtField.sql
+// migrations/20261018240000_CreateHookDeliveriesGameCreatedAtIndex.sql
 // DO NOT EDIT!
 
 package db
@@ -445,6 +460,306 @@ func migrations20180517112014_changeidsequencetypeSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations20261018100000_createhooksecretfieldSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\xce\xb1\x0e\x82\x30\x14\x05\xd0\xbd\x5f\x71\x37\x34\x86\xc5\x84\x89\x09\x2d\x4e\x15\x14\xdb\x0f\xa8\xf0\x02\x0d\x48\x49\x41\xf1\xf3\x05\xa3\x26\x26\x0e\x8e\xef\xbe\x9b\x9b\xe3\xfb\x58\x95\xd6\xf6\x04\xd5\x31\xdf\xc7\xe9\x28\x60\x5a\xf4\x94\x0f\xc6\xb6\xf0\x54\xe7\xc1\xf4\xa0\x3b\xe5\xd7\x81\x0a\x8c\x15\xb5\x18\xaa\x29\xba\x98\xd2\xe9\x67\x69\x3a\x74\xd7\x35\x86\x0a\x16\x09\x19\x67\x90\xd1\x46\xc4\xa8\xac\xad\x7b\x44\x9c\x63\x9b\x0a\xb5\x4f\xe6\x51\x47\x03\x6e\xda\xe5\x95\x76\x8b\x75\x10\x2c\x91\xa4\x12\x89\x12\x02\x3c\xde\x45\x4a\x48\x78\x5e\xc8\x66\xc8\x4b\xc5\xed\xd8\xbe\x5d\x1f\xd4\x1c\xfe\xc5\x72\xb6\x69\xa6\xef\x59\xe7\xf5\x0f\x1a\xcf\xd2\xc3\xb7\x2d\x64\x0f\x3a\x4b\x4d\xc3\x10\x01\x00\x00")
+
+func migrations20261018100000_createhooksecretfieldSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018100000_createhooksecretfieldSql,
+		"migrations/20261018100000_CreateHookSecretField.sql",
+	)
+}
+
+func migrations20261018100000_createhooksecretfieldSql() (*asset, error) {
+	bytes, err := migrations20261018100000_createhooksecretfieldSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018100000_CreateHookSecretField.sql", size: 272, mode: os.FileMode(420), modTime: time.Unix(1792286617, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018110000_createhookdeliveriestableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x92\xcf\x6e\xdb\x30\x0c\xc6\xef\x7e\x0a\xde\xd2\x60\x4d\xbc\x3f\x40\x0f\xed\x30\xcc\xb3\x95\x21\x98\xeb\x74\x8e\x03\xac\x27\x43\x91\x59\x5b\x88\x2d\x09\x92\x92\x34\x8f\xb4\xd7\xd8\x93\x4d\x4a\x9c\x2c\x58\x17\xac\xba\x89\xfc\xf1\xe3\x07\x92\xa3\x11\xac\x1a\x2a\x82\xd1\x08\x1a\x6b\x95\xb9\x0d\xc3\x9a\xdb\x66\xbd\x1c\x33\xd9\x85\x56\xaa\x27\x8d\x58\xd3\x0e\x4d\xd8\x73\x1e\x4d\x39\x43\x61\xb0\x82\xb5\xa8\x50\x83\x6d\x10\xee\xa7\x05\xb4\x87\xf0\xed\x51\xcd\x89\x6d\xb7\xdb\xb1\x54\x2e\x2a\xd7\x9a\xe1\x58\xea\x3a\xec\x29\x13\x76\xdc\x8e\xfa\x8f\xaf\x88\xa5\xda\x69\x5e\x37\x16\x7e\xfd\x84\xf7\x6f\xdf\xdd\x40\x21\x15\x4c\x5c\x7f\xf8\xea\x0d\xc0\xc7\x25\x65\x2b\x14\xd5\x67\xfb\x54\x33\xe9\x0d\x7e\x0a\x7c\xe1\x9b\x5a\x4a\x83\xb0\x50\xfe\x33\xff\x9e\x02\x17\x60\x90\x59\x2e\x05\x0c\x16\x6a\x00\xdc\x00\x3e\x23\x5b\x5b\xe7\x78\xdb\xa0\x70\x86\x5d\xa8\xe3\xb5\xa6\x7b\xc8\x7d\xa8\x52\x2d\xc7\x2a\x88\x73\x12\x15\x04\x8a\xe8\x4b\x4a\xa0\x91\x72\x55\x56\xd8\xf2\x0d\x6a\xee\x0c\x5c\x05\xe0\x1e\xaf\x60\xc9\x6b\xe3\x42\xb4\x85\x87\x7c\x7a\x1f\xe5\x8f\xf0\x8d\x3c\x5e\xef\xb3\xfb\x1a\x87\x70\x61\xb1\x76\xb3\xc9\x66\x05\x64\x8b\x34\x85\x9c\x4c\x48\x4e\xb2\x98\xcc\xf7\x8c\x53\xe3\xd5\x10\x66\x19\x24\x24\x25\xae\x65\x1c\xcd\xe3\x28\x21\x07\x15\x3f\x71\xaf\xb2\xa1\x9a\x35\x54\x5f\x7d\xb8\x19\x9e\x94\x0e\x04\x6e\x50\xd8\xd7\x20\x76\xa7\xf0\x85\x9b\x03\x40\xad\xc5\x4e\xd9\x0b\x59\x63\xa9\x5d\x9b\x92\xc9\xea\x52\x7d\x4b\x2d\x0a\xb6\x2b\x3b\x73\x01\xd0\x68\x94\x74\xeb\x2d\x97\xb2\xda\x81\xc5\x67\xfb\x67\x1e\x09\x99\x44\x8b\xb4\x80\xc1\xa0\x77\xab\xb5\xd4\xff\x61\x98\x46\xd7\xb2\x2a\xa9\xf5\x2b\x70\x3d\x4f\x68\x30\xbc\x3b\xee\x6e\x9a\x25\xe4\xc7\xdf\xbb\x2b\xfb\xbd\x94\x67\x12\x6e\xf6\x2f\x36\xdc\x63\xd7\xe7\xad\x12\x32\x8f\x9d\xfc\xd9\xa9\x25\x72\x2b\x8e\xc7\x76\xba\x34\x1f\x7c\xd5\xad\x69\xd9\xb6\x2e\xeb\xaf\x39\x48\xf2\xd9\xc3\xbf\xaf\xed\x2e\xf8\x0d\x8d\x42\xf8\x03\x9c\x03\x00\x00")
+
+func migrations20261018110000_createhookdeliveriestableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018110000_createhookdeliveriestableSql,
+		"migrations/20261018110000_CreateHookDeliveriesTable.sql",
+	)
+}
+
+func migrations20261018110000_createhookdeliveriestableSql() (*asset, error) {
+	bytes, err := migrations20261018110000_createhookdeliveriestableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018110000_CreateHookDeliveriesTable.sql", size: 924, mode: os.FileMode(420), modTime: time.Unix(1792286676, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018120000_createhookfilterandbodytemplatefieldsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\xcf\xcf\x6e\xc2\x30\x0c\x06\xf0\x7b\x9f\xe2\xbb\xe5\x30\xf5\x05\xe8\xa9\x5b\xba\x53\xd6\x32\xd6\x9c\x51\xff\x78\x34\x10\xe2\xa8\xc9\x04\xd3\xc4\xbb\xaf\xa0\x0d\x09\x09\x01\x47\xdb\x9f\x2d\xff\xd2\x14\x4f\x2b\xe6\x40\xd0\x3e\x49\x53\x7c\xbc\x2b\x18\x87\x40\x5d\x34\xec\x20\xb4\x17\x30\x01\xb4\xa7\xee\x2b\x52\x8f\xdd\x40\x0e\x71\x98\x5a\x5b\xb3\x1a\x9b\x53\x68\x2a\x1a\xef\xad\xa1\x3e\xc9\x55\x5d\x2c\x50\xe7\xcf\xaa\xc0\xc0\xbc\x09\xc8\xa5\xc4\x4b\xa5\xf4\x5b\x89\x4f\x63\x23\x8d\x88\xb4\x8f\x28\xab\x1a\xa5\x56\x0a\xb2\x78\xcd\xb5\xaa\x21\x44\x76\x7b\xbb\xe5\xfe\x7b\x19\x69\xeb\x6d\x13\x09\xeb\xc0\xae\xbd\x72\xe5\xe7\x20\x66\xb3\xd3\x30\x4b\x8e\x9e\x3f\x9c\xe4\x9d\xfb\xe7\x9d\x6d\xc7\xe6\x43\xba\x91\xad\x9d\xa6\x6d\xd3\x6d\xae\xfc\x28\x17\xd5\xfc\x92\x98\xdd\x49\x5d\x50\xb2\xe4\x17\x79\x6c\xad\xcf\x84\x01\x00\x00")
+
+func migrations20261018120000_createhookfilterandbodytemplatefieldsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018120000_createhookfilterandbodytemplatefieldsSql,
+		"migrations/20261018120000_CreateHookFilterAndBodyTemplateFields.sql",
+	)
+}
+
+func migrations20261018120000_createhookfilterandbodytemplatefieldsSql() (*asset, error) {
+	bytes, err := migrations20261018120000_createhookfilterandbodytemplatefieldsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018120000_CreateHookFilterAndBodyTemplateFields.sql", size: 388, mode: os.FileMode(420), modTime: time.Unix(1792286905, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018130000_createhookeventtypesfieldsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\xcf\xbd\x0e\x82\x30\x14\x05\xe0\x9d\xa7\x38\x1b\x83\xe1\x05\x64\x42\x8b\x53\x05\x7f\xe8\x64\x8c\x41\xb8\x81\x2a\xb6\x0d\xad\xa2\x6f\x2f\x10\x75\x72\x60\xbc\xe7\xdc\xdc\xdc\x2f\x08\x30\xab\xb4\xb6\x04\x61\xbc\x20\xc0\x7e\xcb\x21\x15\x2c\x15\x4e\x6a\x05\x5f\x18\x1f\xd2\x82\x9e\x54\xdc\x1d\x95\xe8\x6a\x52\x70\x75\x1f\xdd\x64\xd5\xe6\xe3\x52\x3f\xe4\xc6\x34\x92\x4a\x2f\xe2\x59\xbc\x43\x16\x2d\x78\x8c\x5a\xeb\xab\x45\xc4\x18\x96\x29\x17\xeb\x04\xf4\x20\xe5\x4e\xee\x65\xc8\xe2\x62\xb5\x3a\x23\x49\x33\x24\x82\x73\xb0\x78\x15\x09\x9e\xc1\x3f\x1c\xfd\xf9\x7c\x2c\xc3\x29\xc7\x3a\xd9\x94\x45\xde\x96\xd3\x0e\x0e\xc0\x8f\x96\xe9\x4e\x7d\xbd\x3f\xec\x10\x4e\xe2\xb6\xba\x69\xfa\xf6\x9c\x17\xd7\x3f\x5f\xb2\x5d\xba\xf9\x63\x0e\x27\xad\xfe\x44\xa1\xf7\x06\x8a\x05\xa7\xc4\x9c\x01\x00\x00")
+
+func migrations20261018130000_createhookeventtypesfieldsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018130000_createhookeventtypesfieldsSql,
+		"migrations/20261018130000_CreateHookEventTypesFields.sql",
+	)
+}
+
+func migrations20261018130000_createhookeventtypesfieldsSql() (*asset, error) {
+	bytes, err := migrations20261018130000_createhookeventtypesfieldsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018130000_CreateHookEventTypesFields.sql", size: 412, mode: os.FileMode(420), modTime: time.Unix(1792287263, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018140000_createmembershipbanfieldsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x90\xcb\x6e\x83\x30\x10\x45\xf7\x7c\xc5\xdd\xa5\x55\x85\xd4\x3d\x2b\x1a\x3b\x2b\x17\x1a\x82\xd7\xc8\xe0\x11\x58\x01\x63\xd9\x44\x49\xff\xbe\xa6\x2f\x55\xaa\xaa\xb2\x9c\x99\x3b\x67\x46\x27\x4d\xf1\xd0\xcf\x73\x20\x48\x97\xa4\x29\x4e\x47\x01\x63\x11\xa8\x5b\xcc\x6c\xb1\x93\x6e\x07\x13\x40\x37\xea\x2e\x0b\x69\x5c\x07\xb2\x58\x86\xd8\x9a\x4c\xef\xd5\x7b\x28\x16\xca\xb9\xd1\x90\x4e\x72\x51\xf3\x0a\x75\xfe\x24\x38\x26\x9a\x5a\xf2\x61\x30\x2e\x20\x67\x0c\xfb\x52\xc8\xe7\x02\xad\xb2\x96\x7c\x63\x74\xbc\xb3\x50\x4f\x1e\x85\x14\x02\x15\x3f\xf0\x8a\x17\x7b\x7e\x82\x1b\xd5\x6b\x5c\xc4\x9d\xd1\xf7\xd9\x66\xa4\x6e\xd4\x82\xd6\xf4\x91\x8a\xa2\xac\x3f\xa8\x8c\x1f\x72\x29\x6a\x3c\x6e\xe5\x34\x74\x73\xc6\x53\xf8\x07\xb6\xaa\xfa\xf4\xc6\xe6\xab\xfd\x32\xf7\xad\x6d\x6d\x6e\x12\xe7\xe7\x71\x8c\xd3\x56\x75\xe7\x3f\x3f\x64\x55\xf9\xf2\xcb\x5e\xb6\x3d\xbe\x9a\xd9\x1c\xff\x21\x20\x4b\xde\x00\x43\xd1\xd5\x8e\x1e\x02\x00\x00")
+
+func migrations20261018140000_createmembershipbanfieldsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018140000_createmembershipbanfieldsSql,
+		"migrations/20261018140000_CreateMembershipBanFields.sql",
+	)
+}
+
+func migrations20261018140000_createmembershipbanfieldsSql() (*asset, error) {
+	bytes, err := migrations20261018140000_createmembershipbanfieldsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018140000_CreateMembershipBanFields.sql", size: 542, mode: os.FileMode(420), modTime: time.Unix(1792287473, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018150000_createmembershipexpiresatfieldSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\xce\xbd\x0e\x82\x40\x10\x04\xe0\x9e\xa7\x98\xce\xc2\x90\xd8\x53\xa1\x87\xd5\x09\x8a\x5c\x6d\xf8\xd9\xc0\x46\xb8\xbb\x70\x67\xf0\xf1\x05\xa3\x36\xc6\xc4\x72\x67\x27\x93\x2f\x0c\xb1\x6e\x8d\x71\x04\x65\x83\x30\xc4\xf9\x24\xc1\x1a\x8e\x6a\xcf\x46\x63\xa5\xec\x0a\xec\x40\x77\xaa\x6f\x9e\x1a\x4c\x1d\x69\xf8\x6e\x8e\x06\x6e\xc7\xf2\x59\x9a\x8f\xd2\xda\x9e\xa9\x09\x62\x59\x24\x39\x8a\x78\x2b\x13\x0c\x34\x54\x34\xba\x8e\xad\x43\x2c\x04\x76\x99\x54\x87\x74\x9e\xb2\x3c\x92\xbb\x94\x1e\x15\xb7\xac\x3d\xd2\xac\x40\xaa\xa4\x84\x48\xf6\xb1\x92\x05\x36\x51\xb0\x58\x5e\x30\x61\x26\xfd\xa6\x7d\x5c\x4b\xf8\x97\x6c\x34\x7d\x3f\x7f\xab\xb2\xbe\xfe\xd4\x89\x3c\x3b\x7e\xf3\xa2\xe0\x01\xc1\x7e\x71\x83\x1d\x01\x00\x00")
+
+func migrations20261018150000_createmembershipexpiresatfieldSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018150000_createmembershipexpiresatfieldSql,
+		"migrations/20261018150000_CreateMembershipExpiresAtField.sql",
+	)
+}
+
+func migrations20261018150000_createmembershipexpiresatfieldSql() (*asset, error) {
+	bytes, err := migrations20261018150000_createmembershipexpiresatfieldSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018150000_CreateMembershipExpiresAtField.sql", size: 285, mode: os.FileMode(420), modTime: time.Unix(1792288109, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018160000_createidempotencykeystableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x52\x41\x6e\xdb\x30\x10\xbc\xeb\x15\x7b\xb3\x8d\x46\x56\x9a\x36\x3e\x24\x45\x51\xd5\x56\x0a\xa3\x8a\x9c\x28\x12\xd0\x9c\x04\x9a\xda\x48\x84\x25\x91\x25\xa9\xc8\x7e\x52\xbf\xd1\x97\x85\xb4\x2d\xc7\x70\x52\xa0\xbc\xed\xce\x70\x76\x39\x1c\xd7\x85\x55\x49\x1a\xc7\x75\xa1\xd4\x5a\xa8\x2b\xcf\x2b\x98\x2e\xdb\xe5\x98\xf2\xda\xd3\x5c\x3c\x49\xc4\x82\xd4\xa8\xbc\x3d\xcf\x52\x43\x46\xb1\x51\x98\x43\xdb\xe4\x28\x41\x97\x08\xb7\xf3\x04\xaa\x5d\xfb\xaa\x57\x33\x62\x5d\xd7\x8d\xb9\x30\x5d\xde\x4a\x8a\x63\x2e\x0b\x6f\xcf\x52\x5e\xcd\xb4\xbb\x2f\xec\x8d\x29\x17\x1b\xc9\x8a\x52\xc3\xdf\x3f\x70\x71\xfe\x71\x02\x09\x17\x70\x63\xe6\xc3\x0f\xbb\x00\x7c\x59\x12\xba\xc2\x26\xff\xa6\x9f\x0a\xca\xed\x82\x5f\x1d\x7b\xf1\x43\xc1\xb9\x42\x48\x85\x2d\x1e\xee\x43\x60\x0d\x28\xa4\x9a\xf1\x06\x06\xa9\x18\x00\x53\x80\x6b\xa4\xad\x36\x1b\x77\x25\x36\x66\x61\xd3\xaa\x59\x21\xc9\x96\x64\x0a\x22\x44\xc5\x30\x77\xa6\x71\xe0\x27\x01\x24\xfe\xf7\x30\x00\x96\x63\x2d\xb8\xc6\x86\x6e\xb2\x15\x6e\x14\x0c\x1d\x30\x87\xe5\x46\x5e\x32\x52\xc1\x5d\x3c\xbf\xf5\xe3\x47\xf8\x19\x3c\x9e\x6d\x21\xeb\x54\x66\xf0\x67\x22\x69\x49\xe4\xf0\xd3\x64\x04\xd1\x22\x81\x28\x0d\xc3\x1d\xc3\xe8\x1c\xd0\x8b\xcb\xcb\x53\x58\xe2\xef\x16\x95\xce\x4a\xa2\xca\x03\x6f\xf2\xf9\x94\xa6\x34\xd1\xad\x32\x0f\xd5\x58\x98\x0f\xe8\x41\x98\x05\x37\x7e\x1a\x26\x70\xde\xab\x29\xc1\x8d\xbd\xa0\x71\xad\xdf\xb2\x06\x83\x1d\x8d\x4a\x24\xc6\x9b\x8c\x68\x58\xb2\xc2\x88\x9e\x4c\xc3\xb5\x60\x46\xea\x5d\x7c\x4b\x98\x2e\xa2\x87\x24\xf6\xe7\x51\x72\xec\x99\x79\x6a\x66\x0d\x61\xb9\x75\x0f\xd2\x68\x7e\x9f\x06\xc3\xbd\x45\x67\xd6\x89\x91\x33\xba\xee\x2d\x9f\x47\xb3\xe0\xd7\x1b\xcb\xb3\xa3\xd9\x8b\xe8\x9d\x1f\x79\xc5\x8d\xd4\x51\x1a\x66\xbc\x6b\xfa\x3c\x1c\xc2\x60\x9b\xff\x15\x07\xc9\xab\xca\xa0\x36\x70\xce\x2c\x5e\xdc\xfd\x23\x10\xd7\xce\x0b\x4e\xfe\x92\x1f\x40\x03\x00\x00")
+
+func migrations20261018160000_createidempotencykeystableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018160000_createidempotencykeystableSql,
+		"migrations/20261018160000_CreateIdempotencyKeysTable.sql",
+	)
+}
+
+func migrations20261018160000_createidempotencykeystableSql() (*asset, error) {
+	bytes, err := migrations20261018160000_createidempotencykeystableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018160000_CreateIdempotencyKeysTable.sql", size: 832, mode: os.FileMode(420), modTime: time.Unix(1792288872, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018170000_createclanandplayerversionfieldsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x95\x8f\xb1\x0e\x82\x30\x18\x84\x77\x9e\xe2\x36\x06\xc3\xe0\xcc\x54\x2d\x4e\x15\x14\xe9\x03\x14\x68\xa0\xb1\xb6\x0d\x45\xd1\xb7\x17\x8c\x9a\x68\x18\x74\xfc\xef\xbf\xbb\xdc\x17\x45\x58\x34\xd6\x7a\x09\xee\x82\x28\xc2\x61\xcf\xa0\x0c\xbc\xac\x7a\x65\x0d\x42\xee\x42\x28\x0f\x79\x95\xd5\xb9\x97\x35\x86\x56\x1a\xf4\xed\x28\x9d\x54\xd3\x89\x87\x69\x3c\x84\x73\x5a\xc9\x3a\x20\xac\x48\x72\x14\x64\xc5\x12\x54\x5a\x18\x0f\x42\x29\xd6\x19\xe3\xdb\x14\x17\xd9\xf9\xc9\x5f\xaa\x46\x99\x1e\x69\x56\x20\xe5\x8c\x81\x26\x1b\xc2\x59\x81\x65\xfc\x91\x77\x5a\xdc\xc6\xc4\x9f\x0d\x13\xc4\x93\x88\xda\xc1\xbc\x98\xde\x40\x93\xf8\x13\x52\x67\xb5\x1e\xbf\xa5\xa8\x8e\xb3\xb3\x68\x9e\xed\xbe\x76\xc5\x33\xfc\xb3\xb6\x3b\x38\x10\x33\xab\x76\x01\x00\x00")
+
+func migrations20261018170000_createclanandplayerversionfieldsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018170000_createclanandplayerversionfieldsSql,
+		"migrations/20261018170000_CreateClanAndPlayerVersionFields.sql",
+	)
+}
+
+func migrations20261018170000_createclanandplayerversionfieldsSql() (*asset, error) {
+	bytes, err := migrations20261018170000_createclanandplayerversionfieldsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018170000_CreateClanAndPlayerVersionFields.sql", size: 374, mode: os.FileMode(420), modTime: time.Unix(1792289097, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018180000_createjsonbmergepatchfunctionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x53\x51\x6f\x9b\x30\x10\x7e\xe7\x57\xdc\x43\x24\x40\x0b\x7b\x99\xb4\x69\xa1\xeb\x44\x88\x93\x32\x39\xd0\x61\xd0\xf6\x16\x91\xc4\x23\xb4\x04\x7b\xc1\x59\x57\x4d\xfb\xef\x35\xb6\xa1\x8d\x92\x4e\xe3\xc9\xdc\xdd\xf7\x7d\xe7\xfb\x7c\x9e\x07\x6f\x4a\xc6\x5a\x0a\x39\xb7\x3c\x0f\xc8\x57\x0c\x55\x03\x2d\xdd\x88\x8a\x35\x60\xe7\xdc\x86\xaa\x05\xfa\x9b\x6e\x8e\x82\x6e\xe1\x61\x47\x1b\x10\x3b\x19\xda\x57\xe5\xa1\x50\x45\xf2\xa7\xe0\xbc\xae\xe8\xd6\xea\x28\xee\x5a\xd6\xac\x57\x7b\x7a\x28\xe9\x8a\x17\x62\xb3\x33\x59\x59\x05\x5f\x48\x12\x83\x4a\x81\x4e\x39\xe9\x3c\x84\x0f\xef\x3e\xbe\x77\x41\x30\x59\xa0\xc0\xf0\xab\xa8\x8f\xb4\xe3\x32\xbd\x11\x51\x08\xba\xa7\x8d\x98\xd2\xb2\x6a\xac\x30\x45\x41\x86\x20\x49\x21\x45\xb7\x38\x08\x11\xcc\xf3\x38\xcc\x22\xc9\x7d\x26\xee\x88\x42\x9e\x85\x4e\x8c\x8d\xaa\xfa\x71\x25\x3a\xcb\xd3\x98\x18\xd1\x80\xc0\x68\x64\x4d\xd1\x22\x8a\x2d\x90\x5f\x34\x37\xd5\x11\x81\x38\xc7\xb8\xd3\xd3\xf4\xe2\x91\x53\xf6\xc3\x51\x59\x17\xae\xae\xc1\x66\xeb\x3b\x39\x31\x1b\xb2\x1b\xa4\xc1\xdd\xa7\xe9\x35\x89\xaf\xa2\x28\x9e\x49\x5a\xbf\xa7\x37\xad\xbd\xc6\xaf\xd3\xff\x10\x30\xf8\xc9\x27\xb0\xff\xfc\xb5\x27\x13\x85\x3e\x57\x32\x7d\x84\x49\x80\x11\x09\x91\xe3\x0c\x04\x04\x61\x14\x66\x46\x55\x4b\xac\x8a\xb2\x74\xd4\xfc\xb6\x6f\xef\xe9\xe3\x18\xcc\x59\x59\xe2\x0e\xc8\x79\x9a\x2c\xe1\x99\xe8\x05\x99\xd0\x30\xa1\x11\xba\x50\x0b\xd0\x62\xb0\x43\xba\x7d\x82\xfd\x76\x83\x52\x04\x71\x92\x99\x91\x7f\xd6\x34\x27\x35\x79\xdc\x19\x1c\x60\x7c\x49\x95\x6b\xd5\x57\xed\xf7\xae\xfb\x12\x6e\xae\x72\xd6\x99\xb1\x93\x5f\x68\xec\xd4\xf6\x9e\xa0\xf3\xa5\x39\xd6\xb5\x3d\x20\x5c\x33\x2d\x15\x70\xc7\x2f\x6d\x71\x7d\x4b\x7a\xe2\x5b\xa3\x11\xe0\x20\x5e\xe4\xc1\x02\x01\xaf\x79\xd9\xfe\xac\x21\x5a\x2e\xf3\x2c\x98\x62\xe4\x5f\x7a\xf3\xa8\xd1\x7b\x65\xe2\x33\xf6\xd0\xf4\x9b\x3a\xac\x69\x17\xfc\xaf\x45\x3d\xb0\xba\x96\xd9\x75\xb1\xb9\xb7\x66\x69\x72\xfb\xbc\x39\xf2\x3d\xa2\xef\x11\xc9\xc8\x85\x21\x9a\xe5\xe9\x2f\xf2\x04\xfa\xc5\xb0\xf7\x35\x04\x00\x00")
+
+func migrations20261018180000_createjsonbmergepatchfunctionSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018180000_createjsonbmergepatchfunctionSql,
+		"migrations/20261018180000_CreateJSONBMergePatchFunction.sql",
+	)
+}
+
+func migrations20261018180000_createjsonbmergepatchfunctionSql() (*asset, error) {
+	bytes, err := migrations20261018180000_createjsonbmergepatchfunctionSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018180000_CreateJSONBMergePatchFunction.sql", size: 1077, mode: os.FileMode(420), modTime: time.Unix(1792289327, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018190000_createclanscorestableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x52\x41\x6e\xdb\x30\x10\xbc\xeb\x15\x7b\x8b\x8d\x5a\x56\x9b\x20\x39\x24\x41\x11\x55\x62\x0a\xa3\x8a\x9c\xc8\x32\xd0\x9c\x0c\x9a\x62\x28\xc2\x12\x49\x50\x54\xd5\x3e\xa9\xdf\xe8\xcb\x42\xca\x56\xec\x38\x45\x10\xde\x76\x77\x76\x76\x76\x96\xbe\x0f\x9b\x12\x0b\xcf\xf7\xa1\x34\x46\x35\x97\x41\xc0\xb8\x29\xdb\xf5\x94\xc8\x3a\x30\x52\x3d\x69\x4a\x19\xae\x69\x13\xec\x70\x0e\x9a\x70\x42\x45\x43\x0b\x68\x45\x41\x35\x98\x92\xc2\xdd\x2c\x87\x6a\x9b\xbe\x1c\xd8\x2c\x59\xd7\x75\x53\xa9\x6c\x56\xb6\x9a\xd0\xa9\xd4\x2c\xd8\xa1\x9a\xa0\xe6\xc6\xdf\x05\xae\x23\x92\xea\x8f\xe6\xac\x34\xf0\xef\x2f\x9c\x7e\xfe\x72\x01\xb9\x54\x70\x6b\xe7\xc3\x77\x27\x00\xae\xd7\x98\x6c\xa8\x28\x6e\xcc\x13\x23\xd2\x09\xfc\xea\xb9\xc6\x4f\x4c\xca\x86\xc2\x52\xb9\x60\xf1\x90\x00\x17\xd0\x50\x62\xb8\x14\x70\xb2\x54\x27\xc0\x1b\xa0\xbf\x29\x69\x8d\x55\xdc\x95\x54\x58\xc1\x36\x55\x73\xa6\x71\x0f\xb2\x01\x56\xaa\xe2\xb4\xf0\xa2\x0c\x85\x39\x82\x3c\xfc\x96\x20\x20\x15\x16\xab\x86\x48\x6d\x87\x8f\x3c\xb0\xcf\x39\xb1\xe2\x05\xfc\xc2\x9a\x94\x58\x8f\xce\x2e\xc6\x90\xce\x73\x48\x97\x49\x32\xe9\x11\x05\xaf\xed\x42\x8e\x75\xc0\x9c\x9e\x9f\x1f\x83\x7a\x62\x4b\xc3\x85\xa1\xcc\x1a\x38\x54\x21\x43\xb7\x28\x43\x69\x84\x16\x3d\xc6\x8e\xe5\xc5\x18\xe6\x29\xc4\x28\x41\x56\x57\x14\x2e\xa2\x30\x46\x5b\x96\x5e\x19\x88\xb6\xa6\x9a\x93\xa3\x09\xad\x2a\xb0\x5d\x77\x85\x0d\xac\x39\xb3\x73\x0e\xea\x3d\xe0\x3e\x9b\xdd\x85\xd9\x23\xfc\x40\x8f\x30\xda\xad\x35\xd9\xab\x9f\x0c\x1a\xc7\xde\xf8\x6a\x70\x65\x96\xc6\xe8\xe7\xa1\x2b\x2b\x8d\xc5\x86\x0b\xe6\x14\xbe\x32\xeb\x7f\x84\x5b\xb9\x31\x5a\x44\x7b\xf2\x77\xa8\x07\x8f\x8e\xa9\xf7\xad\x07\xb7\x8f\x65\x27\x86\xeb\xbf\x9c\xde\x25\x3f\x74\x7c\x2d\xab\xca\x56\xdd\xf7\xf2\xe2\x6c\x7e\xff\xf6\xfc\x57\xde\x33\x33\x6f\x2f\xe8\x29\x03\x00\x00")
+
+func migrations20261018190000_createclanscorestableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018190000_createclanscorestableSql,
+		"migrations/20261018190000_CreateClanScoresTable.sql",
+	)
+}
+
+func migrations20261018190000_createclanscorestableSql() (*asset, error) {
+	bytes, err := migrations20261018190000_createclanscorestableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018190000_CreateClanScoresTable.sql", size: 809, mode: os.FileMode(420), modTime: time.Unix(1792289793, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018200000_createauditentriestableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x95\x54\xc1\x72\x9b\x30\x10\xbd\xf3\x15\x7b\x73\x32\x35\xa6\x4d\x27\x39\xc4\x9d\x4e\x69\x20\xa9\xa7\xc4\x76\x6d\x98\x69\x4e\x8c\x80\x35\xa8\xc1\x92\x2a\x44\x5c\x7f\x52\x7f\xa3\x5f\x56\x09\x83\xc7\x66\x3c\x19\x87\x9b\x96\xb7\xfb\x9e\xf4\x76\xd7\xb6\xe1\xb9\x20\xcc\xb2\x6d\x28\x94\x12\xd5\xad\xe3\xe4\x54\x15\x75\x32\x4a\xf9\xda\x51\x5c\xac\x24\x62\x4e\xd6\x58\x39\x2d\xce\x40\x03\x9a\x22\xab\x30\x83\x9a\x65\x28\x41\x15\x08\x8f\x93\x10\xca\x5d\xf8\xb6\xab\xa6\x8b\x6d\x36\x9b\x11\x17\x3a\xca\x6b\x99\xe2\x88\xcb\xdc\x69\x51\x95\xb3\xa6\xca\x6e\x0f\x26\xe3\x8e\x8b\xad\xa4\x79\xa1\xe0\xdf\x5f\xb8\x7a\xff\xe1\x06\x42\x2e\xe0\x5e\xf3\xc3\x83\x11\x00\x9f\x12\x92\x3e\x23\xcb\xbe\xa8\x55\x9e\x72\x23\xf0\xb3\x65\x12\xdf\xe5\x9c\x57\x08\x91\x30\x87\xe5\x8f\x00\x28\x83\x0a\x53\x45\x39\x83\x41\x24\x06\x40\x2b\xc0\x3f\x98\xd6\x4a\x2b\xde\x14\xc8\xb4\x60\x1d\x5a\xd3\x5c\x92\x06\xa4\x0f\x44\x88\x92\x62\x66\xdd\x2d\x7c\x37\xf4\x21\x74\xbf\x06\x3e\x90\x3a\xa3\x2a\x46\xa6\x24\xd5\xf4\x17\x16\xe8\x8f\x66\x90\xd0\xbc\x42\x49\x49\x09\xd3\x59\x08\xd3\x28\x08\x60\xbe\x98\x3c\xba\x8b\x27\xf8\xee\x3f\x0d\x1b\x98\x79\xb2\x58\x63\x5f\x88\x4c\x0b\x22\x2f\x3e\xde\x5c\xee\xd1\x3b\x04\xd9\x09\xec\x00\x57\xd7\xd7\x27\x10\x5c\xc6\xa2\x4e\xf4\x23\x1d\xd6\x3a\x82\x82\xe7\xdf\xbb\x51\x10\xc2\x60\xb0\xcb\x4a\x4b\xc2\xde\x9c\x24\x4a\xb2\xc5\xb7\x73\x25\xb8\xe2\x12\xe1\x57\xc5\x59\xd2\x17\xbf\x52\xba\x33\x4e\xfd\x91\xf8\xbb\xc6\x4a\xbd\xe1\x46\x12\x89\xb6\x2e\x26\xca\x3c\x3d\x65\x6a\x0f\xb5\x2e\xc7\x9d\x63\x93\xa9\xe7\xff\x3c\x76\x2c\x6e\x4c\x38\xc8\x9e\x4d\xfb\x96\xb6\x36\x0d\x0f\x38\x86\xda\xe2\xd7\xcb\x36\x2f\x7c\x66\xd9\x23\x33\x0e\x69\x5e\xa7\x68\xfd\x38\x8f\xa4\x6f\xde\xf9\x34\xbb\x0e\x3b\x8f\xa5\xd7\x8d\x3d\x12\x33\x7a\x66\x0d\x34\xd9\xa0\x24\xa1\x65\x3b\x56\x7a\x60\x6d\xce\xca\xed\x10\xba\x9a\x44\xb7\x8c\x89\x00\xbe\xe8\x16\xc9\xb0\x44\x33\x98\xc9\x16\x84\xac\x19\x65\x79\xa7\x78\x11\xf5\x67\x30\x66\x3c\xae\x45\xa6\x69\xc1\x5d\x1a\xb1\xd1\xdc\x6b\xc6\x75\xd6\x93\xed\xcd\xf4\x7d\x97\xa1\xef\x7a\xa6\x57\xbe\x4d\xa6\x0f\xe3\xc3\x55\xe1\xf1\x0d\xeb\x96\xc5\x7e\x53\x98\xe0\x59\xbb\x42\xf2\xb2\x34\x82\xf5\x36\xb2\xbc\xc5\x6c\x7e\x6a\x5b\x8c\xad\xff\x72\x23\xec\x53\x5a\x05\x00\x00")
+
+func migrations20261018200000_createauditentriestableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018200000_createauditentriestableSql,
+		"migrations/20261018200000_CreateAuditEntriesTable.sql",
+	)
+}
+
+func migrations20261018200000_createauditentriestableSql() (*asset, error) {
+	bytes, err := migrations20261018200000_createauditentriestableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018200000_CreateAuditEntriesTable.sql", size: 1370, mode: os.FileMode(420), modTime: time.Unix(1792290306, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018210000_creategamearchivedatfieldSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x8f\x41\x4e\xc3\x30\x10\x45\xf7\x39\xc5\xec\xba\x40\x69\x0a\x0b\x16\x2d\x42\x84\xa6\x45\x48\x6e\x0b\x25\x59\x23\xc7\x99\xda\x56\x13\xdb\xb2\x1d\x02\x47\xe2\x1a\x9c\x0c\xbb\xb4\x88\x45\x17\x2c\xff\x9f\x3f\x33\xef\xa7\x29\xec\x05\x55\x49\x9a\x82\xf0\xde\xb8\x69\x96\x71\xe9\x45\x5f\x8f\x99\xee\x32\xaf\xcd\xce\x22\x72\xda\xa1\xcb\x8e\xb9\x18\x25\x92\xa1\x72\xd8\x40\xaf\x1a\xb4\xe0\x05\xc2\xea\xb1\x84\xf6\xc7\x9e\x9e\xae\x85\x63\xc3\x30\x8c\xb5\x09\xae\xee\x2d\xc3\xb1\xb6\x3c\x3b\xa6\x5c\xd6\x49\x9f\x1e\x45\xdc\x98\x6b\xf3\x61\x25\x17\x1e\xbe\x3e\xe1\x6a\x72\x79\x0d\xa5\x36\xb0\x0c\xff\xe1\x21\x02\xc0\x4d\x4d\xd9\x1e\x55\x73\xe7\x77\x9c\xe9\x08\x78\x9b\xc4\xc5\x0b\xae\xb5\x43\xa8\x4c\x14\x2f\xcf\x04\xa4\x02\x87\xcc\x4b\xad\x60\x54\x99\x11\x48\x07\xf8\x8e\xac\xf7\x81\x78\x10\xa8\x02\x70\xb0\x3a\xc9\x2d\x3d\x84\x82\xa0\xc6\xb4\x12\x9b\x24\x27\xe5\x62\x0b\x65\x7e\x4f\x16\x70\xa8\x0d\x79\x51\xc0\x7c\x43\xaa\xd5\x1a\xa8\x65\x42\xbe\x61\xf3\x4a\x3d\xd4\x92\x4b\xe5\x61\xbd\x29\x61\x5d\x11\x02\xc5\x62\x99\x57\xa4\x84\xc9\xec\x2f\x53\xa1\x07\x75\xa2\xfa\x45\x8a\xe6\xbf\xa0\xac\x6e\xdb\x30\x8d\xb5\xcf\x80\x15\xdb\xcd\xd3\x19\xb2\x59\xf2\x0d\x8f\xa1\x22\xd7\xd4\x01\x00\x00")
+
+func migrations20261018210000_creategamearchivedatfieldSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018210000_creategamearchivedatfieldSql,
+		"migrations/20261018210000_CreateGameArchivedAtField.sql",
+	)
+}
+
+func migrations20261018210000_creategamearchivedatfieldSql() (*asset, error) {
+	bytes, err := migrations20261018210000_creategamearchivedatfieldSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018210000_CreateGameArchivedAtField.sql", size: 468, mode: os.FileMode(420), modTime: time.Unix(1792290525, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018220000_createapikeystableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x92\xc1\x6e\x9b\x40\x10\x86\xef\x3c\xc5\xdc\x6c\xab\xc1\xa4\x69\xe3\x43\x52\x55\xa5\x36\xa9\x50\x09\x4e\x08\x48\xcd\x09\xad\x97\x0d\xac\x8c\x77\x57\xbb\x4b\x69\x1e\xa9\xaf\xd1\x27\xeb\xac\xb1\x89\xe5\x43\x55\x6e\xf3\xcf\x37\xb3\x33\xff\xe0\xfb\xb0\x6d\x88\xf0\x7c\x1f\x1a\x6b\x95\xb9\x09\x82\x9a\xdb\xa6\xdb\xcc\xa9\xdc\x05\x56\xaa\x17\xcd\x58\x4d\x76\xcc\x04\x07\xce\xa1\x09\xa7\x4c\x18\x56\x41\x27\x2a\xa6\xc1\x36\x0c\xee\xe3\x1c\xda\x41\xbe\x39\x76\xc3\x66\x7d\xdf\xcf\xa5\x42\x55\x76\x9a\xb2\xb9\xd4\x75\x70\xa0\x4c\xb0\xe3\xd6\x3f\x04\xae\x62\x29\xd5\xab\xe6\x75\x63\xe1\xcf\x6f\xb8\xba\x7c\xbf\x80\x5c\x2a\xb8\xc3\xf7\xe1\x9b\x1b\x00\x3e\x6d\x08\xdd\x32\x51\x7d\xb1\x2f\x35\x95\x6e\xc0\xcf\x9e\x2b\x7c\x57\x4b\x69\x18\x14\xca\x05\x4f\x8f\x09\x70\x01\x86\x51\xcb\xa5\x80\x49\xa1\x26\xc0\x0d\xb0\x5f\x8c\x76\x16\x27\xee\x1b\x26\x70\x60\x94\x76\xbc\xd6\x64\x0f\x61\x40\x94\x6a\x39\xab\xbc\x65\x16\x85\x79\x04\x79\xf8\x35\x89\x50\xe4\xe5\x96\xbd\x1a\x98\x7a\x80\x1f\xaf\xb0\xad\xe6\xa4\x85\x74\x9d\x43\x5a\x24\x09\x3c\x64\xf1\x7d\x98\x3d\xc3\xf7\xe8\xf9\x62\xcf\x38\xab\x4a\x04\x7f\x12\x4d\x1b\xa2\xa7\x1f\x16\xb3\x91\x1e\x08\xd5\x6d\x70\xe9\x7f\x33\x02\xbb\x8c\xe9\xab\xeb\xeb\xb7\x3c\xac\xa2\xbb\xb0\x48\x72\x98\x4c\x06\x54\xcb\xf6\x04\xbd\x3c\xef\x84\xe3\x97\x0d\x31\xcd\x88\x2c\x3e\x9e\x23\x54\x33\x82\xce\x94\xc4\xc2\x86\xd7\x5c\xd8\xb3\xfc\x72\x9d\x3e\xe5\x59\x18\xa7\xf9\x7e\x3b\x8e\xe4\xe0\x4b\x39\xac\x82\x9b\x14\x69\xfc\x58\x44\xd3\xc3\xf2\x17\x6f\x3b\xce\xbc\xd9\xed\xd1\xd3\x01\x82\x38\x5d\x45\x3f\x46\x6b\xcb\x71\xc0\x75\x7a\xe2\xf7\x51\xc5\xea\x93\x0b\xaf\x64\x2f\x8e\x37\x1e\x0f\xec\xc4\xff\x3a\x31\x3a\xd5\x62\xd6\xfd\x44\xde\x2a\x5b\x3f\x9c\x1d\xf9\xd6\xfb\x0b\x37\x91\xe4\xd4\x0c\x03\x00\x00")
+
+func migrations20261018220000_createapikeystableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018220000_createapikeystableSql,
+		"migrations/20261018220000_CreateAPIKeysTable.sql",
+	)
+}
+
+func migrations20261018220000_createapikeystableSql() (*asset, error) {
+	bytes, err := migrations20261018220000_createapikeystableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018220000_CreateAPIKeysTable.sql", size: 780, mode: os.FileMode(420), modTime: time.Unix(1792291616, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018230000_backfillmembershipexpiresatfieldSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xdd\x53\xc9\x4e\xc3\x30\x10\xbd\xe7\x2b\xe6\x96\x42\x49\x15\xae\x85\x56\xaa\xd4\x20\x10\x7b\x17\x71\xac\x9c\x64\x9a\x98\xc6\x0b\xb6\xd3\xd2\xbf\x67\x92\xb6\x61\x11\x82\xaa\xe2\xc4\x6d\xfc\x66\xfc\x66\xfc\x9e\x27\x08\xa0\x9d\x29\x65\x11\xa6\xda\x0b\x02\x18\x3f\xde\x00\x97\x60\x31\x71\x5c\x49\xf0\xa7\xda\x07\x6e\x01\x5f\x31\x29\x1d\xa6\xb0\xca\x51\x82\xcb\x09\x12\x3c\x33\xac\x2e\xa2\x03\xd3\xba\xe0\x98\x7a\xd3\x87\xe1\x60\x12\x81\x40\x11\xa3\xb1\x39\xd7\x54\xe7\x8d\xa3\x09\x11\x68\x6e\xd0\xce\x98\xeb\x89\x4e\xa9\x53\x46\x64\x74\x80\x36\xb4\x5a\x59\x47\xa0\x63\x04\xb1\xa0\xdf\xf7\x35\xca\x94\xcb\x6c\x50\x31\x26\x75\x03\x1b\x55\x97\xeb\xd0\x3f\xea\x76\x65\x29\xd0\xf0\x04\x8e\xe1\x34\x0c\x43\x02\x62\x9e\x71\xe9\xbc\x8b\xd1\xfd\x2d\x64\x4c\xa0\x85\xcc\x7b\xba\x8c\x46\x11\x64\x1d\x5d\xc6\x44\x33\xe3\x29\xb5\xad\x72\x14\x79\x00\x83\xbb\x21\x88\xce\x87\x99\xc2\x2d\x94\x62\x81\x9b\xc9\x1a\x88\x9e\x66\xd4\x12\xd3\xde\x9c\x15\x24\xd3\xae\x4e\xf2\x2f\x50\xcc\xa4\xdc\x41\x4d\x0b\x83\x2f\x25\x5a\xa7\xcc\x66\x02\x5d\xb0\x35\x9a\xf7\x19\x9e\xad\x92\xf1\xcc\xad\x35\xaa\xf9\x27\x19\x7e\x53\xa1\xe7\x93\x0a\x24\xb1\xbf\x25\x3a\x54\xc3\x3e\x84\x67\xde\x1f\xba\x76\x25\x97\xdc\xe1\x3f\x31\xec\xbc\x7f\x80\x63\xdf\x28\xb0\x9f\x59\x3f\x4a\xb7\xf1\x29\x68\x96\x75\xa8\x56\x72\xb7\xae\xcd\xae\x56\xe0\x5e\xdb\x6a\x54\x51\x50\x36\x66\xc9\xa2\x22\x99\xe4\x58\xc7\x73\x5e\xc3\xd8\x4c\x00\x8e\x93\x39\x8e\x09\xfa\x10\x09\x93\xbe\x83\x18\xc1\xa9\x22\xa5\x7d\x67\xc6\xc1\xdc\x28\x41\xf4\x08\x4a\x92\x87\x16\x29\xbf\x86\xeb\x9c\xc9\x13\xb0\xaa\x4a\xac\x81\x19\x84\x05\x6a\xe7\xbd\x01\x7d\x5d\xdd\xbd\x69\x04\x00\x00")
+
+func migrations20261018230000_backfillmembershipexpiresatfieldSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018230000_backfillmembershipexpiresatfieldSql,
+		"migrations/20261018230000_BackfillMembershipExpiresAtField.sql",
+	)
+}
+
+func migrations20261018230000_backfillmembershipexpiresatfieldSql() (*asset, error) {
+	bytes, err := migrations20261018230000_backfillmembershipexpiresatfieldSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018230000_BackfillMembershipExpiresAtField.sql", size: 1129, mode: os.FileMode(420), modTime: time.Unix(1792293603, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018240000_createhookdeliveriesgamecreatedatindexSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x90\x41\x4e\xc3\x30\x10\x45\xf7\x39\xc5\xec\x0a\x82\x34\xc0\x82\x45\x8b\x10\xa8\x2d\xa8\x52\x69\x69\x49\x25\x76\x91\xeb\x4c\x6d\xab\x89\xc7\xb2\x1d\x02\x47\xe2\x1a\x9c\x0c\x1b\x52\x40\xac\xba\x9c\xef\x37\xdf\x4f\x93\xa6\xb0\x93\x4c\x27\x69\x0a\xd2\x7b\xe3\x06\x59\x26\x94\x97\xcd\xa6\xcf\xa9\xce\x3c\x99\xad\x45\x14\xac\x46\x97\x75\x5c\x44\x67\x8a\xa3\x76\x58\x42\xa3\x4b\xb4\xe0\x25\xc2\xc3\x34\x87\xea\x3b\x1e\xec\xdb\x42\x59\xdb\xb6\x7d\x32\x21\xa5\xc6\x72\xec\x93\x15\x59\x47\xb9\xac\x56\x3e\xed\x86\xb8\x31\x22\xf3\x66\x95\x90\x1e\x3e\xde\xe1\xe2\xec\xfc\x12\x72\x32\x70\x17\xfe\x87\xfb\x28\x00\x57\x1b\xc6\x77\xa8\xcb\x1b\xbf\x15\x9c\xa2\xe0\x75\x12\x17\x4f\x04\x91\x43\x58\x9b\x38\x3c\x2d\x67\xa0\x34\x38\xe4\x5e\x91\x86\xde\xda\xf4\x40\x39\xc0\x57\xe4\x8d\x0f\xc6\xad\x44\x1d\x84\x43\x54\x2b\x61\xd9\x17\x14\x06\x66\x4c\xa5\xb0\x4c\x46\xab\xc9\x6d\x3e\x81\xe9\x7c\x3c\x79\x06\x49\xb4\x2b\x4a\xac\xd4\x0b\x5a\x85\xae\x88\x77\x28\x54\x59\x70\x8b\x2c\x74\x15\xcc\xc3\x62\xfe\x9f\x82\xa3\x0e\x3b\x85\x5f\xee\x78\xf8\xd7\x74\x4c\xad\xde\xbb\xfe\x88\xc6\xf0\x20\x55\x4b\x55\x15\x5e\xe3\x31\x92\xf1\x6a\xf1\x78\xb0\xec\x30\xf9\x04\xfc\x98\x5e\x56\xee\x01\x00\x00")
+
+func migrations20261018240000_createhookdeliveriesgamecreatedatindexSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018240000_createhookdeliveriesgamecreatedatindexSql,
+		"migrations/20261018240000_CreateHookDeliveriesGameCreatedAtIndex.sql",
+	)
+}
+
+func migrations20261018240000_createhookdeliveriesgamecreatedatindexSql() (*asset, error) {
+	bytes, err := migrations20261018240000_createhookdeliveriesgamecreatedatindexSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018240000_CreateHookDeliveriesGameCreatedAtIndex.sql", size: 494, mode: os.FileMode(420), modTime: time.Unix(1792294441, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -515,6 +830,21 @@ var _bindata = map[string]func() (*asset, error){
 	"migrations/20160729184159_CreateCooldownAfterInviteField.sql": migrations20160729184159_createcooldownafterinvitefieldSql,
 	"migrations/20160819145352_CreateHookTriggerFieldsMetadata.sql": migrations20160819145352_createhooktriggerfieldsmetadataSql,
 	"migrations/20180517112014_ChangeIDSequenceType.sql": migrations20180517112014_changeidsequencetypeSql,
+	"migrations/20261018100000_CreateHookSecretField.sql": migrations20261018100000_createhooksecretfieldSql,
+	"migrations/20261018110000_CreateHookDeliveriesTable.sql": migrations20261018110000_createhookdeliveriestableSql,
+	"migrations/20261018120000_CreateHookFilterAndBodyTemplateFields.sql": migrations20261018120000_createhookfilterandbodytemplatefieldsSql,
+	"migrations/20261018130000_CreateHookEventTypesFields.sql": migrations20261018130000_createhookeventtypesfieldsSql,
+	"migrations/20261018140000_CreateMembershipBanFields.sql": migrations20261018140000_createmembershipbanfieldsSql,
+	"migrations/20261018150000_CreateMembershipExpiresAtField.sql": migrations20261018150000_createmembershipexpiresatfieldSql,
+	"migrations/20261018160000_CreateIdempotencyKeysTable.sql": migrations20261018160000_createidempotencykeystableSql,
+	"migrations/20261018170000_CreateClanAndPlayerVersionFields.sql": migrations20261018170000_createclanandplayerversionfieldsSql,
+	"migrations/20261018180000_CreateJSONBMergePatchFunction.sql": migrations20261018180000_createjsonbmergepatchfunctionSql,
+	"migrations/20261018190000_CreateClanScoresTable.sql": migrations20261018190000_createclanscorestableSql,
+	"migrations/20261018200000_CreateAuditEntriesTable.sql": migrations20261018200000_createauditentriestableSql,
+	"migrations/20261018210000_CreateGameArchivedAtField.sql": migrations20261018210000_creategamearchivedatfieldSql,
+	"migrations/20261018220000_CreateAPIKeysTable.sql": migrations20261018220000_createapikeystableSql,
+	"migrations/20261018230000_BackfillMembershipExpiresAtField.sql": migrations20261018230000_backfillmembershipexpiresatfieldSql,
+	"migrations/20261018240000_CreateHookDeliveriesGameCreatedAtIndex.sql": migrations20261018240000_createhookdeliveriesgamecreatedatindexSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -576,6 +906,21 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"20160729184159_CreateCooldownAfterInviteField.sql": &bintree{migrations20160729184159_createcooldownafterinvitefieldSql, map[string]*bintree{}},
 		"20160819145352_CreateHookTriggerFieldsMetadata.sql": &bintree{migrations20160819145352_createhooktriggerfieldsmetadataSql, map[string]*bintree{}},
 		"20180517112014_ChangeIDSequenceType.sql": &bintree{migrations20180517112014_changeidsequencetypeSql, map[string]*bintree{}},
+		"20261018100000_CreateHookSecretField.sql": &bintree{migrations20261018100000_createhooksecretfieldSql, map[string]*bintree{}},
+		"20261018110000_CreateHookDeliveriesTable.sql": &bintree{migrations20261018110000_createhookdeliveriestableSql, map[string]*bintree{}},
+		"20261018120000_CreateHookFilterAndBodyTemplateFields.sql": &bintree{migrations20261018120000_createhookfilterandbodytemplatefieldsSql, map[string]*bintree{}},
+		"20261018130000_CreateHookEventTypesFields.sql": &bintree{migrations20261018130000_createhookeventtypesfieldsSql, map[string]*bintree{}},
+		"20261018140000_CreateMembershipBanFields.sql": &bintree{migrations20261018140000_createmembershipbanfieldsSql, map[string]*bintree{}},
+		"20261018150000_CreateMembershipExpiresAtField.sql": &bintree{migrations20261018150000_createmembershipexpiresatfieldSql, map[string]*bintree{}},
+		"20261018160000_CreateIdempotencyKeysTable.sql": &bintree{migrations20261018160000_createidempotencykeystableSql, map[string]*bintree{}},
+		"20261018170000_CreateClanAndPlayerVersionFields.sql": &bintree{migrations20261018170000_createclanandplayerversionfieldsSql, map[string]*bintree{}},
+		"20261018180000_CreateJSONBMergePatchFunction.sql": &bintree{migrations20261018180000_createjsonbmergepatchfunctionSql, map[string]*bintree{}},
+		"20261018190000_CreateClanScoresTable.sql": &bintree{migrations20261018190000_createclanscorestableSql, map[string]*bintree{}},
+		"20261018200000_CreateAuditEntriesTable.sql": &bintree{migrations20261018200000_createauditentriestableSql, map[string]*bintree{}},
+		"20261018210000_CreateGameArchivedAtField.sql": &bintree{migrations20261018210000_creategamearchivedatfieldSql, map[string]*bintree{}},
+		"20261018220000_CreateAPIKeysTable.sql": &bintree{migrations20261018220000_createapikeystableSql, map[string]*bintree{}},
+		"20261018230000_BackfillMembershipExpiresAtField.sql": &bintree{migrations20261018230000_backfillmembershipexpiresatfieldSql, map[string]*bintree{}},
+		"20261018240000_CreateHookDeliveriesGameCreatedAtIndex.sql": &bintree{migrations20261018240000_createhookdeliveriesgamecreatedatindexSql, map[string]*bintree{}},
 	}},
 }}
 
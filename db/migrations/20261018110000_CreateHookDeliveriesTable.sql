@@ -0,0 +1,27 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE hook_deliveries (
+    id bigserial PRIMARY KEY,
+    hook_id integer NOT NULL REFERENCES hooks (id) ON DELETE CASCADE,
+    game_id varchar(36) NOT NULL,
+    event_id varchar(36) NOT NULL,
+    event_type integer NOT NULL,
+    attempt integer NOT NULL,
+    status_code integer NOT NULL,
+    latency_ms integer NOT NULL,
+    response_body text NOT NULL DEFAULT '',
+    error text NOT NULL DEFAULT '',
+    created_at bigint NOT NULL
+);
+CREATE INDEX hook_deliveries_hook_id_created_at ON hook_deliveries (hook_id, created_at DESC);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE hook_deliveries;
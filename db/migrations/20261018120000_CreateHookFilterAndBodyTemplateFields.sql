@@ -0,0 +1,9 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE hooks ADD COLUMN filter text NOT NULL DEFAULT '';
+ALTER TABLE hooks ADD COLUMN body_template jsonb NOT NULL DEFAULT '{}'::jsonb;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE hooks DROP COLUMN filter;
+ALTER TABLE hooks DROP COLUMN body_template;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE hooks ADD COLUMN event_types jsonb NOT NULL DEFAULT '[]'::jsonb;
+ALTER TABLE hooks ADD COLUMN event_wildcards jsonb NOT NULL DEFAULT '[]'::jsonb;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE hooks DROP COLUMN event_types;
+ALTER TABLE hooks DROP COLUMN event_wildcards;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE memberships ADD COLUMN banner_id integer NULL REFERENCES players (id);
+ALTER TABLE memberships ADD COLUMN banned_at bigint NOT NULL DEFAULT 0;
+ALTER TABLE memberships ADD COLUMN ban_expires_at bigint NOT NULL DEFAULT 0;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE memberships DROP COLUMN banner_id;
+ALTER TABLE memberships DROP COLUMN banned_at;
+ALTER TABLE memberships DROP COLUMN ban_expires_at;
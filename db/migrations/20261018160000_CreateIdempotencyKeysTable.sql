@@ -0,0 +1,26 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE idempotency_keys (
+    id serial PRIMARY KEY,
+    game_id varchar(36) NOT NULL,
+    key varchar(255) NOT NULL,
+    request_hash varchar(64) NOT NULL,
+    status integer NOT NULL DEFAULT 0,
+    response text NOT NULL DEFAULT '',
+    created_at bigint NOT NULL,
+    expires_at bigint NOT NULL,
+
+    CONSTRAINT idempotencykey_gameid_key UNIQUE(game_id, key)
+);
+CREATE INDEX idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE idempotency_keys;
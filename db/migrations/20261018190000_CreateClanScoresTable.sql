@@ -0,0 +1,24 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE clan_scores (
+    game_id varchar(36) NOT NULL,
+    dimension varchar(255) NOT NULL,
+    clan_id integer NOT NULL REFERENCES clans (id) ON DELETE CASCADE,
+    score numeric NOT NULL,
+    updated_at bigint NOT NULL,
+
+    PRIMARY KEY (game_id, dimension, clan_id)
+);
+CREATE INDEX clan_scores_ranking ON clan_scores (game_id, dimension, score DESC, clan_id);
+CREATE INDEX clan_scores_clan_id ON clan_scores (clan_id);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE clan_scores;
@@ -0,0 +1,32 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE audit_entries (
+    id bigserial NOT NULL PRIMARY KEY,
+    game_id varchar(36) NOT NULL,
+    action varchar(255) NOT NULL,
+    actor_public_id varchar(255) NOT NULL DEFAULT '',
+    clan_public_id varchar(255) NOT NULL DEFAULT '',
+    player_public_id varchar(255) NOT NULL DEFAULT '',
+    before jsonb NOT NULL,
+    after jsonb NOT NULL,
+    request_id varchar(255) NOT NULL DEFAULT '',
+    created_at bigint NOT NULL
+);
+CREATE INDEX audit_entries_game_created_at ON audit_entries (game_id, created_at, id);
+CREATE INDEX audit_entries_clan_created_at ON audit_entries (game_id, clan_public_id, created_at);
+CREATE INDEX audit_entries_player_created_at ON audit_entries (game_id, player_public_id, created_at);
+CREATE INDEX audit_entries_actor_created_at ON audit_entries (game_id, actor_public_id, created_at);
+
+-- the audit trail is append-only, entries are only ever deleted by pruning
+CREATE RULE audit_entries_no_update AS ON UPDATE TO audit_entries DO INSTEAD NOTHING;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE audit_entries;
@@ -0,0 +1,24 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE api_keys (
+    id serial NOT NULL PRIMARY KEY,
+    game_id varchar(36) NOT NULL,
+    public_id varchar(36) NOT NULL,
+    name varchar(255) NOT NULL DEFAULT '',
+    role varchar(20) NOT NULL,
+    key_hash varchar(64) NOT NULL,
+    created_at bigint NOT NULL,
+    CONSTRAINT gameid_api_key_publicid UNIQUE(game_id, public_id)
+);
+CREATE UNIQUE INDEX api_keys_key_hash ON api_keys (key_hash);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE api_keys;
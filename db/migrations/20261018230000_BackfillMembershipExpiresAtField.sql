@@ -0,0 +1,23 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+UPDATE memberships m
+SET expires_at=m.updated_at + ((g.metadata->>'pendingApplicationsExpiration')::numeric * 1000)::bigint
+FROM games g
+WHERE g.public_id=m.game_id
+  AND m.expires_at=0 AND m.deleted_at=0 AND m.approved=false AND m.denied=false AND m.banned=false
+  AND m.requestor_id=m.player_id
+  AND jsonb_typeof(g.metadata->'pendingApplicationsExpiration')='number'
+  AND (g.metadata->>'pendingApplicationsExpiration')::numeric > 0;
+
+UPDATE memberships m
+SET expires_at=m.updated_at + ((g.metadata->>'pendingInvitesExpiration')::numeric * 1000)::bigint
+FROM games g
+WHERE g.public_id=m.game_id
+  AND m.expires_at=0 AND m.deleted_at=0 AND m.approved=false AND m.denied=false AND m.banned=false
+  AND m.requestor_id<>m.player_id
+  AND jsonb_typeof(g.metadata->'pendingInvitesExpiration')='number'
+  AND (g.metadata->>'pendingInvitesExpiration')::numeric > 0;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+-- The backfilled expiration timestamps can't be told apart from the ones set by Khan, so they are kept
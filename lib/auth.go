@@ -0,0 +1,55 @@
+package lib
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Authenticator sets the credentials of the requests sent to khan
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc is a function that can be used as an Authenticator
+type AuthenticatorFunc func(req *http.Request) error
+
+// Authenticate calls f(req)
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// BasicAuthenticator authenticates requests with the basic auth user and password of khan
+type BasicAuthenticator struct {
+	User string
+	Pass string
+}
+
+// Authenticate sets the basic auth header of the request
+func (a *BasicAuthenticator) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.User, a.Pass)
+	return nil
+}
+
+// BearerTokenAuthenticator authenticates requests with a bearer token
+type BearerTokenAuthenticator struct {
+	Token string
+}
+
+// Authenticate sets the authorization header of the request
+func (a *BearerTokenAuthenticator) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.Token))
+	return nil
+}
+
+// HeaderAuthenticator authenticates requests with custom headers, e.g. the API key of a game
+type HeaderAuthenticator struct {
+	Headers map[string]string
+}
+
+// Authenticate sets the headers of the request
+func (a *HeaderAuthenticator) Authenticate(req *http.Request) error {
+	for header, value := range a.Headers {
+		req.Header.Set(header, value)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package lib
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitBreakerSettings configures the circuit breaker of each endpoint of the client. Once an
+// endpoint fails because khan is unavailable too many times in a row, its requests fail right away
+// with a CircuitOpenError until the circuit is probed again
+type CircuitBreakerSettings struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit of an endpoint
+	FailureThreshold int
+	// OpenTimeout is how long a circuit stays open before a single request is let through to probe
+	// khan. The circuit is closed if it succeeds, or opened again otherwise
+	OpenTimeout time.Duration
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+type circuitBreaker struct {
+	mutex    sync.Mutex
+	settings *CircuitBreakerSettings
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+// allow returns whether a request can be sent, letting a single request through once the circuit
+// has been open for longer than the open timeout
+func (b *circuitBreaker) allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if time.Since(b.openedAt) < b.settings.OpenTimeout {
+			return false
+		}
+		b.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		return false
+	}
+	return true
+}
+
+// record records the outcome of a request and returns the new state of the circuit and whether it changed
+func (b *circuitBreaker) record(failed bool) (circuitState, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	previous := b.state
+	if failed {
+		b.failures++
+		if b.state == circuitHalfOpen || b.failures >= b.settings.FailureThreshold {
+			b.state = circuitOpen
+			b.openedAt = time.Now()
+		}
+	} else {
+		b.failures = 0
+		b.state = circuitClosed
+	}
+	return b.state, b.state != previous
+}
+
+// release gives up the request let through by allow without recording its outcome, so the circuit
+// is probed again by the next request if it was half open
+func (b *circuitBreaker) release() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state == circuitHalfOpen {
+		b.state = circuitOpen
+	}
+}
+
+// circuitBreakers holds the circuit breaker of each endpoint of each game of a client
+type circuitBreakers struct {
+	mutex    sync.Mutex
+	settings *CircuitBreakerSettings
+	breakers map[string]*circuitBreaker
+}
+
+func newCircuitBreakers(settings *CircuitBreakerSettings) *circuitBreakers {
+	return &circuitBreakers{
+		settings: settings,
+		breakers: map[string]*circuitBreaker{},
+	}
+}
+
+func (c *circuitBreakers) get(gameID, endpoint string) *circuitBreaker {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := fmt.Sprintf("%s/%s", gameID, endpoint)
+	breaker, ok := c.breakers[key]
+	if !ok {
+		breaker = &circuitBreaker{settings: c.settings}
+		c.breakers[key] = breaker
+	}
+	return breaker
+}
@@ -0,0 +1,72 @@
+package lib
+
+import "context"
+
+// RequestIDHeader is the header khan uses to correlate a request with its audit trail entries
+const RequestIDHeader = "X-Request-ID"
+
+// IdempotencyKeyHeader is the header khan uses to store and replay the response of mutations
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type contextKey int
+
+const (
+	headersContextKey contextKey = iota
+	authenticatorContextKey
+	idempotencyKeyContextKey
+)
+
+// WithHeaders returns a context that makes the client send the given headers, e.g. trace headers,
+// in the requests made with it. Headers set by previous calls are kept unless overridden
+func WithHeaders(ctx context.Context, headers map[string]string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	merged := map[string]string{}
+	for header, value := range getContextHeaders(ctx) {
+		merged[header] = value
+	}
+	for header, value := range headers {
+		merged[header] = value
+	}
+	return context.WithValue(ctx, headersContextKey, merged)
+}
+
+// WithRequestID returns a context that makes the client send the given request ID in the requests
+// made with it
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return WithHeaders(ctx, map[string]string{RequestIDHeader: requestID})
+}
+
+// WithAuthenticator returns a context that makes the client authenticate the requests made with it
+// using the given authenticator instead of its own
+func WithAuthenticator(ctx context.Context, authenticator Authenticator) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, authenticatorContextKey, authenticator)
+}
+
+// WithIdempotencyKey returns a context that makes the client send the given idempotency key in the
+// mutations made with it, so they are retried if the client retry policy allows it
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, idempotencyKeyContextKey, key)
+}
+
+func getContextHeaders(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(headersContextKey).(map[string]string)
+	return headers
+}
+
+func getContextAuthenticator(ctx context.Context) Authenticator {
+	authenticator, _ := ctx.Value(authenticatorContextKey).(Authenticator)
+	return authenticator
+}
+
+func getContextIdempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey).(string)
+	return key
+}
@@ -0,0 +1,262 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Error codes khan sends along with failures caused by known conditions. They never change, so
+// they can be compared with the code of a RequestError
+const (
+	InternalErrorCode                            = "INTERNAL_ERROR"
+	ModelNotFoundErrorCode                       = "MODEL_NOT_FOUND"
+	EmptyGameIDErrorCode                         = "EMPTY_GAME_ID"
+	ClanReachedMaxMembersErrorCode               = "CLAN_REACHED_MAX_MEMBERS"
+	PlayerReachedMaxClansErrorCode               = "PLAYER_REACHED_MAX_CLANS"
+	PlayerReachedMaxInvitesErrorCode             = "PLAYER_REACHED_MAX_INVITES"
+	PlayerCannotCreateMembershipErrorCode        = "PLAYER_CANNOT_CREATE_MEMBERSHIP"
+	PlayerCannotPerformMembershipActionErrorCode = "PLAYER_CANNOT_PERFORM_MEMBERSHIP_ACTION"
+	MembershipAlreadyProcessedErrorCode          = "MEMBERSHIP_ALREADY_PROCESSED"
+	CannotPromoteOrDemoteInvalidMemberErrorCode  = "CANNOT_PROMOTE_OR_DEMOTE_INVALID_MEMBER"
+	CannotPromoteOrDemoteMemberLevelErrorCode    = "CANNOT_PROMOTE_OR_DEMOTE_MEMBER_LEVEL"
+	InvalidMembershipActionErrorCode             = "INVALID_MEMBERSHIP_ACTION"
+	InvalidLevelForGameErrorCode                 = "INVALID_LEVEL_FOR_GAME"
+	ClanHasNoMembersErrorCode                    = "CLAN_HAS_NO_MEMBERS"
+	EmptySearchTermErrorCode                     = "EMPTY_SEARCH_TERM"
+	AlreadyHasValidMembershipErrorCode           = "ALREADY_HAS_VALID_MEMBERSHIP"
+	MustWaitMembershipCooldownErrorCode          = "MUST_WAIT_MEMBERSHIP_COOLDOWN"
+	PlayerBannedFromClanErrorCode                = "PLAYER_BANNED_FROM_CLAN"
+	CouldNotFindAllClansErrorCode                = "COULD_NOT_FIND_ALL_CLANS"
+	ForbiddenErrorCode                           = "FORBIDDEN"
+	InvalidHookFilterErrorCode                   = "INVALID_HOOK_FILTER"
+	InvalidHookEventsErrorCode                   = "INVALID_HOOK_EVENTS"
+	InvalidCursorErrorCode                       = "INVALID_CURSOR"
+	InvalidImportRecordErrorCode                 = "INVALID_IMPORT_RECORD"
+	VersionConflictErrorCode                     = "VERSION_CONFLICT"
+	InvalidMetadataIncrementErrorCode            = "INVALID_METADATA_INCREMENT"
+	GameArchivedErrorCode                        = "GAME_ARCHIVED"
+	GameNotArchivedErrorCode                     = "GAME_NOT_ARCHIVED"
+	InvalidAPIKeyRoleErrorCode                   = "INVALID_API_KEY_ROLE"
+)
+
+// RequestError contains code and body of a request that failed. The client returns it wrapped in the
+// error type of the failure, e.g. NotFoundError, so callers that asserted err.(*RequestError) must use
+// GetRequestError instead
+type RequestError struct {
+	statusCode int
+	body       string
+	reason     string
+	code       string
+}
+
+// errorDetails are the fields khan sends in the details of a failure
+type errorDetails struct {
+	PlayerPublicID string `json:"playerPublicID"`
+	ClanPublicID   string `json:"clanPublicID"`
+	GameID         string `json:"gameID"`
+	Time           int    `json:"time"`
+	ExpiresAt      int64  `json:"expiresAt"`
+}
+
+// newRequestError decodes the failure payload of a request. Failures with a known error code are
+// returned as the matching error type, and the others as the error type of their status code
+func newRequestError(statusCode int, body string) error {
+	var payload struct {
+		Reason  string       `json:"reason"`
+		Code    string       `json:"code"`
+		Version int64        `json:"version"`
+		Details errorDetails `json:"details"`
+	}
+	json.Unmarshal([]byte(body), &payload)
+
+	r := &RequestError{
+		statusCode: statusCode,
+		body:       body,
+		reason:     payload.Reason,
+		code:       payload.Code,
+	}
+	details := payload.Details
+
+	switch payload.Code {
+	case ClanReachedMaxMembersErrorCode:
+		return &ClanReachedMaxMembersError{r, details.ClanPublicID}
+	case PlayerReachedMaxClansErrorCode:
+		return &PlayerReachedMaxClansError{r, details.PlayerPublicID}
+	case PlayerReachedMaxInvitesErrorCode:
+		return &PlayerReachedMaxInvitesError{r, details.PlayerPublicID}
+	case AlreadyHasValidMembershipErrorCode:
+		return &AlreadyHasValidMembershipError{r, details.PlayerPublicID, details.ClanPublicID}
+	case MustWaitMembershipCooldownErrorCode:
+		return &MustWaitMembershipCooldownError{r, details.PlayerPublicID, details.ClanPublicID, details.Time}
+	case PlayerBannedFromClanErrorCode:
+		return &PlayerBannedFromClanError{r, details.PlayerPublicID, details.ClanPublicID, details.ExpiresAt}
+	case GameArchivedErrorCode:
+		return &GameArchivedError{r, details.GameID}
+	}
+
+	switch {
+	case statusCode == http.StatusBadRequest:
+		return &BadRequestError{r}
+	case statusCode == http.StatusUnauthorized:
+		return &UnauthorizedError{r}
+	case statusCode == http.StatusForbidden:
+		return &ForbiddenError{r}
+	case statusCode == http.StatusNotFound:
+		return &NotFoundError{r}
+	case statusCode == http.StatusConflict:
+		return &ConflictError{r, payload.Version}
+	case statusCode == http.StatusUnprocessableEntity:
+		return &UnprocessableEntityError{r}
+	case statusCode >= http.StatusInternalServerError:
+		return &ServerError{r}
+	}
+	return r
+}
+
+// GetRequestError returns the RequestError of a failed request, whatever error type it is wrapped in,
+// or false if err does not come from a response of khan
+func GetRequestError(err error) (*RequestError, bool) {
+	wrapper, ok := err.(interface {
+		requestError() *RequestError
+	})
+	if !ok {
+		return nil, false
+	}
+	return wrapper.requestError(), true
+}
+
+func (r *RequestError) requestError() *RequestError {
+	return r
+}
+
+func (r *RequestError) Error() string {
+	return fmt.Sprintf("Request error. Status code: %d. Body: %s", r.statusCode, r.body)
+}
+
+//Status returns the status code of the error
+func (r *RequestError) Status() int {
+	return r.statusCode
+}
+
+// Body returns the body of the failed response
+func (r *RequestError) Body() string {
+	return r.body
+}
+
+// Reason returns the reason khan gave for the failure, if any
+func (r *RequestError) Reason() string {
+	return r.reason
+}
+
+// Code returns the error code khan gave for the failure, if any
+func (r *RequestError) Code() string {
+	return r.code
+}
+
+// BadRequestError is returned when khan rejects the parameters or payload of a request (status 400)
+type BadRequestError struct {
+	*RequestError
+}
+
+// UnauthorizedError is returned when the credentials of the client are not valid (status 401)
+type UnauthorizedError struct {
+	*RequestError
+}
+
+// ForbiddenError is returned when the requestor can't perform the action, e.g. a player
+// without the membership level to promote another one (status 403)
+type ForbiddenError struct {
+	*RequestError
+}
+
+// NotFoundError is returned when the game, player, clan, membership or hook of a request
+// does not exist (status 404)
+type NotFoundError struct {
+	*RequestError
+}
+
+// ConflictError is returned when a request conflicts with the current state of khan, e.g. a player
+// that already has a membership, an archived game or an outdated version (status 409)
+type ConflictError struct {
+	*RequestError
+	version int64
+}
+
+// Version returns the current version of the clan or player when the conflict is a version
+// mismatch, or 0 otherwise
+func (e *ConflictError) Version() int64 {
+	return e.version
+}
+
+// UnprocessableEntityError is returned when a payload is well formed but not valid (status 422)
+type UnprocessableEntityError struct {
+	*RequestError
+}
+
+// ServerError is returned when khan fails to process a request (status 5xx)
+type ServerError struct {
+	*RequestError
+}
+
+// ClanReachedMaxMembersError is returned when a clan already has the max number of members of the game
+type ClanReachedMaxMembersError struct {
+	*RequestError
+	ClanPublicID string
+}
+
+// PlayerReachedMaxClansError is returned when a player already is a member of the max number of clans
+// of the game
+type PlayerReachedMaxClansError struct {
+	*RequestError
+	PlayerPublicID string
+}
+
+// PlayerReachedMaxInvitesError is returned when a player already has the max number of pending invites
+// of the game
+type PlayerReachedMaxInvitesError struct {
+	*RequestError
+	PlayerPublicID string
+}
+
+// AlreadyHasValidMembershipError is returned when a player already is a member of the clan
+type AlreadyHasValidMembershipError struct {
+	*RequestError
+	PlayerPublicID string
+	ClanPublicID   string
+}
+
+// MustWaitMembershipCooldownError is returned when a membership can't be created until the cooldown of
+// the game is over
+type MustWaitMembershipCooldownError struct {
+	*RequestError
+	PlayerPublicID string
+	ClanPublicID   string
+	// Time is the number of seconds left before the membership can be created
+	Time int
+}
+
+// PlayerBannedFromClanError is returned when a player can't create a membership because they are banned
+// from the clan
+type PlayerBannedFromClanError struct {
+	*RequestError
+	PlayerPublicID string
+	ClanPublicID   string
+	// ExpiresAt is the time in milliseconds when the ban expires, or 0 if it is permanent
+	ExpiresAt int64
+}
+
+// GameArchivedError is returned when the data of an archived game is changed
+type GameArchivedError struct {
+	*RequestError
+	GameID string
+}
+
+// CircuitOpenError is returned without sending the request when the circuit of its endpoint is open
+type CircuitOpenError struct {
+	Endpoint string
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("Circuit of endpoint %s is open.", e.Endpoint)
+}
@@ -7,19 +7,48 @@ type KhanInterface interface {
 	ApplyForMembership(context.Context, *ApplicationPayload) (*ClanApplyResult, error)
 	ApproveDenyMembershipApplication(context.Context, *ApplicationApprovalPayload) (*Result, error)
 	ApproveDenyMembershipInvitation(context.Context, *InvitationApprovalPayload) (*Result, error)
+	ArchiveGame(context.Context, string) (*ArchiveGameResult, error)
+	BanUnban(context.Context, *BanUnbanPayload) (*BanUnbanResult, error)
+	BatchMemberships(context.Context, *BatchMembershipsPayload) (*BatchMembershipsResult, error)
 	CreateClan(context.Context, *ClanPayload) (string, error)
+	CreateGame(context.Context, *GamePayload) (string, error)
+	CreateHook(context.Context, *HookPayload) (string, error)
 	CreatePlayer(context.Context, string, string, interface{}) (string, error)
+	DeleteClan(context.Context, string, string) (*Result, error)
+	DeleteClanAsAdmin(context.Context, string) (*Result, error)
 	DeleteMembership(context.Context, *DeleteMembershipPayload) (*Result, error)
+	ForGame(string) KhanInterface
+	Healthcheck(context.Context) error
 	InviteForMembership(context.Context, *InvitationPayload) (*Result, error)
 	LeaveClan(context.Context, string) (*LeaveClanResult, error)
+	ListClans(context.Context, *ListClansOptions) (*ListClansResult, error)
+	ListGames(context.Context, *ListGamesOptions) (*ListGamesResult, error)
+	ListHooks(context.Context) ([]*Hook, error)
+	PatchClan(context.Context, *ClanPatchPayload) (*ClanPatchResult, error)
+	PatchGame(context.Context, *GamePatchPayload) (*GamePatchResult, error)
+	PatchPlayer(context.Context, *PlayerPatchPayload) (*PlayerPatchResult, error)
 	PromoteDemote(context.Context, *PromoteDemotePayload) (*Result, error)
+	RemoveHook(context.Context, string) (*Result, error)
+	RetrieveAuditEntries(context.Context, *AuditEntriesOptions) (*AuditEntriesResult, error)
 	RetrieveClan(context.Context, string) (*Clan, error)
 	RetrieveClansSummary(context.Context, []string) ([]*ClanSummary, error)
 	RetrieveClanMembers(context.Context, string) (*ClanMembers, error)
+	RetrieveClanMembersPage(context.Context, *ClanMembersOptions) (*ClanMembersPage, error)
 	RetrieveClanSummary(context.Context, string) (*ClanSummary, error)
+	RetrieveGame(context.Context, string) (*Game, error)
+	RetrieveHook(context.Context, string) (*Hook, error)
+	RetrieveHookDeliveries(context.Context, string, int) ([]*HookDelivery, error)
 	RetrievePlayer(context.Context, string) (*Player, error)
+	RetrievePlayerMemberships(context.Context, *PlayerMembershipsOptions) (*PlayerMembershipsResult, error)
+	Status(context.Context) (*Status, error)
+	TestHook(context.Context, string) (*TestHookResult, error)
+	TopClans(context.Context, *TopClansOptions) (*TopClansResult, error)
 	TransferOwnership(context.Context, string, string) (*TransferOwnershipResult, error)
+	UnarchiveGame(context.Context, string) (*ArchiveGameResult, error)
 	UpdateClan(context.Context, *ClanPayload) (*Result, error)
+	UpdateGame(context.Context, *GamePayload) (*Result, error)
+	UpdateHook(context.Context, *HookPayload) (*Hook, error)
 	UpdatePlayer(context.Context, string, string, interface{}) (*Result, error)
 	SearchClans(context.Context, string) (*SearchClansResult, error)
+	SearchClansWithPageSize(context.Context, string, int) (*SearchClansResult, error)
 }
@@ -8,22 +8,27 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
+	uuid "github.com/satori/go.uuid"
 	"github.com/spf13/viper"
 	ehttp "github.com/topfreegames/extensions/http"
 )
 
 // Khan is a struct that represents a khan API application
 type Khan struct {
-	httpClient *http.Client
-	Config     *viper.Viper
-	url        string
-	user       string
-	pass       string
-	gameID     string
+	httpClient      *http.Client
+	Config          *viper.Viper
+	url             string
+	authenticator   Authenticator
+	gameID          string
+	retryPolicy     *RetryPolicy
+	circuitBreakers *circuitBreakers
+	hedgeDelay      time.Duration
+	metrics         MetricsReporter
 }
 
 // KhanParams represents the params to create a Khan client
@@ -35,24 +40,31 @@ type KhanParams struct {
 	User                string
 	Pass                string
 	GameID              string
+	// Authenticator sets the credentials of the requests. If nil, basic auth with User and Pass is used
+	Authenticator Authenticator
+	// HTTPClient is used instead of a new client with Timeout, MaxIdleConns and MaxIdleConnsPerHost,
+	// so several instances can share connections
+	HTTPClient *http.Client
+	// RetryPolicy retries the requests that fail because khan is unavailable. If nil, requests are sent once
+	RetryPolicy *RetryPolicy
+	// CircuitBreaker enables a circuit breaker for each endpoint. If nil, circuits are never opened
+	CircuitBreaker *CircuitBreakerSettings
+	// HedgeDelay is how long a safe request may take before a second one is sent, using the response
+	// that arrives first. Hedging is disabled if it is 0
+	HedgeDelay time.Duration
+	// Metrics is notified of retries, hedged requests and circuit breaker changes
+	Metrics MetricsReporter
 }
 
-var (
-	client *http.Client
-	once   sync.Once
-)
-
 func getHTTPClient(
 	timeout time.Duration,
 	maxIdleConns, maxIdleConnsPerHost int,
 ) *http.Client {
-	once.Do(func() {
-		client = &http.Client{
-			Transport: getHTTPTransport(maxIdleConns, maxIdleConnsPerHost),
-			Timeout:   timeout,
-		}
-		ehttp.Instrument(client)
-	})
+	client := &http.Client{
+		Transport: getHTTPTransport(maxIdleConns, maxIdleConnsPerHost),
+		Timeout:   timeout,
+	}
+	ehttp.Instrument(client)
 	return client
 }
 
@@ -82,24 +94,11 @@ func getHTTPTransport(
 	}
 }
 
-// NewKhan returns a new khan API application
+// NewKhan returns a new khan API application. Requests are authenticated with the bearer token
+// in khan.token if it is set, or with khan.user and khan.pass otherwise
 func NewKhan(config *viper.Viper) KhanInterface {
-	config.SetDefault("khan.timeout", 500*time.Millisecond)
-	config.SetDefault("khan.maxIdleConnsPerHost", http.DefaultMaxIdleConnsPerHost)
-	config.SetDefault("khan.maxIdleConns", 100)
-
-	k := &Khan{
-		httpClient: getHTTPClient(
-			config.GetDuration("khan.timeout"),
-			config.GetInt("khan.maxIdleConns"),
-			config.GetInt("khan.maxIdleConnsPerHost"),
-		),
-		Config: config,
-		url:    config.GetString("khan.url"),
-		user:   config.GetString("khan.user"),
-		pass:   config.GetString("khan.pass"),
-		gameID: config.GetString("khan.gameid"),
-	}
+	k := newKhan(NewKhanParamsFromConfig(config))
+	k.Config = config
 	return k
 }
 
@@ -112,31 +111,178 @@ func NewKhanParams() *KhanParams {
 	}
 }
 
+// NewKhanParamsFromConfig returns a new KhanParams instance with the values of the khan.* config
+// keys, so the params not available in the config, e.g. Metrics, can be set before creating the client
+func NewKhanParamsFromConfig(config *viper.Viper) *KhanParams {
+	config.SetDefault("khan.timeout", 500*time.Millisecond)
+	config.SetDefault("khan.maxIdleConnsPerHost", http.DefaultMaxIdleConnsPerHost)
+	config.SetDefault("khan.maxIdleConns", 100)
+	config.SetDefault("khan.retry.maxAttempts", 1)
+	config.SetDefault("khan.retry.backoff", 50*time.Millisecond)
+	config.SetDefault("khan.retry.maxBackoff", time.Second)
+	config.SetDefault("khan.retry.mutations", false)
+	config.SetDefault("khan.circuitBreaker.enabled", false)
+	config.SetDefault("khan.circuitBreaker.failureThreshold", 5)
+	config.SetDefault("khan.circuitBreaker.openTimeout", 10*time.Second)
+	config.SetDefault("khan.hedging.delay", 0)
+
+	params := &KhanParams{
+		Timeout:             config.GetDuration("khan.timeout"),
+		MaxIdleConns:        config.GetInt("khan.maxIdleConns"),
+		MaxIdleConnsPerHost: config.GetInt("khan.maxIdleConnsPerHost"),
+		URL:                 config.GetString("khan.url"),
+		User:                config.GetString("khan.user"),
+		Pass:                config.GetString("khan.pass"),
+		GameID:              config.GetString("khan.gameid"),
+		HedgeDelay:          config.GetDuration("khan.hedging.delay"),
+	}
+	if token := config.GetString("khan.token"); token != "" {
+		params.Authenticator = &BearerTokenAuthenticator{Token: token}
+	}
+	if maxAttempts := config.GetInt("khan.retry.maxAttempts"); maxAttempts > 1 {
+		params.RetryPolicy = &RetryPolicy{
+			MaxAttempts:    maxAttempts,
+			Backoff:        config.GetDuration("khan.retry.backoff"),
+			MaxBackoff:     config.GetDuration("khan.retry.maxBackoff"),
+			RetryMutations: config.GetBool("khan.retry.mutations"),
+		}
+	}
+	if config.GetBool("khan.circuitBreaker.enabled") {
+		params.CircuitBreaker = &CircuitBreakerSettings{
+			FailureThreshold: config.GetInt("khan.circuitBreaker.failureThreshold"),
+			OpenTimeout:      config.GetDuration("khan.circuitBreaker.openTimeout"),
+		}
+	}
+	return params
+}
+
 // NewKhanWithParams returns a new khan API application initialized with passed params
 func NewKhanWithParams(params *KhanParams) KhanInterface {
-	return &Khan{
-		httpClient: getHTTPClient(
+	return newKhan(params)
+}
+
+func newKhan(params *KhanParams) *Khan {
+	httpClient := params.HTTPClient
+	if httpClient == nil {
+		httpClient = getHTTPClient(
 			params.Timeout,
 			params.MaxIdleConns,
 			params.MaxIdleConnsPerHost,
-		),
-		url:    params.URL,
-		user:   params.User,
-		pass:   params.Pass,
-		gameID: params.GameID,
+		)
+	}
+	authenticator := params.Authenticator
+	if authenticator == nil {
+		authenticator = &BasicAuthenticator{User: params.User, Pass: params.Pass}
+	}
+	retryPolicy := params.RetryPolicy
+	if retryPolicy == nil {
+		retryPolicy = &RetryPolicy{MaxAttempts: 1}
+	}
+	var breakers *circuitBreakers
+	if params.CircuitBreaker != nil {
+		breakers = newCircuitBreakers(params.CircuitBreaker)
+	}
+	var metrics MetricsReporter = nopMetricsReporter{}
+	if params.Metrics != nil {
+		metrics = params.Metrics
+	}
+	return &Khan{
+		httpClient:      httpClient,
+		url:             params.URL,
+		authenticator:   authenticator,
+		gameID:          params.GameID,
+		retryPolicy:     retryPolicy,
+		circuitBreakers: breakers,
+		hedgeDelay:      params.HedgeDelay,
+		metrics:         metrics,
 	}
 }
 
-func (k *Khan) sendTo(ctx context.Context, method, url string, payload interface{}) ([]byte, error) {
+// ForGame returns a client that sends its requests to the given game. It shares the connections,
+// configuration and credentials of k, so it is cheap enough to be called for every request. Circuits
+// are still opened separately for each game
+func (k *Khan) ForGame(gameID string) KhanInterface {
+	game := *k
+	game.gameID = gameID
+	return &game
+}
+
+// sendTo sends a request to khan, retrying, hedging and short-circuiting it as configured. The endpoint
+// identifies the circuit breaker of the request and is reported to the metrics
+func (k *Khan) sendTo(ctx context.Context, endpoint, method, url string, payload interface{}) ([]byte, error) {
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
+	if payload == nil {
+		payloadJSON = nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	safe := isSafeMethod(method)
+	idempotencyKey := getContextIdempotencyKey(ctx)
+	if !safe && idempotencyKey == "" && k.retryPolicy.RetryMutations && k.retryPolicy.MaxAttempts > 1 {
+		idempotencyKey = uuid.NewV4().String()
+	}
+	retryable := safe || idempotencyKey != ""
+
+	for attempt := 1; ; attempt++ {
+		var breaker *circuitBreaker
+		if k.circuitBreakers != nil {
+			breaker = k.circuitBreakers.get(k.gameID, endpoint)
+			if !breaker.allow() {
+				k.metrics.ReportCircuitRejected(endpoint)
+				return nil, &CircuitOpenError{Endpoint: endpoint}
+			}
+		}
 
+		req, err := k.newRequest(ctx, method, url, payloadJSON, idempotencyKey)
+		if err != nil {
+			return nil, err
+		}
+		var body []byte
+		if safe && k.hedgeDelay > 0 {
+			body, err = k.doHedged(ctx, endpoint, req)
+		} else {
+			body, err = k.do(req)
+		}
+
+		unavailable := isUnavailableError(err) && ctx.Err() == nil
+		if breaker != nil && ctx.Err() != nil {
+			// the caller gave up on the request, which tells nothing about khan
+			breaker.release()
+		} else if breaker != nil {
+			if state, changed := breaker.record(unavailable); changed {
+				if state == circuitOpen {
+					k.metrics.ReportCircuitOpened(endpoint)
+				} else if state == circuitClosed {
+					k.metrics.ReportCircuitClosed(endpoint)
+				}
+			}
+		}
+		if !unavailable || !retryable || attempt >= k.retryPolicy.MaxAttempts {
+			return body, err
+		}
+
+		k.metrics.ReportRetry(endpoint, attempt, err)
+		timer := time.NewTimer(k.retryPolicy.getBackoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+	}
+}
+
+func (k *Khan) newRequest(ctx context.Context, method, url string, payload []byte, idempotencyKey string) (*http.Request, error) {
 	var req *http.Request
+	var err error
 
 	if payload != nil {
-		req, err = http.NewRequest(method, url, bytes.NewBuffer(payloadJSON))
+		req, err = http.NewRequest(method, url, bytes.NewReader(payload))
 		if err != nil {
 			return nil, err
 		}
@@ -147,12 +293,24 @@ func (k *Khan) sendTo(ctx context.Context, method, url string, payload interface
 		}
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(k.user, k.pass)
-	if ctx == nil {
-		ctx = context.Background()
+	for header, value := range getContextHeaders(ctx) {
+		req.Header.Set(header, value)
+	}
+	if idempotencyKey != "" && !isSafeMethod(method) {
+		req.Header.Set(IdempotencyKeyHeader, idempotencyKey)
 	}
-	req = req.WithContext(ctx)
+	authenticator := getContextAuthenticator(ctx)
+	if authenticator == nil {
+		authenticator = k.authenticator
+	}
+	err = authenticator.Authenticate(req)
+	if err != nil {
+		return nil, err
+	}
+	return req.WithContext(ctx), nil
+}
 
+func (k *Khan) do(req *http.Request) ([]byte, error) {
 	resp, err := k.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -171,6 +329,41 @@ func (k *Khan) sendTo(ctx context.Context, method, url string, payload interface
 	return body, nil
 }
 
+// doHedged sends the request and, if it takes longer than the hedge delay, a copy of it. The first
+// response that does not tell khan is unavailable is used, and the other request is cancelled
+func (k *Khan) doHedged(ctx context.Context, endpoint string, req *http.Request) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type response struct {
+		body []byte
+		err  error
+	}
+	responses := make(chan response, 2)
+	send := func(req *http.Request) {
+		body, err := k.do(req.WithContext(ctx))
+		responses <- response{body, err}
+	}
+
+	go send(req)
+	pending := 1
+	timer := time.NewTimer(k.hedgeDelay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			k.metrics.ReportHedge(endpoint)
+			pending++
+			go send(req)
+		case res := <-responses:
+			pending--
+			if !isUnavailableError(res.err) || pending == 0 {
+				return res.body, res.err
+			}
+		}
+	}
+}
+
 func (k *Khan) buildURL(pathname string) string {
 	return fmt.Sprintf("%s/games/%s/%s", k.url, k.gameID, pathname)
 }
@@ -260,11 +453,220 @@ func (k *Khan) buildTransferOwnershipURL(clanID string) string {
 	return k.buildURL(pathname)
 }
 
-func (k *Khan) buildSearchClansURL(clanName string) string {
-	pathname := fmt.Sprintf("clans/search?term=%s", clanName)
+func (k *Khan) buildSearchClansURL(clanName string, pageSize int) string {
+	query := url.Values{}
+	query.Set("term", clanName)
+	if pageSize > 0 {
+		query.Set("pageSize", strconv.Itoa(pageSize))
+	}
+	pathname := fmt.Sprintf("clans/search?%s", query.Encode())
+	return k.buildURL(pathname)
+}
+
+func (k *Khan) buildHealthcheckURL() string {
+	return fmt.Sprintf("%s/healthcheck", k.url)
+}
+
+func (k *Khan) buildStatusURL() string {
+	return fmt.Sprintf("%s/status", k.url)
+}
+
+func (k *Khan) buildGamesURL() string {
+	return fmt.Sprintf("%s/games", k.url)
+}
+
+func (k *Khan) buildListGamesURL(options *ListGamesOptions) string {
+	query := url.Values{}
+	if options.Limit > 0 {
+		query.Set("limit", strconv.Itoa(options.Limit))
+	}
+	if options.Cursor != "" {
+		query.Set("cursor", options.Cursor)
+	}
+	if options.Archived != nil {
+		query.Set("archived", strconv.FormatBool(*options.Archived))
+	}
+	return buildURLWithQuery(k.buildGamesURL(), query)
+}
+
+func (k *Khan) buildGameURL(gameID string) string {
+	return fmt.Sprintf("%s/games/%s", k.url, gameID)
+}
+
+func (k *Khan) buildArchiveGameURL(gameID string) string {
+	return fmt.Sprintf("%s/games/%s/archive", k.url, gameID)
+}
+
+func (k *Khan) buildAuditEntriesURL(options *AuditEntriesOptions) string {
+	pathname := "audit"
+	if options.ClanPublicID != "" {
+		pathname = fmt.Sprintf("clans/%s/audit", options.ClanPublicID)
+	} else if options.PlayerPublicID != "" {
+		pathname = fmt.Sprintf("players/%s/audit", options.PlayerPublicID)
+	}
+	query := url.Values{}
+	if options.Limit > 0 {
+		query.Set("limit", strconv.Itoa(options.Limit))
+	}
+	if options.Cursor != "" {
+		query.Set("cursor", options.Cursor)
+	}
+	if options.Since > 0 {
+		query.Set("since", strconv.FormatInt(options.Since, 10))
+	}
+	if options.Until > 0 {
+		query.Set("until", strconv.FormatInt(options.Until, 10))
+	}
+	return buildURLWithQuery(k.buildURL(pathname), query)
+}
+
+func (k *Khan) buildHooksURL() string {
+	pathname := "hooks"
+	return k.buildURL(pathname)
+}
+
+func (k *Khan) buildHookURL(hookID string) string {
+	pathname := fmt.Sprintf("hooks/%s", hookID)
+	return k.buildURL(pathname)
+}
+
+func (k *Khan) buildTestHookURL(hookID string) string {
+	pathname := fmt.Sprintf("hooks/%s/test", hookID)
+	return k.buildURL(pathname)
+}
+
+func (k *Khan) buildHookDeliveriesURL(hookID string, limit int) string {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	pathname := fmt.Sprintf("hooks/%s/deliveries", hookID)
+	return buildURLWithQuery(k.buildURL(pathname), query)
+}
+
+func (k *Khan) buildPatchPlayerURL(playerID string) string {
+	pathname := fmt.Sprintf("players/%s", playerID)
+	return k.buildURL(pathname)
+}
+
+func (k *Khan) buildRetrievePlayerMembershipsURL(options *PlayerMembershipsOptions) string {
+	query := url.Values{}
+	if options.Limit > 0 {
+		query.Set("limit", strconv.Itoa(options.Limit))
+	}
+	if options.Cursor != "" {
+		query.Set("cursor", options.Cursor)
+	}
+	if options.ClanPublicID != "" {
+		query.Set("clanPublicID", options.ClanPublicID)
+	}
+	if len(options.Statuses) > 0 {
+		query.Set("status", strings.Join(options.Statuses, ","))
+	}
+	if options.Order != "" {
+		query.Set("order", options.Order)
+	}
+	pathname := fmt.Sprintf("players/%s/memberships", options.PlayerPublicID)
+	return buildURLWithQuery(k.buildURL(pathname), query)
+}
+
+func (k *Khan) buildListClansURL(options *ListClansOptions) string {
+	query := url.Values{}
+	if options.Limit > 0 {
+		query.Set("limit", strconv.Itoa(options.Limit))
+	}
+	if options.Cursor != "" {
+		query.Set("cursor", options.Cursor)
+	}
+	if options.OrderBy != "" {
+		query.Set("orderBy", options.OrderBy)
+	}
+	if options.Order != "" {
+		query.Set("order", options.Order)
+	}
+	if options.AllowApplication != nil {
+		query.Set("allowApplication", strconv.FormatBool(*options.AllowApplication))
+	}
+	if options.AutoJoin != nil {
+		query.Set("autoJoin", strconv.FormatBool(*options.AutoJoin))
+	}
+	if options.MinMembershipCount != nil {
+		query.Set("minMembershipCount", strconv.Itoa(*options.MinMembershipCount))
+	}
+	if options.MaxMembershipCount != nil {
+		query.Set("maxMembershipCount", strconv.Itoa(*options.MaxMembershipCount))
+	}
+	for field, value := range options.Metadata {
+		query.Set(fmt.Sprintf("metadata.%s", field), value)
+	}
+	return buildURLWithQuery(k.buildCreateClanURL(), query)
+}
+
+func (k *Khan) buildPatchClanURL(clanID string) string {
+	pathname := fmt.Sprintf("clans/%s", clanID)
 	return k.buildURL(pathname)
 }
 
+func (k *Khan) buildDeleteClanURL(clanID, requestorPublicID string, admin bool) string {
+	query := url.Values{}
+	if admin {
+		query.Set("admin", "true")
+	} else {
+		query.Set("requestorPublicID", requestorPublicID)
+	}
+	pathname := fmt.Sprintf("clans/%s", clanID)
+	return buildURLWithQuery(k.buildURL(pathname), query)
+}
+
+func (k *Khan) buildRetrieveClanMembersPageURL(options *ClanMembersOptions) string {
+	query := url.Values{}
+	if options.Limit > 0 {
+		query.Set("limit", strconv.Itoa(options.Limit))
+	}
+	if options.Cursor != "" {
+		query.Set("cursor", options.Cursor)
+	}
+	if options.Level != "" {
+		query.Set("level", options.Level)
+	}
+	if options.OrderBy != "" {
+		query.Set("orderBy", options.OrderBy)
+	}
+	if options.Order != "" {
+		query.Set("order", options.Order)
+	}
+	return buildURLWithQuery(k.buildRetrieveClanMembersURL(options.ClanID), query)
+}
+
+func (k *Khan) buildTopClansURL(options *TopClansOptions) string {
+	query := url.Values{}
+	if options.Limit > 0 {
+		query.Set("limit", strconv.Itoa(options.Limit))
+	}
+	if options.ClanPublicID != "" {
+		query.Set("clanPublicID", options.ClanPublicID)
+	}
+	pathname := fmt.Sprintf("clans-top/%s", options.Dimension)
+	return buildURLWithQuery(k.buildURL(pathname), query)
+}
+
+func (k *Khan) buildBanUnbanURL(clanID, action string) string {
+	pathname := fmt.Sprintf("clans/%s/memberships/%s", clanID, action)
+	return k.buildURL(pathname)
+}
+
+func (k *Khan) buildBatchMembershipsURL(clanID string) string {
+	pathname := fmt.Sprintf("clans/%s/memberships/batch", clanID)
+	return k.buildURL(pathname)
+}
+
+func buildURLWithQuery(route string, query url.Values) string {
+	if len(query) == 0 {
+		return route
+	}
+	return fmt.Sprintf("%s?%s", route, query.Encode())
+}
+
 // CreatePlayer calls Khan to create a new player
 func (k *Khan) CreatePlayer(ctx context.Context, publicID, name string, metadata interface{}) (string, error) {
 	route := k.buildCreatePlayerURL()
@@ -273,7 +675,7 @@ func (k *Khan) CreatePlayer(ctx context.Context, publicID, name string, metadata
 		Name:     name,
 		Metadata: metadata,
 	}
-	body, err := k.sendTo(ctx, "POST", route, playerPayload)
+	body, err := k.sendTo(ctx, "CreatePlayer", "POST", route, playerPayload)
 
 	if err != nil {
 		return "", err
@@ -293,7 +695,7 @@ func (k *Khan) UpdatePlayer(
 ) (*Result, error) {
 	route := k.buildUpdatePlayerURL(publicID)
 	playerPayload := &Player{Name: name, Metadata: metadata}
-	body, err := k.sendTo(ctx, "PUT", route, playerPayload)
+	body, err := k.sendTo(ctx, "UpdatePlayer", "PUT", route, playerPayload)
 	if err != nil {
 		return nil, err
 	}
@@ -306,7 +708,7 @@ func (k *Khan) UpdatePlayer(
 // RetrievePlayer calls the retrieve player route from khan
 func (k *Khan) RetrievePlayer(ctx context.Context, publicID string) (*Player, error) {
 	route := k.buildRetrievePlayerURL(publicID)
-	body, err := k.sendTo(ctx, "GET", route, nil)
+	body, err := k.sendTo(ctx, "RetrievePlayer", "GET", route, nil)
 
 	if err != nil {
 		return nil, err
@@ -320,7 +722,7 @@ func (k *Khan) RetrievePlayer(ctx context.Context, publicID string) (*Player, er
 // CreateClan calls the create clan route from khan
 func (k *Khan) CreateClan(ctx context.Context, clan *ClanPayload) (string, error) {
 	route := k.buildCreateClanURL()
-	body, err := k.sendTo(ctx, "POST", route, clan)
+	body, err := k.sendTo(ctx, "CreateClan", "POST", route, clan)
 
 	if err != nil {
 		return "", err
@@ -334,7 +736,7 @@ func (k *Khan) CreateClan(ctx context.Context, clan *ClanPayload) (string, error
 // UpdateClan calls the update clan route from khan
 func (k *Khan) UpdateClan(ctx context.Context, clan *ClanPayload) (*Result, error) {
 	route := k.buildUpdateClanURL(clan.PublicID)
-	body, err := k.sendTo(ctx, "PUT", route, clan)
+	body, err := k.sendTo(ctx, "UpdateClan", "PUT", route, clan)
 	if err != nil {
 		return nil, err
 	}
@@ -347,7 +749,7 @@ func (k *Khan) UpdateClan(ctx context.Context, clan *ClanPayload) (*Result, erro
 // RetrieveClanMembers calls the route to retrieve clan members from khan
 func (k *Khan) RetrieveClanMembers(ctx context.Context, clanID string) (*ClanMembers, error) {
 	route := k.buildRetrieveClanMembersURL(clanID)
-	body, err := k.sendTo(ctx, "GET", route, nil)
+	body, err := k.sendTo(ctx, "RetrieveClanMembers", "GET", route, nil)
 
 	if err != nil {
 		return nil, err
@@ -361,7 +763,7 @@ func (k *Khan) RetrieveClanMembers(ctx context.Context, clanID string) (*ClanMem
 // RetrieveClanSummary calls the route to retrieve clan summary from khan
 func (k *Khan) RetrieveClanSummary(ctx context.Context, clanID string) (*ClanSummary, error) {
 	route := k.buildRetrieveClanSummaryURL(clanID)
-	body, err := k.sendTo(ctx, "GET", route, nil)
+	body, err := k.sendTo(ctx, "RetrieveClanSummary", "GET", route, nil)
 
 	if err != nil {
 		return nil, err
@@ -375,7 +777,7 @@ func (k *Khan) RetrieveClanSummary(ctx context.Context, clanID string) (*ClanSum
 // RetrieveClansSummary calls the route to retrieve clans summary from khan
 func (k *Khan) RetrieveClansSummary(ctx context.Context, clanIDs []string) ([]*ClanSummary, error) {
 	route := k.buildRetrieveClansSummaryURL(clanIDs)
-	body, err := k.sendTo(ctx, "GET", route, nil)
+	body, err := k.sendTo(ctx, "RetrieveClansSummary", "GET", route, nil)
 
 	if err != nil {
 		return nil, err
@@ -392,7 +794,7 @@ func (k *Khan) RetrieveClansSummary(ctx context.Context, clanIDs []string) ([]*C
 // RetrieveClan calls the route to retrieve clan from khan
 func (k *Khan) RetrieveClan(ctx context.Context, clanID string) (*Clan, error) {
 	route := k.buildRetrieveClanURL(clanID)
-	body, err := k.sendTo(ctx, "GET", route, nil)
+	body, err := k.sendTo(ctx, "RetrieveClan", "GET", route, nil)
 
 	if err != nil {
 		return nil, err
@@ -409,7 +811,7 @@ func (k *Khan) ApplyForMembership(
 	payload *ApplicationPayload,
 ) (*ClanApplyResult, error) {
 	route := k.buildApplyForMembershipURL(payload.ClanID)
-	body, err := k.sendTo(ctx, "POST", route, payload)
+	body, err := k.sendTo(ctx, "ApplyForMembership", "POST", route, payload)
 
 	if err != nil {
 		return nil, err
@@ -426,7 +828,7 @@ func (k *Khan) InviteForMembership(
 	payload *InvitationPayload,
 ) (*Result, error) {
 	route := k.buildInviteForMembershipURL(payload.ClanID)
-	return k.defaultPostRequest(ctx, route, payload)
+	return k.defaultPostRequest(ctx, "InviteForMembership", route, payload)
 }
 
 // ApproveDenyMembershipApplication approves or deny player
@@ -436,7 +838,7 @@ func (k *Khan) ApproveDenyMembershipApplication(
 	payload *ApplicationApprovalPayload,
 ) (*Result, error) {
 	route := k.buildApproveDenyMembershipApplicationURL(payload.ClanID, payload.Action)
-	return k.defaultPostRequest(ctx, route, payload)
+	return k.defaultPostRequest(ctx, "ApproveDenyMembershipApplication", route, payload)
 }
 
 // ApproveDenyMembershipInvitation approves or deny player
@@ -446,7 +848,7 @@ func (k *Khan) ApproveDenyMembershipInvitation(
 	payload *InvitationApprovalPayload,
 ) (*Result, error) {
 	route := k.buildApproveDenyMembershipInvitationURL(payload.ClanID, payload.Action)
-	return k.defaultPostRequest(ctx, route, payload)
+	return k.defaultPostRequest(ctx, "ApproveDenyMembershipInvitation", route, payload)
 }
 
 // PromoteDemote promotes or demotes player on clan
@@ -455,7 +857,7 @@ func (k *Khan) PromoteDemote(
 	payload *PromoteDemotePayload,
 ) (*Result, error) {
 	route := k.buildPromoteDemoteURL(payload.ClanID, payload.Action)
-	return k.defaultPostRequest(ctx, route, payload)
+	return k.defaultPostRequest(ctx, "PromoteDemote", route, payload)
 }
 
 // DeleteMembership deletes membership on clan
@@ -464,7 +866,7 @@ func (k *Khan) DeleteMembership(
 	payload *DeleteMembershipPayload,
 ) (*Result, error) {
 	route := k.buildDeleteMembershipURL(payload.ClanID)
-	return k.defaultPostRequest(ctx, route, payload)
+	return k.defaultPostRequest(ctx, "DeleteMembership", route, payload)
 }
 
 // LeaveClan allows member to leave clan
@@ -473,7 +875,7 @@ func (k *Khan) LeaveClan(
 	clanID string,
 ) (*LeaveClanResult, error) {
 	route := k.buildLeaveClanURL(clanID)
-	body, err := k.sendTo(ctx, "POST", route, nil)
+	body, err := k.sendTo(ctx, "LeaveClan", "POST", route, nil)
 
 	if err != nil {
 		return nil, err
@@ -490,7 +892,7 @@ func (k *Khan) TransferOwnership(
 	playerPublicID, clanID string,
 ) (*TransferOwnershipResult, error) {
 	route := k.buildTransferOwnershipURL(clanID)
-	body, err := k.sendTo(ctx, "POST", route, map[string]interface{}{
+	body, err := k.sendTo(ctx, "TransferOwnership", "POST", route, map[string]interface{}{
 		"playerPublicID": playerPublicID,
 	})
 
@@ -505,10 +907,11 @@ func (k *Khan) TransferOwnership(
 
 func (k *Khan) defaultPostRequest(
 	ctx context.Context,
+	endpoint string,
 	route string,
 	payload interface{},
 ) (*Result, error) {
-	body, err := k.sendTo(ctx, "POST", route, payload)
+	body, err := k.sendTo(ctx, endpoint, "POST", route, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -520,8 +923,14 @@ func (k *Khan) defaultPostRequest(
 
 // SearchClans returns clan summaries for all clans that contain the string "clanName".
 func (k *Khan) SearchClans(ctx context.Context, clanName string) (*SearchClansResult, error) {
-	route := k.buildSearchClansURL(clanName)
-	body, err := k.sendTo(ctx, "GET", route, nil)
+	return k.SearchClansWithPageSize(ctx, clanName, 0)
+}
+
+// SearchClansWithPageSize returns at most pageSize clan summaries for clans that contain the
+// string "clanName". The khan default page size is used when pageSize is 0
+func (k *Khan) SearchClansWithPageSize(ctx context.Context, clanName string, pageSize int) (*SearchClansResult, error) {
+	route := k.buildSearchClansURL(clanName, pageSize)
+	body, err := k.sendTo(ctx, "SearchClansWithPageSize", "GET", route, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -530,3 +939,362 @@ func (k *Khan) SearchClans(ctx context.Context, clanName string) (*SearchClansRe
 	err = json.Unmarshal(body, &result)
 	return &result, err
 }
+
+// Healthcheck calls the healthcheck route from khan, returning an error if khan is not working
+func (k *Khan) Healthcheck(ctx context.Context) error {
+	route := k.buildHealthcheckURL()
+	_, err := k.sendTo(ctx, "Healthcheck", "GET", route, nil)
+	return err
+}
+
+// Status calls the status route from khan
+func (k *Khan) Status(ctx context.Context) (*Status, error) {
+	route := k.buildStatusURL()
+	body, err := k.sendTo(ctx, "Status", "GET", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var status Status
+	err = json.Unmarshal(body, &status)
+	return &status, err
+}
+
+// CreateGame calls the create game route from khan
+func (k *Khan) CreateGame(ctx context.Context, game *GamePayload) (string, error) {
+	route := k.buildGamesURL()
+	body, err := k.sendTo(ctx, "CreateGame", "POST", route, game)
+	if err != nil {
+		return "", err
+	}
+
+	var result Game
+	err = json.Unmarshal(body, &result)
+	return result.PublicID, err
+}
+
+// UpdateGame calls the update game route from khan
+func (k *Khan) UpdateGame(ctx context.Context, game *GamePayload) (*Result, error) {
+	route := k.buildGameURL(game.PublicID)
+	body, err := k.sendTo(ctx, "UpdateGame", "PUT", route, game)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Result
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// PatchGame calls the patch game route from khan
+func (k *Khan) PatchGame(ctx context.Context, payload *GamePatchPayload) (*GamePatchResult, error) {
+	route := k.buildGameURL(payload.PublicID)
+	body, err := k.sendTo(ctx, "PatchGame", "PATCH", route, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var result GamePatchResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// RetrieveGame calls the retrieve game route from khan
+func (k *Khan) RetrieveGame(ctx context.Context, gameID string) (*Game, error) {
+	route := k.buildGameURL(gameID)
+	body, err := k.sendTo(ctx, "RetrieveGame", "GET", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var game Game
+	err = json.Unmarshal(body, &game)
+	return &game, err
+}
+
+// ListGames calls the list games route from khan
+func (k *Khan) ListGames(ctx context.Context, options *ListGamesOptions) (*ListGamesResult, error) {
+	route := k.buildListGamesURL(options)
+	body, err := k.sendTo(ctx, "ListGames", "GET", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ListGamesResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// ArchiveGame archives a game, so khan rejects any change to its data
+func (k *Khan) ArchiveGame(ctx context.Context, gameID string) (*ArchiveGameResult, error) {
+	route := k.buildArchiveGameURL(gameID)
+	return k.archiveGameRequest(ctx, "ArchiveGame", "POST", route)
+}
+
+// UnarchiveGame unarchives a game, so its data can be changed again
+func (k *Khan) UnarchiveGame(ctx context.Context, gameID string) (*ArchiveGameResult, error) {
+	route := k.buildArchiveGameURL(gameID)
+	return k.archiveGameRequest(ctx, "UnarchiveGame", "DELETE", route)
+}
+
+func (k *Khan) archiveGameRequest(ctx context.Context, endpoint, method, route string) (*ArchiveGameResult, error) {
+	body, err := k.sendTo(ctx, endpoint, method, route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ArchiveGameResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// RetrieveAuditEntries calls the audit trail route of the game, clan or player from khan
+func (k *Khan) RetrieveAuditEntries(ctx context.Context, options *AuditEntriesOptions) (*AuditEntriesResult, error) {
+	route := k.buildAuditEntriesURL(options)
+	body, err := k.sendTo(ctx, "RetrieveAuditEntries", "GET", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result AuditEntriesResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// CreateHook calls the create hook route from khan
+func (k *Khan) CreateHook(ctx context.Context, hook *HookPayload) (string, error) {
+	route := k.buildHooksURL()
+	body, err := k.sendTo(ctx, "CreateHook", "POST", route, hook)
+	if err != nil {
+		return "", err
+	}
+
+	var result Hook
+	err = json.Unmarshal(body, &result)
+	return result.PublicID, err
+}
+
+// UpdateHook calls the update hook route from khan
+func (k *Khan) UpdateHook(ctx context.Context, hook *HookPayload) (*Hook, error) {
+	route := k.buildHookURL(hook.PublicID)
+	body, err := k.sendTo(ctx, "UpdateHook", "PUT", route, hook)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Hook
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// RetrieveHook calls the retrieve hook route from khan
+func (k *Khan) RetrieveHook(ctx context.Context, hookID string) (*Hook, error) {
+	route := k.buildHookURL(hookID)
+	body, err := k.sendTo(ctx, "RetrieveHook", "GET", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var hook Hook
+	err = json.Unmarshal(body, &hook)
+	return &hook, err
+}
+
+// ListHooks calls the list hooks route from khan
+func (k *Khan) ListHooks(ctx context.Context) ([]*Hook, error) {
+	route := k.buildHooksURL()
+	body, err := k.sendTo(ctx, "ListHooks", "GET", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ListHooksResult
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Hooks, nil
+}
+
+// RemoveHook calls the remove hook route from khan
+func (k *Khan) RemoveHook(ctx context.Context, hookID string) (*Result, error) {
+	route := k.buildHookURL(hookID)
+	body, err := k.sendTo(ctx, "RemoveHook", "DELETE", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Result
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// TestHook sends a synthetic event to a hook
+func (k *Khan) TestHook(ctx context.Context, hookID string) (*TestHookResult, error) {
+	route := k.buildTestHookURL(hookID)
+	body, err := k.sendTo(ctx, "TestHook", "POST", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result TestHookResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// RetrieveHookDeliveries returns the latest deliveries of a hook, newest first. The khan default
+// limit is used when limit is 0
+func (k *Khan) RetrieveHookDeliveries(ctx context.Context, hookID string, limit int) ([]*HookDelivery, error) {
+	route := k.buildHookDeliveriesURL(hookID, limit)
+	body, err := k.sendTo(ctx, "RetrieveHookDeliveries", "GET", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result HookDeliveriesResult
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Deliveries, nil
+}
+
+// PatchPlayer calls the patch player route from khan
+func (k *Khan) PatchPlayer(ctx context.Context, payload *PlayerPatchPayload) (*PlayerPatchResult, error) {
+	route := k.buildPatchPlayerURL(payload.PublicID)
+	body, err := k.sendTo(ctx, "PatchPlayer", "PATCH", route, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var result PlayerPatchResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// RetrievePlayerMemberships calls the route to retrieve a page of player memberships from khan
+func (k *Khan) RetrievePlayerMemberships(
+	ctx context.Context,
+	options *PlayerMembershipsOptions,
+) (*PlayerMembershipsResult, error) {
+	route := k.buildRetrievePlayerMembershipsURL(options)
+	body, err := k.sendTo(ctx, "RetrievePlayerMemberships", "GET", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result PlayerMembershipsResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// ListClans calls the route to list a page of the game clans from khan
+func (k *Khan) ListClans(ctx context.Context, options *ListClansOptions) (*ListClansResult, error) {
+	route := k.buildListClansURL(options)
+	body, err := k.sendTo(ctx, "ListClans", "GET", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ListClansResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// PatchClan calls the patch clan route from khan
+func (k *Khan) PatchClan(ctx context.Context, payload *ClanPatchPayload) (*ClanPatchResult, error) {
+	route := k.buildPatchClanURL(payload.PublicID)
+	body, err := k.sendTo(ctx, "PatchClan", "PATCH", route, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ClanPatchResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// DeleteClan deletes a clan and ends all of its memberships. Only the clan owner can delete it
+func (k *Khan) DeleteClan(ctx context.Context, clanID, requestorPublicID string) (*Result, error) {
+	route := k.buildDeleteClanURL(clanID, requestorPublicID, false)
+	body, err := k.sendTo(ctx, "DeleteClan", "DELETE", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Result
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// DeleteClanAsAdmin deletes any clan of the game and ends all of its memberships. It requires the
+// basic auth credentials or an admin API key
+func (k *Khan) DeleteClanAsAdmin(ctx context.Context, clanID string) (*Result, error) {
+	route := k.buildDeleteClanURL(clanID, "", true)
+	body, err := k.sendTo(ctx, "DeleteClanAsAdmin", "DELETE", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Result
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// RetrieveClanMembersPage calls the route to retrieve a page of clan members from khan
+func (k *Khan) RetrieveClanMembersPage(ctx context.Context, options *ClanMembersOptions) (*ClanMembersPage, error) {
+	route := k.buildRetrieveClanMembersPageURL(options)
+	body, err := k.sendTo(ctx, "RetrieveClanMembersPage", "GET", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var page ClanMembersPage
+	err = json.Unmarshal(body, &page)
+	return &page, err
+}
+
+// TopClans calls the route to retrieve the top clans of a ranking dimension from khan
+func (k *Khan) TopClans(ctx context.Context, options *TopClansOptions) (*TopClansResult, error) {
+	route := k.buildTopClansURL(options)
+	body, err := k.sendTo(ctx, "TopClans", "GET", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result TopClansResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// BanUnban bans or unbans player from clan
+func (k *Khan) BanUnban(
+	ctx context.Context,
+	payload *BanUnbanPayload,
+) (*BanUnbanResult, error) {
+	route := k.buildBanUnbanURL(payload.ClanID, payload.Action)
+	body, err := k.sendTo(ctx, "BanUnban", "POST", route, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var result BanUnbanResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// BatchMemberships runs several membership operations on clan in a single request
+func (k *Khan) BatchMemberships(
+	ctx context.Context,
+	payload *BatchMembershipsPayload,
+) (*BatchMembershipsResult, error) {
+	route := k.buildBatchMembershipsURL(payload.ClanID)
+	body, err := k.sendTo(ctx, "BatchMemberships", "POST", route, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var result BatchMembershipsResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
@@ -1,6 +1,13 @@
 package lib_test
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
 	"github.com/jarcoal/httpmock"
 	"github.com/spf13/viper"
 	"github.com/topfreegames/khan/lib"
@@ -9,6 +16,45 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+type testMetricsReporter struct {
+	mutex          sync.Mutex
+	retries        int
+	hedges         int
+	openedCircuits []string
+	closedCircuits []string
+	rejected       int
+}
+
+func (r *testMetricsReporter) ReportRetry(endpoint string, attempt int, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.retries++
+}
+
+func (r *testMetricsReporter) ReportHedge(endpoint string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.hedges++
+}
+
+func (r *testMetricsReporter) ReportCircuitOpened(endpoint string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.openedCircuits = append(r.openedCircuits, endpoint)
+}
+
+func (r *testMetricsReporter) ReportCircuitClosed(endpoint string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.closedCircuits = append(r.closedCircuits, endpoint)
+}
+
+func (r *testMetricsReporter) ReportCircuitRejected(endpoint string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.rejected++
+}
+
 var _ = Describe("Lib", func() {
 	var k lib.KhanInterface
 	var config *viper.Viper
@@ -37,6 +83,269 @@ var _ = Describe("Lib", func() {
 		})
 	})
 
+	Describe("ForGame", func() {
+		It("Should call khan API of the given game", func() {
+			httpmock.RegisterResponder("POST", "http://khan/games/othergame/players",
+				httpmock.NewStringResponder(200, `{ "success": true, "publicID": "otherid" }`))
+			httpmock.RegisterResponder("POST", "http://khan/games/"+gameID+"/players",
+				httpmock.NewStringResponder(200, `{ "success": true, "publicID": "testid" }`))
+
+			playerID, err := k.ForGame("othergame").CreatePlayer(nil, "otherid", "othername", nil)
+			Expect(err).To(BeNil())
+			Expect(playerID).To(Equal("otherid"))
+
+			playerID, err = k.CreatePlayer(nil, "testid", "testname", nil)
+			Expect(err).To(BeNil())
+			Expect(playerID).To(Equal("testid"))
+		})
+	})
+
+	Describe("Authentication", func() {
+		var headers http.Header
+
+		BeforeEach(func() {
+			headers = nil
+			httpmock.RegisterResponder("GET", "http://khan/healthcheck", func(req *http.Request) (*http.Response, error) {
+				headers = req.Header
+				return httpmock.NewStringResponse(200, "WORKING"), nil
+			})
+		})
+
+		It("Should use basic auth by default", func() {
+			err := k.Healthcheck(nil)
+
+			Expect(err).To(BeNil())
+			Expect(headers.Get("Authorization")).To(HavePrefix("Basic "))
+		})
+
+		It("Should use the bearer token in the config", func() {
+			config.Set("khan.token", "token")
+			defer config.Set("khan.token", "")
+
+			err := lib.NewKhan(config).Healthcheck(nil)
+
+			Expect(err).To(BeNil())
+			Expect(headers.Get("Authorization")).To(Equal("Bearer token"))
+		})
+
+		It("Should use the authenticator in the params", func() {
+			k = lib.NewKhanWithParams(&lib.KhanParams{
+				URL:           "http://khan",
+				GameID:        gameID,
+				Authenticator: &lib.HeaderAuthenticator{Headers: map[string]string{"X-Api-Key": "key"}},
+			})
+
+			err := k.Healthcheck(nil)
+
+			Expect(err).To(BeNil())
+			Expect(headers.Get("X-Api-Key")).To(Equal("key"))
+			Expect(headers.Get("Authorization")).To(BeEmpty())
+		})
+
+		It("Should use the authenticator and headers in the context", func() {
+			ctx := lib.WithAuthenticator(nil, &lib.BearerTokenAuthenticator{Token: "calltoken"})
+			ctx = lib.WithHeaders(ctx, map[string]string{"X-Trace-ID": "trace"})
+			ctx = lib.WithRequestID(ctx, "request")
+
+			err := k.Healthcheck(ctx)
+
+			Expect(err).To(BeNil())
+			Expect(headers.Get("Authorization")).To(Equal("Bearer calltoken"))
+			Expect(headers.Get("X-Trace-ID")).To(Equal("trace"))
+			Expect(headers.Get(lib.RequestIDHeader)).To(Equal("request"))
+		})
+	})
+
+	Describe("Resilience", func() {
+		var metrics *testMetricsReporter
+		var calls int
+		var idempotencyKeys []string
+
+		// failing returns a responder that fails with status 503 the first n times it is called
+		failing := func(n int) httpmock.Responder {
+			return func(req *http.Request) (*http.Response, error) {
+				calls++
+				idempotencyKeys = append(idempotencyKeys, req.Header.Get(lib.IdempotencyKeyHeader))
+				if calls <= n {
+					return httpmock.NewStringResponse(503, `{ "success": false, "reason": "unavailable" }`), nil
+				}
+				return httpmock.NewStringResponse(200, `{ "success": true, "publicID": "testid" }`), nil
+			}
+		}
+
+		newKhan := func(params *lib.KhanParams) lib.KhanInterface {
+			params.URL = "http://khan"
+			params.GameID = gameID
+			params.Metrics = metrics
+			return lib.NewKhanWithParams(params)
+		}
+
+		retryPolicy := func(retryMutations bool) *lib.RetryPolicy {
+			return &lib.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond, RetryMutations: retryMutations}
+		}
+
+		BeforeEach(func() {
+			metrics = &testMetricsReporter{}
+			calls = 0
+			idempotencyKeys = nil
+		})
+
+		It("Should retry safe requests", func() {
+			httpmock.RegisterResponder("GET", "http://khan/games/"+gameID+"/players/testid", failing(2))
+			k = newKhan(&lib.KhanParams{RetryPolicy: retryPolicy(false)})
+
+			player, err := k.RetrievePlayer(nil, "testid")
+
+			Expect(err).To(BeNil())
+			Expect(player.PublicID).To(Equal("testid"))
+			Expect(calls).To(Equal(3))
+			Expect(metrics.retries).To(Equal(2))
+		})
+
+		It("Should not retry mutations without an idempotency key", func() {
+			httpmock.RegisterResponder("POST", "http://khan/games/"+gameID+"/players", failing(1))
+			k = newKhan(&lib.KhanParams{RetryPolicy: retryPolicy(false)})
+
+			_, err := k.CreatePlayer(nil, "testid", "testname", nil)
+
+			Expect(err).To(BeAssignableToTypeOf(&lib.ServerError{}))
+			Expect(calls).To(Equal(1))
+			Expect(idempotencyKeys).To(Equal([]string{""}))
+		})
+
+		It("Should retry mutations with the idempotency key in the context", func() {
+			httpmock.RegisterResponder("POST", "http://khan/games/"+gameID+"/players", failing(1))
+			k = newKhan(&lib.KhanParams{RetryPolicy: retryPolicy(false)})
+
+			_, err := k.CreatePlayer(lib.WithIdempotencyKey(nil, "key"), "testid", "testname", nil)
+
+			Expect(err).To(BeNil())
+			Expect(idempotencyKeys).To(Equal([]string{"key", "key"}))
+		})
+
+		It("Should retry mutations with a generated idempotency key", func() {
+			httpmock.RegisterResponder("POST", "http://khan/games/"+gameID+"/players", failing(1))
+			k = newKhan(&lib.KhanParams{RetryPolicy: retryPolicy(true)})
+
+			_, err := k.CreatePlayer(nil, "testid", "testname", nil)
+
+			Expect(err).To(BeNil())
+			Expect(idempotencyKeys).To(HaveLen(2))
+			Expect(idempotencyKeys[0]).NotTo(BeEmpty())
+			Expect(idempotencyKeys[1]).To(Equal(idempotencyKeys[0]))
+		})
+
+		It("Should not retry client errors", func() {
+			httpmock.RegisterResponder("GET", "http://khan/games/"+gameID+"/players/testid",
+				httpmock.NewStringResponder(404, `{ "success": false, "reason": "Player was not found with id: testid" }`))
+			k = newKhan(&lib.KhanParams{RetryPolicy: retryPolicy(false)})
+
+			_, err := k.RetrievePlayer(nil, "testid")
+
+			Expect(err).To(BeAssignableToTypeOf(&lib.NotFoundError{}))
+			Expect(metrics.retries).To(Equal(0))
+		})
+
+		It("Should open the circuit of an endpoint after consecutive failures", func() {
+			httpmock.RegisterResponder("GET", "http://khan/games/"+gameID+"/players/testid", failing(2))
+			httpmock.RegisterResponder("GET", "http://khan/games/"+gameID+"/clans/clanid",
+				httpmock.NewStringResponder(200, `{ "success": true, "publicID": "clanid" }`))
+			k = newKhan(&lib.KhanParams{
+				CircuitBreaker: &lib.CircuitBreakerSettings{FailureThreshold: 2, OpenTimeout: 50 * time.Millisecond},
+			})
+
+			for i := 0; i < 2; i++ {
+				_, err := k.RetrievePlayer(nil, "testid")
+				Expect(err).To(BeAssignableToTypeOf(&lib.ServerError{}))
+			}
+			Expect(metrics.openedCircuits).To(Equal([]string{"RetrievePlayer"}))
+
+			_, err := k.RetrievePlayer(nil, "testid")
+			Expect(err).To(Equal(&lib.CircuitOpenError{Endpoint: "RetrievePlayer"}))
+			Expect(calls).To(Equal(2))
+			Expect(metrics.rejected).To(Equal(1))
+
+			_, err = k.RetrieveClan(nil, "clanid")
+			Expect(err).To(BeNil())
+
+			time.Sleep(60 * time.Millisecond)
+			_, err = k.RetrievePlayer(nil, "testid")
+			Expect(err).To(BeNil())
+			Expect(metrics.closedCircuits).To(Equal([]string{"RetrievePlayer"}))
+		})
+
+		It("Should open the circuits of each game separately", func() {
+			httpmock.RegisterResponder("GET", "http://khan/games/"+gameID+"/players/testid", failing(2))
+			httpmock.RegisterResponder("GET", "http://khan/games/othergame/players/testid",
+				httpmock.NewStringResponder(200, `{ "success": true, "publicID": "testid" }`))
+			k = newKhan(&lib.KhanParams{
+				CircuitBreaker: &lib.CircuitBreakerSettings{FailureThreshold: 2, OpenTimeout: time.Minute},
+			})
+
+			for i := 0; i < 2; i++ {
+				_, err := k.RetrievePlayer(nil, "testid")
+				Expect(err).To(BeAssignableToTypeOf(&lib.ServerError{}))
+			}
+			_, err := k.RetrievePlayer(nil, "testid")
+			Expect(err).To(Equal(&lib.CircuitOpenError{Endpoint: "RetrievePlayer"}))
+
+			_, err = k.ForGame("othergame").RetrievePlayer(nil, "testid")
+			Expect(err).To(BeNil())
+		})
+
+		It("Should not record the outcome of cancelled requests", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			httpmock.RegisterResponder("GET", "http://khan/games/"+gameID+"/players/testid", func(req *http.Request) (*http.Response, error) {
+				calls++
+				if calls == 1 {
+					return httpmock.NewStringResponse(503, `{ "success": false, "reason": "unavailable" }`), nil
+				}
+				if calls == 2 {
+					cancel()
+				}
+				return httpmock.NewStringResponse(200, `{ "success": true, "publicID": "testid" }`), nil
+			})
+			k = newKhan(&lib.KhanParams{
+				CircuitBreaker: &lib.CircuitBreakerSettings{FailureThreshold: 1, OpenTimeout: 50 * time.Millisecond},
+			})
+
+			_, err := k.RetrievePlayer(nil, "testid")
+			Expect(err).To(BeAssignableToTypeOf(&lib.ServerError{}))
+			Expect(metrics.openedCircuits).To(Equal([]string{"RetrievePlayer"}))
+
+			time.Sleep(60 * time.Millisecond)
+			k.RetrievePlayer(ctx, "testid")
+			Expect(metrics.closedCircuits).To(BeEmpty())
+
+			_, err = k.RetrievePlayer(nil, "testid")
+			Expect(err).To(BeNil())
+			Expect(metrics.closedCircuits).To(Equal([]string{"RetrievePlayer"}))
+		})
+
+		It("Should hedge slow safe requests", func() {
+			httpmock.RegisterResponder("GET", "http://khan/games/"+gameID+"/players/testid", func(req *http.Request) (*http.Response, error) {
+				metrics.mutex.Lock()
+				calls++
+				slow := calls == 1
+				metrics.mutex.Unlock()
+				if slow {
+					time.Sleep(200 * time.Millisecond)
+				}
+				return httpmock.NewStringResponse(200, `{ "success": true, "publicID": "testid" }`), nil
+			})
+			k = newKhan(&lib.KhanParams{HedgeDelay: 10 * time.Millisecond})
+
+			start := time.Now()
+			player, err := k.RetrievePlayer(context.Background(), "testid")
+
+			Expect(err).To(BeNil())
+			Expect(player.PublicID).To(Equal("testid"))
+			Expect(time.Since(start)).To(BeNumerically("<", 200*time.Millisecond))
+			Expect(metrics.hedges).To(Equal(1))
+		})
+	})
+
 	Describe("CreatePlayer", func() {
 		It("Should call khan API to create player", func() {
 			url := "http://khan/games/" + gameID + "/players"
@@ -313,6 +622,412 @@ var _ = Describe("Lib", func() {
 		})
 	})
 
+	Describe("Healthcheck", func() {
+		It("Should call khan API healthcheck", func() {
+			httpmock.RegisterResponder("GET", "http://khan/healthcheck",
+				httpmock.NewStringResponder(200, "WORKING"))
+
+			err := k.Healthcheck(nil)
+
+			Expect(err).To(BeNil())
+		})
+
+		It("Should return a server error if khan is not working", func() {
+			httpmock.RegisterResponder("GET", "http://khan/healthcheck",
+				httpmock.NewStringResponder(500, `{ "success": false, "reason": "Error connecting to database" }`))
+
+			err := k.Healthcheck(nil)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&lib.ServerError{}))
+			Expect(err.(*lib.ServerError).Status()).To(Equal(500))
+			Expect(err.(*lib.ServerError).Reason()).To(Equal("Error connecting to database"))
+		})
+
+		It("Should return the request error of any failure", func() {
+			httpmock.RegisterResponder("GET", "http://khan/healthcheck",
+				httpmock.NewStringResponder(503, `{ "success": false, "reason": "unavailable" }`))
+
+			err := k.Healthcheck(nil)
+
+			requestError, ok := lib.GetRequestError(err)
+			Expect(ok).To(BeTrue())
+			Expect(requestError.Status()).To(Equal(503))
+			Expect(requestError.Reason()).To(Equal("unavailable"))
+
+			_, ok = lib.GetRequestError(&lib.CircuitOpenError{Endpoint: "Healthcheck"})
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("CreateGame", func() {
+		It("Should call khan API to create game", func() {
+			httpmock.RegisterResponder("POST", "http://khan/games",
+				func(req *http.Request) (*http.Response, error) {
+					var payload map[string]interface{}
+					json.NewDecoder(req.Body).Decode(&payload)
+					Expect(payload["publicID"]).To(Equal("newgame"))
+					Expect(payload["maxPendingInvites"]).To(BeEquivalentTo(5))
+					Expect(payload).NotTo(HaveKey("cooldownBeforeApply"))
+					return httpmock.NewStringResponse(200, `{ "success": true, "publicID": "newgame" }`), nil
+				})
+
+			maxPendingInvites := 5
+			gameID, err := k.CreateGame(nil, &lib.GamePayload{
+				PublicID:          "newgame",
+				Name:              "new game",
+				MembershipLevels:  map[string]int{"member": 1, "owner": 2},
+				Metadata:          map[string]interface{}{},
+				MaxMembers:        10,
+				MaxClansPerPlayer: 1,
+				MaxPendingInvites: &maxPendingInvites,
+			})
+
+			Expect(err).To(BeNil())
+			Expect(gameID).To(Equal("newgame"))
+		})
+	})
+
+	Describe("ListGames", func() {
+		It("Should call khan API to list games", func() {
+			url := "http://khan/games?archived=false&cursor=abc&limit=2"
+			httpmock.RegisterResponder("GET", url,
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"games": [
+						{ "publicID": "game1", "name": "game 1", "membershipLevels": {"member": 1}, "archived": false },
+						{ "publicID": "game2", "name": "game 2", "membershipLevels": {"member": 1}, "archived": false }
+					],
+					"nextCursor": "def"
+				}`))
+
+			archived := false
+			result, err := k.ListGames(nil, &lib.ListGamesOptions{Limit: 2, Cursor: "abc", Archived: &archived})
+
+			Expect(err).To(BeNil())
+			Expect(result.Games).To(HaveLen(2))
+			Expect(result.Games[0].PublicID).To(Equal("game1"))
+			Expect(result.Games[0].MembershipLevels).To(Equal(map[string]int{"member": 1}))
+			Expect(result.NextCursor).To(Equal("def"))
+		})
+	})
+
+	Describe("DeleteClan", func() {
+		It("Should call khan API to delete clan on behalf of its owner", func() {
+			var query url.Values
+			httpmock.RegisterResponder("DELETE", "http://khan/games/"+gameID+"/clans/clan1",
+				func(req *http.Request) (*http.Response, error) {
+					query = req.URL.Query()
+					return httpmock.NewStringResponse(200, `{ "success": true }`), nil
+				})
+
+			result, err := k.DeleteClan(nil, "clan1", "owner1")
+
+			Expect(err).To(BeNil())
+			Expect(result.Success).To(BeTrue())
+			Expect(query.Get("requestorPublicID")).To(Equal("owner1"))
+			Expect(query.Get("admin")).To(BeEmpty())
+		})
+
+		It("Should call khan API to delete clan as an admin", func() {
+			var query url.Values
+			httpmock.RegisterResponder("DELETE", "http://khan/games/"+gameID+"/clans/clan1",
+				func(req *http.Request) (*http.Response, error) {
+					query = req.URL.Query()
+					return httpmock.NewStringResponse(200, `{ "success": true }`), nil
+				})
+
+			result, err := k.DeleteClanAsAdmin(nil, "clan1")
+
+			Expect(err).To(BeNil())
+			Expect(result.Success).To(BeTrue())
+			Expect(query.Get("admin")).To(Equal("true"))
+			Expect(query.Get("requestorPublicID")).To(BeEmpty())
+		})
+	})
+
+	Describe("ArchiveGame", func() {
+		It("Should call khan API to archive game", func() {
+			httpmock.RegisterResponder("POST", "http://khan/games/"+gameID+"/archive",
+				httpmock.NewStringResponder(200, `{ "success": true, "publicID": "testgame", "archived": true, "archivedAt": 123456789 }`))
+
+			result, err := k.ArchiveGame(nil, gameID)
+
+			Expect(err).To(BeNil())
+			Expect(result.Archived).To(BeTrue())
+			Expect(result.ArchivedAt).To(Equal(int64(123456789)))
+		})
+
+		It("Should return a conflict error if the game is archived", func() {
+			httpmock.RegisterResponder("PUT", "http://khan/games/"+gameID+"/clans/testid",
+				httpmock.NewStringResponder(409, `{ "success": false, "reason": "Game testgame is archived and can't be changed." }`))
+
+			_, err := k.UpdateClan(nil, &lib.ClanPayload{PublicID: "testid"})
+
+			Expect(err).To(BeAssignableToTypeOf(&lib.ConflictError{}))
+			Expect(err.(*lib.ConflictError).Reason()).To(Equal("Game testgame is archived and can't be changed."))
+		})
+	})
+
+	Describe("Hooks", func() {
+		It("Should call khan API to create hook", func() {
+			httpmock.RegisterResponder("POST", "http://khan/games/"+gameID+"/hooks",
+				httpmock.NewStringResponder(200, `{ "success": true, "publicID": "hookid" }`))
+
+			hookID, err := k.CreateHook(nil, &lib.HookPayload{Wildcards: []string{"clan.*"}, HookURL: "http://hook"})
+
+			Expect(err).To(BeNil())
+			Expect(hookID).To(Equal("hookid"))
+		})
+
+		It("Should call khan API to list hooks", func() {
+			httpmock.RegisterResponder("GET", "http://khan/games/"+gameID+"/hooks",
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"hooks": [{
+						"gameID": "testgame",
+						"publicID": "hookid",
+						"type": -1,
+						"types": [1, 2],
+						"wildcards": ["clan.*"],
+						"hookURL": "http://hook",
+						"signed": true
+					}]
+				}`))
+
+			hooks, err := k.ListHooks(nil)
+
+			Expect(err).To(BeNil())
+			Expect(hooks).To(HaveLen(1))
+			Expect(hooks[0].PublicID).To(Equal("hookid"))
+			Expect(hooks[0].Types).To(Equal([]int{1, 2}))
+			Expect(hooks[0].Signed).To(BeTrue())
+		})
+
+		It("Should call khan API to retrieve hook deliveries", func() {
+			httpmock.RegisterResponder("GET", "http://khan/games/"+gameID+"/hooks/hookid/deliveries?limit=1",
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"deliveries": [{ "eventID": "eventid", "eventType": 1, "attempt": 2, "statusCode": 500 }]
+				}`))
+
+			deliveries, err := k.RetrieveHookDeliveries(nil, "hookid", 1)
+
+			Expect(err).To(BeNil())
+			Expect(deliveries).To(HaveLen(1))
+			Expect(deliveries[0].EventID).To(Equal("eventid"))
+			Expect(deliveries[0].Attempt).To(Equal(2))
+			Expect(deliveries[0].StatusCode).To(Equal(500))
+		})
+
+		It("Should return a not found error if the hook does not exist", func() {
+			httpmock.RegisterResponder("DELETE", "http://khan/games/"+gameID+"/hooks/hookid",
+				httpmock.NewStringResponder(404, `{ "success": false, "reason": "Hook was not found with id: hookid" }`))
+
+			_, err := k.RemoveHook(nil, "hookid")
+
+			Expect(err).To(BeAssignableToTypeOf(&lib.NotFoundError{}))
+			Expect(err.(*lib.NotFoundError).Status()).To(Equal(404))
+		})
+	})
+
+	Describe("PatchPlayer", func() {
+		It("Should return the current version on a version conflict", func() {
+			httpmock.RegisterResponder("PATCH", "http://khan/games/"+gameID+"/players/testid",
+				httpmock.NewStringResponder(409, `{ "success": false, "reason": "version conflict", "version": 3 }`))
+
+			name := "newname"
+			_, err := k.PatchPlayer(nil, &lib.PlayerPatchPayload{PublicID: "testid", Name: &name})
+
+			Expect(err).To(BeAssignableToTypeOf(&lib.ConflictError{}))
+			Expect(err.(*lib.ConflictError).Version()).To(Equal(int64(3)))
+		})
+	})
+
+	Describe("Error Codes", func() {
+		It("Should return the remaining cooldown when a membership must wait", func() {
+			httpmock.RegisterResponder("POST", "http://khan/games/"+gameID+"/clans/clanid/memberships/application",
+				httpmock.NewStringResponder(500, `{
+					"success": false,
+					"reason": "Player playerid must wait 30 seconds before creating a membership in clan clanid.",
+					"code": "MUST_WAIT_MEMBERSHIP_COOLDOWN",
+					"details": { "playerPublicID": "playerid", "clanPublicID": "clanid", "time": 30 }
+				}`))
+
+			_, err := k.ApplyForMembership(nil, &lib.ApplicationPayload{ClanID: "clanid", PlayerPublicID: "playerid"})
+
+			Expect(err).To(BeAssignableToTypeOf(&lib.MustWaitMembershipCooldownError{}))
+			cooldownErr := err.(*lib.MustWaitMembershipCooldownError)
+			Expect(cooldownErr.Code()).To(Equal(lib.MustWaitMembershipCooldownErrorCode))
+			Expect(cooldownErr.Time).To(Equal(30))
+			Expect(cooldownErr.PlayerPublicID).To(Equal("playerid"))
+			Expect(cooldownErr.ClanPublicID).To(Equal("clanid"))
+		})
+
+		It("Should tell a full clan from a player banned from it", func() {
+			httpmock.RegisterResponder("POST", "http://khan/games/"+gameID+"/clans/clanid/memberships/invitation",
+				httpmock.NewStringResponder(500, `{
+					"success": false,
+					"reason": "Clan clanid reached max members",
+					"code": "CLAN_REACHED_MAX_MEMBERS",
+					"details": { "clanPublicID": "clanid" }
+				}`))
+			httpmock.RegisterResponder("POST", "http://khan/games/"+gameID+"/clans/clanid/memberships/application",
+				httpmock.NewStringResponder(403, `{
+					"success": false,
+					"reason": "Player playerid is banned from clan clanid until 123456789.",
+					"code": "PLAYER_BANNED_FROM_CLAN",
+					"details": { "playerPublicID": "playerid", "clanPublicID": "clanid", "expiresAt": 123456789 }
+				}`))
+
+			_, err := k.InviteForMembership(nil, &lib.InvitationPayload{ClanID: "clanid", PlayerPublicID: "playerid"})
+			Expect(err).To(BeAssignableToTypeOf(&lib.ClanReachedMaxMembersError{}))
+			Expect(err.(*lib.ClanReachedMaxMembersError).ClanPublicID).To(Equal("clanid"))
+
+			_, err = k.ApplyForMembership(nil, &lib.ApplicationPayload{ClanID: "clanid", PlayerPublicID: "playerid"})
+			Expect(err).To(BeAssignableToTypeOf(&lib.PlayerBannedFromClanError{}))
+			Expect(err.(*lib.PlayerBannedFromClanError).ExpiresAt).To(Equal(int64(123456789)))
+			Expect(err.(*lib.PlayerBannedFromClanError).Status()).To(Equal(403))
+		})
+
+		It("Should fall back to the status error type for codes without a matching type", func() {
+			httpmock.RegisterResponder("GET", "http://khan/games/"+gameID+"/players/playerid",
+				httpmock.NewStringResponder(404, `{
+					"success": false,
+					"reason": "Player was not found with id: playerid",
+					"code": "MODEL_NOT_FOUND",
+					"details": { "type": "Player", "id": "playerid" }
+				}`))
+
+			_, err := k.RetrievePlayer(nil, "playerid")
+
+			Expect(err).To(BeAssignableToTypeOf(&lib.NotFoundError{}))
+			Expect(err.(*lib.NotFoundError).Code()).To(Equal(lib.ModelNotFoundErrorCode))
+		})
+	})
+
+	Describe("ListClans", func() {
+		It("Should call khan API to list clans", func() {
+			url := "http://khan/games/" + gameID + "/clans?allowApplication=true&limit=10&metadata.region=us&orderBy=name"
+			httpmock.RegisterResponder("GET", url,
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"clans": [{ "publicID": "testid", "name": "testname", "membershipCount": 3 }],
+					"nextCursor": "abc"
+				}`))
+
+			allowApplication := true
+			result, err := k.ListClans(nil, &lib.ListClansOptions{
+				Limit:            10,
+				OrderBy:          "name",
+				AllowApplication: &allowApplication,
+				Metadata:         map[string]string{"region": "us"},
+			})
+
+			Expect(err).To(BeNil())
+			Expect(result.Clans).To(HaveLen(1))
+			Expect(result.Clans[0].PublicID).To(Equal("testid"))
+			Expect(result.Clans[0].MembershipCount).To(Equal(3))
+			Expect(result.NextCursor).To(Equal("abc"))
+		})
+	})
+
+	Describe("SearchClansWithPageSize", func() {
+		It("Should call khan API to search clans", func() {
+			url := "http://khan/games/" + gameID + "/clans/search?pageSize=5&term=some+clan"
+			httpmock.RegisterResponder("GET", url,
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"clans": [{ "publicID": "testid", "name": "some clan" }]
+				}`))
+
+			result, err := k.SearchClansWithPageSize(nil, "some clan", 5)
+
+			Expect(err).To(BeNil())
+			Expect(result.Clans).To(HaveLen(1))
+			Expect(result.Clans[0].Name).To(Equal("some clan"))
+		})
+	})
+
+	Describe("TopClans", func() {
+		It("Should call khan API to retrieve top clans", func() {
+			url := "http://khan/games/" + gameID + "/clans-top/trophies?clanPublicID=testid&limit=1"
+			httpmock.RegisterResponder("GET", url,
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"dimension": "trophies",
+					"clans": [{ "rank": 1, "score": 30, "publicID": "topid" }],
+					"clan": { "rank": 3, "score": 10, "publicID": "testid" }
+				}`))
+
+			result, err := k.TopClans(nil, &lib.TopClansOptions{Dimension: "trophies", Limit: 1, ClanPublicID: "testid"})
+
+			Expect(err).To(BeNil())
+			Expect(result.Clans).To(HaveLen(1))
+			Expect(result.Clans[0].Score).To(Equal(float64(30)))
+			Expect(result.Clan.Rank).To(Equal(3))
+		})
+	})
+
+	Describe("BatchMemberships", func() {
+		It("Should call khan API to run a membership batch", func() {
+			url := "http://khan/games/" + gameID + "/clans/testid/memberships/batch"
+			httpmock.RegisterResponder("POST", url,
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"results": [
+						{ "index": 0, "action": "promote", "playerPublicID": "pid1", "success": true, "level": "elder" },
+						{ "index": 1, "action": "kick", "playerPublicID": "pid2", "success": false, "status": 403, "reason": "forbidden" }
+					],
+					"succeeded": 1,
+					"failed": 1
+				}`))
+
+			result, err := k.BatchMemberships(nil, &lib.BatchMembershipsPayload{
+				ClanID: "testid",
+				Mode:   "bestEffort",
+				Operations: []*lib.BatchMembershipOperation{
+					{Action: "promote", PlayerPublicID: "pid1", RequestorPublicID: "ownerID"},
+					{Action: "kick", PlayerPublicID: "pid2", RequestorPublicID: "ownerID"},
+				},
+			})
+
+			Expect(err).To(BeNil())
+			Expect(result.Succeeded).To(Equal(1))
+			Expect(result.Failed).To(Equal(1))
+			Expect(result.Results[0].Level).To(Equal("elder"))
+			Expect(result.Results[1].Status).To(Equal(403))
+		})
+	})
+
+	Describe("RetrieveAuditEntries", func() {
+		It("Should call khan API to retrieve the audit trail of a clan", func() {
+			url := "http://khan/games/" + gameID + "/clans/testid/audit?limit=1&since=1000"
+			httpmock.RegisterResponder("GET", url,
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"entries": [{
+						"action": "clan.updated",
+						"actorPublicID": "ownerID",
+						"clanPublicID": "testid",
+						"before": { "name": "old name" },
+						"after": { "name": "new name" },
+						"requestID": "requestid",
+						"createdAt": 2000
+					}],
+					"nextCursor": "abc"
+				}`))
+
+			result, err := k.RetrieveAuditEntries(nil, &lib.AuditEntriesOptions{ClanPublicID: "testid", Limit: 1, Since: 1000})
+
+			Expect(err).To(BeNil())
+			Expect(result.Entries).To(HaveLen(1))
+			Expect(result.Entries[0].After["name"]).To(Equal("new name"))
+			Expect(result.Entries[0].RequestID).To(Equal("requestid"))
+			Expect(result.NextCursor).To(Equal("abc"))
+		})
+	})
+
 	AfterSuite(func() {
 		defer httpmock.DeactivateAndReset()
 	})
@@ -0,0 +1,24 @@
+package lib
+
+// MetricsReporter is notified of the retries, hedged requests and circuit breaker changes of a
+// client, so callers can observe them. The endpoint is the name of the client method, e.g. ApplyForMembership
+type MetricsReporter interface {
+	// ReportRetry is called before a request is sent again, with the attempt that failed
+	ReportRetry(endpoint string, attempt int, err error)
+	// ReportHedge is called when a hedged request is sent because the first one is taking too long
+	ReportHedge(endpoint string)
+	// ReportCircuitOpened is called when the circuit of an endpoint opens
+	ReportCircuitOpened(endpoint string)
+	// ReportCircuitClosed is called when the circuit of an endpoint closes again
+	ReportCircuitClosed(endpoint string)
+	// ReportCircuitRejected is called when a request is not sent because its circuit is open
+	ReportCircuitRejected(endpoint string)
+}
+
+type nopMetricsReporter struct{}
+
+func (nopMetricsReporter) ReportRetry(string, int, error) {}
+func (nopMetricsReporter) ReportHedge(string)             {}
+func (nopMetricsReporter) ReportCircuitOpened(string)     {}
+func (nopMetricsReporter) ReportCircuitClosed(string)     {}
+func (nopMetricsReporter) ReportCircuitRejected(string)   {}
@@ -79,6 +79,51 @@ func (mr *MockKhanInterfaceMockRecorder) ApproveDenyMembershipInvitation(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveDenyMembershipInvitation", reflect.TypeOf((*MockKhanInterface)(nil).ApproveDenyMembershipInvitation), arg0, arg1)
 }
 
+// ArchiveGame mocks base method
+func (m *MockKhanInterface) ArchiveGame(arg0 context.Context, arg1 string) (*lib.ArchiveGameResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveGame", arg0, arg1)
+	ret0, _ := ret[0].(*lib.ArchiveGameResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveGame indicates an expected call of ArchiveGame
+func (mr *MockKhanInterfaceMockRecorder) ArchiveGame(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveGame", reflect.TypeOf((*MockKhanInterface)(nil).ArchiveGame), arg0, arg1)
+}
+
+// BanUnban mocks base method
+func (m *MockKhanInterface) BanUnban(arg0 context.Context, arg1 *lib.BanUnbanPayload) (*lib.BanUnbanResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BanUnban", arg0, arg1)
+	ret0, _ := ret[0].(*lib.BanUnbanResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BanUnban indicates an expected call of BanUnban
+func (mr *MockKhanInterfaceMockRecorder) BanUnban(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BanUnban", reflect.TypeOf((*MockKhanInterface)(nil).BanUnban), arg0, arg1)
+}
+
+// BatchMemberships mocks base method
+func (m *MockKhanInterface) BatchMemberships(arg0 context.Context, arg1 *lib.BatchMembershipsPayload) (*lib.BatchMembershipsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchMemberships", arg0, arg1)
+	ret0, _ := ret[0].(*lib.BatchMembershipsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchMemberships indicates an expected call of BatchMemberships
+func (mr *MockKhanInterfaceMockRecorder) BatchMemberships(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchMemberships", reflect.TypeOf((*MockKhanInterface)(nil).BatchMemberships), arg0, arg1)
+}
+
 // CreateClan mocks base method
 func (m *MockKhanInterface) CreateClan(arg0 context.Context, arg1 *lib.ClanPayload) (string, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +139,36 @@ func (mr *MockKhanInterfaceMockRecorder) CreateClan(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClan", reflect.TypeOf((*MockKhanInterface)(nil).CreateClan), arg0, arg1)
 }
 
+// CreateGame mocks base method
+func (m *MockKhanInterface) CreateGame(arg0 context.Context, arg1 *lib.GamePayload) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGame", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGame indicates an expected call of CreateGame
+func (mr *MockKhanInterfaceMockRecorder) CreateGame(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGame", reflect.TypeOf((*MockKhanInterface)(nil).CreateGame), arg0, arg1)
+}
+
+// CreateHook mocks base method
+func (m *MockKhanInterface) CreateHook(arg0 context.Context, arg1 *lib.HookPayload) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHook", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHook indicates an expected call of CreateHook
+func (mr *MockKhanInterfaceMockRecorder) CreateHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHook", reflect.TypeOf((*MockKhanInterface)(nil).CreateHook), arg0, arg1)
+}
+
 // CreatePlayer mocks base method
 func (m *MockKhanInterface) CreatePlayer(arg0 context.Context, arg1, arg2 string, arg3 interface{}) (string, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +184,36 @@ func (mr *MockKhanInterfaceMockRecorder) CreatePlayer(arg0, arg1, arg2, arg3 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePlayer", reflect.TypeOf((*MockKhanInterface)(nil).CreatePlayer), arg0, arg1, arg2, arg3)
 }
 
+// DeleteClan mocks base method
+func (m *MockKhanInterface) DeleteClan(arg0 context.Context, arg1, arg2 string) (*lib.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClan", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*lib.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteClan indicates an expected call of DeleteClan
+func (mr *MockKhanInterfaceMockRecorder) DeleteClan(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClan", reflect.TypeOf((*MockKhanInterface)(nil).DeleteClan), arg0, arg1, arg2)
+}
+
+// DeleteClanAsAdmin mocks base method
+func (m *MockKhanInterface) DeleteClanAsAdmin(arg0 context.Context, arg1 string) (*lib.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClanAsAdmin", arg0, arg1)
+	ret0, _ := ret[0].(*lib.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteClanAsAdmin indicates an expected call of DeleteClanAsAdmin
+func (mr *MockKhanInterfaceMockRecorder) DeleteClanAsAdmin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClanAsAdmin", reflect.TypeOf((*MockKhanInterface)(nil).DeleteClanAsAdmin), arg0, arg1)
+}
+
 // DeleteMembership mocks base method
 func (m *MockKhanInterface) DeleteMembership(arg0 context.Context, arg1 *lib.DeleteMembershipPayload) (*lib.Result, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +229,34 @@ func (mr *MockKhanInterfaceMockRecorder) DeleteMembership(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMembership", reflect.TypeOf((*MockKhanInterface)(nil).DeleteMembership), arg0, arg1)
 }
 
+// ForGame mocks base method
+func (m *MockKhanInterface) ForGame(arg0 string) lib.KhanInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForGame", arg0)
+	ret0, _ := ret[0].(lib.KhanInterface)
+	return ret0
+}
+
+// ForGame indicates an expected call of ForGame
+func (mr *MockKhanInterfaceMockRecorder) ForGame(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForGame", reflect.TypeOf((*MockKhanInterface)(nil).ForGame), arg0)
+}
+
+// Healthcheck mocks base method
+func (m *MockKhanInterface) Healthcheck(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Healthcheck", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Healthcheck indicates an expected call of Healthcheck
+func (mr *MockKhanInterfaceMockRecorder) Healthcheck(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Healthcheck", reflect.TypeOf((*MockKhanInterface)(nil).Healthcheck), arg0)
+}
+
 // InviteForMembership mocks base method
 func (m *MockKhanInterface) InviteForMembership(arg0 context.Context, arg1 *lib.InvitationPayload) (*lib.Result, error) {
 	m.ctrl.T.Helper()
@@ -154,6 +287,96 @@ func (mr *MockKhanInterfaceMockRecorder) LeaveClan(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveClan", reflect.TypeOf((*MockKhanInterface)(nil).LeaveClan), arg0, arg1)
 }
 
+// ListClans mocks base method
+func (m *MockKhanInterface) ListClans(arg0 context.Context, arg1 *lib.ListClansOptions) (*lib.ListClansResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClans", arg0, arg1)
+	ret0, _ := ret[0].(*lib.ListClansResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClans indicates an expected call of ListClans
+func (mr *MockKhanInterfaceMockRecorder) ListClans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClans", reflect.TypeOf((*MockKhanInterface)(nil).ListClans), arg0, arg1)
+}
+
+// ListGames mocks base method
+func (m *MockKhanInterface) ListGames(arg0 context.Context, arg1 *lib.ListGamesOptions) (*lib.ListGamesResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGames", arg0, arg1)
+	ret0, _ := ret[0].(*lib.ListGamesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGames indicates an expected call of ListGames
+func (mr *MockKhanInterfaceMockRecorder) ListGames(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGames", reflect.TypeOf((*MockKhanInterface)(nil).ListGames), arg0, arg1)
+}
+
+// ListHooks mocks base method
+func (m *MockKhanInterface) ListHooks(arg0 context.Context) ([]*lib.Hook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHooks", arg0)
+	ret0, _ := ret[0].([]*lib.Hook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHooks indicates an expected call of ListHooks
+func (mr *MockKhanInterfaceMockRecorder) ListHooks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHooks", reflect.TypeOf((*MockKhanInterface)(nil).ListHooks), arg0)
+}
+
+// PatchClan mocks base method
+func (m *MockKhanInterface) PatchClan(arg0 context.Context, arg1 *lib.ClanPatchPayload) (*lib.ClanPatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchClan", arg0, arg1)
+	ret0, _ := ret[0].(*lib.ClanPatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchClan indicates an expected call of PatchClan
+func (mr *MockKhanInterfaceMockRecorder) PatchClan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchClan", reflect.TypeOf((*MockKhanInterface)(nil).PatchClan), arg0, arg1)
+}
+
+// PatchGame mocks base method
+func (m *MockKhanInterface) PatchGame(arg0 context.Context, arg1 *lib.GamePatchPayload) (*lib.GamePatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchGame", arg0, arg1)
+	ret0, _ := ret[0].(*lib.GamePatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchGame indicates an expected call of PatchGame
+func (mr *MockKhanInterfaceMockRecorder) PatchGame(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchGame", reflect.TypeOf((*MockKhanInterface)(nil).PatchGame), arg0, arg1)
+}
+
+// PatchPlayer mocks base method
+func (m *MockKhanInterface) PatchPlayer(arg0 context.Context, arg1 *lib.PlayerPatchPayload) (*lib.PlayerPatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchPlayer", arg0, arg1)
+	ret0, _ := ret[0].(*lib.PlayerPatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchPlayer indicates an expected call of PatchPlayer
+func (mr *MockKhanInterfaceMockRecorder) PatchPlayer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchPlayer", reflect.TypeOf((*MockKhanInterface)(nil).PatchPlayer), arg0, arg1)
+}
+
 // PromoteDemote mocks base method
 func (m *MockKhanInterface) PromoteDemote(arg0 context.Context, arg1 *lib.PromoteDemotePayload) (*lib.Result, error) {
 	m.ctrl.T.Helper()
@@ -169,6 +392,36 @@ func (mr *MockKhanInterfaceMockRecorder) PromoteDemote(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteDemote", reflect.TypeOf((*MockKhanInterface)(nil).PromoteDemote), arg0, arg1)
 }
 
+// RemoveHook mocks base method
+func (m *MockKhanInterface) RemoveHook(arg0 context.Context, arg1 string) (*lib.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveHook", arg0, arg1)
+	ret0, _ := ret[0].(*lib.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveHook indicates an expected call of RemoveHook
+func (mr *MockKhanInterfaceMockRecorder) RemoveHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveHook", reflect.TypeOf((*MockKhanInterface)(nil).RemoveHook), arg0, arg1)
+}
+
+// RetrieveAuditEntries mocks base method
+func (m *MockKhanInterface) RetrieveAuditEntries(arg0 context.Context, arg1 *lib.AuditEntriesOptions) (*lib.AuditEntriesResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveAuditEntries", arg0, arg1)
+	ret0, _ := ret[0].(*lib.AuditEntriesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveAuditEntries indicates an expected call of RetrieveAuditEntries
+func (mr *MockKhanInterfaceMockRecorder) RetrieveAuditEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveAuditEntries", reflect.TypeOf((*MockKhanInterface)(nil).RetrieveAuditEntries), arg0, arg1)
+}
+
 // RetrieveClan mocks base method
 func (m *MockKhanInterface) RetrieveClan(arg0 context.Context, arg1 string) (*lib.Clan, error) {
 	m.ctrl.T.Helper()
@@ -184,6 +437,36 @@ func (mr *MockKhanInterfaceMockRecorder) RetrieveClan(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveClan", reflect.TypeOf((*MockKhanInterface)(nil).RetrieveClan), arg0, arg1)
 }
 
+// RetrieveClanMembers mocks base method
+func (m *MockKhanInterface) RetrieveClanMembers(arg0 context.Context, arg1 string) (*lib.ClanMembers, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveClanMembers", arg0, arg1)
+	ret0, _ := ret[0].(*lib.ClanMembers)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveClanMembers indicates an expected call of RetrieveClanMembers
+func (mr *MockKhanInterfaceMockRecorder) RetrieveClanMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveClanMembers", reflect.TypeOf((*MockKhanInterface)(nil).RetrieveClanMembers), arg0, arg1)
+}
+
+// RetrieveClanMembersPage mocks base method
+func (m *MockKhanInterface) RetrieveClanMembersPage(arg0 context.Context, arg1 *lib.ClanMembersOptions) (*lib.ClanMembersPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveClanMembersPage", arg0, arg1)
+	ret0, _ := ret[0].(*lib.ClanMembersPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveClanMembersPage indicates an expected call of RetrieveClanMembersPage
+func (mr *MockKhanInterfaceMockRecorder) RetrieveClanMembersPage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveClanMembersPage", reflect.TypeOf((*MockKhanInterface)(nil).RetrieveClanMembersPage), arg0, arg1)
+}
+
 // RetrieveClanSummary mocks base method
 func (m *MockKhanInterface) RetrieveClanSummary(arg0 context.Context, arg1 string) (*lib.ClanSummary, error) {
 	m.ctrl.T.Helper()
@@ -214,6 +497,51 @@ func (mr *MockKhanInterfaceMockRecorder) RetrieveClansSummary(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveClansSummary", reflect.TypeOf((*MockKhanInterface)(nil).RetrieveClansSummary), arg0, arg1)
 }
 
+// RetrieveGame mocks base method
+func (m *MockKhanInterface) RetrieveGame(arg0 context.Context, arg1 string) (*lib.Game, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveGame", arg0, arg1)
+	ret0, _ := ret[0].(*lib.Game)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveGame indicates an expected call of RetrieveGame
+func (mr *MockKhanInterfaceMockRecorder) RetrieveGame(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveGame", reflect.TypeOf((*MockKhanInterface)(nil).RetrieveGame), arg0, arg1)
+}
+
+// RetrieveHook mocks base method
+func (m *MockKhanInterface) RetrieveHook(arg0 context.Context, arg1 string) (*lib.Hook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveHook", arg0, arg1)
+	ret0, _ := ret[0].(*lib.Hook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveHook indicates an expected call of RetrieveHook
+func (mr *MockKhanInterfaceMockRecorder) RetrieveHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveHook", reflect.TypeOf((*MockKhanInterface)(nil).RetrieveHook), arg0, arg1)
+}
+
+// RetrieveHookDeliveries mocks base method
+func (m *MockKhanInterface) RetrieveHookDeliveries(arg0 context.Context, arg1 string, arg2 int) ([]*lib.HookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveHookDeliveries", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*lib.HookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveHookDeliveries indicates an expected call of RetrieveHookDeliveries
+func (mr *MockKhanInterfaceMockRecorder) RetrieveHookDeliveries(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveHookDeliveries", reflect.TypeOf((*MockKhanInterface)(nil).RetrieveHookDeliveries), arg0, arg1, arg2)
+}
+
 // RetrievePlayer mocks base method
 func (m *MockKhanInterface) RetrievePlayer(arg0 context.Context, arg1 string) (*lib.Player, error) {
 	m.ctrl.T.Helper()
@@ -229,6 +557,21 @@ func (mr *MockKhanInterfaceMockRecorder) RetrievePlayer(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrievePlayer", reflect.TypeOf((*MockKhanInterface)(nil).RetrievePlayer), arg0, arg1)
 }
 
+// RetrievePlayerMemberships mocks base method
+func (m *MockKhanInterface) RetrievePlayerMemberships(arg0 context.Context, arg1 *lib.PlayerMembershipsOptions) (*lib.PlayerMembershipsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrievePlayerMemberships", arg0, arg1)
+	ret0, _ := ret[0].(*lib.PlayerMembershipsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrievePlayerMemberships indicates an expected call of RetrievePlayerMemberships
+func (mr *MockKhanInterfaceMockRecorder) RetrievePlayerMemberships(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrievePlayerMemberships", reflect.TypeOf((*MockKhanInterface)(nil).RetrievePlayerMemberships), arg0, arg1)
+}
+
 // SearchClans mocks base method
 func (m *MockKhanInterface) SearchClans(arg0 context.Context, arg1 string) (*lib.SearchClansResult, error) {
 	m.ctrl.T.Helper()
@@ -244,6 +587,66 @@ func (mr *MockKhanInterfaceMockRecorder) SearchClans(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchClans", reflect.TypeOf((*MockKhanInterface)(nil).SearchClans), arg0, arg1)
 }
 
+// SearchClansWithPageSize mocks base method
+func (m *MockKhanInterface) SearchClansWithPageSize(arg0 context.Context, arg1 string, arg2 int) (*lib.SearchClansResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchClansWithPageSize", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*lib.SearchClansResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchClansWithPageSize indicates an expected call of SearchClansWithPageSize
+func (mr *MockKhanInterfaceMockRecorder) SearchClansWithPageSize(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchClansWithPageSize", reflect.TypeOf((*MockKhanInterface)(nil).SearchClansWithPageSize), arg0, arg1, arg2)
+}
+
+// Status mocks base method
+func (m *MockKhanInterface) Status(arg0 context.Context) (*lib.Status, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", arg0)
+	ret0, _ := ret[0].(*lib.Status)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status
+func (mr *MockKhanInterfaceMockRecorder) Status(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockKhanInterface)(nil).Status), arg0)
+}
+
+// TestHook mocks base method
+func (m *MockKhanInterface) TestHook(arg0 context.Context, arg1 string) (*lib.TestHookResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TestHook", arg0, arg1)
+	ret0, _ := ret[0].(*lib.TestHookResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestHook indicates an expected call of TestHook
+func (mr *MockKhanInterfaceMockRecorder) TestHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestHook", reflect.TypeOf((*MockKhanInterface)(nil).TestHook), arg0, arg1)
+}
+
+// TopClans mocks base method
+func (m *MockKhanInterface) TopClans(arg0 context.Context, arg1 *lib.TopClansOptions) (*lib.TopClansResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopClans", arg0, arg1)
+	ret0, _ := ret[0].(*lib.TopClansResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopClans indicates an expected call of TopClans
+func (mr *MockKhanInterfaceMockRecorder) TopClans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopClans", reflect.TypeOf((*MockKhanInterface)(nil).TopClans), arg0, arg1)
+}
+
 // TransferOwnership mocks base method
 func (m *MockKhanInterface) TransferOwnership(arg0 context.Context, arg1, arg2 string) (*lib.TransferOwnershipResult, error) {
 	m.ctrl.T.Helper()
@@ -259,6 +662,21 @@ func (mr *MockKhanInterfaceMockRecorder) TransferOwnership(arg0, arg1, arg2 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferOwnership", reflect.TypeOf((*MockKhanInterface)(nil).TransferOwnership), arg0, arg1, arg2)
 }
 
+// UnarchiveGame mocks base method
+func (m *MockKhanInterface) UnarchiveGame(arg0 context.Context, arg1 string) (*lib.ArchiveGameResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnarchiveGame", arg0, arg1)
+	ret0, _ := ret[0].(*lib.ArchiveGameResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnarchiveGame indicates an expected call of UnarchiveGame
+func (mr *MockKhanInterfaceMockRecorder) UnarchiveGame(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnarchiveGame", reflect.TypeOf((*MockKhanInterface)(nil).UnarchiveGame), arg0, arg1)
+}
+
 // UpdateClan mocks base method
 func (m *MockKhanInterface) UpdateClan(arg0 context.Context, arg1 *lib.ClanPayload) (*lib.Result, error) {
 	m.ctrl.T.Helper()
@@ -274,6 +692,36 @@ func (mr *MockKhanInterfaceMockRecorder) UpdateClan(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClan", reflect.TypeOf((*MockKhanInterface)(nil).UpdateClan), arg0, arg1)
 }
 
+// UpdateGame mocks base method
+func (m *MockKhanInterface) UpdateGame(arg0 context.Context, arg1 *lib.GamePayload) (*lib.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGame", arg0, arg1)
+	ret0, _ := ret[0].(*lib.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateGame indicates an expected call of UpdateGame
+func (mr *MockKhanInterfaceMockRecorder) UpdateGame(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGame", reflect.TypeOf((*MockKhanInterface)(nil).UpdateGame), arg0, arg1)
+}
+
+// UpdateHook mocks base method
+func (m *MockKhanInterface) UpdateHook(arg0 context.Context, arg1 *lib.HookPayload) (*lib.Hook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHook", arg0, arg1)
+	ret0, _ := ret[0].(*lib.Hook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHook indicates an expected call of UpdateHook
+func (mr *MockKhanInterfaceMockRecorder) UpdateHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHook", reflect.TypeOf((*MockKhanInterface)(nil).UpdateHook), arg0, arg1)
+}
+
 // UpdatePlayer mocks base method
 func (m *MockKhanInterface) UpdatePlayer(arg0 context.Context, arg1, arg2 string, arg3 interface{}) (*lib.Result, error) {
 	m.ctrl.T.Helper()
@@ -1,29 +1,5 @@
 package lib
 
-import "fmt"
-
-// RequestError contains code and body of a request that failed
-type RequestError struct {
-	statusCode int
-	body       string
-}
-
-func newRequestError(statusCode int, body string) *RequestError {
-	return &RequestError{
-		statusCode: statusCode,
-		body:       body,
-	}
-}
-
-func (r *RequestError) Error() string {
-	return fmt.Sprintf("Request error. Status code: %d. Body: %s", r.statusCode, r.body)
-}
-
-//Status returns the status code of the error
-func (r *RequestError) Status() int {
-	return r.statusCode
-}
-
 //ClanPayload maps the payload for the Create Clan route and Update Clan route
 type ClanPayload struct {
 	PublicID         string      `json:"publicID,omitempty"`
@@ -70,6 +46,8 @@ type PlayerMembership struct {
 	DeletedAt  int64            `json:"deletedAt"`
 	ApprovedAt int64            `json:"approvedAt"`
 	DeniedAt   int64            `json:"deniedAt"`
+	ExpiresAt  int64            `json:"expiresAt"`
+	Status     string           `json:"status,omitempty"`
 	Level      string           `json:"level"`
 	Message    string           `json:"message"`
 	Requestor  *ShortPlayerInfo `json:"requestor"`
@@ -222,7 +200,386 @@ type Result struct {
 	Success bool
 }
 
+// SearchClansResult is the result of search clans method
 type SearchClansResult struct {
 	Success bool
 	Clans   []*ClanSummary
 }
+
+// Status is the structure returned by the status route
+type Status struct {
+	App *AppStatus `json:"app"`
+}
+
+// AppStatus is the status of the khan application
+type AppStatus struct {
+	ErrorRate float64 `json:"errorRate"`
+}
+
+// GamePayload maps the payload for the Create Game route and Update Game route.
+// The cooldowns before apply and invite and the max pending invites use the khan
+// defaults when nil
+type GamePayload struct {
+	PublicID                      string         `json:"publicID,omitempty"`
+	Name                          string         `json:"name"`
+	MembershipLevels              map[string]int `json:"membershipLevels"`
+	Metadata                      interface{}    `json:"metadata"`
+	MinLevelToAcceptApplication   int            `json:"minLevelToAcceptApplication"`
+	MinLevelToCreateInvitation    int            `json:"minLevelToCreateInvitation"`
+	MinLevelToRemoveMember        int            `json:"minLevelToRemoveMember"`
+	MinLevelOffsetToRemoveMember  int            `json:"minLevelOffsetToRemoveMember"`
+	MinLevelOffsetToPromoteMember int            `json:"minLevelOffsetToPromoteMember"`
+	MinLevelOffsetToDemoteMember  int            `json:"minLevelOffsetToDemoteMember"`
+	MaxMembers                    int            `json:"maxMembers"`
+	MaxClansPerPlayer             int            `json:"maxClansPerPlayer"`
+	CooldownAfterDeny             int            `json:"cooldownAfterDeny"`
+	CooldownAfterDelete           int            `json:"cooldownAfterDelete"`
+	CooldownBeforeApply           *int           `json:"cooldownBeforeApply,omitempty"`
+	CooldownBeforeInvite          *int           `json:"cooldownBeforeInvite,omitempty"`
+	MaxPendingInvites             *int           `json:"maxPendingInvites,omitempty"`
+	ClanHookFieldsWhitelist       string         `json:"clanHookFieldsWhitelist"`
+	PlayerHookFieldsWhitelist     string         `json:"playerHookFieldsWhitelist"`
+}
+
+// Game is the structure returned by the retrieve game and list games routes
+type Game struct {
+	PublicID                      string         `json:"publicID"`
+	Name                          string         `json:"name"`
+	MembershipLevels              map[string]int `json:"membershipLevels"`
+	Metadata                      interface{}    `json:"metadata"`
+	MinLevelToAcceptApplication   int            `json:"minLevelToAcceptApplication"`
+	MinLevelToCreateInvitation    int            `json:"minLevelToCreateInvitation"`
+	MinLevelToRemoveMember        int            `json:"minLevelToRemoveMember"`
+	MinLevelOffsetToRemoveMember  int            `json:"minLevelOffsetToRemoveMember"`
+	MinLevelOffsetToPromoteMember int            `json:"minLevelOffsetToPromoteMember"`
+	MinLevelOffsetToDemoteMember  int            `json:"minLevelOffsetToDemoteMember"`
+	MaxMembers                    int            `json:"maxMembers"`
+	MaxClansPerPlayer             int            `json:"maxClansPerPlayer"`
+	CooldownAfterDeny             int            `json:"cooldownAfterDeny"`
+	CooldownAfterDelete           int            `json:"cooldownAfterDelete"`
+	CooldownBeforeApply           int            `json:"cooldownBeforeApply"`
+	CooldownBeforeInvite          int            `json:"cooldownBeforeInvite"`
+	MaxPendingInvites             int            `json:"maxPendingInvites"`
+	ClanHookFieldsWhitelist       string         `json:"clanHookFieldsWhitelist"`
+	PlayerHookFieldsWhitelist     string         `json:"playerHookFieldsWhitelist"`
+	Archived                      bool           `json:"archived"`
+	ArchivedAt                    int64          `json:"archivedAt"`
+}
+
+// GamePatchPayload is the argument on patch game method. Only the non nil fields are changed,
+// metadata is merged into the game metadata (nil values remove the field) and the increments
+// are added to numeric metadata fields
+type GamePatchPayload struct {
+	PublicID          string                 `json:"-"`
+	Name              *string                `json:"name,omitempty"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+	IncrementMetadata map[string]float64     `json:"incrementMetadata,omitempty"`
+}
+
+// GamePatchResult is the result of patch game method
+type GamePatchResult struct {
+	Success  bool        `json:"success"`
+	Name     string      `json:"name"`
+	Metadata interface{} `json:"metadata"`
+}
+
+// ListGamesOptions is the argument on list games method. Archived filters archived or
+// active games when not nil
+type ListGamesOptions struct {
+	Limit    int
+	Cursor   string
+	Archived *bool
+}
+
+// ListGamesResult is the result of list games method
+type ListGamesResult struct {
+	Success    bool    `json:"success"`
+	Games      []*Game `json:"games"`
+	NextCursor string  `json:"nextCursor"`
+}
+
+// ArchiveGameResult is the result of archive and unarchive game methods
+type ArchiveGameResult struct {
+	Success    bool   `json:"success"`
+	PublicID   string `json:"publicID"`
+	Archived   bool   `json:"archived"`
+	ArchivedAt int64  `json:"archivedAt"`
+}
+
+// HookPayload is the argument on create hook and update hook methods. A hook is subscribed
+// to Type unless Types or Wildcards are sent
+type HookPayload struct {
+	PublicID     string                 `json:"-"`
+	Type         int                    `json:"type"`
+	Types        []int                  `json:"types,omitempty"`
+	Wildcards    []string               `json:"wildcards,omitempty"`
+	HookURL      string                 `json:"hookURL"`
+	Secret       string                 `json:"secret,omitempty"`
+	Filter       string                 `json:"filter,omitempty"`
+	BodyTemplate map[string]interface{} `json:"bodyTemplate,omitempty"`
+}
+
+// Hook is the structure returned by the hook routes. The secret is never returned
+type Hook struct {
+	GameID       string                 `json:"gameID"`
+	PublicID     string                 `json:"publicID"`
+	Type         int                    `json:"type"`
+	Types        []int                  `json:"types"`
+	Wildcards    []string               `json:"wildcards"`
+	HookURL      string                 `json:"hookURL"`
+	Signed       bool                   `json:"signed"`
+	Filter       string                 `json:"filter"`
+	BodyTemplate map[string]interface{} `json:"bodyTemplate"`
+	CreatedAt    int64                  `json:"createdAt"`
+	UpdatedAt    int64                  `json:"updatedAt"`
+}
+
+// ListHooksResult is the result of list hooks method
+type ListHooksResult struct {
+	Success bool    `json:"success"`
+	Hooks   []*Hook `json:"hooks"`
+}
+
+// TestHookResult is the result of test hook method
+type TestHookResult struct {
+	Success bool   `json:"success"`
+	EventID string `json:"eventID"`
+}
+
+// HookDelivery is an attempt to deliver an event to a hook
+type HookDelivery struct {
+	EventID      string `json:"eventID"`
+	EventType    int    `json:"eventType"`
+	Attempt      int    `json:"attempt"`
+	StatusCode   int    `json:"statusCode"`
+	LatencyMs    int64  `json:"latencyMs"`
+	ResponseBody string `json:"responseBody"`
+	Error        string `json:"error"`
+	CreatedAt    int64  `json:"createdAt"`
+}
+
+// HookDeliveriesResult is the result of retrieve hook deliveries method
+type HookDeliveriesResult struct {
+	Success    bool            `json:"success"`
+	Deliveries []*HookDelivery `json:"deliveries"`
+}
+
+// PlayerPatchPayload is the argument on patch player method. Only the non nil fields are changed,
+// metadata is merged into the player metadata (nil values remove the field) and the increments
+// are added to numeric metadata fields
+type PlayerPatchPayload struct {
+	PublicID          string                 `json:"-"`
+	Name              *string                `json:"name,omitempty"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+	IncrementMetadata map[string]float64     `json:"incrementMetadata,omitempty"`
+}
+
+// PlayerPatchResult is the result of patch player method
+type PlayerPatchResult struct {
+	Success  bool        `json:"success"`
+	Name     string      `json:"name"`
+	Metadata interface{} `json:"metadata"`
+	Version  int64       `json:"version"`
+}
+
+// PlayerMembershipsOptions is the argument on retrieve player memberships method. Statuses
+// are approved, pendingApplication, pendingInvite, expired, denied, banned or deleted
+type PlayerMembershipsOptions struct {
+	PlayerPublicID string
+	Limit          int
+	Cursor         string
+	ClanPublicID   string
+	Statuses       []string
+	Order          string
+}
+
+// PlayerMembershipsResult is the result of retrieve player memberships method
+type PlayerMembershipsResult struct {
+	Success     bool                `json:"success"`
+	Memberships []*PlayerMembership `json:"memberships"`
+	NextCursor  string              `json:"nextCursor"`
+}
+
+// ListClansOptions is the argument on list clans method. OrderBy is name, membershipCount
+// or createdAt and the metadata filters match clan metadata fields by value
+type ListClansOptions struct {
+	Limit              int
+	Cursor             string
+	OrderBy            string
+	Order              string
+	AllowApplication   *bool
+	AutoJoin           *bool
+	MinMembershipCount *int
+	MaxMembershipCount *int
+	Metadata           map[string]string
+}
+
+// ListClansResult is the result of list clans method
+type ListClansResult struct {
+	Success    bool           `json:"success"`
+	Clans      []*ClanSummary `json:"clans"`
+	NextCursor string         `json:"nextCursor"`
+}
+
+// ClanPatchPayload is the argument on patch clan method. Only the non nil fields are changed,
+// metadata is merged into the clan metadata (nil values remove the field) and the increments
+// are added to numeric metadata fields
+type ClanPatchPayload struct {
+	PublicID          string                 `json:"-"`
+	OwnerPublicID     string                 `json:"ownerPublicID"`
+	Name              *string                `json:"name,omitempty"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+	IncrementMetadata map[string]float64     `json:"incrementMetadata,omitempty"`
+	AllowApplication  *bool                  `json:"allowApplication,omitempty"`
+	AutoJoin          *bool                  `json:"autoJoin,omitempty"`
+}
+
+// ClanPatchResult is the result of patch clan method
+type ClanPatchResult struct {
+	Success          bool        `json:"success"`
+	PublicID         string      `json:"publicID"`
+	Name             string      `json:"name"`
+	Metadata         interface{} `json:"metadata"`
+	AllowApplication bool        `json:"allowApplication"`
+	AutoJoin         bool        `json:"autoJoin"`
+	Version          int64       `json:"version"`
+}
+
+// ClanMembersOptions is the argument on retrieve clan members page method. OrderBy is
+// approvedAt or level
+type ClanMembersOptions struct {
+	ClanID  string
+	Limit   int
+	Cursor  string
+	Level   string
+	OrderBy string
+	Order   string
+}
+
+// ClanMember is a member returned in a page of clan members
+type ClanMember struct {
+	PublicID   string      `json:"publicID"`
+	Name       string      `json:"name"`
+	Metadata   interface{} `json:"metadata"`
+	Level      string      `json:"level"`
+	ApprovedAt int64       `json:"approvedAt"`
+}
+
+// ClanMembersPage is the result of retrieve clan members page method
+type ClanMembersPage struct {
+	Success    bool             `json:"success"`
+	Owner      *ShortPlayerInfo `json:"owner"`
+	Members    []*ClanMember    `json:"members"`
+	NextCursor string           `json:"nextCursor"`
+}
+
+// TopClansOptions is the argument on top clans method. The rank of ClanPublicID is also
+// returned when it is sent
+type TopClansOptions struct {
+	Dimension    string
+	Limit        int
+	ClanPublicID string
+}
+
+// ClanRank is the position of a clan in a ranking dimension
+type ClanRank struct {
+	Rank            int         `json:"rank"`
+	Score           float64     `json:"score"`
+	PublicID        string      `json:"publicID"`
+	Name            string      `json:"name"`
+	MembershipCount int         `json:"membershipCount"`
+	Metadata        interface{} `json:"metadata"`
+}
+
+// TopClansResult is the result of top clans method
+type TopClansResult struct {
+	Success   bool        `json:"success"`
+	Dimension string      `json:"dimension"`
+	Clans     []*ClanRank `json:"clans"`
+	Clan      *ClanRank   `json:"clan"`
+}
+
+// BanUnbanPayload is the argument on ban or unban method. Duration is the number of
+// seconds of the ban, zero meaning permanent
+type BanUnbanPayload struct {
+	ClanID            string `json:"-"`
+	Action            string `json:"-"`
+	PlayerPublicID    string `json:"playerPublicID"`
+	RequestorPublicID string `json:"requestorPublicID"`
+	Duration          int    `json:"duration,omitempty"`
+}
+
+// BanUnbanResult is the result of ban or unban method
+type BanUnbanResult struct {
+	Success      bool  `json:"success"`
+	BanExpiresAt int64 `json:"banExpiresAt"`
+}
+
+// BatchMembershipOperation is an operation of the batch memberships method. Action is
+// approve, deny, invite, kick, promote or demote
+type BatchMembershipOperation struct {
+	Action            string `json:"action"`
+	PlayerPublicID    string `json:"playerPublicID"`
+	RequestorPublicID string `json:"requestorPublicID"`
+	Level             string `json:"level,omitempty"`
+	Message           string `json:"message,omitempty"`
+}
+
+// BatchMembershipsPayload is the argument on batch memberships method. Mode is atomic or bestEffort
+type BatchMembershipsPayload struct {
+	ClanID     string                      `json:"-"`
+	Mode       string                      `json:"mode,omitempty"`
+	Operations []*BatchMembershipOperation `json:"operations"`
+}
+
+// BatchMembershipOperationResult is the result of an operation of the batch memberships method
+type BatchMembershipOperationResult struct {
+	Index          int    `json:"index"`
+	Action         string `json:"action"`
+	PlayerPublicID string `json:"playerPublicID"`
+	Success        bool   `json:"success"`
+	Status         int    `json:"status"`
+	Code           string `json:"code"`
+	Reason         string `json:"reason"`
+	Approved       bool   `json:"approved"`
+	Level          string `json:"level"`
+}
+
+// BatchMembershipsResult is the result of batch memberships method
+type BatchMembershipsResult struct {
+	Success   bool                              `json:"success"`
+	Results   []*BatchMembershipOperationResult `json:"results"`
+	Succeeded int                               `json:"succeeded"`
+	Failed    int                               `json:"failed"`
+}
+
+// AuditEntriesOptions is the argument on retrieve audit entries method. The trail is restricted
+// to a clan or a player when ClanPublicID or PlayerPublicID are sent. Since and Until are
+// timestamps in milliseconds
+type AuditEntriesOptions struct {
+	ClanPublicID   string
+	PlayerPublicID string
+	Limit          int
+	Cursor         string
+	Since          int64
+	Until          int64
+}
+
+// AuditEntry is an action recorded in the audit trail of a game
+type AuditEntry struct {
+	Action         string                 `json:"action"`
+	ActorPublicID  string                 `json:"actorPublicID"`
+	ClanPublicID   string                 `json:"clanPublicID"`
+	PlayerPublicID string                 `json:"playerPublicID"`
+	Before         map[string]interface{} `json:"before"`
+	After          map[string]interface{} `json:"after"`
+	RequestID      string                 `json:"requestID"`
+	CreatedAt      int64                  `json:"createdAt"`
+}
+
+// AuditEntriesResult is the result of retrieve audit entries method
+type AuditEntriesResult struct {
+	Success    bool          `json:"success"`
+	Entries    []*AuditEntry `json:"entries"`
+	NextCursor string        `json:"nextCursor"`
+}
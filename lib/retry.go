@@ -0,0 +1,58 @@
+package lib
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy configures the retries of requests that fail because khan is unavailable, i.e. with
+// a connection error or with status 502, 503 or 504
+type RetryPolicy struct {
+	// MaxAttempts is the max number of times a request is sent. Retries are disabled below 2
+	MaxAttempts int
+	// Backoff is the time waited before the first retry. It doubles after each retry, up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// RetryMutations makes mutations sent without an idempotency key be retried with a generated one.
+	// Otherwise only safe requests and mutations with a key set by WithIdempotencyKey are retried.
+	// Khan only honors idempotency keys in the routes of a game, so creating a game is never retried
+	RetryMutations bool
+}
+
+// getBackoff returns the time to wait before the given retry, with a random jitter of up to half of it
+// so clients that failed together do not retry together
+func (p *RetryPolicy) getBackoff(retry int) time.Duration {
+	backoff := p.Backoff
+	for i := 1; i < retry && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+func isSafeMethod(method string) bool {
+	return method == "GET" || method == "HEAD" || method == "OPTIONS"
+}
+
+// isUnavailableError returns whether a request failed because khan could not process it, in
+// which case it may succeed if sent again
+func isUnavailableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if reqErr, ok := err.(interface {
+		Status() int
+	}); ok {
+		status := reqErr.Status()
+		return status == http.StatusBadGateway ||
+			status == http.StatusServiceUnavailable ||
+			status == http.StatusGatewayTimeout
+	}
+	return true
+}
@@ -21,6 +21,7 @@ type clanDetailsDAO struct {
 	ClanAllowApplication bool
 	ClanAutoJoin         bool
 	ClanMembershipCount  int
+	ClanVersion          int64
 
 	//Membership Information
 	MembershipLevel      sql.NullString
@@ -33,6 +34,9 @@ type clanDetailsDAO struct {
 	MembershipDeniedAt   sql.NullInt64
 	MembershipMessage    sql.NullString
 
+	MembershipBannedAt     sql.NullInt64
+	MembershipBanExpiresAt sql.NullInt64
+
 	// Clan Owner Information
 	OwnerPublicID string
 	OwnerName     string
@@ -57,6 +61,10 @@ type clanDetailsDAO struct {
 	// Denier Information
 	DenierPublicID sql.NullString
 	DenierName     sql.NullString
+
+	// Banner Information
+	BannerPublicID sql.NullString
+	BannerName     sql.NullString
 }
 
 func (member *clanDetailsDAO) Serialize(includeMembershipLevel bool) map[string]interface{} {
@@ -87,6 +95,13 @@ func (member *clanDetailsDAO) Serialize(includeMembershipLevel bool) map[string]
 			"publicID": member.DenierPublicID.String,
 		}
 	}
+
+	if member.BannerName.Valid {
+		result["player"].(map[string]interface{})["banner"] = map[string]interface{}{
+			"name":     member.BannerName.String,
+			"publicID": member.BannerPublicID.String,
+		}
+	}
 	return result
 }
 
@@ -98,8 +113,10 @@ type playerDetailsDAO struct {
 	PlayerPublicID  string
 	PlayerCreatedAt int64
 	PlayerUpdatedAt int64
+	PlayerVersion   int64
 
 	// Membership Details
+	MembershipID         sql.NullInt64
 	MembershipLevel      sql.NullString
 	MembershipApproved   sql.NullBool
 	MembershipDenied     sql.NullBool
@@ -109,6 +126,7 @@ type playerDetailsDAO struct {
 	MembershipDeletedAt  sql.NullInt64
 	MembershipApprovedAt sql.NullInt64
 	MembershipDeniedAt   sql.NullInt64
+	MembershipExpiresAt  sql.NullInt64
 	MembershipMessage    sql.NullString
 
 	// Clan Details
@@ -154,6 +172,7 @@ func (p *playerDetailsDAO) Serialize() map[string]interface{} {
 		"deletedAt":  nullOrInt(p.MembershipDeletedAt),
 		"approvedAt": nullOrInt(p.MembershipApprovedAt),
 		"deniedAt":   nullOrInt(p.MembershipDeniedAt),
+		"expiresAt":  nullOrInt(p.MembershipExpiresAt),
 		"message":    nullOrString(p.MembershipMessage),
 		"clan": map[string]interface{}{
 			"publicID":        nullOrString(p.ClanPublicID),
@@ -217,3 +236,53 @@ func (p *playerDetailsDAO) Serialize() map[string]interface{} {
 	}
 	return result
 }
+
+type clanMemberDAO struct {
+	MembershipID         int64
+	MembershipLevel      string
+	MembershipLevelInt   int64
+	MembershipApprovedAt int64
+	PlayerPublicID       string
+	PlayerName           string
+	DBPlayerMetadata     sql.NullString
+}
+
+func (m *clanMemberDAO) Serialize() map[string]interface{} {
+	metadata := map[string]interface{}{}
+	if m.DBPlayerMetadata.Valid {
+		json.Unmarshal([]byte(m.DBPlayerMetadata.String), &metadata)
+	}
+	return map[string]interface{}{
+		"publicID":   m.PlayerPublicID,
+		"name":       m.PlayerName,
+		"metadata":   metadata,
+		"level":      m.MembershipLevel,
+		"approvedAt": m.MembershipApprovedAt,
+	}
+}
+
+type transferClanDAO struct {
+	ID               int64
+	PublicID         string
+	Name             string
+	Metadata         map[string]interface{}
+	AllowApplication bool
+	AutoJoin         bool
+	OwnerPublicID    string
+	DeletedAt        int64
+}
+
+type transferMembershipDAO struct {
+	ID                int64
+	PlayerPublicID    string
+	ClanPublicID      string
+	RequestorPublicID string
+	Level             string
+	Approved          bool
+	Denied            bool
+	Banned            bool
+	BanExpiresAt      int64
+	Message           string
+	DeletedAt         int64
+	DeletedByPublicID sql.NullString
+}
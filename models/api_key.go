@@ -0,0 +1,146 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/go-gorp/gorp"
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/util"
+)
+
+const (
+	//APIKeyRoleAdmin manages the game, its hooks and API keys, besides everything the server role does
+	APIKeyRoleAdmin = "admin"
+
+	//APIKeyRoleServer runs every player, clan and membership operation of the game
+	APIKeyRoleServer = "server"
+
+	//APIKeyRoleReadOnly only retrieves the data of the game
+	APIKeyRoleReadOnly = "readonly"
+)
+
+// apiKeySize is the number of random bytes of an API key
+const apiKeySize = 32
+
+// APIKey authenticates the requests of a game backend to the routes of that game. Only the
+// SHA-256 hash of the key is stored, so the key itself is only known when it is created
+type APIKey struct {
+	ID        int    `db:"id"`
+	GameID    string `db:"game_id"`
+	PublicID  string `db:"public_id"`
+	Name      string `db:"name"`
+	Role      string `db:"role"`
+	KeyHash   string `db:"key_hash"`
+	CreatedAt int64  `db:"created_at"`
+}
+
+// Serialize returns a JSON with the API key details. The key hash is never included
+func (k *APIKey) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"gameID":    k.GameID,
+		"publicID":  k.PublicID,
+		"name":      k.Name,
+		"role":      k.Role,
+		"createdAt": k.CreatedAt,
+	}
+}
+
+// AuditActor returns the actor recorded in the audit trail for the admin actions performed with the key
+func (k *APIKey) AuditActor() string {
+	return fmt.Sprintf("apiKey:%s", k.PublicID)
+}
+
+// PreInsert populates fields before inserting a new API key
+func (k *APIKey) PreInsert(s gorp.SqlExecutor) error {
+	k.CreatedAt = util.NowMilli()
+	return nil
+}
+
+// IsValidAPIKeyRole returns whether role is one of the API key roles
+func IsValidAPIKeyRole(role string) bool {
+	return role == APIKeyRoleAdmin || role == APIKeyRoleServer || role == APIKeyRoleReadOnly
+}
+
+// HashAPIKey returns the hash of an API key, as it is stored
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// CreateAPIKey creates an API key with the given role for a game. It returns the key along with
+// the model, since the key can't be retrieved again
+func CreateAPIKey(db DB, gameID, name, role string) (*APIKey, string, error) {
+	if !IsValidAPIKeyRole(role) {
+		return nil, "", &InvalidAPIKeyRoleError{role}
+	}
+
+	secret := make([]byte, apiKeySize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	key := hex.EncodeToString(secret)
+
+	apiKey := &APIKey{
+		GameID:   gameID,
+		PublicID: uuid.NewV4().String(),
+		Name:     name,
+		Role:     role,
+		KeyHash:  HashAPIKey(key),
+	}
+	err := db.Insert(apiKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return apiKey, key, nil
+}
+
+// GetAPIKeyByKey returns the API key that matches key, whatever its game
+func GetAPIKeyByKey(db DB, key string) (*APIKey, error) {
+	var apiKeys []*APIKey
+	_, err := db.Select(&apiKeys, "SELECT * FROM api_keys WHERE key_hash=$1", HashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if len(apiKeys) == 0 {
+		// the key itself is never included in errors
+		return nil, &ModelNotFoundError{"APIKey", "<redacted>"}
+	}
+	return apiKeys[0], nil
+}
+
+// GetAPIKeysByGameID returns all the API keys of a game, oldest first
+func GetAPIKeysByGameID(db DB, gameID string) ([]*APIKey, error) {
+	var apiKeys []*APIKey
+	_, err := db.Select(&apiKeys, "SELECT * FROM api_keys WHERE game_id=$1 ORDER BY id", gameID)
+	if err != nil {
+		return nil, err
+	}
+	return apiKeys, nil
+}
+
+// DeleteAPIKey revokes an API key of a game and returns it
+func DeleteAPIKey(db DB, gameID, publicID string) (*APIKey, error) {
+	var apiKeys []*APIKey
+	_, err := db.Select(
+		&apiKeys,
+		"DELETE FROM api_keys WHERE game_id=$1 AND public_id=$2 RETURNING *",
+		gameID, publicID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(apiKeys) == 0 {
+		return nil, &ModelNotFoundError{"APIKey", publicID}
+	}
+	return apiKeys[0], nil
+}
@@ -0,0 +1,107 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("API Key Model", func() {
+	var testDb DB
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Create API Key", func() {
+		It("Should create an API key storing only its hash", func() {
+			gameID := uuid.NewV4().String()
+			apiKey, key, err := CreateAPIKey(testDb, gameID, "game-server", APIKeyRoleServer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apiKey.ID).NotTo(BeEquivalentTo(0))
+			Expect(apiKey.PublicID).NotTo(BeEquivalentTo(""))
+			Expect(apiKey.CreatedAt).To(BeNumerically(">", 0))
+			Expect(key).To(HaveLen(64))
+			Expect(apiKey.KeyHash).To(Equal(HashAPIKey(key)))
+			Expect(apiKey.KeyHash).NotTo(Equal(key))
+			Expect(apiKey.Serialize()).NotTo(HaveKey("keyHash"))
+		})
+
+		It("Should not create an API key with invalid role", func() {
+			_, _, err := CreateAPIKey(testDb, uuid.NewV4().String(), "", "superuser")
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&InvalidAPIKeyRoleError{}))
+		})
+	})
+
+	Describe("Get API Key By Key", func() {
+		It("Should get existing API key", func() {
+			gameID := uuid.NewV4().String()
+			apiKey, key, err := CreateAPIKey(testDb, gameID, "", APIKeyRoleAdmin)
+			Expect(err).NotTo(HaveOccurred())
+
+			dbAPIKey, err := GetAPIKeyByKey(testDb, key)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbAPIKey.PublicID).To(Equal(apiKey.PublicID))
+			Expect(dbAPIKey.GameID).To(Equal(gameID))
+			Expect(dbAPIKey.Role).To(Equal(APIKeyRoleAdmin))
+		})
+
+		It("Should not get non-existing API key nor include it in the error", func() {
+			_, err := GetAPIKeyByKey(testDb, "invalid-key")
+			Expect(err).To(BeAssignableToTypeOf(&ModelNotFoundError{}))
+			Expect(err.Error()).NotTo(ContainSubstring("invalid-key"))
+		})
+	})
+
+	Describe("Get API Keys By Game ID", func() {
+		It("Should get the API keys of the game", func() {
+			gameID := uuid.NewV4().String()
+			first, _, err := CreateAPIKey(testDb, gameID, "", APIKeyRoleAdmin)
+			Expect(err).NotTo(HaveOccurred())
+			second, _, err := CreateAPIKey(testDb, gameID, "", APIKeyRoleReadOnly)
+			Expect(err).NotTo(HaveOccurred())
+			_, _, err = CreateAPIKey(testDb, uuid.NewV4().String(), "", APIKeyRoleAdmin)
+			Expect(err).NotTo(HaveOccurred())
+
+			apiKeys, err := GetAPIKeysByGameID(testDb, gameID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apiKeys).To(HaveLen(2))
+			Expect(apiKeys[0].PublicID).To(Equal(first.PublicID))
+			Expect(apiKeys[1].PublicID).To(Equal(second.PublicID))
+		})
+	})
+
+	Describe("Delete API Key", func() {
+		It("Should delete API key", func() {
+			gameID := uuid.NewV4().String()
+			apiKey, key, err := CreateAPIKey(testDb, gameID, "", APIKeyRoleServer)
+			Expect(err).NotTo(HaveOccurred())
+
+			deleted, err := DeleteAPIKey(testDb, gameID, apiKey.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted.KeyHash).To(Equal(apiKey.KeyHash))
+
+			_, err = GetAPIKeyByKey(testDb, key)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should not delete API key of another game", func() {
+			apiKey, _, err := CreateAPIKey(testDb, uuid.NewV4().String(), "", APIKeyRoleServer)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = DeleteAPIKey(testDb, uuid.NewV4().String(), apiKey.PublicID)
+			Expect(err).To(BeAssignableToTypeOf(&ModelNotFoundError{}))
+		})
+	})
+})
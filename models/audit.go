@@ -0,0 +1,309 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+	"github.com/topfreegames/extensions/gorp/interfaces"
+	"github.com/topfreegames/khan/util"
+)
+
+const (
+	//ClanCreatedAction is audited when a clan is created
+	ClanCreatedAction = "clan.created"
+
+	//ClanUpdatedAction is audited when a clan is updated or patched
+	ClanUpdatedAction = "clan.updated"
+
+	//ClanLeftAction is audited when a clan owner leaves the clan
+	ClanLeftAction = "clan.left"
+
+	//ClanOwnershipTransferredAction is audited when a clan owner transfers ownership to another player
+	ClanOwnershipTransferredAction = "clan.ownershipTransferred"
+
+	//ClanDeletedAction is audited when a clan is deleted
+	ClanDeletedAction = "clan.deleted"
+
+	//MembershipAppliedAction is audited when a player applies to a clan
+	MembershipAppliedAction = "membership.applied"
+
+	//MembershipInvitedAction is audited when a player is invited to a clan
+	MembershipInvitedAction = "membership.invited"
+
+	//MembershipApprovedAction is audited when an application or invitation is approved
+	MembershipApprovedAction = "membership.approved"
+
+	//MembershipDeniedAction is audited when an application or invitation is denied
+	MembershipDeniedAction = "membership.denied"
+
+	//MembershipPromotedAction is audited when a clan member is promoted
+	MembershipPromotedAction = "membership.promoted"
+
+	//MembershipDemotedAction is audited when a clan member is demoted
+	MembershipDemotedAction = "membership.demoted"
+
+	//MembershipDeletedAction is audited when a player leaves or is removed from a clan
+	MembershipDeletedAction = "membership.deleted"
+
+	//MembershipBannedAction is audited when a player is banned from a clan
+	MembershipBannedAction = "membership.banned"
+
+	//MembershipUnbannedAction is audited when a player is unbanned from a clan
+	MembershipUnbannedAction = "membership.unbanned"
+)
+
+//AdminAuditActor is the actor of the admin actions performed with the basic auth credentials
+const AdminAuditActor = "admin"
+
+// AuditEntriesDefaultLimitKey is string constant
+const AuditEntriesDefaultLimitKey string = "audit.defaultLimit"
+
+// AuditEntriesMaxLimitKey is string constant
+const AuditEntriesMaxLimitKey string = "audit.maxLimit"
+
+// AuditEntry is an append-only record of an action performed on a clan or membership
+type AuditEntry struct {
+	ID             int64                  `db:"id"`
+	GameID         string                 `db:"game_id"`
+	Action         string                 `db:"action"`
+	ActorPublicID  string                 `db:"actor_public_id"`
+	ClanPublicID   string                 `db:"clan_public_id"`
+	PlayerPublicID string                 `db:"player_public_id"`
+	Before         map[string]interface{} `db:"before"`
+	After          map[string]interface{} `db:"after"`
+	RequestID      string                 `db:"request_id"`
+	CreatedAt      int64                  `db:"created_at"`
+}
+
+// PreInsert populates fields before inserting a new audit entry
+func (e *AuditEntry) PreInsert(s gorp.SqlExecutor) error {
+	e.CreatedAt = util.NowMilli()
+	return nil
+}
+
+// Serialize returns a JSON with the audit entry details
+func (e *AuditEntry) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"action":         e.Action,
+		"actorPublicID":  e.ActorPublicID,
+		"clanPublicID":   e.ClanPublicID,
+		"playerPublicID": e.PlayerPublicID,
+		"before":         e.Before,
+		"after":          e.After,
+		"requestID":      e.RequestID,
+		"createdAt":      e.CreatedAt,
+	}
+}
+
+// requestDatabase is a database serving a request, so the audit entries written with it record the request ID
+type requestDatabase struct {
+	interfaces.Database
+	requestID string
+}
+
+// Begin starts a transaction that also records the request ID in audit entries
+func (d *requestDatabase) Begin() (interfaces.Transaction, error) {
+	tx, err := d.Database.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &requestTransaction{tx, d.requestID}, nil
+}
+
+// requestTransaction is a transaction serving a request, so the audit entries written with it record the request ID
+type requestTransaction struct {
+	interfaces.Transaction
+	requestID string
+}
+
+// WithRequestID returns a database that records requestID in the audit entries written with it
+func WithRequestID(db interfaces.Database, requestID string) interfaces.Database {
+	if requestID == "" {
+		return db
+	}
+	return &requestDatabase{db, requestID}
+}
+
+// getRequestID returns the ID of the request db is serving, if any
+func getRequestID(db DB) string {
+	switch d := db.(type) {
+	case *requestDatabase:
+		return d.requestID
+	case *requestTransaction:
+		return d.requestID
+	}
+	return ""
+}
+
+// getSQLExecutor returns the gorp executor behind db, used to call model hooks explicitly
+func getSQLExecutor(db DB) (gorp.SqlExecutor, error) {
+	switch d := db.(type) {
+	case *requestDatabase:
+		db = d.Database
+	case *requestTransaction:
+		db = d.Transaction
+	}
+	gorpSQLExecutor, ok := db.(gorp.SqlExecutor)
+	if !ok {
+		return nil, &InvalidCastToGorpSQLExecutorError{}
+	}
+	return gorpSQLExecutor, nil
+}
+
+// createAuditEntry appends an entry to the audit trail using db, so it is written in the same transaction as the action
+func createAuditEntry(db DB, gameID, action, actorPublicID, clanPublicID, playerPublicID string, before, after map[string]interface{}) error {
+	return db.Insert(&AuditEntry{
+		GameID:         gameID,
+		Action:         action,
+		ActorPublicID:  actorPublicID,
+		ClanPublicID:   clanPublicID,
+		PlayerPublicID: playerPublicID,
+		Before:         before,
+		After:          after,
+		RequestID:      getRequestID(db),
+	})
+}
+
+// getClanAuditValues returns the clan fields recorded in the audit trail
+func getClanAuditValues(clan *Clan) map[string]interface{} {
+	if clan == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"name":             clan.Name,
+		"metadata":         clan.Metadata,
+		"allowApplication": clan.AllowApplication,
+		"autoJoin":         clan.AutoJoin,
+		"version":          clan.Version,
+	}
+}
+
+// getMembershipAuditValues returns the membership fields recorded in the audit trail
+func getMembershipAuditValues(membership *Membership) map[string]interface{} {
+	if membership == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"level":        membership.Level,
+		"approved":     membership.Approved,
+		"denied":       membership.Denied,
+		"banned":       membership.Banned,
+		"message":      membership.Message,
+		"deletedAt":    membership.DeletedAt,
+		"banExpiresAt": membership.BanExpiresAt,
+		"expiresAt":    membership.ExpiresAt,
+	}
+}
+
+// auditMembershipAction runs a membership action and appends it to the audit trail,
+// along with the membership as it was before and after the action
+func auditMembershipAction(
+	db DB, gameID, action, actorPublicID, clanPublicID, playerPublicID string,
+	run func() (*Membership, error),
+) (*Membership, error) {
+	// the membership does not exist before it is first created
+	before, err := GetMembershipByClanAndPlayerPublicID(db, gameID, clanPublicID, playerPublicID)
+	if _, ok := err.(*ModelNotFoundError); err != nil && !ok {
+		return nil, err
+	}
+	beforeValues := getMembershipAuditValues(before)
+
+	membership, err := run()
+	if err != nil {
+		return nil, err
+	}
+
+	err = createAuditEntry(
+		db, gameID, action, actorPublicID, clanPublicID, playerPublicID,
+		beforeValues, getMembershipAuditValues(membership),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// AuditEntriesOptions holds the pagination and filtering options of GetAuditEntries()
+type AuditEntriesOptions struct {
+	Limit          int
+	Cursor         string
+	ClanPublicID   string
+	PlayerPublicID string
+	Since          int64
+	Until          int64
+}
+
+// GetAuditEntries returns a page of the audit trail of a game, newest entries first, and the cursor of the next page.
+// Filtering by player returns the entries where the player is either the target or the actor of the action
+func GetAuditEntries(db DB, gameID string, options *AuditEntriesOptions) ([]*AuditEntry, string, error) {
+	conditions := []string{"game_id=$1"}
+	args := []interface{}{gameID}
+	addCondition := func(condition string, values ...interface{}) {
+		placeholders := make([]interface{}, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if options.ClanPublicID != "" {
+		addCondition("clan_public_id=%s", options.ClanPublicID)
+	}
+	if options.PlayerPublicID != "" {
+		addCondition("(player_public_id=%s OR actor_public_id=%s)", options.PlayerPublicID, options.PlayerPublicID)
+	}
+	if options.Since > 0 {
+		addCondition("created_at>=%s", options.Since)
+	}
+	if options.Until > 0 {
+		addCondition("created_at<%s", options.Until)
+	}
+	if options.Cursor != "" {
+		value, id, err := decodeCursor(options.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		createdAt, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, "", &InvalidCursorError{options.Cursor}
+		}
+		addCondition("(created_at, id)<(%s, %s)", createdAt, id)
+	}
+
+	// fetch one extra entry to find out whether there is a next page
+	args = append(args, options.Limit+1)
+	query := fmt.Sprintf(
+		"SELECT * FROM audit_entries WHERE %s ORDER BY created_at DESC, id DESC LIMIT $%d",
+		strings.Join(conditions, " AND "), len(args),
+	)
+
+	var entries []*AuditEntry
+	_, err := db.Select(&entries, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(entries) > options.Limit {
+		entries = entries[:options.Limit]
+		last := entries[len(entries)-1]
+		nextCursor = encodeCursor(strconv.FormatInt(last.CreatedAt, 10), last.ID)
+	}
+	return entries, nextCursor, nil
+}
+
+// PruneAuditEntries deletes the audit entries of a game older than retention seconds
+func PruneAuditEntries(db DB, gameID string, retention int) (int, error) {
+	createdAt := util.NowMilli() - int64(retention)*1000
+	return runAndReturnRowsAffected("DELETE FROM audit_entries WHERE game_id=$1 AND created_at < $2", db, gameID, createdAt)
+}
@@ -0,0 +1,205 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/extensions/gorp/interfaces"
+	. "github.com/topfreegames/khan/models"
+	"github.com/topfreegames/khan/util"
+)
+
+var _ = Describe("Audit Model", func() {
+	var testDb DB
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	createClan := func(db DB) (*Game, *Player, *Clan) {
+		game, owner, err := CreatePlayerFactory(db, "")
+		Expect(err).NotTo(HaveOccurred())
+		clan, err := CreateClan(
+			db, game, game.PublicID, "audited-clan", "audited clan", owner.PublicID,
+			map[string]interface{}{"region": "us"}, true, false, 1,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		return game, owner, clan
+	}
+
+	Describe("Audit Trail", func() {
+		It("Should record clan creation and updates", func() {
+			game, owner, clan := createClan(testDb)
+
+			_, err := UpdateClan(
+				testDb, game, game.PublicID, clan.PublicID, "new name", owner.PublicID,
+				clan.Metadata, clan.AllowApplication, clan.AutoJoin,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			entries, nextCursor, err := GetAuditEntries(testDb, game.PublicID, &AuditEntriesOptions{
+				Limit: 10, ClanPublicID: clan.PublicID,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nextCursor).To(BeEmpty())
+			Expect(entries).To(HaveLen(2))
+
+			Expect(entries[0].Action).To(Equal(ClanUpdatedAction))
+			Expect(entries[0].ActorPublicID).To(Equal(owner.PublicID))
+			Expect(entries[0].Before["name"]).To(Equal("audited clan"))
+			Expect(entries[0].After["name"]).To(Equal("new name"))
+			Expect(entries[0].After["version"]).To(BeEquivalentTo(2))
+
+			Expect(entries[1].Action).To(Equal(ClanCreatedAction))
+			Expect(entries[1].Before).To(BeNil())
+			Expect(entries[1].After["metadata"]).To(Equal(map[string]interface{}{"region": "us"}))
+		})
+
+		It("Should record membership actions with the membership before and after them", func() {
+			game, owner, clan := createClan(testDb)
+			player := PlayerFactory.MustCreateWithOption(map[string]interface{}{
+				"GameID": game.PublicID,
+			}).(*Player)
+			err := testDb.Insert(player)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = CreateMembership(testDb, game, game.PublicID, "Member", player.PublicID, clan.PublicID, player.PublicID, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = ApproveOrDenyMembershipApplication(testDb, game, game.PublicID, player.PublicID, clan.PublicID, owner.PublicID, "approve")
+			Expect(err).NotTo(HaveOccurred())
+
+			entries, _, err := GetAuditEntries(testDb, game.PublicID, &AuditEntriesOptions{
+				Limit: 10, PlayerPublicID: player.PublicID,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+
+			Expect(entries[0].Action).To(Equal(MembershipApprovedAction))
+			Expect(entries[0].ActorPublicID).To(Equal(owner.PublicID))
+			Expect(entries[0].ClanPublicID).To(Equal(clan.PublicID))
+			Expect(entries[0].Before["approved"]).To(BeFalse())
+			Expect(entries[0].After["approved"]).To(BeTrue())
+
+			Expect(entries[1].Action).To(Equal(MembershipAppliedAction))
+			Expect(entries[1].ActorPublicID).To(Equal(player.PublicID))
+			Expect(entries[1].Before).To(BeNil())
+
+			entries, _, err = GetAuditEntries(testDb, game.PublicID, &AuditEntriesOptions{
+				Limit: 10, PlayerPublicID: owner.PublicID,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].Action).To(Equal(MembershipApprovedAction))
+			Expect(entries[1].Action).To(Equal(ClanCreatedAction))
+		})
+
+		It("Should not record failed actions", func() {
+			game, _, clan := createClan(testDb)
+
+			_, err := UpdateClan(
+				testDb, game, game.PublicID, clan.PublicID, "new name", "not-the-owner",
+				clan.Metadata, clan.AllowApplication, clan.AutoJoin,
+			)
+			Expect(err).To(HaveOccurred())
+
+			entries, _, err := GetAuditEntries(testDb, game.PublicID, &AuditEntriesOptions{Limit: 10})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].Action).To(Equal(ClanCreatedAction))
+		})
+
+		It("Should record the actor of admin clan deletions", func() {
+			game, _, clan := createClan(testDb)
+
+			_, _, err := DeleteClanAsAdmin(testDb, game.PublicID, clan.PublicID, AdminAuditActor)
+			Expect(err).NotTo(HaveOccurred())
+
+			entries, _, err := GetAuditEntries(testDb, game.PublicID, &AuditEntriesOptions{
+				Limit: 10, ClanPublicID: clan.PublicID,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].Action).To(Equal(ClanDeletedAction))
+			Expect(entries[0].ActorPublicID).To(Equal(AdminAuditActor))
+		})
+
+		It("Should record the request ID", func() {
+			db := WithRequestID(testDb.(interfaces.Database), "some-request-id")
+			game, _, clan := createClan(db)
+
+			entries, _, err := GetAuditEntries(testDb, game.PublicID, &AuditEntriesOptions{
+				Limit: 10, ClanPublicID: clan.PublicID,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].RequestID).To(Equal("some-request-id"))
+		})
+	})
+
+	Describe("Get Audit Entries", func() {
+		var gameID string
+
+		BeforeEach(func() {
+			game, _, clan := createClan(testDb)
+			gameID = game.PublicID
+			for i := 0; i < 4; i++ {
+				_, err := testDb.Exec(
+					"INSERT INTO audit_entries (game_id, action, clan_public_id, before, after, created_at) VALUES ($1, $2, $3, 'null', 'null', $4)",
+					gameID, ClanUpdatedAction, clan.PublicID, int64(1000*(i+1)),
+				)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("Should filter entries by time range", func() {
+			entries, _, err := GetAuditEntries(testDb, gameID, &AuditEntriesOptions{
+				Limit: 10, Since: 2000, Until: 4000,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].CreatedAt).To(BeEquivalentTo(3000))
+			Expect(entries[1].CreatedAt).To(BeEquivalentTo(2000))
+		})
+
+		It("Should paginate entries with a cursor", func() {
+			options := &AuditEntriesOptions{Limit: 3, Until: 5000}
+			entries, nextCursor, err := GetAuditEntries(testDb, gameID, options)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(3))
+			Expect(nextCursor).NotTo(BeEmpty())
+
+			options.Cursor = nextCursor
+			entries, nextCursor, err = GetAuditEntries(testDb, gameID, options)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].CreatedAt).To(BeEquivalentTo(1000))
+			Expect(nextCursor).To(BeEmpty())
+		})
+
+		It("Should fail with an invalid cursor", func() {
+			_, _, err := GetAuditEntries(testDb, gameID, &AuditEntriesOptions{Limit: 3, Cursor: "invalid"})
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&InvalidCursorError{}))
+		})
+
+		It("Should prune entries older than the retention", func() {
+			pruned, err := PruneAuditEntries(testDb, gameID, 3600)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pruned).To(Equal(4))
+
+			entries, _, err := GetAuditEntries(testDb, gameID, &AuditEntriesOptions{Limit: 10})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].Action).To(Equal(ClanCreatedAction))
+			Expect(entries[0].CreatedAt).To(BeNumerically("~", util.NowMilli(), 60000))
+		})
+	})
+})
@@ -14,6 +14,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -53,6 +54,7 @@ type Clan struct {
 	CreatedAt        int64                  `db:"created_at" json:"createdAt" bson:"createdAt"`
 	UpdatedAt        int64                  `db:"updated_at" json:"updatedAt" bson:"updatedAt"`
 	DeletedAt        int64                  `db:"deleted_at" json:"deletedAt" bson:"deletedAt"`
+	Version          int64                  `db:"version" json:"version" bson:"version"`
 }
 
 // ClanWithNamePrefixes extends Clan with a field to help name indexation in MongoDB
@@ -111,6 +113,59 @@ func NewDefaultGetClanDetailsOptions(config *viper.Viper) *GetClanDetailsOptions
 	}
 }
 
+// ListClansOptions holds the pagination, sorting and filtering options of ListClans()
+type ListClansOptions struct {
+	Limit              int
+	Cursor             string
+	OrderBy            string
+	Order              string
+	AllowApplication   *bool
+	AutoJoin           *bool
+	MinMembershipCount *int
+	MaxMembershipCount *int
+	Metadata           map[string]string
+}
+
+// Ascending is the constant "asc"
+const Ascending string = "asc"
+
+// Descending is the constant "desc"
+const Descending string = "desc"
+
+// ListClansDefaultLimitKey is string constant
+const ListClansDefaultLimitKey string = "listClans.defaultLimit"
+
+// ListClansMaxLimitKey is string constant
+const ListClansMaxLimitKey string = "listClans.maxLimit"
+
+// SearchClansPageSizeKey is string constant
+const SearchClansPageSizeKey string = "search.pageSize"
+
+// SearchClansMaxPageSizeKey is string constant
+const SearchClansMaxPageSizeKey string = "search.maxPageSize"
+
+var clanListOrderColumns = map[string]string{
+	"name":            "name",
+	"membershipCount": "membership_count",
+	"createdAt":       "created_at",
+}
+
+// IsValidClanListOrderBy returns whether clans can be listed sorted by the given field
+func IsValidClanListOrderBy(orderBy string) bool {
+	_, ok := clanListOrderColumns[orderBy]
+	return ok
+}
+
+// NewDefaultListClansOptions returns a new options structure with default values for ListClans()
+func NewDefaultListClansOptions(config *viper.Viper) *ListClansOptions {
+	return &ListClansOptions{
+		Limit:    config.GetInt(ListClansDefaultLimitKey),
+		OrderBy:  "name",
+		Order:    Ascending,
+		Metadata: map[string]string{},
+	}
+}
+
 //ToJSON returns the clan as JSON
 func (c *Clan) ToJSON() ([]byte, error) {
 	w := jwriter.Writer{}
@@ -130,6 +185,7 @@ func GetClanFromJSON(data []byte) (*Clan, error) {
 func (c *Clan) PreInsert(s gorp.SqlExecutor) error {
 	c.CreatedAt = util.NowMilli()
 	c.UpdatedAt = c.CreatedAt
+	c.Version = 1
 	return nil
 }
 
@@ -146,6 +202,7 @@ func (c *Clan) PostInsert(s gorp.SqlExecutor) error {
 //PreUpdate populates fields before updating a clan
 func (c *Clan) PreUpdate(s gorp.SqlExecutor) error {
 	c.UpdatedAt = util.NowMilli()
+	c.Version++
 	return nil
 }
 
@@ -159,14 +216,17 @@ func (c *Clan) PostUpdate(s gorp.SqlExecutor) error {
 	return err
 }
 
-//PostDelete deletes clan from elasticsearch after deleting from PG
+//PostDelete deletes clan from elasticsearch and rankings after deleting from PG
 func (c *Clan) PostDelete(s gorp.SqlExecutor) error {
 	err := c.DeleteClanFromElasticSearch()
 	if err != nil {
 		return err
 	}
 	err = c.DeleteClanFromMongoDB()
-	return err
+	if err != nil {
+		return err
+	}
+	return deleteClanScores(s, c.ID)
 }
 
 func getNewLogger() zap.Logger {
@@ -317,8 +377,8 @@ func (c *Clan) Serialize() map[string]interface{} {
 	}
 }
 
-// UpdateClanMembershipCount updates the clan membership count
-func UpdateClanMembershipCount(db DB, id int64) error {
+// UpdateClanMembershipCount updates the clan membership count and its scores in the rankings of the game
+func UpdateClanMembershipCount(db DB, game *Game, id int64) error {
 	query := `
 	UPDATE clans SET membership_count=membership.count+1
 	FROM (
@@ -355,7 +415,7 @@ func UpdateClanMembershipCount(db DB, id int64) error {
 		return err
 	}
 
-	return nil
+	return RefreshClanScores(db, game, id)
 }
 
 // GetClanByID returns a clan by id
@@ -373,7 +433,7 @@ func GetClanByID(db DB, id int64) (*Clan, error) {
 // GetClanByPublicID returns a clan by its public id
 func GetClanByPublicID(db DB, gameID, publicID string) (*Clan, error) {
 	var clans []*Clan
-	_, err := db.Select(&clans, "SELECT * FROM clans WHERE game_id=$1 AND public_id=$2", gameID, publicID)
+	_, err := db.Select(&clans, "SELECT * FROM clans WHERE game_id=$1 AND public_id=$2 AND deleted_at=0", gameID, publicID)
 	if err != nil {
 		return nil, err
 	}
@@ -388,7 +448,7 @@ func GetClanByShortPublicID(db DB, gameID, publicID string) (*Clan, error) {
 	var clans []*Clan
 	// String for between don't need to be be same length as UUID
 	startRange, endRange := publicID+"-0000-0000-0000-000000000000", publicID+"-ffff-ffff-ffff-ffffffffffff"
-	_, err := db.Select(&clans, "SELECT * FROM clans WHERE game_id=$1 AND public_id BETWEEN $2 AND $3", gameID, startRange, endRange)
+	_, err := db.Select(&clans, "SELECT * FROM clans WHERE game_id=$1 AND public_id BETWEEN $2 AND $3 AND deleted_at=0", gameID, startRange, endRange)
 	if err != nil {
 		return nil, err
 	}
@@ -430,7 +490,7 @@ func sliceDiff(slice1 []string, slice2 []string) []string {
 func GetClansByPublicIDs(db DB, gameID string, publicIDs []string) ([]Clan, error) {
 	var clans []Clan
 
-	queryPart := "SELECT * from clans WHERE game_id=$1 AND public_id=%s"
+	queryPart := "SELECT * from clans WHERE game_id=$1 AND public_id=%s AND deleted_at=0"
 	queryParts := []string{}
 	for i := 0; i < len(publicIDs); i++ {
 		paramIndex := fmt.Sprintf("$%d", (i + 2))
@@ -466,7 +526,7 @@ func GetClansByPublicIDs(db DB, gameID string, publicIDs []string) ([]Clan, erro
 func GetClanByPublicIDAndOwnerPublicID(db DB, gameID, publicID, ownerPublicID string) (*Clan, error) {
 	var clans []*Clan
 	var players []*Player
-	_, err := db.Select(&clans, "SELECT * FROM clans WHERE game_id=$1 AND public_id=$2", gameID, publicID)
+	_, err := db.Select(&clans, "SELECT * FROM clans WHERE game_id=$1 AND public_id=$2 AND deleted_at=0", gameID, publicID)
 	if err != nil {
 		return nil, err
 	}
@@ -487,7 +547,7 @@ func GetClanByPublicIDAndOwnerPublicID(db DB, gameID, publicID, ownerPublicID st
 }
 
 // CreateClan creates a new clan
-func CreateClan(db DB, gameID, publicID, name, ownerPublicID string, metadata map[string]interface{}, allowApplication, autoJoin bool, maxClansPerPlayer int) (*Clan, error) {
+func CreateClan(db DB, game *Game, gameID, publicID, name, ownerPublicID string, metadata map[string]interface{}, allowApplication, autoJoin bool, maxClansPerPlayer int) (*Clan, error) {
 	player, err := GetPlayerByPublicID(db, gameID, ownerPublicID)
 	if err != nil {
 		return nil, err
@@ -518,11 +578,21 @@ func CreateClan(db DB, gameID, publicID, name, ownerPublicID string, metadata ma
 		return nil, err
 	}
 
+	err = RefreshClanScores(db, game, clan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = createAuditEntry(db, gameID, ClanCreatedAction, ownerPublicID, publicID, ownerPublicID, nil, getClanAuditValues(clan))
+	if err != nil {
+		return nil, err
+	}
+
 	return clan, nil
 }
 
 // LeaveClan allows the clan owner to leave the clan and transfer the clan ownership to the next player in line
-func LeaveClan(db DB, gameID, publicID string) (*Clan, *Player, *Player, error) {
+func LeaveClan(db DB, game *Game, gameID, publicID string) (*Clan, *Player, *Player, error) {
 	clan, err := GetClanByPublicID(db, gameID, publicID)
 	if err != nil {
 		return nil, nil, nil, err
@@ -551,6 +621,13 @@ func LeaveClan(db DB, gameID, publicID string) (*Clan, *Player, *Player, error)
 			if err != nil {
 				return nil, nil, nil, err
 			}
+			err = createAuditEntry(
+				db, gameID, ClanLeftAction, oldOwner.PublicID, publicID, oldOwner.PublicID,
+				map[string]interface{}{"ownerPublicID": oldOwner.PublicID}, nil,
+			)
+			if err != nil {
+				return nil, nil, nil, err
+			}
 			return clan, oldOwner, nil, nil
 		}
 		return nil, nil, nil, err
@@ -568,7 +645,7 @@ func LeaveClan(db DB, gameID, publicID string) (*Clan, *Player, *Player, error)
 		return nil, nil, nil, err
 	}
 
-	_, err = deleteMembershipHelper(db, newOwnerMembership, newOwnerMembership.PlayerID)
+	_, err = deleteMembershipHelper(db, game, newOwnerMembership, newOwnerMembership.PlayerID)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -582,6 +659,15 @@ func LeaveClan(db DB, gameID, publicID string) (*Clan, *Player, *Player, error)
 		return nil, nil, nil, err
 	}
 
+	err = createAuditEntry(
+		db, gameID, ClanLeftAction, oldOwner.PublicID, publicID, oldOwner.PublicID,
+		map[string]interface{}{"ownerPublicID": oldOwner.PublicID},
+		map[string]interface{}{"ownerPublicID": newOwner.PublicID},
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	newOwner.MembershipCount--
 	newOwner.OwnershipCount++
 
@@ -592,8 +678,105 @@ func LeaveClan(db DB, gameID, publicID string) (*Clan, *Player, *Player, error)
 	return clan, oldOwner, newOwner, nil
 }
 
+// DeleteClan soft-deletes a clan and ends all of its memberships, invites and applications.
+// The requestor must own the clan
+func DeleteClan(db DB, gameID, publicID, requestorPublicID string) (*Clan, *Player, error) {
+	if requestorPublicID == "" {
+		return nil, nil, &ForbiddenError{gameID, requestorPublicID, publicID}
+	}
+	return deleteClan(db, gameID, publicID, requestorPublicID, requestorPublicID)
+}
+
+// DeleteClanAsAdmin soft-deletes any clan of the game, the same way DeleteClan does, on behalf of an admin.
+// The actor is recorded in the audit trail, e.g. AdminAuditActor
+func DeleteClanAsAdmin(db DB, gameID, publicID, actor string) (*Clan, *Player, error) {
+	return deleteClan(db, gameID, publicID, "", actor)
+}
+
+// deleteClan deletes a clan on behalf of its owner or, if requestorPublicID is empty, of an admin
+func deleteClan(db DB, gameID, publicID, requestorPublicID, actor string) (*Clan, *Player, error) {
+	clan, owner, err := GetClanAndOwnerByPublicID(db, gameID, publicID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// memberships ended by an admin are not attributed to any player
+	var deletedBy int64
+	if requestorPublicID != "" {
+		if requestorPublicID != owner.PublicID {
+			return nil, nil, &ForbiddenError{gameID, requestorPublicID, publicID}
+		}
+		deletedBy = owner.ID
+	}
+
+	before := getClanAuditValues(clan)
+	deletedAt := util.NowMilli()
+	query := `
+	UPDATE memberships
+	SET deleted_at=$1, deleted_by=$2, approved=false, denied=false, updated_at=$1
+	WHERE clan_id=$3 AND deleted_at=0
+	RETURNING player_id
+	`
+	var playerIDs []int64
+	_, err = db.Select(&playerIDs, query, deletedAt, deletedBy, clan.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = db.Exec(
+		"UPDATE clans SET deleted_at=$1, updated_at=$1, membership_count=0 WHERE id=$2",
+		deletedAt, clan.ID,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	clan.DeletedAt = deletedAt
+	clan.UpdatedAt = deletedAt
+	clan.MembershipCount = 0
+
+	updatedPlayers := map[int64]bool{}
+	for _, playerID := range playerIDs {
+		if updatedPlayers[playerID] {
+			continue
+		}
+		updatedPlayers[playerID] = true
+		err = UpdatePlayerMembershipCount(db, playerID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = UpdatePlayerOwnershipCount(db, owner.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the clan row is kept, so clan.PostDelete() is called explicitly
+	// to remove it from the search indexes
+	gorpSQLExecutor, err := getSQLExecutor(db)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = clan.PostDelete(gorpSQLExecutor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = createAuditEntry(db, gameID, ClanDeletedAction, actor, publicID, "", before, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	owner, err = GetPlayerByID(db, owner.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return clan, owner, nil
+}
+
 // TransferClanOwnership allows the clan owner to transfer the clan ownership to a clan member
-func TransferClanOwnership(db DB, gameID, clanPublicID, playerPublicID string, levels map[string]interface{}, maxLevel int) (*Clan, *Player, *Player, error) {
+func TransferClanOwnership(db DB, game *Game, gameID, clanPublicID, playerPublicID string, levels map[string]interface{}, maxLevel int) (*Clan, *Player, *Player, error) {
 	clan, err := GetClanByPublicID(db, gameID, clanPublicID)
 	if err != nil {
 		return nil, nil, nil, err
@@ -647,7 +830,7 @@ func TransferClanOwnership(db DB, gameID, clanPublicID, playerPublicID string, l
 		}
 	}
 
-	_, err = deleteMembershipHelper(db, newOwnerMembership, newOwnerMembership.PlayerID)
+	_, err = deleteMembershipHelper(db, game, newOwnerMembership, newOwnerMembership.PlayerID)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -677,15 +860,34 @@ func TransferClanOwnership(db DB, gameID, clanPublicID, playerPublicID string, l
 		return nil, nil, nil, err
 	}
 
+	err = createAuditEntry(
+		db, gameID, ClanOwnershipTransferredAction, oldOwner.PublicID, clanPublicID, playerPublicID,
+		map[string]interface{}{"ownerPublicID": oldOwner.PublicID},
+		map[string]interface{}{"ownerPublicID": newOwner.PublicID},
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	return clan, oldOwner, newOwner, nil
 }
 
 // UpdateClan updates an existing clan
-func UpdateClan(db DB, gameID, publicID, name, ownerPublicID string, metadata map[string]interface{}, allowApplication, autoJoin bool) (*Clan, error) {
+func UpdateClan(db DB, game *Game, gameID, publicID, name, ownerPublicID string, metadata map[string]interface{}, allowApplication, autoJoin bool) (*Clan, error) {
+	return UpdateClanWithVersion(db, game, gameID, publicID, name, ownerPublicID, metadata, allowApplication, autoJoin, 0)
+}
+
+// UpdateClanWithVersion updates an existing clan only if its current version is the expected one.
+// If version is 0 the clan is updated regardless of its version, as in UpdateClan
+func UpdateClanWithVersion(db DB, game *Game, gameID, publicID, name, ownerPublicID string, metadata map[string]interface{}, allowApplication, autoJoin bool, version int64) (*Clan, error) {
 	clan, err := GetClanByPublicIDAndOwnerPublicID(db, gameID, publicID, ownerPublicID)
 	if err != nil {
 		return nil, err
 	}
+	if version != 0 && clan.Version != version {
+		return nil, &VersionConflictError{"Clan", publicID, clan.Version}
+	}
+	before := getClanAuditValues(clan)
 
 	clan.Name = name
 	clan.Metadata = metadata
@@ -700,26 +902,46 @@ func UpdateClan(db DB, gameID, publicID, name, ownerPublicID string, metadata ma
 	}
 
 	query := `
-		UPDATE clans SET name=$1, metadata=$2, allow_application=$3, auto_join=$4
-		WHERE clans.id=$5
+		UPDATE clans SET name=$1, metadata=$2, allow_application=$3, auto_join=$4, version=version+1
+		WHERE clans.id=$5 AND ($6=0 OR clans.version=$6)
+		RETURNING version
 	`
-	_, err = db.Exec(query, name, metadataBuffer.String(), allowApplication, autoJoin, clan.ID)
+	var versions []int64
+	_, err = db.Select(&versions, query, name, metadataBuffer.String(), allowApplication, autoJoin, clan.ID, version)
 	if err != nil {
 		return nil, err
 	}
+	if len(versions) == 0 {
+		current, err := GetClanByID(db, clan.ID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &VersionConflictError{"Clan", publicID, current.Version}
+	}
+	clan.Version = versions[0]
 
-	// since this function should update only the 4 fields above,
+	// since this function should update only the 5 fields above,
 	// we cannot use db.Update(clan), so clan.PostUpdate() should
 	// be called explicitly
-	gorpSQLExecutor, ok := db.(gorp.SqlExecutor)
-	if !ok {
-		return nil, &InvalidCastToGorpSQLExecutorError{}
+	gorpSQLExecutor, err := getSQLExecutor(db)
+	if err != nil {
+		return nil, err
 	}
 	err = clan.PostUpdate(gorpSQLExecutor)
 	if err != nil {
 		return nil, err
 	}
 
+	err = RefreshClanScores(db, game, clan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = createAuditEntry(db, gameID, ClanUpdatedAction, ownerPublicID, publicID, ownerPublicID, before, getClanAuditValues(clan))
+	if err != nil {
+		return nil, err
+	}
+
 	return clan, nil
 }
 
@@ -730,7 +952,7 @@ func GetAllClans(db DB, gameID string) ([]Clan, error) {
 	}
 
 	var clans []Clan
-	_, err := db.Select(&clans, "select * from clans where game_id=$1 order by name", gameID)
+	_, err := db.Select(&clans, "select * from clans where game_id=$1 and deleted_at=0 order by name", gameID)
 	if err != nil {
 		return nil, err
 	}
@@ -738,6 +960,93 @@ func GetAllClans(db DB, gameID string) ([]Clan, error) {
 	return clans, nil
 }
 
+// ListClans returns a page of the clans in a given game, filtered and sorted according to options,
+// and the cursor of the next page ("" if there are no more clans)
+func ListClans(db DB, gameID string, options *ListClansOptions) ([]Clan, string, error) {
+	if gameID == "" {
+		return nil, "", &EmptyGameIDError{"Clan"}
+	}
+
+	column := clanListOrderColumns[options.OrderBy]
+	if column == "" {
+		column = "name"
+	}
+	direction, comparison := "ASC", ">"
+	if options.Order == Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	conditions := []string{"game_id=$1", "deleted_at=0"}
+	args := []interface{}{gameID}
+	addCondition := func(condition string, values ...interface{}) {
+		placeholders := make([]interface{}, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if options.AllowApplication != nil {
+		addCondition("allow_application=%s", *options.AllowApplication)
+	}
+	if options.AutoJoin != nil {
+		addCondition("auto_join=%s", *options.AutoJoin)
+	}
+	if options.MinMembershipCount != nil {
+		addCondition("membership_count>=%s", *options.MinMembershipCount)
+	}
+	if options.MaxMembershipCount != nil {
+		addCondition("membership_count<=%s", *options.MaxMembershipCount)
+	}
+	for key, value := range options.Metadata {
+		addCondition("metadata->>%s=%s", key, value)
+	}
+
+	if options.Cursor != "" {
+		value, id, err := decodeCursor(options.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		var cursorValue interface{} = value
+		if column != "name" {
+			cursorValue, err = strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, "", &InvalidCursorError{options.Cursor}
+			}
+		}
+		addCondition(fmt.Sprintf("(%s, id)%s(%%s, %%s)", column, comparison), cursorValue, id)
+	}
+
+	// fetch one extra clan to find out whether there is a next page
+	args = append(args, options.Limit+1)
+	query := fmt.Sprintf(
+		"SELECT * FROM clans WHERE %s ORDER BY %s %s, id %s LIMIT $%d",
+		strings.Join(conditions, " AND "), column, direction, direction, len(args),
+	)
+
+	var clans []Clan
+	_, err := db.Select(&clans, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(clans) > options.Limit {
+		clans = clans[:options.Limit]
+		last := clans[len(clans)-1]
+		switch column {
+		case "membership_count":
+			nextCursor = encodeCursor(strconv.Itoa(last.MembershipCount), last.ID)
+		case "created_at":
+			nextCursor = encodeCursor(strconv.FormatInt(last.CreatedAt, 10), last.ID)
+		default:
+			nextCursor = encodeCursor(last.Name, last.ID)
+		}
+	}
+	return clans, nextCursor, nil
+}
+
 // GetClanMembers gets only the ids of then clan members
 func GetClanMembers(db DB, gameID, publicID string) (map[string]interface{}, error) {
 	clan, err := GetClanByPublicID(db, gameID, publicID)
@@ -773,28 +1082,167 @@ func GetClanMembers(db DB, gameID, publicID string) (map[string]interface{}, err
 
 }
 
+// GetClanMembersOptions holds the pagination, sorting and filtering options of GetClanMembersPage()
+type GetClanMembersOptions struct {
+	Limit   int
+	Cursor  string
+	Level   string
+	OrderBy string
+	Order   string
+}
+
+// ClanMembersDefaultLimitKey is string constant
+const ClanMembersDefaultLimitKey string = "clanMembers.defaultLimit"
+
+// ClanMembersMaxLimitKey is string constant
+const ClanMembersMaxLimitKey string = "clanMembers.maxLimit"
+
+// IsValidClanMembersOrderBy returns whether clan members can be listed sorted by the given field
+func IsValidClanMembersOrderBy(orderBy string) bool {
+	return orderBy == "approvedAt" || orderBy == "level"
+}
+
+// NewDefaultGetClanMembersOptions returns a new options structure with default values for GetClanMembersPage()
+func NewDefaultGetClanMembersOptions(config *viper.Viper) *GetClanMembersOptions {
+	return &GetClanMembersOptions{
+		Limit:   config.GetInt(ClanMembersDefaultLimitKey),
+		OrderBy: "approvedAt",
+		Order:   Ascending,
+	}
+}
+
+// GetClanMembersPage returns a page of the approved members of a clan, filtered and sorted according to options,
+// along with the clan owner and the cursor of the next page ("" if there are no more members)
+func GetClanMembersPage(db DB, game *Game, publicID string, options *GetClanMembersOptions) (map[string]interface{}, error) {
+	clan, err := GetClanByPublicID(db, game.PublicID, publicID)
+	if err != nil {
+		return nil, err
+	}
+	if options.Level != "" {
+		if _, ok := game.MembershipLevels[options.Level]; !ok {
+			return nil, &InvalidLevelForGameError{game.PublicID, options.Level}
+		}
+	}
+
+	args := []interface{}{clan.ID}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	levelCases := []string{}
+	for level := range game.MembershipLevels {
+		levelInt := GetLevelIntByLevel(level, game.MembershipLevels)
+		levelCases = append(levelCases, fmt.Sprintf("WHEN %s THEN %s::bigint", arg(level), arg(levelInt)))
+	}
+	levelExpr := "0"
+	if len(levelCases) > 0 {
+		levelExpr = fmt.Sprintf("CASE m.membership_level %s ELSE 0 END", strings.Join(levelCases, " "))
+	}
+	joinedAtExpr := "COALESCE(m.approved_at, m.created_at)"
+
+	sortExpr := joinedAtExpr
+	if options.OrderBy == "level" {
+		sortExpr = levelExpr
+	}
+	direction, comparison := "ASC", ">"
+	if options.Order == Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	conditions := []string{"m.clan_id=$1", "m.deleted_at=0", "m.approved=true"}
+	if options.Level != "" {
+		conditions = append(conditions, fmt.Sprintf("m.membership_level=%s", arg(options.Level)))
+	}
+	if options.Cursor != "" {
+		value, id, err := decodeCursor(options.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursorValue, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, &InvalidCursorError{options.Cursor}
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s, m.id)%s(%s, %s)", sortExpr, comparison, arg(cursorValue), arg(id)))
+	}
+
+	// fetch one extra member to find out whether there is a next page
+	query := fmt.Sprintf(`
+	SELECT
+		m.id MembershipID, m.membership_level MembershipLevel,
+		%s MembershipLevelInt, %s MembershipApprovedAt,
+		p.public_id PlayerPublicID, p.name PlayerName, p.metadata DBPlayerMetadata
+	FROM memberships m
+		INNER JOIN players p ON p.id=m.player_id
+	WHERE %s
+	ORDER BY %s %s, m.id %s
+	LIMIT %s`,
+		levelExpr, joinedAtExpr, strings.Join(conditions, " AND "), sortExpr, direction, direction, arg(options.Limit+1),
+	)
+
+	var members []clanMemberDAO
+	_, err = db.Select(&members, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	nextCursor := ""
+	if len(members) > options.Limit {
+		members = members[:options.Limit]
+		last := members[len(members)-1]
+		if options.OrderBy == "level" {
+			nextCursor = encodeCursor(strconv.FormatInt(last.MembershipLevelInt, 10), last.MembershipID)
+		} else {
+			nextCursor = encodeCursor(strconv.FormatInt(last.MembershipApprovedAt, 10), last.MembershipID)
+		}
+	}
+
+	owner, err := GetPlayerByID(db, clan.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	serializedMembers := make([]map[string]interface{}, len(members))
+	for i := range members {
+		serializedMembers[i] = members[i].Serialize()
+	}
+
+	return map[string]interface{}{
+		"owner": map[string]interface{}{
+			"publicID": owner.PublicID,
+			"name":     owner.Name,
+			"metadata": owner.Metadata,
+		},
+		"members":    serializedMembers,
+		"nextCursor": nextCursor,
+	}, nil
+}
+
 // GetClanDetails returns all details for a given clan by its game id and public id
 func GetClanDetails(db DB, gameID string, clan *Clan, maxClansPerPlayer int, options *GetClanDetailsOptions) (map[string]interface{}, error) {
 	query := fmt.Sprintf(`
 	WITH memberships_pending AS (
 		SELECT *
 		FROM memberships im
-		WHERE im.clan_id=$2 AND im.deleted_at=0 AND im.approved=false AND im.denied=false AND im.banned=false
+		WHERE im.clan_id=$2 AND im.deleted_at=0 AND im.approved=false AND im.denied=false AND im.banned=false AND
+			(im.expires_at=0 OR im.expires_at>$5)
 	)
 	SELECT
 		c.game_id GameID,
 		c.public_id ClanPublicID, c.name ClanName, c.metadata ClanMetadata,
 		c.allow_application ClanAllowApplication, c.auto_join ClanAutoJoin,
-		c.membership_count ClanMembershipCount,
+		c.membership_count ClanMembershipCount, c.version ClanVersion,
 		m.membership_level MembershipLevel, m.approved MembershipApproved, m.denied MembershipDenied,
 		m.banned MembershipBanned, m.message MembershipMessage,
 		m.created_at MembershipCreatedAt, m.updated_at MembershipUpdatedAt,
 		m.approved_at MembershipApprovedAt, m.denied_at MembershipDeniedAt,
+		m.banned_at MembershipBannedAt, m.ban_expires_at MembershipBanExpiresAt,
 		o.public_id OwnerPublicID, o.name OwnerName, o.metadata OwnerMetadata,
 		p.public_id PlayerPublicID, p.name PlayerName, p.metadata DBPlayerMetadata,
 		r.public_id RequestorPublicID, r.name RequestorName,
 		a.public_id ApproverPublicID, a.name ApproverName,
 		y.name DenierName, y.public_id DenierPublicID,
+		b.name BannerName, b.public_id BannerPublicID,
 		Coalesce(p.membership_count, 0) MembershipCount,
 		Coalesce(p.ownership_count, 0) OwnershipCount
 	FROM clans c
@@ -817,19 +1265,23 @@ func GetClanDetails(db DB, gameID string, clan *Clan, maxClansPerPlayer int, opt
 			UNION ALL (
 				SELECT *
 				FROM memberships im
-				WHERE im.clan_id=$2 AND im.deleted_at=0 AND (im.approved=true OR im.denied=true OR im.banned=true)
+				WHERE im.clan_id=$2 AND im.deleted_at=0 AND (
+					im.approved=true OR im.denied=true OR
+					(im.banned=true AND (im.ban_expires_at=0 OR im.ban_expires_at>$5))
+				)
 			)
 		) m ON m.clan_id=c.id
 		LEFT OUTER JOIN players r ON m.requestor_id=r.id
 		LEFT OUTER JOIN players a ON m.approver_id=a.id
 		LEFT OUTER JOIN players p ON m.player_id=p.id
 		LEFT OUTER JOIN players y ON m.denier_id=y.id
+		LEFT OUTER JOIN players b ON m.banner_id=b.id
 	WHERE
 		c.game_id=$1 AND c.id=$2
 	`, getSQLOrderFromSemanticOrder(options.PendingApplicationsOrder), getSQLOrderFromSemanticOrder(options.PendingInvitesOrder))
 
 	var details []clanDetailsDAO
-	_, err := db.Select(&details, query, gameID, clan.ID, options.MaxPendingApplications, options.MaxPendingInvites)
+	_, err := db.Select(&details, query, gameID, clan.ID, options.MaxPendingApplications, options.MaxPendingInvites, util.NowMilli())
 	if err != nil {
 		return nil, err
 	}
@@ -844,6 +1296,7 @@ func GetClanDetails(db DB, gameID string, clan *Clan, maxClansPerPlayer int, opt
 	result["metadata"] = details[0].ClanMetadata
 	result["allowApplication"] = details[0].ClanAllowApplication
 	result["autoJoin"] = details[0].ClanAutoJoin
+	result["version"] = details[0].ClanVersion
 	result["membershipCount"] = details[0].ClanMembershipCount
 
 	result["owner"] = map[string]interface{}{
@@ -883,6 +1336,8 @@ func GetClanDetails(db DB, gameID string, clan *Clan, maxClansPerPlayer int, opt
 				}
 			case banned:
 				memberData := member.Serialize(false)
+				memberData["bannedAt"] = nullOrInt(member.MembershipBannedAt)
+				memberData["banExpiresAt"] = nullOrInt(member.MembershipBanExpiresAt)
 				memberships["banned"] = append(memberships["banned"].([]map[string]interface{}), memberData)
 			case denied:
 				memberData := member.Serialize(false)
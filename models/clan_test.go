@@ -244,7 +244,7 @@ var _ = Describe("Clan Model", func() {
 			Describe("Update Clan Membership Count", func() {
 				It("Should work if membership is created", func() {
 					previousAmount := 5
-					_, clan, _, _, _, err := GetClanWithMemberships(testDb, previousAmount-1, 2, 3, 4, "", "")
+					game, clan, _, _, _, err := GetClanWithMemberships(testDb, previousAmount-1, 2, 3, 4, "", "")
 					Expect(err).NotTo(HaveOccurred())
 
 					_, player, err := CreatePlayerFactory(testDb, clan.GameID, true)
@@ -261,7 +261,7 @@ var _ = Describe("Clan Model", func() {
 					err = testDb.Insert(membership)
 					Expect(err).NotTo(HaveOccurred())
 
-					err = UpdateClanMembershipCount(testDb, clan.ID)
+					err = UpdateClanMembershipCount(testDb, game, clan.ID)
 					Expect(err).NotTo(HaveOccurred())
 					dbClan, err := GetClanByID(testDb, clan.ID)
 					Expect(err).NotTo(HaveOccurred())
@@ -270,13 +270,13 @@ var _ = Describe("Clan Model", func() {
 
 				It("Should work if membership is deleted", func() {
 					previousAmount := 5
-					_, clan, _, _, memberships, err := GetClanWithMemberships(testDb, previousAmount-1, 2, 3, 4, "", "")
+					game, clan, _, _, memberships, err := GetClanWithMemberships(testDb, previousAmount-1, 2, 3, 4, "", "")
 					Expect(err).NotTo(HaveOccurred())
 
 					_, err = testDb.Delete(memberships[0])
 					Expect(err).NotTo(HaveOccurred())
 
-					err = UpdateClanMembershipCount(testDb, clan.ID)
+					err = UpdateClanMembershipCount(testDb, game, clan.ID)
 					Expect(err).NotTo(HaveOccurred())
 					dbClan, err := GetClanByID(testDb, clan.ID)
 					Expect(err).NotTo(HaveOccurred())
@@ -284,7 +284,8 @@ var _ = Describe("Clan Model", func() {
 				})
 
 				It("Should not work if non-existing Player", func() {
-					err := UpdateClanMembershipCount(testDb, -1)
+					game := GameFactory.MustCreate().(*Game)
+					err := UpdateClanMembershipCount(testDb, game, -1)
 					Expect(err).To(HaveOccurred())
 					Expect(err.Error()).To(Equal("Clan was not found with id: -1"))
 				})
@@ -298,6 +299,7 @@ var _ = Describe("Clan Model", func() {
 
 				clan, err := CreateClan(
 					testDb,
+					game,
 					player.GameID,
 					"create-1",
 					randomdata.FullName(randomdata.RandomGender),
@@ -329,6 +331,7 @@ var _ = Describe("Clan Model", func() {
 
 				_, err = CreateClan(
 					testDb,
+					game,
 					player.GameID,
 					strings.Repeat("a", 256),
 					"clan-name",
@@ -349,6 +352,7 @@ var _ = Describe("Clan Model", func() {
 
 				_, err = CreateClan(
 					testDb,
+					game,
 					owner.GameID,
 					"create-1",
 					randomdata.FullName(randomdata.RandomGender),
@@ -369,6 +373,7 @@ var _ = Describe("Clan Model", func() {
 
 				_, err = CreateClan(
 					testDb,
+					game,
 					game.PublicID,
 					"create-1",
 					randomdata.FullName(randomdata.RandomGender),
@@ -388,6 +393,7 @@ var _ = Describe("Clan Model", func() {
 				playerPublicID := randomdata.FullName(randomdata.RandomGender)
 				_, err = CreateClan(
 					testDb,
+					game,
 					"create-1",
 					randomdata.FullName(randomdata.RandomGender),
 					"clan-name",
@@ -408,12 +414,15 @@ var _ = Describe("Clan Model", func() {
 				player, clans, err := GetTestClans(testDb, "", "", 1)
 				Expect(err).NotTo(HaveOccurred())
 				clan := clans[0]
+				game, err := GetGameByPublicID(testDb, clan.GameID)
+				Expect(err).NotTo(HaveOccurred())
 
 				metadata := map[string]interface{}{"x": "1"}
 				allowApplication := !clan.AllowApplication
 				autoJoin := !clan.AutoJoin
 				updClan, err := UpdateClan(
 					testDb,
+					game,
 					clan.GameID,
 					clan.PublicID,
 					clan.Name,
@@ -435,10 +444,70 @@ var _ = Describe("Clan Model", func() {
 				Expect(dbClan.OwnerID).To(Equal(clan.OwnerID))
 			})
 
+			It("Should increment the Clan version with UpdateClanWithVersion", func() {
+				player, clans, err := GetTestClans(testDb, "", "", 1)
+				Expect(err).NotTo(HaveOccurred())
+				clan, err := GetClanByPublicID(testDb, clans[0].GameID, clans[0].PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				game, err := GetGameByPublicID(testDb, clan.GameID)
+				Expect(err).NotTo(HaveOccurred())
+
+				updClan, err := UpdateClanWithVersion(
+					testDb,
+					game,
+					clan.GameID,
+					clan.PublicID,
+					clan.Name,
+					player.PublicID,
+					map[string]interface{}{"x": "1"},
+					clan.AllowApplication,
+					clan.AutoJoin,
+					clan.Version,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(updClan.Version).To(Equal(clan.Version + 1))
+
+				dbClan, err := GetClanByPublicID(testDb, clan.GameID, clan.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbClan.Version).To(Equal(clan.Version + 1))
+			})
+
+			It("Should not update a Clan if version does not match with UpdateClanWithVersion", func() {
+				player, clans, err := GetTestClans(testDb, "", "", 1)
+				Expect(err).NotTo(HaveOccurred())
+				clan, err := GetClanByPublicID(testDb, clans[0].GameID, clans[0].PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				game, err := GetGameByPublicID(testDb, clan.GameID)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = UpdateClanWithVersion(
+					testDb,
+					game,
+					clan.GameID,
+					clan.PublicID,
+					clan.Name,
+					player.PublicID,
+					map[string]interface{}{"x": "1"},
+					clan.AllowApplication,
+					clan.AutoJoin,
+					clan.Version+1,
+				)
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(&VersionConflictError{}))
+				Expect(err.(*VersionConflictError).Version).To(Equal(clan.Version))
+
+				dbClan, err := GetClanByPublicID(testDb, clan.GameID, clan.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbClan.Metadata).To(Equal(clan.Metadata))
+				Expect(dbClan.Version).To(Equal(clan.Version))
+			})
+
 			It("Should not update a Clan if player is not the clan owner with UpdateClan", func() {
 				_, clans, err := GetTestClans(testDb, "", "", 1)
 				Expect(err).NotTo(HaveOccurred())
 				clan := clans[0]
+				game, err := GetGameByPublicID(testDb, clan.GameID)
+				Expect(err).NotTo(HaveOccurred())
 
 				_, player, err := CreatePlayerFactory(testDb, clan.GameID, true)
 				Expect(err).NotTo(HaveOccurred())
@@ -446,6 +515,7 @@ var _ = Describe("Clan Model", func() {
 				metadata := map[string]interface{}{"x": "1"}
 				_, err = UpdateClan(
 					testDb,
+					game,
 					clan.GameID,
 					clan.PublicID,
 					clan.Name,
@@ -468,10 +538,13 @@ var _ = Describe("Clan Model", func() {
 				player, clans, err := GetTestClans(testDb, "", "", 1)
 				Expect(err).NotTo(HaveOccurred())
 				clan := clans[0]
+				game, err := GetGameByPublicID(testDb, clan.GameID)
+				Expect(err).NotTo(HaveOccurred())
 
 				metadata := map[string]interface{}{}
 				_, err = UpdateClan(
 					testDb,
+					game,
 					clan.GameID,
 					clan.PublicID,
 					strings.Repeat("a", 256),
@@ -489,6 +562,8 @@ var _ = Describe("Clan Model", func() {
 				player, clans, err := GetTestClans(testDb, "", "", 1)
 				Expect(err).NotTo(HaveOccurred())
 				clan := clans[0]
+				game, err := GetGameByPublicID(testDb, clan.GameID)
+				Expect(err).NotTo(HaveOccurred())
 
 				metadata := map[string]interface{}{"x": "1"}
 				allowApplication := !clan.AllowApplication
@@ -497,6 +572,7 @@ var _ = Describe("Clan Model", func() {
 				runtime := b.Time("runtime", func() {
 					UpdateClan(
 						testDb,
+						game,
 						clan.GameID,
 						clan.PublicID,
 						clan.Name,
@@ -514,10 +590,10 @@ var _ = Describe("Clan Model", func() {
 		Describe("Leave Clan", func() {
 			Describe("Should leave a Clan with LeaveClan if clan owner", func() {
 				It("And clan has memberships", func() {
-					_, clan, owner, players, memberships, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+					game, clan, owner, players, memberships, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
 					Expect(err).NotTo(HaveOccurred())
 
-					clan, previousOwner, newOwner, err := LeaveClan(testDb, clan.GameID, clan.PublicID)
+					clan, previousOwner, newOwner, err := LeaveClan(testDb, game, clan.GameID, clan.PublicID)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(previousOwner.ID).To(Equal(owner.ID))
@@ -546,10 +622,10 @@ var _ = Describe("Clan Model", func() {
 				})
 
 				It("And clan has no memberships", func() {
-					_, clan, owner, _, _, err := GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+					game, clan, owner, _, _, err := GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
 					Expect(err).NotTo(HaveOccurred())
 
-					clan, previousOwner, newOwner, err := LeaveClan(testDb, clan.GameID, clan.PublicID)
+					clan, previousOwner, newOwner, err := LeaveClan(testDb, game, clan.GameID, clan.PublicID)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(previousOwner.ID).To(Equal(owner.ID))
 					Expect(newOwner).To(BeNil())
@@ -567,16 +643,107 @@ var _ = Describe("Clan Model", func() {
 
 			Describe("Should not leave a Clan with LeaveClan if", func() {
 				It("Clan does not exist", func() {
-					_, clan, _, _, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+					game, clan, _, _, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
 					Expect(err).NotTo(HaveOccurred())
 
-					_, _, _, err = LeaveClan(testDb, clan.GameID, "-1")
+					_, _, _, err = LeaveClan(testDb, game, clan.GameID, "-1")
 					Expect(err).To(HaveOccurred())
 					Expect(err.Error()).To(Equal("Clan was not found with id: -1"))
 				})
 			})
 		})
 
+		Describe("Delete Clan", func() {
+			It("Should delete a Clan with DeleteClan if clan owner", func() {
+				_, clan, owner, players, memberships, err := GetClanWithMemberships(testDb, 2, 0, 0, 1, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				deletedClan, dbOwner, err := DeleteClan(testDb, clan.GameID, clan.PublicID, owner.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deletedClan.DeletedAt).To(BeNumerically(">", util.NowMilli()-1000))
+				Expect(dbOwner.ID).To(Equal(owner.ID))
+				Expect(dbOwner.OwnershipCount).To(Equal(0))
+
+				_, err = GetClanByPublicID(testDb, clan.GameID, clan.PublicID)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Clan was not found with id: %s", clan.PublicID)))
+
+				dbClan, err := GetClanByID(testDb, clan.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbClan.DeletedAt).To(Equal(deletedClan.DeletedAt))
+				Expect(dbClan.MembershipCount).To(Equal(0))
+
+				for _, membership := range memberships {
+					dbMembership, err := GetMembershipByID(testDb, membership.ID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(dbMembership.DeletedAt).To(Equal(deletedClan.DeletedAt))
+					Expect(dbMembership.DeletedBy).To(Equal(owner.ID))
+					Expect(dbMembership.Approved).To(BeFalse())
+				}
+
+				for _, player := range players {
+					dbPlayer, err := GetPlayerByID(testDb, player.ID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(dbPlayer.MembershipCount).To(Equal(0))
+				}
+			})
+
+			It("Should delete a Clan with DeleteClanAsAdmin", func() {
+				_, clan, owner, _, memberships, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, err = DeleteClanAsAdmin(testDb, clan.GameID, clan.PublicID, AdminAuditActor)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbMembership, err := GetMembershipByID(testDb, memberships[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbMembership.DeletedAt).To(BeNumerically(">", 0))
+				Expect(dbMembership.DeletedBy).To(Equal(int64(0)))
+
+				dbPlayer, err := GetPlayerByID(testDb, owner.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbPlayer.OwnershipCount).To(Equal(0))
+
+				clans, err := GetAllClans(testDb, clan.GameID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(clans).To(BeEmpty())
+			})
+
+			It("Should not delete a Clan with DeleteClan without requestor", func() {
+				_, clan, _, _, _, err := GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, err = DeleteClan(testDb, clan.GameID, clan.PublicID, "")
+				Expect(err).To(BeAssignableToTypeOf(&ForbiddenError{}))
+
+				dbClan, err := GetClanByPublicID(testDb, clan.GameID, clan.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbClan.DeletedAt).To(Equal(int64(0)))
+			})
+
+			It("Should not delete a Clan with DeleteClan if requestor is not the owner", func() {
+				_, clan, _, players, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, err = DeleteClan(testDb, clan.GameID, clan.PublicID, players[0].PublicID)
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(&ForbiddenError{}))
+
+				dbClan, err := GetClanByPublicID(testDb, clan.GameID, clan.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbClan.DeletedAt).To(Equal(int64(0)))
+			})
+
+			It("Should not delete a Clan with DeleteClan if clan does not exist", func() {
+				game, clan, owner, _, _, err := GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, err = DeleteClan(testDb, clan.GameID, "-1", owner.PublicID)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Clan was not found with id: -1"))
+			})
+		})
+
 		Describe("Transfer Clan Ownership", func() {
 			Describe("Should transfer the Clan ownership with TransferClanOwnership if clan owner", func() {
 				It("And first clan owner and next owner memberhip exists", func() {
@@ -584,6 +751,7 @@ var _ = Describe("Clan Model", func() {
 					Expect(err).NotTo(HaveOccurred())
 					clan, previousOwner, newOwner, err := TransferClanOwnership(
 						testDb,
+						game,
 						clan.GameID,
 						clan.PublicID,
 						players[0].PublicID,
@@ -627,6 +795,7 @@ var _ = Describe("Clan Model", func() {
 
 					clan, previousOwner, newOwner, err := TransferClanOwnership(
 						testDb,
+						game,
 						clan.GameID,
 						clan.PublicID,
 						players[0].PublicID,
@@ -639,6 +808,7 @@ var _ = Describe("Clan Model", func() {
 
 					clan, previousOwner, newOwner, err = TransferClanOwnership(
 						testDb,
+						game,
 						clan.GameID,
 						clan.PublicID,
 						players[1].PublicID,
@@ -693,6 +863,7 @@ var _ = Describe("Clan Model", func() {
 
 					_, _, _, err = TransferClanOwnership(
 						testDb,
+						game,
 						clan.GameID,
 						"-1",
 						players[0].PublicID,
@@ -709,6 +880,7 @@ var _ = Describe("Clan Model", func() {
 
 					_, _, _, err = TransferClanOwnership(
 						testDb,
+						game,
 						clan.GameID,
 						clan.PublicID,
 						"some-random-player",
@@ -746,6 +918,123 @@ var _ = Describe("Clan Model", func() {
 			})
 		})
 
+		Describe("List Clans", func() {
+			var options *ListClansOptions
+
+			BeforeEach(func() {
+				options = &ListClansOptions{
+					Limit:    100,
+					OrderBy:  "name",
+					Order:    Ascending,
+					Metadata: map[string]string{},
+				}
+			})
+
+			It("Should page through all clans sorted by name", func() {
+				player, expectedClans, err := GetTestClans(testDb, "", "", 5)
+				Expect(err).NotTo(HaveOccurred())
+				sort.Sort(ClanByName(expectedClans))
+
+				options.Limit = 2
+				var publicIDs []string
+				for page := 0; page < 3; page++ {
+					clans, nextCursor, err := ListClans(testDb, player.GameID, options)
+					Expect(err).NotTo(HaveOccurred())
+					for _, clan := range clans {
+						publicIDs = append(publicIDs, clan.PublicID)
+					}
+					if page < 2 {
+						Expect(nextCursor).NotTo(BeEmpty())
+					} else {
+						Expect(nextCursor).To(BeEmpty())
+					}
+					options.Cursor = nextCursor
+				}
+
+				Expect(publicIDs).To(HaveLen(5))
+				for i, clan := range expectedClans {
+					Expect(publicIDs[i]).To(Equal(clan.PublicID))
+				}
+			})
+
+			It("Should sort clans by membership count descending", func() {
+				player, clans, err := GetTestClans(testDb, "", "", 3)
+				Expect(err).NotTo(HaveOccurred())
+				for i, clan := range clans {
+					clan.MembershipCount = i + 1
+					_, err = testDb.Update(clan)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				options.OrderBy = "membershipCount"
+				options.Order = Descending
+				options.Limit = 2
+				page, nextCursor, err := ListClans(testDb, player.GameID, options)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(page).To(HaveLen(2))
+				Expect(page[0].PublicID).To(Equal(clans[2].PublicID))
+				Expect(page[1].PublicID).To(Equal(clans[1].PublicID))
+
+				options.Cursor = nextCursor
+				page, nextCursor, err = ListClans(testDb, player.GameID, options)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(page).To(HaveLen(1))
+				Expect(page[0].PublicID).To(Equal(clans[0].PublicID))
+				Expect(nextCursor).To(BeEmpty())
+			})
+
+			It("Should filter clans", func() {
+				player, clans, err := GetTestClans(testDb, "", "", 4)
+				Expect(err).NotTo(HaveOccurred())
+				for i, clan := range clans {
+					clan.AllowApplication = i%2 == 0
+					clan.AutoJoin = false
+					clan.MembershipCount = i * 10
+					clan.Metadata = map[string]interface{}{"region": "us"}
+					if i == 2 {
+						clan.Metadata = map[string]interface{}{"region": "eu"}
+					}
+					_, err = testDb.Update(clan)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				allowApplication := true
+				minMembershipCount := 0
+				maxMembershipCount := 20
+				options.AllowApplication = &allowApplication
+				options.MinMembershipCount = &minMembershipCount
+				options.MaxMembershipCount = &maxMembershipCount
+				options.Metadata["region"] = "us"
+
+				page, nextCursor, err := ListClans(testDb, player.GameID, options)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nextCursor).To(BeEmpty())
+				Expect(page).To(HaveLen(1))
+				Expect(page[0].PublicID).To(Equal(clans[0].PublicID))
+
+				autoJoin := true
+				options.AutoJoin = &autoJoin
+				page, _, err = ListClans(testDb, player.GameID, options)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(page).To(BeEmpty())
+			})
+
+			It("Should fail when cursor is invalid", func() {
+				options.Cursor = "invalid cursor"
+				clans, _, err := ListClans(testDb, "game-id", options)
+				Expect(clans).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Cursor invalid cursor is invalid."))
+			})
+
+			It("Should fail when game id is empty", func() {
+				clans, _, err := ListClans(testDb, "", options)
+				Expect(clans).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Game ID is required to retrieve Clan!"))
+			})
+		})
+
 		Describe("Get Clan Members", func() {
 			It("Should get clan player ids", func() {
 				gameID := uuid.NewV4().String()
@@ -786,6 +1075,84 @@ var _ = Describe("Clan Model", func() {
 			})
 		})
 
+		Describe("Get Clan Members Page", func() {
+			var options *GetClanMembersOptions
+
+			BeforeEach(func() {
+				options = &GetClanMembersOptions{
+					Limit:   100,
+					OrderBy: "approvedAt",
+					Order:   Ascending,
+				}
+			})
+
+			It("Should page through approved members sorted by join date", func() {
+				game, clan, owner, _, memberships, err := GetClanWithMemberships(testDb, 3, 1, 1, 1, "", "")
+				Expect(err).NotTo(HaveOccurred())
+				for i, membership := range memberships[:3] {
+					membership.ApprovedAt = int64(1000 * (3 - i))
+					_, err = testDb.Update(membership)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				options.Limit = 2
+				page, err := GetClanMembersPage(testDb, game, clan.PublicID, options)
+				Expect(err).NotTo(HaveOccurred())
+				members := page["members"].([]map[string]interface{})
+				Expect(members).To(HaveLen(2))
+				Expect(members[0]["approvedAt"]).To(BeEquivalentTo(1000))
+				Expect(members[1]["approvedAt"]).To(BeEquivalentTo(2000))
+				Expect(page["owner"].(map[string]interface{})["publicID"]).To(Equal(owner.PublicID))
+				Expect(page["nextCursor"]).NotTo(BeEmpty())
+
+				options.Cursor = page["nextCursor"].(string)
+				page, err = GetClanMembersPage(testDb, game, clan.PublicID, options)
+				Expect(err).NotTo(HaveOccurred())
+				members = page["members"].([]map[string]interface{})
+				Expect(members).To(HaveLen(1))
+				Expect(members[0]["approvedAt"]).To(BeEquivalentTo(3000))
+				Expect(page["nextCursor"]).To(Equal(""))
+			})
+
+			It("Should filter and sort members by level", func() {
+				game, clan, _, players, memberships, err := GetClanWithMemberships(testDb, 3, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+				for i, level := range []string{"Member", "CoLeader", "Elder"} {
+					memberships[i].Level = level
+					_, err = testDb.Update(memberships[i])
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				options.OrderBy = "level"
+				options.Order = Descending
+				page, err := GetClanMembersPage(testDb, game, clan.PublicID, options)
+				Expect(err).NotTo(HaveOccurred())
+				members := page["members"].([]map[string]interface{})
+				Expect(members).To(HaveLen(3))
+				Expect(members[0]["publicID"]).To(Equal(players[1].PublicID))
+				Expect(members[1]["publicID"]).To(Equal(players[2].PublicID))
+				Expect(members[2]["publicID"]).To(Equal(players[0].PublicID))
+
+				options.Level = "Elder"
+				page, err = GetClanMembersPage(testDb, game, clan.PublicID, options)
+				Expect(err).NotTo(HaveOccurred())
+				members = page["members"].([]map[string]interface{})
+				Expect(members).To(HaveLen(1))
+				Expect(members[0]["publicID"]).To(Equal(players[2].PublicID))
+				Expect(members[0]["level"]).To(Equal("Elder"))
+			})
+
+			It("Should fail if level is invalid", func() {
+				game, clan, _, _, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				options.Level = "Overlord"
+				_, err = GetClanMembersPage(testDb, game, clan.PublicID, options)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Level Overlord is not valid for game %s.", game.PublicID)))
+			})
+		})
+
 		Describe("Get Clan Details", func() {
 			It("Should get clan members", func() {
 				gameID := uuid.NewV4().String()
@@ -164,6 +164,20 @@ func (e *MustWaitMembershipCooldownError) Error() string {
 	return fmt.Sprintf("Player %s must wait %d seconds before creating a membership in clan %s.", e.PlayerID, e.Time, e.ClanID)
 }
 
+// PlayerBannedFromClanError identifies that a player cannot create a membership because they are banned from the clan
+type PlayerBannedFromClanError struct {
+	PlayerID  string
+	ClanID    string
+	ExpiresAt int64
+}
+
+func (e *PlayerBannedFromClanError) Error() string {
+	if e.ExpiresAt == 0 {
+		return fmt.Sprintf("Player %s is banned from clan %s.", e.PlayerID, e.ClanID)
+	}
+	return fmt.Sprintf("Player %s is banned from clan %s until %d.", e.PlayerID, e.ClanID, e.ExpiresAt)
+}
+
 // CouldNotFindAllClansError identifies that one or more of the requested clans do not exist
 type CouldNotFindAllClansError struct {
 	gameID  string
@@ -202,3 +216,110 @@ type InvalidCastToGorpSQLExecutorError struct {
 func (e *InvalidCastToGorpSQLExecutorError) Error() string {
 	return "Invalid cast to gorp.SqlExecutor"
 }
+
+// InvalidHookFilterError identifies that a hook filter expression could not be parsed
+type InvalidHookFilterError struct {
+	Filter string
+	Err    error
+}
+
+func (e *InvalidHookFilterError) Error() string {
+	return fmt.Sprintf("Hook filter %q is invalid: %s", e.Filter, e.Err.Error())
+}
+
+// InvalidHookEventsError identifies that a hook was subscribed to unknown event types or wildcards
+type InvalidHookEventsError struct {
+	Reason string
+}
+
+func (e *InvalidHookEventsError) Error() string {
+	return fmt.Sprintf("Hook events are invalid: %s", e.Reason)
+}
+
+// HookSecretConflictError identifies that a hook was created again with a secret other than its own
+type HookSecretConflictError struct {
+	PublicID string
+}
+
+func (e *HookSecretConflictError) Error() string {
+	return fmt.Sprintf("Hook %s already exists with another secret. Update the hook to change its secret.", e.PublicID)
+}
+
+// HookOptionConflictError identifies that a hook was created again with a filter or body template
+// other than its own
+type HookOptionConflictError struct {
+	PublicID string
+	Option   string
+}
+
+func (e *HookOptionConflictError) Error() string {
+	return fmt.Sprintf("Hook %s already exists with another %s. Update the hook to change its %s.", e.PublicID, e.Option, e.Option)
+}
+
+// InvalidCursorError identifies that a pagination cursor could not be decoded
+type InvalidCursorError struct {
+	Cursor string
+}
+
+func (e *InvalidCursorError) Error() string {
+	return fmt.Sprintf("Cursor %s is invalid.", e.Cursor)
+}
+
+// InvalidImportRecordError identifies that a line of a game import could not be imported
+type InvalidImportRecordError struct {
+	Line   int
+	Reason string
+}
+
+func (e *InvalidImportRecordError) Error() string {
+	return fmt.Sprintf("Could not import line %d: %s", e.Line, e.Reason)
+}
+
+// VersionConflictError identifies that the current version of an entity is not the one the client expected
+type VersionConflictError struct {
+	Type    string
+	ID      string
+	Version int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s %s does not match the expected version. Current version is %d.", e.Type, e.ID, e.Version)
+}
+
+// InvalidMetadataIncrementError identifies that metadata fields could not be incremented because they are not numbers
+type InvalidMetadataIncrementError struct {
+	Type   string
+	ID     string
+	Fields []string
+}
+
+func (e *InvalidMetadataIncrementError) Error() string {
+	return fmt.Sprintf("Could not increment metadata of %s %s: fields %s must be numbers.", e.Type, e.ID, strings.Join(e.Fields, ", "))
+}
+
+// GameArchivedError identifies that a game was archived, so its data can't be changed
+type GameArchivedError struct {
+	PublicID string
+}
+
+func (e *GameArchivedError) Error() string {
+	return fmt.Sprintf("Game %s is archived and can't be changed.", e.PublicID)
+}
+
+// GameNotArchivedError identifies that a game must be archived before its data is deleted
+type GameNotArchivedError struct {
+	PublicID string
+}
+
+func (e *GameNotArchivedError) Error() string {
+	return fmt.Sprintf("Game %s must be archived before it is deleted.", e.PublicID)
+}
+
+// InvalidAPIKeyRoleError identifies that an API key was created with an unknown role
+type InvalidAPIKeyRoleError struct {
+	Role string
+}
+
+func (e *InvalidAPIKeyRoleError) Error() string {
+	return fmt.Sprintf("API key role %s is invalid. Valid roles are admin, server and readonly.", e.Role)
+}
@@ -347,6 +347,11 @@ func GetClanWithMemberships(
 		}
 	}
 
+	err = RefreshClanScores(db, game, clan.ID)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
 	return game, clan, owner, players, memberships, nil
 }
 
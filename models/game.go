@@ -42,8 +42,18 @@ type Game struct {
 	MaxPendingInvites                              int                    `db:"max_pending_invites"`
 	ClanUpdateMetadataFieldsHookTriggerWhitelist   string                 `db:"clan_metadata_fields_whitelist"`
 	PlayerUpdateMetadataFieldsHookTriggerWhitelist string                 `db:"player_metadata_fields_whitelist"`
+	ArchivedAt                                     int64                  `db:"archived_at"`
 }
 
+// ListGamesDefaultLimitKey is string constant
+const ListGamesDefaultLimitKey string = "listGames.defaultLimit"
+
+// ListGamesMaxLimitKey is string constant
+const ListGamesMaxLimitKey string = "listGames.maxLimit"
+
+// DefaultGameDeletionBatchSize is the number of rows deleted per query when a game is deleted
+const DefaultGameDeletionBatchSize = 1000
+
 // PreInsert populates fields before inserting a new game
 func (g *Game) PreInsert(s gorp.SqlExecutor) error {
 	// Handle JSON fields
@@ -64,6 +74,33 @@ func (g *Game) PreUpdate(s gorp.SqlExecutor) error {
 	return nil
 }
 
+// Serialize returns a JSON with the game details
+func (g *Game) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"publicID":                      g.PublicID,
+		"name":                          g.Name,
+		"membershipLevels":              g.MembershipLevels,
+		"metadata":                      g.Metadata,
+		"minLevelToAcceptApplication":   g.MinLevelToAcceptApplication,
+		"minLevelToCreateInvitation":    g.MinLevelToCreateInvitation,
+		"minLevelToRemoveMember":        g.MinLevelToRemoveMember,
+		"minLevelOffsetToRemoveMember":  g.MinLevelOffsetToRemoveMember,
+		"minLevelOffsetToPromoteMember": g.MinLevelOffsetToPromoteMember,
+		"minLevelOffsetToDemoteMember":  g.MinLevelOffsetToDemoteMember,
+		"maxMembers":                    g.MaxMembers,
+		"maxClansPerPlayer":             g.MaxClansPerPlayer,
+		"cooldownAfterDeny":             g.CooldownAfterDeny,
+		"cooldownAfterDelete":           g.CooldownAfterDelete,
+		"cooldownBeforeApply":           g.CooldownBeforeApply,
+		"cooldownBeforeInvite":          g.CooldownBeforeInvite,
+		"maxPendingInvites":             g.MaxPendingInvites,
+		"clanHookFieldsWhitelist":       g.ClanUpdateMetadataFieldsHookTriggerWhitelist,
+		"playerHookFieldsWhitelist":     g.PlayerUpdateMetadataFieldsHookTriggerWhitelist,
+		"archived":                      g.ArchivedAt > 0,
+		"archivedAt":                    g.ArchivedAt,
+	}
+}
+
 // GetGameByID returns a game by id
 func GetGameByID(db DB, id int) (*Game, error) {
 	obj, err := db.Get(Game{}, id)
@@ -101,6 +138,169 @@ func GetAllGames(db DB) ([]*Game, error) {
 	return games, nil
 }
 
+// ListGamesOptions holds the pagination and filtering options of ListGames()
+type ListGamesOptions struct {
+	Limit  int
+	Cursor string
+	// Archived returns only archived (true) or only active (false) games, nil returns both
+	Archived *bool
+}
+
+// ListGames returns a page of games in the order they were created and the cursor of the next page
+func ListGames(db DB, options *ListGamesOptions) ([]*Game, string, error) {
+	var lastID int64
+	if options.Cursor != "" {
+		var err error
+		_, lastID, err = decodeCursor(options.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	query := "SELECT * FROM games WHERE id>$1"
+	if options.Archived != nil {
+		if *options.Archived {
+			query += " AND archived_at>0"
+		} else {
+			query += " AND archived_at=0"
+		}
+	}
+	// fetch one extra game to find out whether there is a next page
+	query += " ORDER BY id LIMIT $2"
+
+	var games []*Game
+	_, err := db.Select(&games, query, lastID, options.Limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(games) > options.Limit {
+		games = games[:options.Limit]
+		last := games[len(games)-1]
+		nextCursor = encodeCursor(last.PublicID, int64(last.ID))
+	}
+	return games, nextCursor, nil
+}
+
+// IsGameArchived returns whether the game with the given public id was archived
+func IsGameArchived(db DB, publicID string) (bool, error) {
+	count, err := db.SelectInt("SELECT COUNT(*) FROM games WHERE public_id=$1 AND archived_at>0", publicID)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ArchiveGame archives a game, so its data can't be changed anymore until it is unarchived
+func ArchiveGame(db DB, publicID string) (*Game, error) {
+	now := util.NowMilli()
+	_, err := db.Exec(
+		"UPDATE games SET archived_at=$2, updated_at=$2 WHERE public_id=$1 AND archived_at=0",
+		publicID, now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return GetGameByPublicID(db, publicID)
+}
+
+// UnarchiveGame allows the data of an archived game to be changed again
+func UnarchiveGame(db DB, publicID string) (*Game, error) {
+	_, err := db.Exec(
+		"UPDATE games SET archived_at=0, updated_at=$2 WHERE public_id=$1 AND archived_at>0",
+		publicID, util.NowMilli(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return GetGameByPublicID(db, publicID)
+}
+
+// GameDeletionStats show stats about what has been deleted along with a game
+type GameDeletionStats struct {
+	Memberships     int
+	Clans           int
+	Players         int
+	Hooks           int
+	IdempotencyKeys int
+	AuditEntries    int
+	APIKeys         int
+}
+
+// GetStats returns a formatted message
+func (s *GameDeletionStats) GetStats() string {
+	return fmt.Sprintf(
+		"-Memberships: %d\n-Clans: %d\n-Players: %d\n-Hooks: %d\n-Idempotency Keys: %d\n-Audit Entries: %d\n-API Keys: %d\n",
+		s.Memberships,
+		s.Clans,
+		s.Players,
+		s.Hooks,
+		s.IdempotencyKeys,
+		s.AuditEntries,
+		s.APIKeys,
+	)
+}
+
+// DeleteGame removes an archived game and all its rows from the database, batchSize rows per query.
+// onProgress, if given, is called with the stats so far after each batch. Since every batch is
+// committed on its own, a failed deletion can be resumed by calling DeleteGame again
+func DeleteGame(db DB, publicID string, batchSize int, onProgress func(*GameDeletionStats)) (*GameDeletionStats, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultGameDeletionBatchSize
+	}
+
+	game, err := GetGameByPublicID(db, publicID)
+	if err != nil {
+		return nil, err
+	}
+	if game.ArchivedAt == 0 {
+		return nil, &GameNotArchivedError{publicID}
+	}
+
+	stats := &GameDeletionStats{}
+	// rows are deleted in dependency order: memberships reference clans and
+	// players and clans reference their owners. Clan scores and hook deliveries
+	// are removed along with their clans and hooks
+	tables := []struct {
+		name  string
+		count *int
+	}{
+		{"memberships", &stats.Memberships},
+		{"clans", &stats.Clans},
+		{"players", &stats.Players},
+		{"hooks", &stats.Hooks},
+		{"idempotency_keys", &stats.IdempotencyKeys},
+		{"audit_entries", &stats.AuditEntries},
+		{"api_keys", &stats.APIKeys},
+	}
+	for _, table := range tables {
+		query := fmt.Sprintf(
+			"DELETE FROM %s WHERE id IN (SELECT id FROM %s WHERE game_id=$1 LIMIT $2)",
+			table.name, table.name,
+		)
+		for {
+			deleted, err := runAndReturnRowsAffected(query, db, publicID, batchSize)
+			if err != nil {
+				return nil, err
+			}
+			*table.count += deleted
+			if onProgress != nil && deleted > 0 {
+				onProgress(stats)
+			}
+			if deleted < batchSize {
+				break
+			}
+		}
+	}
+
+	_, err = db.Exec("DELETE FROM games WHERE public_id=$1", publicID)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
 // CreateGame creates a new game
 func CreateGame(
 	db DB,
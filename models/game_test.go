@@ -326,4 +326,134 @@ var _ = Describe("Game Model", func() {
 			Expect(len(games)).To(BeNumerically(">", 1))
 		})
 	})
+
+	Describe("List Games", func() {
+		listAllGames := func(archived *bool) []string {
+			options := &ListGamesOptions{Limit: 1000, Archived: archived}
+			var publicIDs []string
+			for {
+				games, nextCursor, err := ListGames(testDb, options)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(games)).To(BeNumerically("<=", 1000))
+				for _, game := range games {
+					publicIDs = append(publicIDs, game.PublicID)
+				}
+				if nextCursor == "" {
+					return publicIDs
+				}
+				options.Cursor = nextCursor
+			}
+		}
+
+		It("Should list all games in the order they were created", func() {
+			var created []string
+			for i := 0; i < 3; i++ {
+				game := GameFactory.MustCreate().(*Game)
+				err := testDb.Insert(game)
+				Expect(err).NotTo(HaveOccurred())
+				created = append(created, game.PublicID)
+			}
+
+			publicIDs := listAllGames(nil)
+			Expect(publicIDs[len(publicIDs)-3:]).To(Equal(created))
+		})
+
+		It("Should filter archived games", func() {
+			active := GameFactory.MustCreate().(*Game)
+			err := testDb.Insert(active)
+			Expect(err).NotTo(HaveOccurred())
+			archived := GameFactory.MustCreate().(*Game)
+			err = testDb.Insert(archived)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = ArchiveGame(testDb, archived.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+
+			yes, no := true, false
+			Expect(listAllGames(&yes)).To(ContainElement(archived.PublicID))
+			Expect(listAllGames(&yes)).NotTo(ContainElement(active.PublicID))
+			Expect(listAllGames(&no)).To(ContainElement(active.PublicID))
+			Expect(listAllGames(&no)).NotTo(ContainElement(archived.PublicID))
+		})
+
+		It("Should fail with an invalid cursor", func() {
+			_, _, err := ListGames(testDb, &ListGamesOptions{Limit: 10, Cursor: "invalid"})
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&InvalidCursorError{}))
+		})
+	})
+
+	Describe("Archive Game", func() {
+		It("Should archive and unarchive a game", func() {
+			game := GameFactory.MustCreate().(*Game)
+			err := testDb.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			archivedGame, err := ArchiveGame(testDb, game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(archivedGame.ArchivedAt).To(BeNumerically(">", 0))
+			archived, err := IsGameArchived(testDb, game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(archived).To(BeTrue())
+
+			again, err := ArchiveGame(testDb, game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(again.ArchivedAt).To(Equal(archivedGame.ArchivedAt))
+
+			unarchivedGame, err := UnarchiveGame(testDb, game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unarchivedGame.ArchivedAt).To(BeEquivalentTo(0))
+			archived, err = IsGameArchived(testDb, game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(archived).To(BeFalse())
+		})
+
+		It("Should fail if the game does not exist", func() {
+			_, err := ArchiveGame(testDb, "invalid-game")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Game was not found with id: invalid-game"))
+		})
+	})
+
+	Describe("Delete Game", func() {
+		It("Should delete all rows of an archived game", func() {
+			game, clan, owner, _, _, err := GetClanWithMemberships(testDb, 3, 1, 1, 1, "", "")
+			Expect(err).NotTo(HaveOccurred())
+			hook, err := CreateHook(testDb, game.PublicID, GameUpdatedHook, nil, nil, "http://test/delete", "", "", nil)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = UpdateClan(
+				testDb, game.PublicID, clan.PublicID, "deleted clan", owner.PublicID,
+				clan.Metadata, clan.AllowApplication, clan.AutoJoin,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = DeleteGame(testDb, game.PublicID, 2, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&GameNotArchivedError{}))
+
+			_, err = ArchiveGame(testDb, game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+
+			progress := 0
+			stats, err := DeleteGame(testDb, game.PublicID, 2, func(*GameDeletionStats) {
+				progress++
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.Memberships).To(Equal(6))
+			Expect(stats.Clans).To(Equal(1))
+			Expect(stats.Players).To(Equal(7))
+			Expect(stats.Hooks).To(Equal(1))
+			Expect(stats.AuditEntries).To(Equal(1))
+			Expect(progress).To(BeNumerically(">=", 4))
+
+			_, err = GetGameByPublicID(testDb, game.PublicID)
+			Expect(err).To(HaveOccurred())
+			_, err = GetHookByID(testDb, hook.ID)
+			Expect(err).To(HaveOccurred())
+			for _, table := range []string{"players", "clans", "memberships", "hooks", "audit_entries"} {
+				count, err := testDb.SelectInt("SELECT COUNT(*) FROM "+table+" WHERE game_id=$1", game.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(BeEquivalentTo(0), table)
+			}
+		})
+	})
 })
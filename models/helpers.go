@@ -9,6 +9,8 @@ package models
 
 import (
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 
 	"github.com/go-gorp/gorp"
@@ -85,6 +87,10 @@ func InitDb(host string, user string, port int, sslmode string, dbName string, p
 	dbmap.AddTableWithName(Clan{}, "clans").SetKeys(true, "ID")
 	dbmap.AddTableWithName(Membership{}, "memberships").SetKeys(true, "ID")
 	dbmap.AddTableWithName(Hook{}, "hooks").SetKeys(true, "ID")
+	dbmap.AddTableWithName(HookDelivery{}, "hook_deliveries").SetKeys(true, "ID")
+	dbmap.AddTableWithName(IdempotencyKey{}, "idempotency_keys").SetKeys(true, "ID")
+	dbmap.AddTableWithName(AuditEntry{}, "audit_entries").SetKeys(true, "ID")
+	dbmap.AddTableWithName(APIKey{}, "api_keys").SetKeys(true, "ID")
 
 	// dbmap.TraceOn("[gorp]", log.New(os.Stdout, "KHAN:", log.Lmicroseconds))
 	return egorp.New(dbmap, dbName), nil
@@ -122,3 +128,29 @@ func nullOrBool(value sql.NullBool) bool {
 	}
 	return false
 }
+
+// pageCursor is the position of the last item of a page, used to fetch the page that follows it
+type pageCursor struct {
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+// encodeCursor returns an opaque cursor pointing right after the item with the given sort value and id
+func encodeCursor(value string, id int64) string {
+	data, _ := json.Marshal(pageCursor{Value: value, ID: id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns the sort value and id encoded in a cursor created with encodeCursor
+func decodeCursor(cursor string) (string, int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, &InvalidCursorError{cursor}
+	}
+	var c pageCursor
+	err = json.Unmarshal(data, &c)
+	if err != nil {
+		return "", 0, &InvalidCursorError{cursor}
+	}
+	return c.Value, c.ID, nil
+}
@@ -8,6 +8,10 @@
 package models
 
 import (
+	"fmt"
+	"reflect"
+	"sort"
+
 	"github.com/satori/go.uuid"
 	"github.com/topfreegames/khan/util"
 
@@ -53,17 +57,162 @@ const (
 
 	//MembershipLeftHook happens when a player leaves a clan
 	MembershipLeftHook = 12
+
+	//ClanDeletedHook happens when a clan is deleted
+	ClanDeletedHook = 13
+
+	//MembershipBannedHook happens when a player is banned from a clan
+	MembershipBannedHook = 14
+
+	//MembershipUnbannedHook happens when a player is unbanned from a clan
+	MembershipUnbannedHook = 15
 )
 
+//MultipleEventsHook is the event type of hooks subscribed to a list of event types or wildcards
+const MultipleEventsHook = -1
+
+//AllEventsWildcard subscribes a hook to every event type
+const AllEventsWildcard = "*"
+
+//HookEventWildcards maps the wildcards a hook can subscribe to into the event types they match
+var HookEventWildcards = map[string][]int{
+	"game.*":   {GameUpdatedHook},
+	"player.*": {PlayerCreatedHook, PlayerUpdatedHook},
+	"clan.*": {
+		ClanCreatedHook, ClanUpdatedHook, ClanLeftHook, ClanOwnershipTransferredHook,
+		ClanDeletedHook,
+	},
+	"membership.*": {
+		MembershipApplicationCreatedHook, MembershipApprovedHook, MembershipDeniedHook,
+		MembershipPromotedHook, MembershipDemotedHook, MembershipLeftHook,
+		MembershipBannedHook, MembershipUnbannedHook,
+	},
+}
+
+// GetEventTypesForWildcard returns the event types matched by wildcard
+func GetEventTypesForWildcard(wildcard string) ([]int, bool) {
+	if wildcard != AllEventsWildcard {
+		eventTypes, ok := HookEventWildcards[wildcard]
+		return eventTypes, ok
+	}
+
+	var eventTypes []int
+	for _, groupEventTypes := range HookEventWildcards {
+		eventTypes = append(eventTypes, groupEventTypes...)
+	}
+	sort.Ints(eventTypes)
+	return eventTypes, true
+}
+
+func isValidHookEventType(eventType int) bool {
+	allEventTypes, _ := GetEventTypesForWildcard(AllEventsWildcard)
+	for _, validEventType := range allEventTypes {
+		if validEventType == eventType {
+			return true
+		}
+	}
+	return false
+}
+
 // Hook identifies a webhook for a given event
 type Hook struct {
-	ID        int    `db:"id"`
-	GameID    string `db:"game_id"`
-	PublicID  string `db:"public_id"`
-	EventType int    `db:"event_type"`
-	URL       string `db:"url"`
-	CreatedAt int64  `db:"created_at"`
-	UpdatedAt int64  `db:"updated_at"`
+	ID             int                    `db:"id"`
+	GameID         string                 `db:"game_id"`
+	PublicID       string                 `db:"public_id"`
+	EventType      int                    `db:"event_type"`
+	EventTypes     []int                  `db:"event_types"`
+	EventWildcards []string               `db:"event_wildcards"`
+	URL            string                 `db:"url"`
+	Secret         string                 `db:"secret"`
+	Filter         string                 `db:"filter"`
+	BodyTemplate   map[string]interface{} `db:"body_template"`
+	CreatedAt      int64                  `db:"created_at"`
+	UpdatedAt      int64                  `db:"updated_at"`
+
+	compiledFilter      *util.HookFilter `db:"-"`
+	compiledFilterError error            `db:"-"`
+}
+
+// Serialize returns a JSON with the hook details. The secret is never included
+func (h *Hook) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"gameID":       h.GameID,
+		"publicID":     h.PublicID,
+		"type":         h.EventType,
+		"types":        h.GetEventTypes(),
+		"wildcards":    h.getEventWildcards(),
+		"hookURL":      h.URL,
+		"signed":       h.Secret != "",
+		"filter":       h.Filter,
+		"bodyTemplate": h.BodyTemplate,
+		"createdAt":    h.CreatedAt,
+		"updatedAt":    h.UpdatedAt,
+	}
+}
+
+// GetEventTypes returns all the event types the hook is subscribed to, in order
+func (h *Hook) GetEventTypes() []int {
+	if h.EventType != MultipleEventsHook {
+		return []int{h.EventType}
+	}
+
+	subscribed := map[int]bool{}
+	for _, eventType := range h.EventTypes {
+		subscribed[eventType] = true
+	}
+	for _, wildcard := range h.EventWildcards {
+		eventTypes, _ := GetEventTypesForWildcard(wildcard)
+		for _, eventType := range eventTypes {
+			subscribed[eventType] = true
+		}
+	}
+
+	eventTypes := []int{}
+	for eventType := range subscribed {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Ints(eventTypes)
+	return eventTypes
+}
+
+func (h *Hook) getEventWildcards() []string {
+	if h.EventWildcards == nil {
+		return []string{}
+	}
+	return h.EventWildcards
+}
+
+// Matches returns whether the hook filter accepts payload. Hooks without a filter accept every payload
+func (h *Hook) Matches(payload map[string]interface{}) (bool, error) {
+	if h.Filter == "" {
+		return true, nil
+	}
+	filter, err := h.compiledFilter, h.compiledFilterError
+	if filter == nil && err == nil || filter != nil && filter.String() != h.Filter {
+		// the hook was not loaded from the database or its filter changed since then
+		filter, err = util.CompileHookFilter(h.Filter)
+	}
+	if err != nil {
+		return false, err
+	}
+	return filter.Match(payload), nil
+}
+
+// compileFilter compiles the hook filter so it is not parsed again for every event
+func (h *Hook) compileFilter() {
+	h.compiledFilter, h.compiledFilterError = nil, nil
+	if h.Filter != "" {
+		h.compiledFilter, h.compiledFilterError = util.CompileHookFilter(h.Filter)
+	}
+}
+
+// RenderBody returns the body to send to the hook for payload, applying the
+// hook body template if there is one
+func (h *Hook) RenderBody(payload map[string]interface{}) interface{} {
+	if len(h.BodyTemplate) == 0 {
+		return payload
+	}
+	return util.RenderHookBody(h.BodyTemplate, payload)
 }
 
 // PreInsert populates fields before inserting a new hook
@@ -79,6 +228,12 @@ func (h *Hook) PreUpdate(s gorp.SqlExecutor) error {
 	return nil
 }
 
+// PostGet compiles the filter of a hook loaded from the database
+func (h *Hook) PostGet(s gorp.SqlExecutor) error {
+	h.compileFilter()
+	return nil
+}
+
 // GetHookByID returns a hook by id
 func GetHookByID(db DB, id int) (*Hook, error) {
 	obj, err := db.Get(Hook{}, id)
@@ -111,21 +266,111 @@ func GetHookByDetails(db DB, gameID string, eventType int, hookURL string) *Hook
 	return &hook
 }
 
-// CreateHook returns a newly created event hook
-func CreateHook(db DB, gameID string, eventType int, url string) (*Hook, error) {
+func validateHookEvents(eventType int, eventTypes []int, eventWildcards []string) error {
+	if len(eventTypes) == 0 && len(eventWildcards) == 0 {
+		if !isValidHookEventType(eventType) {
+			return &InvalidHookEventsError{fmt.Sprintf("%d is not a valid event type", eventType)}
+		}
+		return nil
+	}
+	for _, eventType := range eventTypes {
+		if !isValidHookEventType(eventType) {
+			return &InvalidHookEventsError{fmt.Sprintf("%d is not a valid event type", eventType)}
+		}
+	}
+	for _, wildcard := range eventWildcards {
+		if _, ok := GetEventTypesForWildcard(wildcard); !ok {
+			return &InvalidHookEventsError{fmt.Sprintf("%s is not a valid event wildcard", wildcard)}
+		}
+	}
+	return nil
+}
+
+func validateHookFilter(filter string) error {
+	if filter == "" {
+		return nil
+	}
+	if _, err := util.CompileHookFilter(filter); err != nil {
+		return &InvalidHookFilterError{filter, err}
+	}
+	return nil
+}
+
+// CreateHook returns a newly created event hook. The hook is subscribed to
+// eventType or, if eventTypes or eventWildcards are not empty, to all the
+// event types they list. If secret is not empty, deliveries to this hook will
+// be signed with it. Creating an existing hook again adds eventTypes and
+// eventWildcards to its subscriptions and fails with HookSecretConflictError
+// if secret is not its own. The hook only fires for payloads that match filter, if
+// any, and sends bodyTemplate rendered with the payload instead of the
+// payload itself, if any. Like the secret, the filter and body template of an
+// existing hook are kept if none are sent, and creating it again with other ones
+// fails with HookOptionConflictError
+func CreateHook(
+	db DB, gameID string, eventType int, eventTypes []int, eventWildcards []string,
+	url string, secret string, filter string, bodyTemplate map[string]interface{},
+) (*Hook, error) {
+	if err := validateHookEvents(eventType, eventTypes, eventWildcards); err != nil {
+		return nil, err
+	}
+	if err := validateHookFilter(filter); err != nil {
+		return nil, err
+	}
+	if len(eventTypes) > 0 || len(eventWildcards) > 0 {
+		eventType = MultipleEventsHook
+	}
+	if bodyTemplate == nil {
+		bodyTemplate = map[string]interface{}{}
+	}
+
 	hook := GetHookByDetails(db, gameID, eventType, url)
 
 	if hook != nil {
+		// the secret is shared with whoever receives the hook, so it is only
+		// replaced explicitly, through UpdateHook
+		if secret != "" && hook.Secret != "" && secret != hook.Secret {
+			return nil, &HookSecretConflictError{hook.PublicID}
+		}
+		if secret != "" {
+			hook.Secret = secret
+		}
+		// so are the filter and body template, which other clients may rely on
+		if filter != "" && hook.Filter != "" && filter != hook.Filter {
+			return nil, &HookOptionConflictError{hook.PublicID, "filter"}
+		}
+		if len(bodyTemplate) > 0 && len(hook.BodyTemplate) > 0 && !reflect.DeepEqual(bodyTemplate, hook.BodyTemplate) {
+			return nil, &HookOptionConflictError{hook.PublicID, "bodyTemplate"}
+		}
+		if filter != "" {
+			hook.Filter = filter
+		}
+		if len(bodyTemplate) > 0 {
+			hook.BodyTemplate = bodyTemplate
+		}
+		// other clients may rely on the events the hook is already subscribed
+		// to, so the new ones are added to them
+		hook.EventTypes = mergeHookEventTypes(hook.EventTypes, eventTypes)
+		hook.EventWildcards = mergeHookEventWildcards(hook.EventWildcards, eventWildcards)
+		hook.compileFilter()
+		if _, err := db.Update(hook); err != nil {
+			return nil, err
+		}
 		return hook, nil
 	}
 
 	publicID := uuid.NewV4().String()
 	hook = &Hook{
-		GameID:    gameID,
-		PublicID:  publicID,
-		EventType: eventType,
-		URL:       url,
+		GameID:         gameID,
+		PublicID:       publicID,
+		EventType:      eventType,
+		EventTypes:     eventTypes,
+		EventWildcards: eventWildcards,
+		URL:            url,
+		Secret:         secret,
+		Filter:         filter,
+		BodyTemplate:   bodyTemplate,
 	}
+	hook.compileFilter()
 	err := db.Insert(hook)
 	if err != nil {
 		return nil, err
@@ -133,6 +378,81 @@ func CreateHook(db DB, gameID string, eventType int, url string) (*Hook, error)
 	return hook, nil
 }
 
+func mergeHookEventTypes(current, added []int) []int {
+	merged := append([]int{}, current...)
+	for _, eventType := range added {
+		found := false
+		for _, existing := range merged {
+			if existing == eventType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, eventType)
+		}
+	}
+	return merged
+}
+
+func mergeHookEventWildcards(current, added []string) []string {
+	merged := append([]string{}, current...)
+	for _, wildcard := range added {
+		found := false
+		for _, existing := range merged {
+			if existing == wildcard {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, wildcard)
+		}
+	}
+	return merged
+}
+
+// UpdateHook updates the event types, url, filter and body template of a hook.
+// If secret is not empty the hook secret is replaced by it
+func UpdateHook(
+	db DB, gameID, publicID string, eventType int, eventTypes []int, eventWildcards []string,
+	url, secret string, filter string, bodyTemplate map[string]interface{},
+) (*Hook, error) {
+	if err := validateHookEvents(eventType, eventTypes, eventWildcards); err != nil {
+		return nil, err
+	}
+	if err := validateHookFilter(filter); err != nil {
+		return nil, err
+	}
+	if len(eventTypes) > 0 || len(eventWildcards) > 0 {
+		eventType = MultipleEventsHook
+	}
+	if bodyTemplate == nil {
+		bodyTemplate = map[string]interface{}{}
+	}
+
+	hook, err := GetHookByPublicID(db, gameID, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	hook.EventType = eventType
+	hook.EventTypes = eventTypes
+	hook.EventWildcards = eventWildcards
+	hook.URL = url
+	hook.Filter = filter
+	hook.BodyTemplate = bodyTemplate
+	if secret != "" {
+		hook.Secret = secret
+	}
+	hook.compileFilter()
+	_, err = db.Update(hook)
+	if err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
 // RemoveHook removes a hook by public ID
 func RemoveHook(db DB, gameID string, publicID string) error {
 	hook, err := GetHookByPublicID(db, gameID, publicID)
@@ -143,6 +463,16 @@ func RemoveHook(db DB, gameID string, publicID string) error {
 	return err
 }
 
+// GetHooksByGameID returns all the hooks registered for a game
+func GetHooksByGameID(db DB, gameID string) ([]*Hook, error) {
+	var hooks []*Hook
+	_, err := db.Select(&hooks, "SELECT * FROM hooks WHERE game_id=$1 ORDER BY created_at, id", gameID)
+	if err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
 // GetAllHooks returns all the available hooks
 func GetAllHooks(db DB) ([]*Hook, error) {
 	var hooks []*Hook
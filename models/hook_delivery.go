@@ -0,0 +1,83 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"github.com/go-gorp/gorp"
+	"github.com/topfreegames/khan/util"
+)
+
+// HookDeliveryBodyExcerptSize is the maximum number of bytes of a response body kept for a delivery
+const HookDeliveryBodyExcerptSize = 1024
+
+// HookDelivery identifies an attempt to deliver an event to a webhook
+type HookDelivery struct {
+	ID           int64  `db:"id"`
+	HookID       int    `db:"hook_id"`
+	GameID       string `db:"game_id"`
+	EventID      string `db:"event_id"`
+	EventType    int    `db:"event_type"`
+	Attempt      int    `db:"attempt"`
+	StatusCode   int    `db:"status_code"`
+	LatencyMs    int64  `db:"latency_ms"`
+	ResponseBody string `db:"response_body"`
+	Error        string `db:"error"`
+	CreatedAt    int64  `db:"created_at"`
+}
+
+// PreInsert populates fields before inserting a new hook delivery
+func (d *HookDelivery) PreInsert(s gorp.SqlExecutor) error {
+	d.CreatedAt = util.NowMilli()
+	d.ResponseBody = util.TruncateString(d.ResponseBody, HookDeliveryBodyExcerptSize)
+	return nil
+}
+
+// Serialize returns a JSON with the hook delivery details
+func (d *HookDelivery) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"eventID":      d.EventID,
+		"eventType":    d.EventType,
+		"attempt":      d.Attempt,
+		"statusCode":   d.StatusCode,
+		"latencyMs":    d.LatencyMs,
+		"responseBody": d.ResponseBody,
+		"error":        d.Error,
+		"createdAt":    d.CreatedAt,
+	}
+}
+
+// CreateHookDelivery stores a delivery attempt of a hook
+func CreateHookDelivery(db DB, delivery *HookDelivery) error {
+	return db.Insert(delivery)
+}
+
+// PruneHookDeliveries deletes the hook deliveries of a game older than retention seconds
+func PruneHookDeliveries(db DB, gameID string, retention int) (int, error) {
+	createdAt := util.NowMilli() - int64(retention)*1000
+	return runAndReturnRowsAffected("DELETE FROM hook_deliveries WHERE game_id=$1 AND created_at < $2", db, gameID, createdAt)
+}
+
+// GetHookDeliveries returns the latest deliveries of the hook with the given
+// public id, newest first
+func GetHookDeliveries(db DB, gameID, publicID string, limit int) ([]*HookDelivery, error) {
+	hook, err := GetHookByPublicID(db, gameID, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []*HookDelivery
+	_, err = db.Select(
+		&deliveries,
+		"SELECT * FROM hook_deliveries WHERE hook_id=$1 ORDER BY created_at DESC, id DESC LIMIT $2",
+		hook.ID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
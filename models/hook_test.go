@@ -8,12 +8,15 @@
 package models_test
 
 import (
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/satori/go.uuid"
 	. "github.com/topfreegames/khan/models"
+	"github.com/topfreegames/khan/util"
 )
 
 var _ = Describe("Hook Model", func() {
@@ -101,7 +104,12 @@ var _ = Describe("Hook Model", func() {
 					testDb,
 					game.PublicID,
 					GameUpdatedHook,
+					nil,
+					nil,
 					"http://test/created",
+					"",
+					"",
+					nil,
 				)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(hook.ID).NotTo(BeEquivalentTo(0))
@@ -114,6 +122,98 @@ var _ = Describe("Hook Model", func() {
 				Expect(dbHook.URL).To(Equal(hook.URL))
 			})
 
+			It("Should create a new Hook with a secret", func() {
+				game := GameFactory.MustCreate().(*Game)
+				err := testDb.Insert(game)
+				Expect(err).NotTo(HaveOccurred())
+
+				hook, err := CreateHook(
+					testDb,
+					game.PublicID,
+					GameUpdatedHook,
+					nil,
+					nil,
+					"http://test/signed",
+					"my-secret",
+					"",
+					nil,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbHook, err := GetHookByID(testDb, hook.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbHook.Secret).To(Equal("my-secret"))
+			})
+
+			It("Should not replace the secret of an existing Hook", func() {
+				gameID := uuid.NewV4().String()
+				hook, err := CreateHook(testDb, gameID, GameUpdatedHook, nil, nil, "http://test/resigned", "my-secret", "", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, secret := range []string{"", "my-secret"} {
+					hook2, err := CreateHook(testDb, gameID, GameUpdatedHook, nil, nil, "http://test/resigned", secret, "", nil)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(hook2.ID).To(Equal(hook.ID))
+					Expect(hook2.Secret).To(Equal("my-secret"))
+				}
+
+				_, err = CreateHook(testDb, gameID, GameUpdatedHook, nil, nil, "http://test/resigned", "other-secret", "", nil)
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(&HookSecretConflictError{}))
+
+				dbHook, err := GetHookByID(testDb, hook.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbHook.Secret).To(Equal("my-secret"))
+			})
+
+			It("Should keep the filter and body template of an existing Hook", func() {
+				gameID := uuid.NewV4().String()
+				filter := `metadata.region == "eu"`
+				bodyTemplate := map[string]interface{}{"text": "{{name}} updated"}
+				hook, err := CreateHook(testDb, gameID, GameUpdatedHook, nil, nil, "http://test/refiltered", "", filter, bodyTemplate)
+				Expect(err).NotTo(HaveOccurred())
+
+				hook2, err := CreateHook(testDb, gameID, GameUpdatedHook, nil, nil, "http://test/refiltered", "", "", nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hook2.ID).To(Equal(hook.ID))
+				Expect(hook2.Filter).To(Equal(filter))
+				Expect(hook2.BodyTemplate).To(Equal(bodyTemplate))
+
+				_, err = CreateHook(testDb, gameID, GameUpdatedHook, nil, nil, "http://test/refiltered", "", `metadata.region == "us"`, nil)
+				Expect(err).To(Equal(&HookOptionConflictError{hook.PublicID, "filter"}))
+
+				_, err = CreateHook(
+					testDb, gameID, GameUpdatedHook, nil, nil, "http://test/refiltered", "", filter,
+					map[string]interface{}{"text": "{{name}} changed"},
+				)
+				Expect(err).To(Equal(&HookOptionConflictError{hook.PublicID, "bodyTemplate"}))
+
+				dbHook, err := GetHookByID(testDb, hook.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbHook.Filter).To(Equal(filter))
+				Expect(dbHook.BodyTemplate).To(Equal(bodyTemplate))
+			})
+
+			It("Should not create a Hook with an invalid filter", func() {
+				game := GameFactory.MustCreate().(*Game)
+				err := testDb.Insert(game)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = CreateHook(
+					testDb,
+					game.PublicID,
+					GameUpdatedHook,
+					nil,
+					nil,
+					"http://test/filtered",
+					"",
+					"name == (",
+					nil,
+				)
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(&InvalidHookFilterError{}))
+			})
+
 			It("Create same Hook works fine", func() {
 				gameID := uuid.NewV4().String()
 				hook, err := CreateHookFactory(testDb, gameID, GameUpdatedHook, "http://test/created")
@@ -122,7 +222,12 @@ var _ = Describe("Hook Model", func() {
 					testDb,
 					gameID,
 					GameUpdatedHook,
+					nil,
+					nil,
 					"http://test/created",
+					"",
+					"",
+					nil,
 				)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(hook2.ID == hook.ID).To(BeTrue())
@@ -135,6 +240,249 @@ var _ = Describe("Hook Model", func() {
 				Expect(dbHook.URL).To(Equal(hook.URL))
 			})
 
+			It("Should create a Hook subscribed to event types and wildcards", func() {
+				gameID := uuid.NewV4().String()
+				hook, err := CreateHook(
+					testDb,
+					gameID,
+					GameUpdatedHook,
+					[]int{PlayerCreatedHook, ClanCreatedHook},
+					[]string{"membership.*"},
+					"http://test/multiple",
+					"",
+					"",
+					nil,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hook.EventType).To(Equal(MultipleEventsHook))
+
+				dbHook, err := GetHookByID(testDb, hook.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbHook.EventTypes).To(Equal([]int{PlayerCreatedHook, ClanCreatedHook}))
+				Expect(dbHook.EventWildcards).To(Equal([]string{"membership.*"}))
+				Expect(dbHook.GetEventTypes()).To(Equal([]int{
+					PlayerCreatedHook, ClanCreatedHook,
+					MembershipApplicationCreatedHook, MembershipApprovedHook, MembershipDeniedHook,
+					MembershipPromotedHook, MembershipDemotedHook, MembershipLeftHook,
+					MembershipBannedHook, MembershipUnbannedHook,
+				}))
+			})
+
+			It("Should add the event types and wildcards of a Hook created again", func() {
+				gameID := uuid.NewV4().String()
+				hook, err := CreateHook(
+					testDb, gameID, GameUpdatedHook, []int{PlayerCreatedHook}, nil,
+					"http://test/merged", "", "", nil,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				hook2, err := CreateHook(
+					testDb, gameID, GameUpdatedHook, []int{ClanCreatedHook, PlayerCreatedHook}, []string{"membership.*"},
+					"http://test/merged", "", "", nil,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hook2.ID).To(Equal(hook.ID))
+
+				dbHook, err := GetHookByID(testDb, hook.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbHook.EventTypes).To(Equal([]int{PlayerCreatedHook, ClanCreatedHook}))
+				Expect(dbHook.EventWildcards).To(Equal([]string{"membership.*"}))
+			})
+
+			It("Should subscribe a Hook with the * wildcard to every event type", func() {
+				hook, err := CreateHook(
+					testDb,
+					uuid.NewV4().String(),
+					GameUpdatedHook,
+					nil,
+					[]string{AllEventsWildcard},
+					"http://test/all",
+					"",
+					"",
+					nil,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				eventTypes := hook.GetEventTypes()
+				Expect(eventTypes).To(HaveLen(16))
+				Expect(eventTypes[0]).To(Equal(GameUpdatedHook))
+				Expect(eventTypes[15]).To(Equal(MembershipUnbannedHook))
+			})
+
+			It("Should not create a Hook with an invalid event type", func() {
+				_, err := CreateHook(
+					testDb,
+					uuid.NewV4().String(),
+					GameUpdatedHook,
+					[]int{ClanCreatedHook, 999},
+					nil,
+					"http://test/invalid",
+					"",
+					"",
+					nil,
+				)
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(&InvalidHookEventsError{}))
+			})
+
+			It("Should not create a Hook with an invalid wildcard", func() {
+				_, err := CreateHook(
+					testDb,
+					uuid.NewV4().String(),
+					GameUpdatedHook,
+					nil,
+					[]string{"guild.*"},
+					"http://test/invalid",
+					"",
+					"",
+					nil,
+				)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Hook events are invalid: guild.* is not a valid event wildcard"))
+			})
+		})
+
+		Describe("Update Hook", func() {
+			It("Should update a Hook with UpdateHook", func() {
+				hook, err := CreateHookFactory(testDb, "", GameUpdatedHook, "http://test/update")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = UpdateHook(testDb, hook.GameID, hook.PublicID, ClanCreatedHook, nil, nil, "http://test/updated", "", "", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbHook, err := GetHookByID(testDb, hook.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbHook.EventType).To(Equal(ClanCreatedHook))
+				Expect(dbHook.URL).To(Equal("http://test/updated"))
+				Expect(dbHook.Secret).To(Equal(""))
+			})
+
+			It("Should not update a Hook that does not exist", func() {
+				_, err := UpdateHook(testDb, "invalid", "key", ClanCreatedHook, nil, nil, "http://test/updated", "", "", nil)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Hook was not found with id: key"))
+			})
+		})
+
+		Describe("Matches", func() {
+			It("Should match events with the filter of a loaded hook", func() {
+				hook, err := CreateHook(
+					testDb, uuid.NewV4().String(), GameUpdatedHook, nil, nil,
+					"http://test/matches", "", `region == "eu"`, nil,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbHook, err := GetHookByPublicID(testDb, hook.GameID, hook.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbHook.Matches(map[string]interface{}{"region": "eu"})).To(BeTrue())
+				Expect(dbHook.Matches(map[string]interface{}{"region": "us"})).To(BeFalse())
+
+				dbHook.Filter = `region == "us"`
+				Expect(dbHook.Matches(map[string]interface{}{"region": "us"})).To(BeTrue())
+			})
+
+			It("Should return an error if the stored filter is invalid", func() {
+				hook, err := CreateHookFactory(testDb, "", GameUpdatedHook, "http://test/invalid-filter")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = testDb.Exec("UPDATE hooks SET filter='name == (' WHERE id=$1", hook.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbHook, err := GetHookByID(testDb, hook.ID)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = dbHook.Matches(map[string]interface{}{})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Describe("Get Hooks By Game ID", func() {
+			It("Should return only the hooks of the game", func() {
+				hook, err := CreateHookFactory(testDb, "", GameUpdatedHook, "http://test/game")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = CreateHookFactory(testDb, "", GameUpdatedHook, "http://test/other-game")
+				Expect(err).NotTo(HaveOccurred())
+
+				hooks, err := GetHooksByGameID(testDb, hook.GameID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hooks).To(HaveLen(1))
+				Expect(hooks[0].PublicID).To(Equal(hook.PublicID))
+			})
+		})
+
+		Describe("Hook Deliveries", func() {
+			It("Should store and return the latest deliveries", func() {
+				hook, err := CreateHookFactory(testDb, "", GameUpdatedHook, "http://test/deliveries")
+				Expect(err).NotTo(HaveOccurred())
+
+				for attempt := 1; attempt <= 3; attempt++ {
+					err = CreateHookDelivery(testDb, &HookDelivery{
+						HookID:       hook.ID,
+						GameID:       hook.GameID,
+						EventID:      "event-id",
+						EventType:    hook.EventType,
+						Attempt:      attempt,
+						StatusCode:   500,
+						ResponseBody: strings.Repeat("a", HookDeliveryBodyExcerptSize+10),
+					})
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				deliveries, err := GetHookDeliveries(testDb, hook.GameID, hook.PublicID, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deliveries).To(HaveLen(2))
+				Expect(deliveries[0].Attempt).To(Equal(3))
+				Expect(deliveries[0].ResponseBody).To(HaveLen(HookDeliveryBodyExcerptSize))
+			})
+
+			It("Should not split multi-byte characters of the response body", func() {
+				hook, err := CreateHookFactory(testDb, "", GameUpdatedHook, "http://test/deliveries")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = CreateHookDelivery(testDb, &HookDelivery{
+					HookID:       hook.ID,
+					GameID:       hook.GameID,
+					EventID:      "event-id",
+					EventType:    hook.EventType,
+					Attempt:      1,
+					StatusCode:   500,
+					ResponseBody: "a" + strings.Repeat("é", HookDeliveryBodyExcerptSize),
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				deliveries, err := GetHookDeliveries(testDb, hook.GameID, hook.PublicID, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deliveries[0].ResponseBody).To(HaveLen(HookDeliveryBodyExcerptSize - 1))
+				Expect(utf8.ValidString(deliveries[0].ResponseBody)).To(BeTrue())
+			})
+
+			It("Should prune deliveries older than the retention", func() {
+				hook, err := CreateHookFactory(testDb, "", GameUpdatedHook, "http://test/deliveries")
+				Expect(err).NotTo(HaveOccurred())
+
+				for attempt := 1; attempt <= 3; attempt++ {
+					delivery := &HookDelivery{
+						HookID:    hook.ID,
+						GameID:    hook.GameID,
+						EventID:   "event-id",
+						EventType: hook.EventType,
+						Attempt:   attempt,
+					}
+					err = CreateHookDelivery(testDb, delivery)
+					Expect(err).NotTo(HaveOccurred())
+					if attempt < 3 {
+						_, err = testDb.Exec("UPDATE hook_deliveries SET created_at=$1 WHERE id=$2", util.NowMilli()-7200*1000, delivery.ID)
+						Expect(err).NotTo(HaveOccurred())
+					}
+				}
+
+				pruned, err := PruneHookDeliveries(testDb, hook.GameID, 3600)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pruned).To(Equal(2))
+
+				deliveries, err := GetHookDeliveries(testDb, hook.GameID, hook.PublicID, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deliveries).To(HaveLen(1))
+				Expect(deliveries[0].Attempt).To(Equal(3))
+			})
 		})
 
 		Describe("Remove Hook", func() {
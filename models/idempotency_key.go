@@ -0,0 +1,89 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"github.com/topfreegames/khan/util"
+)
+
+// IdempotencyKey stores the response of a mutating request so retries with the same key can replay it
+type IdempotencyKey struct {
+	ID          int64  `db:"id"`
+	GameID      string `db:"game_id"`
+	Key         string `db:"key"`
+	RequestHash string `db:"request_hash"`
+	// Status is zero while the first request with the key is still being processed
+	Status    int    `db:"status"`
+	Response  string `db:"response"`
+	CreatedAt int64  `db:"created_at"`
+	ExpiresAt int64  `db:"expires_at"`
+}
+
+// IsCompleted returns whether the response of the request with this key was already stored
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.Status != 0
+}
+
+// ReserveIdempotencyKey reserves a key for a request that is about to be processed.
+// If the key was already used and did not expire yet, it is returned instead and nothing is reserved
+func ReserveIdempotencyKey(db DB, gameID, key, requestHash string, ttl int) (*IdempotencyKey, error) {
+	now := util.NowMilli()
+	expiresAt := now + int64(ttl)*1000
+	query := `
+	INSERT INTO idempotency_keys(game_id, key, request_hash, status, response, created_at, expires_at)
+		VALUES($1, $2, $3, 0, '', $4, $5)
+	ON CONFLICT (game_id, key) DO UPDATE
+		SET request_hash=$3, status=0, response='', created_at=$4, expires_at=$5
+		WHERE idempotency_keys.expires_at <= $4
+	RETURNING id`
+	id, err := db.SelectInt(query, gameID, key, requestHash, now, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if id != 0 {
+		return nil, nil
+	}
+	return GetIdempotencyKey(db, gameID, key)
+}
+
+// GetIdempotencyKey returns a key that did not expire yet
+func GetIdempotencyKey(db DB, gameID, key string) (*IdempotencyKey, error) {
+	var keys []*IdempotencyKey
+	_, err := db.Select(
+		&keys,
+		"SELECT * FROM idempotency_keys WHERE game_id=$1 AND key=$2 AND expires_at>$3",
+		gameID, key, util.NowMilli(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, &ModelNotFoundError{"IdempotencyKey", key}
+	}
+	return keys[0], nil
+}
+
+// CompleteIdempotencyKey stores the response of the request that reserved the key
+func CompleteIdempotencyKey(db DB, gameID, key string, status int, response string) error {
+	_, err := db.Exec(
+		"UPDATE idempotency_keys SET status=$3, response=$4 WHERE game_id=$1 AND key=$2",
+		gameID, key, status, response,
+	)
+	return err
+}
+
+// ReleaseIdempotencyKey removes a reserved key, so the request can be retried with it
+func ReleaseIdempotencyKey(db DB, gameID, key string) error {
+	_, err := db.Exec("DELETE FROM idempotency_keys WHERE game_id=$1 AND key=$2", gameID, key)
+	return err
+}
+
+// PruneExpiredIdempotencyKeys deletes all keys that already expired
+func PruneExpiredIdempotencyKeys(db DB) (int, error) {
+	return runAndReturnRowsAffected("DELETE FROM idempotency_keys WHERE expires_at <= $1", db, util.NowMilli())
+}
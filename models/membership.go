@@ -19,24 +19,28 @@ var approveString = "approve"
 
 // Membership relates a player to a clan
 type Membership struct {
-	ID          int64         `db:"id"`
-	GameID      string        `db:"game_id"`
-	Level       string        `db:"membership_level"`
-	Approved    bool          `db:"approved"`
-	Denied      bool          `db:"denied"`
-	Banned      bool          `db:"banned"`
-	PlayerID    int64         `db:"player_id"`
-	ClanID      int64         `db:"clan_id"`
-	RequestorID int64         `db:"requestor_id"`
-	ApproverID  sql.NullInt64 `db:"approver_id"`
-	DenierID    sql.NullInt64 `db:"denier_id"`
-	CreatedAt   int64         `db:"created_at"`
-	UpdatedAt   int64         `db:"updated_at"`
-	DeletedBy   int64         `db:"deleted_by"`
-	DeletedAt   int64         `db:"deleted_at"`
-	ApprovedAt  int64         `db:"approved_at"`
-	DeniedAt    int64         `db:"denied_at"`
-	Message     string        `db:"message"`
+	ID           int64         `db:"id"`
+	GameID       string        `db:"game_id"`
+	Level        string        `db:"membership_level"`
+	Approved     bool          `db:"approved"`
+	Denied       bool          `db:"denied"`
+	Banned       bool          `db:"banned"`
+	PlayerID     int64         `db:"player_id"`
+	ClanID       int64         `db:"clan_id"`
+	RequestorID  int64         `db:"requestor_id"`
+	ApproverID   sql.NullInt64 `db:"approver_id"`
+	DenierID     sql.NullInt64 `db:"denier_id"`
+	CreatedAt    int64         `db:"created_at"`
+	UpdatedAt    int64         `db:"updated_at"`
+	DeletedBy    int64         `db:"deleted_by"`
+	DeletedAt    int64         `db:"deleted_at"`
+	ApprovedAt   int64         `db:"approved_at"`
+	DeniedAt     int64         `db:"denied_at"`
+	Message      string        `db:"message"`
+	BannerID     sql.NullInt64 `db:"banner_id"`
+	BannedAt     int64         `db:"banned_at"`
+	BanExpiresAt int64         `db:"ban_expires_at"`
+	ExpiresAt    int64         `db:"expires_at"`
 }
 
 // PreInsert populates fields before inserting a new clan
@@ -77,9 +81,11 @@ func GetValidMembershipByClanAndPlayerPublicID(db DB, gameID, clanPublicID, play
 		INNER JOIN players p ON p.game_id=$3 AND p.public_id=$2 AND p.id=m.player_id
 	WHERE
 		m.game_id=$3 AND
-		m.deleted_at=0`
+		m.deleted_at=0 AND
+		m.banned=false AND
+		(m.expires_at=0 OR m.expires_at>$4)`
 
-	_, err := db.Select(&memberships, query, clanPublicID, playerPublicID, gameID)
+	_, err := db.Select(&memberships, query, clanPublicID, playerPublicID, gameID, util.NowMilli())
 	if err != nil {
 		return nil, err
 	}
@@ -213,8 +219,9 @@ func GetNumberOfPendingInvites(db DB, player *Player) (int, error) {
 		FROM memberships m
 		WHERE
 			m.player_id = $1 AND m.player_id != m.requestor_id AND m.deleted_at = 0 AND
-			m.approved = false AND m.denied = false AND m.banned = false
-	`, player.ID)
+			m.approved = false AND m.denied = false AND m.banned = false AND
+			(m.expires_at = 0 OR m.expires_at > $2)
+	`, player.ID, util.NowMilli())
 	if err != nil {
 		return -1, nil
 	}
@@ -223,6 +230,12 @@ func GetNumberOfPendingInvites(db DB, player *Player) (int, error) {
 
 // ApproveOrDenyMembershipInvitation sets Membership.Approved to true or Membership.Denied to true
 func ApproveOrDenyMembershipInvitation(db DB, game *Game, gameID, playerPublicID, clanPublicID, action string) (*Membership, error) {
+	return auditMembershipAction(db, gameID, getApproveOrDenyAuditAction(action), playerPublicID, clanPublicID, playerPublicID, func() (*Membership, error) {
+		return approveOrDenyMembershipInvitation(db, game, gameID, playerPublicID, clanPublicID, action)
+	})
+}
+
+func approveOrDenyMembershipInvitation(db DB, game *Game, gameID, playerPublicID, clanPublicID, action string) (*Membership, error) {
 	membership, err := GetValidMembershipByClanAndPlayerPublicID(db, gameID, clanPublicID, playerPublicID)
 	if err != nil {
 		return nil, err
@@ -251,11 +264,17 @@ func ApproveOrDenyMembershipInvitation(db DB, game *Game, gameID, playerPublicID
 			return nil, reachedMaxMembersError
 		}
 	}
-	return approveOrDenyMembershipHelper(db, membership, action, player)
+	return approveOrDenyMembershipHelper(db, game, membership, action, player)
 }
 
 // ApproveOrDenyMembershipApplication sets Membership.Approved to true or Membership.Denied to true
 func ApproveOrDenyMembershipApplication(db DB, game *Game, gameID, playerPublicID, clanPublicID, requestorPublicID, action string) (*Membership, error) {
+	return auditMembershipAction(db, gameID, getApproveOrDenyAuditAction(action), requestorPublicID, clanPublicID, playerPublicID, func() (*Membership, error) {
+		return approveOrDenyMembershipApplication(db, game, gameID, playerPublicID, clanPublicID, requestorPublicID, action)
+	})
+}
+
+func approveOrDenyMembershipApplication(db DB, game *Game, gameID, playerPublicID, clanPublicID, requestorPublicID, action string) (*Membership, error) {
 	if playerPublicID == requestorPublicID {
 		return nil, &PlayerCannotPerformMembershipActionError{action, playerPublicID, clanPublicID, requestorPublicID}
 	}
@@ -299,18 +318,28 @@ func ApproveOrDenyMembershipApplication(db DB, game *Game, gameID, playerPublicI
 		if clanErr != nil {
 			return nil, &PlayerCannotPerformMembershipActionError{action, playerPublicID, clanPublicID, requestorPublicID}
 		}
-		return approveOrDenyMembershipHelper(db, membership, action, requestor)
+		return approveOrDenyMembershipHelper(db, game, membership, action, requestor)
 	}
 
 	levelInt := GetLevelIntByLevel(reqMembership.Level, game.MembershipLevels)
 	if !reqMembership.Approved || levelInt < game.MinLevelToAcceptApplication {
 		return nil, &PlayerCannotPerformMembershipActionError{action, playerPublicID, clanPublicID, requestorPublicID}
 	}
-	return approveOrDenyMembershipHelper(db, membership, action, requestor)
+	return approveOrDenyMembershipHelper(db, game, membership, action, requestor)
 }
 
 // CreateMembership creates a new membership
 func CreateMembership(db DB, game *Game, gameID, level, playerPublicID, clanPublicID, requestorPublicID, message string) (*Membership, error) {
+	action := MembershipInvitedAction
+	if requestorPublicID == playerPublicID {
+		action = MembershipAppliedAction
+	}
+	return auditMembershipAction(db, gameID, action, requestorPublicID, clanPublicID, playerPublicID, func() (*Membership, error) {
+		return createMembership(db, game, gameID, level, playerPublicID, clanPublicID, requestorPublicID, message)
+	})
+}
+
+func createMembership(db DB, game *Game, gameID, level, playerPublicID, clanPublicID, requestorPublicID, message string) (*Membership, error) {
 	if _, levelValid := game.MembershipLevels[level]; !levelValid {
 		return nil, &InvalidLevelForGameError{gameID, level}
 	}
@@ -341,6 +370,10 @@ func validateMembership(db DB, game *Game, membership *Membership, clan *Clan, p
 	playerID := int64(-1)
 	previousMembership := false
 	if membership != nil {
+		if isBanActive(membership) {
+			return -1, false, &PlayerBannedFromClanError{playerPublicID, clan.PublicID, membership.BanExpiresAt}
+		}
+
 		previousMembership = true
 		nowInMilliseconds := util.NowMilli()
 		applicationInOpenClan := requestorPublicID == playerPublicID && clan.AllowApplication && clan.AutoJoin
@@ -414,13 +447,15 @@ func applyForMembership(db DB, game *Game, membership *Membership, level string,
 	if reachedMaxMembersError != nil {
 		return nil, reachedMaxMembersError
 	}
+	expiresAt := pendingMembershipExpiresAt(game, true)
 	if previousMembership {
-		return updatePreviousMembershipHelper(db, membership, level, membership.PlayerID, message, clan.AutoJoin)
+		return updatePreviousMembershipHelper(db, game, membership, level, membership.PlayerID, message, clan.AutoJoin, expiresAt)
 	}
-	return createMembershipHelper(db, game.PublicID, level, playerID, clan.ID, playerID, message, clan.AutoJoin)
+	return createMembershipHelper(db, game, level, playerID, clan.ID, playerID, message, clan.AutoJoin, expiresAt)
 }
 
 func inviteMember(db DB, game *Game, membership *Membership, level string, clan *Clan, playerID int64, requestorPublicID, message string, previousMembership bool) (*Membership, error) {
+	expiresAt := pendingMembershipExpiresAt(game, false)
 	reqMembership, _ := GetValidMembershipByClanAndPlayerPublicID(db, game.PublicID, clan.PublicID, requestorPublicID)
 	if reqMembership == nil {
 		requestor, err := GetPlayerByPublicID(db, game.PublicID, requestorPublicID)
@@ -440,9 +475,9 @@ func inviteMember(db DB, game *Game, membership *Membership, level string, clan
 			return nil, reachedMaxMembersError
 		}
 		if previousMembership {
-			return updatePreviousMembershipHelper(db, membership, level, clan.OwnerID, message, false)
+			return updatePreviousMembershipHelper(db, game, membership, level, clan.OwnerID, message, false, expiresAt)
 		}
-		return createMembershipHelper(db, game.PublicID, level, playerID, clan.ID, clan.OwnerID, message, false)
+		return createMembershipHelper(db, game, level, playerID, clan.ID, clan.OwnerID, message, false, expiresAt)
 	}
 
 	reachedMaxMembersError := clanReachedMaxMemberships(db, game, nil, reqMembership.ClanID)
@@ -454,15 +489,25 @@ func inviteMember(db DB, game *Game, membership *Membership, level string, clan
 
 	if isValidMember(reqMembership) && levelInt >= game.MinLevelToCreateInvitation {
 		if previousMembership {
-			return updatePreviousMembershipHelper(db, membership, level, reqMembership.PlayerID, message, false)
+			return updatePreviousMembershipHelper(db, game, membership, level, reqMembership.PlayerID, message, false, expiresAt)
 		}
-		return createMembershipHelper(db, game.PublicID, level, playerID, reqMembership.ClanID, reqMembership.PlayerID, message, false)
+		return createMembershipHelper(db, game, level, playerID, reqMembership.ClanID, reqMembership.PlayerID, message, false, expiresAt)
 	}
 	return nil, &PlayerCannotCreateMembershipError{requestorPublicID, clan.PublicID}
 }
 
 // PromoteOrDemoteMember increments or decrements Membership.LevelInt by one
 func PromoteOrDemoteMember(db DB, game *Game, gameID, playerPublicID, clanPublicID, requestorPublicID, action string) (*Membership, error) {
+	auditAction := MembershipPromotedAction
+	if action == "demote" {
+		auditAction = MembershipDemotedAction
+	}
+	return auditMembershipAction(db, gameID, auditAction, requestorPublicID, clanPublicID, playerPublicID, func() (*Membership, error) {
+		return promoteOrDemoteMember(db, game, gameID, playerPublicID, clanPublicID, requestorPublicID, action)
+	})
+}
+
+func promoteOrDemoteMember(db DB, game *Game, gameID, playerPublicID, clanPublicID, requestorPublicID, action string) (*Membership, error) {
 	demote := action == "demote"
 	promote := action == "promote"
 
@@ -506,12 +551,18 @@ func PromoteOrDemoteMember(db DB, game *Game, gameID, playerPublicID, clanPublic
 
 // DeleteMembership soft deletes a membership
 func DeleteMembership(db DB, game *Game, gameID, playerPublicID, clanPublicID, requestorPublicID string) (*Membership, error) {
+	return auditMembershipAction(db, gameID, MembershipDeletedAction, requestorPublicID, clanPublicID, playerPublicID, func() (*Membership, error) {
+		return deleteMembership(db, game, gameID, playerPublicID, clanPublicID, requestorPublicID)
+	})
+}
+
+func deleteMembership(db DB, game *Game, gameID, playerPublicID, clanPublicID, requestorPublicID string) (*Membership, error) {
 	membership, err := GetValidMembershipByClanAndPlayerPublicID(db, gameID, clanPublicID, playerPublicID)
 	if err != nil {
 		return nil, err
 	}
 	if playerPublicID == requestorPublicID {
-		return deleteMembershipHelper(db, membership, membership.PlayerID)
+		return deleteMembershipHelper(db, game, membership, membership.PlayerID)
 	}
 	reqMembership, _ := GetValidMembershipByClanAndPlayerPublicID(db, gameID, clanPublicID, requestorPublicID)
 	if reqMembership == nil {
@@ -519,22 +570,147 @@ func DeleteMembership(db DB, game *Game, gameID, playerPublicID, clanPublicID, r
 		if clanErr != nil {
 			return nil, &PlayerCannotPerformMembershipActionError{"delete", playerPublicID, clanPublicID, requestorPublicID}
 		}
-		return deleteMembershipHelper(db, membership, clan.OwnerID)
+		return deleteMembershipHelper(db, game, membership, clan.OwnerID)
 	}
 
 	levelInt := GetLevelIntByLevel(membership.Level, game.MembershipLevels)
 	reqLevelInt := GetLevelIntByLevel(reqMembership.Level, game.MembershipLevels)
 	if isValidMember(reqMembership) && reqLevelInt >= game.MinLevelToRemoveMember && reqLevelInt >= levelInt+game.MinLevelOffsetToRemoveMember {
-		return deleteMembershipHelper(db, membership, reqMembership.PlayerID)
+		return deleteMembershipHelper(db, game, membership, reqMembership.PlayerID)
 	}
 	return nil, &PlayerCannotPerformMembershipActionError{"delete", playerPublicID, clanPublicID, requestorPublicID}
 }
 
+// BanMember bans a player from a clan, ending their membership, application or invitation if there is one.
+// The ban expires after duration seconds or never if duration is 0
+func BanMember(db DB, game *Game, gameID, playerPublicID, clanPublicID, requestorPublicID string, duration int) (*Membership, error) {
+	return auditMembershipAction(db, gameID, MembershipBannedAction, requestorPublicID, clanPublicID, playerPublicID, func() (*Membership, error) {
+		return banMember(db, game, gameID, playerPublicID, clanPublicID, requestorPublicID, duration)
+	})
+}
+
+func banMember(db DB, game *Game, gameID, playerPublicID, clanPublicID, requestorPublicID string, duration int) (*Membership, error) {
+	if playerPublicID == requestorPublicID {
+		return nil, &PlayerCannotPerformMembershipActionError{"ban", playerPublicID, clanPublicID, requestorPublicID}
+	}
+
+	clan, err := GetClanByPublicID(db, gameID, clanPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := GetPlayerByPublicID(db, gameID, playerPublicID)
+	if err != nil {
+		return nil, err
+	}
+	if player.ID == clan.OwnerID {
+		return nil, &PlayerCannotPerformMembershipActionError{"ban", playerPublicID, clanPublicID, requestorPublicID}
+	}
+
+	membership, _ := GetMembershipByClanAndPlayerPublicID(db, gameID, clanPublicID, playerPublicID)
+	bannerID, err := getMemberRemoverID(db, game, "ban", clan, membership, playerPublicID, requestorPublicID)
+	if err != nil {
+		return nil, err
+	}
+	return banMemberHelper(db, game, membership, player.ID, clan.ID, bannerID, duration)
+}
+
+// UnbanMember lifts the ban of a player from a clan
+func UnbanMember(db DB, game *Game, gameID, playerPublicID, clanPublicID, requestorPublicID string) (*Membership, error) {
+	return auditMembershipAction(db, gameID, MembershipUnbannedAction, requestorPublicID, clanPublicID, playerPublicID, func() (*Membership, error) {
+		return unbanMember(db, game, gameID, playerPublicID, clanPublicID, requestorPublicID)
+	})
+}
+
+func unbanMember(db DB, game *Game, gameID, playerPublicID, clanPublicID, requestorPublicID string) (*Membership, error) {
+	clan, err := GetClanByPublicID(db, gameID, clanPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	membership, err := GetMembershipByClanAndPlayerPublicID(db, gameID, clanPublicID, playerPublicID)
+	if err != nil || !isBanActive(membership) {
+		return nil, &ModelNotFoundError{"Ban", playerPublicID}
+	}
+
+	unbannerID, err := getMemberRemoverID(db, game, "unban", clan, nil, playerPublicID, requestorPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	membership.Banned = false
+	membership.DeletedAt = util.NowMilli()
+	membership.DeletedBy = unbannerID
+	_, err = db.Update(membership)
+	if err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+func getApproveOrDenyAuditAction(action string) string {
+	if action == approveString {
+		return MembershipApprovedAction
+	}
+	return MembershipDeniedAction
+}
+
 func isValidMember(membership *Membership) bool {
 	return membership.Approved && !membership.Denied
 }
 
-func approveOrDenyMembershipHelper(db DB, membership *Membership, action string, performer *Player) (*Membership, error) {
+// pendingMembershipExpiresAt returns when a new application or invitation expires, given the
+// pendingApplicationsExpiration and pendingInvitesExpiration game metadata (in seconds), or 0 if it never expires
+func pendingMembershipExpiresAt(game *Game, application bool) int64 {
+	key := "pendingInvitesExpiration"
+	if application {
+		key = "pendingApplicationsExpiration"
+	}
+
+	var expiration int64
+	switch v := game.Metadata[key].(type) {
+	case float64:
+		expiration = int64(v)
+	case int:
+		expiration = int64(v)
+	}
+	if expiration <= 0 {
+		return 0
+	}
+	return util.NowMilli() + expiration*1000
+}
+
+func isBanActive(membership *Membership) bool {
+	if !membership.Banned || membership.DeletedAt != 0 {
+		return false
+	}
+	return membership.BanExpiresAt == 0 || membership.BanExpiresAt > util.NowMilli()
+}
+
+// getMemberRemoverID returns the id of the requestor if they can remove the player from the clan, following
+// the same rules as DeleteMembership. Players without a valid membership are removed as the lowest level
+func getMemberRemoverID(db DB, game *Game, action string, clan *Clan, membership *Membership, playerPublicID, requestorPublicID string) (int64, error) {
+	reqMembership, _ := GetValidMembershipByClanAndPlayerPublicID(db, game.PublicID, clan.PublicID, requestorPublicID)
+	if reqMembership == nil {
+		requestor, err := GetPlayerByPublicID(db, game.PublicID, requestorPublicID)
+		if err != nil || requestor.ID != clan.OwnerID {
+			return -1, &PlayerCannotPerformMembershipActionError{action, playerPublicID, clan.PublicID, requestorPublicID}
+		}
+		return requestor.ID, nil
+	}
+
+	levelInt := game.MinMembershipLevel
+	if membership != nil && membership.DeletedAt == 0 && isValidMember(membership) {
+		levelInt = GetLevelIntByLevel(membership.Level, game.MembershipLevels)
+	}
+	reqLevelInt := GetLevelIntByLevel(reqMembership.Level, game.MembershipLevels)
+	if isValidMember(reqMembership) && reqLevelInt >= game.MinLevelToRemoveMember && reqLevelInt >= levelInt+game.MinLevelOffsetToRemoveMember {
+		return reqMembership.PlayerID, nil
+	}
+	return -1, &PlayerCannotPerformMembershipActionError{action, playerPublicID, clan.PublicID, requestorPublicID}
+}
+
+func approveOrDenyMembershipHelper(db DB, game *Game, membership *Membership, action string, performer *Player) (*Membership, error) {
 	approve := action == approveString
 	if approve {
 		membership.Approved = true
@@ -548,6 +724,7 @@ func approveOrDenyMembershipHelper(db DB, membership *Membership, action string,
 	} else {
 		return nil, &InvalidMembershipActionError{action}
 	}
+	membership.ExpiresAt = 0
 	_, err := db.Update(membership)
 	if err != nil {
 		return nil, err
@@ -557,7 +734,7 @@ func approveOrDenyMembershipHelper(db DB, membership *Membership, action string,
 		if err != nil {
 			return nil, err
 		}
-		err = UpdateClanMembershipCount(db, membership.ClanID)
+		err = UpdateClanMembershipCount(db, game, membership.ClanID)
 		if err != nil {
 			return nil, err
 		}
@@ -565,9 +742,9 @@ func approveOrDenyMembershipHelper(db DB, membership *Membership, action string,
 	return membership, nil
 }
 
-func createMembershipHelper(db DB, gameID, level string, playerID, clanID, requestorID int64, message string, approved bool) (*Membership, error) {
+func createMembershipHelper(db DB, game *Game, level string, playerID, clanID, requestorID int64, message string, approved bool, expiresAt int64) (*Membership, error) {
 	membership := &Membership{
-		GameID:      gameID,
+		GameID:      game.PublicID,
 		ClanID:      clanID,
 		PlayerID:    playerID,
 		RequestorID: requestorID,
@@ -579,6 +756,8 @@ func createMembershipHelper(db DB, gameID, level string, playerID, clanID, reque
 
 	if approved {
 		membership.ApproverID = sql.NullInt64{Int64: requestorID, Valid: true}
+	} else {
+		membership.ExpiresAt = expiresAt
 	}
 	err := db.Insert(membership)
 	if err != nil {
@@ -589,7 +768,7 @@ func createMembershipHelper(db DB, gameID, level string, playerID, clanID, reque
 		if err != nil {
 			return nil, err
 		}
-		err = UpdateClanMembershipCount(db, membership.ClanID)
+		err = UpdateClanMembershipCount(db, game, membership.ClanID)
 		if err != nil {
 			return nil, err
 		}
@@ -597,7 +776,7 @@ func createMembershipHelper(db DB, gameID, level string, playerID, clanID, reque
 	return membership, nil
 }
 
-func updatePreviousMembershipHelper(db DB, membership *Membership, level string, requestorID int64, message string, approved bool) (*Membership, error) {
+func updatePreviousMembershipHelper(db DB, game *Game, membership *Membership, level string, requestorID int64, message string, approved bool, expiresAt int64) (*Membership, error) {
 	membership.RequestorID = requestorID
 	membership.Level = level
 	membership.Approved = approved
@@ -606,8 +785,11 @@ func updatePreviousMembershipHelper(db DB, membership *Membership, level string,
 	membership.DeletedAt = 0
 	membership.DeletedBy = 0
 	membership.Message = message
+	membership.ExpiresAt = 0
 	if approved {
 		membership.ApproverID = sql.NullInt64{Int64: requestorID, Valid: true}
+	} else {
+		membership.ExpiresAt = expiresAt
 	}
 
 	_, err := db.Update(membership)
@@ -619,7 +801,7 @@ func updatePreviousMembershipHelper(db DB, membership *Membership, level string,
 		if err != nil {
 			return nil, err
 		}
-		err = UpdateClanMembershipCount(db, membership.ClanID)
+		err = UpdateClanMembershipCount(db, game, membership.ClanID)
 		if err != nil {
 			return nil, err
 		}
@@ -648,7 +830,7 @@ func promoteOrDemoteMemberHelper(db DB, membership *Membership, action string, l
 	return membership, nil
 }
 
-func deleteMembershipHelper(db DB, membership *Membership, deletedBy int64) (*Membership, error) {
+func deleteMembershipHelper(db DB, game *Game, membership *Membership, deletedBy int64) (*Membership, error) {
 	membershipWasApproved := membership.Approved
 	membership.DeletedAt = util.NowMilli()
 	membership.DeletedBy = deletedBy
@@ -667,7 +849,7 @@ func deleteMembershipHelper(db DB, membership *Membership, deletedBy int64) (*Me
 		if err != nil {
 			return nil, err
 		}
-		err = UpdateClanMembershipCount(db, membership.ClanID)
+		err = UpdateClanMembershipCount(db, game, membership.ClanID)
 		if err != nil {
 			return nil, err
 		}
@@ -675,6 +857,56 @@ func deleteMembershipHelper(db DB, membership *Membership, deletedBy int64) (*Me
 	return membership, err
 }
 
+func banMemberHelper(db DB, game *Game, membership *Membership, playerID, clanID, bannerID int64, duration int) (*Membership, error) {
+	membershipWasApproved := false
+	if membership == nil {
+		membership = &Membership{
+			GameID:      game.PublicID,
+			ClanID:      clanID,
+			PlayerID:    playerID,
+			RequestorID: bannerID,
+			Level:       GetLevelByLevelInt(game.MinMembershipLevel, game.MembershipLevels),
+		}
+	} else {
+		membershipWasApproved = membership.Approved && membership.DeletedAt == 0
+	}
+
+	membership.Approved = false
+	membership.Denied = false
+	membership.Banned = true
+	membership.DeletedAt = 0
+	membership.DeletedBy = 0
+	membership.ExpiresAt = 0
+	membership.BannerID = sql.NullInt64{Int64: bannerID, Valid: true}
+	membership.BannedAt = util.NowMilli()
+	membership.BanExpiresAt = 0
+	if duration > 0 {
+		membership.BanExpiresAt = membership.BannedAt + int64(duration)*1000
+	}
+
+	var err error
+	if membership.ID == 0 {
+		err = db.Insert(membership)
+	} else {
+		_, err = db.Update(membership)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if membershipWasApproved {
+		err = UpdatePlayerMembershipCount(db, membership.PlayerID)
+		if err != nil {
+			return nil, err
+		}
+		err = UpdateClanMembershipCount(db, game, membership.ClanID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return membership, nil
+}
+
 // GetLevelByLevelInt returns the level string given the level int
 func GetLevelByLevelInt(levelInt int, levels map[string]interface{}) string {
 	for k, v := range levels {
@@ -39,6 +39,113 @@ var _ = Describe("Membership Model", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(totalInvites).To(Equal(20))
 			})
+
+			It("Should not count expired invites", func() {
+				_, _, _, players, memberships, err := GetClanWithMemberships(testDb, 0, 0, 0, 1, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				memberships[0].ExpiresAt = util.NowMilli() - 1000
+				_, err = testDb.Update(memberships[0])
+				Expect(err).NotTo(HaveOccurred())
+
+				totalInvites, err := GetNumberOfPendingInvites(testDb, players[0])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(totalInvites).To(Equal(0))
+			})
+		})
+
+		Describe("Pending membership expiration", func() {
+			It("Should set the expiration of invitations from the game metadata", func() {
+				game, clan, owner, _, _, err := GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				game.Metadata = map[string]interface{}{"pendingInvitesExpiration": 3600}
+				_, err = testDb.Update(game)
+				Expect(err).NotTo(HaveOccurred())
+
+				player := PlayerFactory.MustCreateWithOption(map[string]interface{}{
+					"GameID": clan.GameID,
+				}).(*Player)
+				err = testDb.Insert(player)
+				Expect(err).NotTo(HaveOccurred())
+
+				membership, err := CreateMembership(testDb, game, game.PublicID, "Member", player.PublicID, clan.PublicID, owner.PublicID, "")
+				Expect(err).NotTo(HaveOccurred())
+
+				dbMembership, err := GetMembershipByID(testDb, membership.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbMembership.ExpiresAt).To(BeNumerically("~", util.NowMilli()+3600*1000, 1000))
+			})
+
+			It("Should not expire memberships if the game has no expiration", func() {
+				game, clan, _, _, _, err := GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				player := PlayerFactory.MustCreateWithOption(map[string]interface{}{
+					"GameID": clan.GameID,
+				}).(*Player)
+				err = testDb.Insert(player)
+				Expect(err).NotTo(HaveOccurred())
+
+				membership, err := CreateMembership(testDb, game, game.PublicID, "Member", player.PublicID, clan.PublicID, player.PublicID, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(membership.ExpiresAt).To(BeEquivalentTo(0))
+			})
+
+			It("Should not approve an expired invitation", func() {
+				game, clan, _, players, memberships, err := GetClanWithMemberships(testDb, 0, 0, 0, 1, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				memberships[0].ExpiresAt = util.NowMilli() - 1000
+				_, err = testDb.Update(memberships[0])
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = ApproveOrDenyMembershipInvitation(testDb, game, game.PublicID, players[0].PublicID, clan.PublicID, "approve")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Membership was not found with id: %s", players[0].PublicID)))
+			})
+
+			It("Should clear the expiration when an invitation is approved", func() {
+				game, clan, _, players, memberships, err := GetClanWithMemberships(testDb, 0, 0, 0, 1, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				memberships[0].ExpiresAt = util.NowMilli() + 60000
+				_, err = testDb.Update(memberships[0])
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = ApproveOrDenyMembershipInvitation(testDb, game, game.PublicID, players[0].PublicID, clan.PublicID, "approve")
+				Expect(err).NotTo(HaveOccurred())
+
+				dbMembership, err := GetMembershipByID(testDb, memberships[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbMembership.Approved).To(BeTrue())
+				Expect(dbMembership.ExpiresAt).To(BeEquivalentTo(0))
+			})
+
+			It("Should not list expired invitations in the clan or player details", func() {
+				_, clan, _, players, memberships, err := GetClanWithMemberships(testDb, 0, 0, 0, 2, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				memberships[0].ExpiresAt = util.NowMilli() - 1000
+				_, err = testDb.Update(memberships[0])
+				Expect(err).NotTo(HaveOccurred())
+
+				options := &GetClanDetailsOptions{
+					MaxPendingApplications:   100,
+					MaxPendingInvites:        100,
+					PendingApplicationsOrder: "newest",
+					PendingInvitesOrder:      "newest",
+				}
+				clanData, err := GetClanDetails(testDb, clan.GameID, clan, 1, options)
+				Expect(err).NotTo(HaveOccurred())
+				pendingInvites := clanData["memberships"].(map[string]interface{})["pendingInvites"].([]map[string]interface{})
+				Expect(pendingInvites).To(HaveLen(1))
+				Expect(pendingInvites[0]["player"].(map[string]interface{})["publicID"]).To(Equal(players[1].PublicID))
+
+				playerData, err := GetPlayerDetails(testDb, clan.GameID, players[0].PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(playerData["memberships"]).To(BeEmpty())
+			})
 		})
 		Describe("Create Membership", func() {
 			It("Should create a new Membership", func() {
@@ -2770,5 +2877,247 @@ var _ = Describe("Membership Model", func() {
 				Expect(err.Error()).To(Equal(fmt.Sprintf("Player %s cannot %s membership for player %s and clan %s", players[1].PublicID, "delete", players[0].PublicID, clan.PublicID)))
 			})
 		})
+
+		Describe("Should ban a player with BanMember", func() {
+			It("If requestor is the owner and player is a member", func() {
+				game, clan, owner, players, memberships, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				membership, err := BanMember(
+					testDb,
+					game,
+					clan.GameID,
+					players[0].PublicID,
+					clan.PublicID,
+					owner.PublicID,
+					0,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(membership.ID).To(Equal(memberships[0].ID))
+
+				dbMembership, err := GetMembershipByID(testDb, memberships[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbMembership.Banned).To(BeTrue())
+				Expect(dbMembership.Approved).To(BeFalse())
+				Expect(dbMembership.Denied).To(BeFalse())
+				Expect(dbMembership.DeletedAt).To(BeEquivalentTo(0))
+				Expect(dbMembership.BannerID.Int64).To(Equal(owner.ID))
+				Expect(dbMembership.BannedAt).To(BeNumerically(">", util.NowMilli()-1000))
+				Expect(dbMembership.BanExpiresAt).To(BeEquivalentTo(0))
+
+				dbPlayer, err := GetPlayerByID(testDb, players[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbPlayer.MembershipCount).To(Equal(0))
+
+				dbClan, err := GetClanByID(testDb, clan.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbClan.MembershipCount).To(Equal(1))
+			})
+
+			It("If player has no membership and ban has a duration", func() {
+				game, clan, owner, _, _, err := GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				player := PlayerFactory.MustCreateWithOption(map[string]interface{}{
+					"GameID": clan.GameID,
+				}).(*Player)
+				err = testDb.Insert(player)
+				Expect(err).NotTo(HaveOccurred())
+
+				membership, err := BanMember(
+					testDb,
+					game,
+					clan.GameID,
+					player.PublicID,
+					clan.PublicID,
+					owner.PublicID,
+					3600,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbMembership, err := GetMembershipByID(testDb, membership.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbMembership.PlayerID).To(Equal(player.ID))
+				Expect(dbMembership.ClanID).To(Equal(clan.ID))
+				Expect(dbMembership.Banned).To(BeTrue())
+				Expect(dbMembership.BanExpiresAt).To(Equal(dbMembership.BannedAt + 3600*1000))
+			})
+
+			It("If requestor has enough level and offset", func() {
+				game, clan, _, players, memberships, err := GetClanWithMemberships(testDb, 2, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				memberships[1].Level = "CoLeader"
+				_, err = testDb.Update(memberships[1])
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(
+					testDb,
+					game,
+					clan.GameID,
+					players[0].PublicID,
+					clan.PublicID,
+					players[1].PublicID,
+					0,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbMembership, err := GetMembershipByID(testDb, memberships[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbMembership.Banned).To(BeTrue())
+				Expect(dbMembership.BannerID.Int64).To(Equal(players[1].ID))
+			})
+
+			It("And the ban is listed in the clan details", func() {
+				game, clan, owner, players, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID, 60)
+				Expect(err).NotTo(HaveOccurred())
+
+				options := &GetClanDetailsOptions{
+					MaxPendingApplications:   100,
+					MaxPendingInvites:        100,
+					PendingApplicationsOrder: "newest",
+					PendingInvitesOrder:      "newest",
+				}
+				clanData, err := GetClanDetails(testDb, clan.GameID, clan, 1, options)
+				Expect(err).NotTo(HaveOccurred())
+
+				banned := clanData["memberships"].(map[string]interface{})["banned"].([]map[string]interface{})
+				Expect(len(banned)).To(Equal(1))
+				Expect(banned[0]["player"].(map[string]interface{})["publicID"]).To(Equal(players[0].PublicID))
+				Expect(banned[0]["banExpiresAt"]).To(BeNumerically(">", util.NowMilli()))
+				banner := banned[0]["player"].(map[string]interface{})["banner"].(map[string]interface{})
+				Expect(banner["publicID"]).To(Equal(owner.PublicID))
+			})
+		})
+
+		Describe("Should not ban a player with BanMember", func() {
+			It("If requestor is the player", func() {
+				game, clan, _, players, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, players[0].PublicID, 0)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Player %s cannot %s membership for player %s and clan %s", players[0].PublicID, "ban", players[0].PublicID, clan.PublicID)))
+			})
+
+			It("If player is the clan owner", func() {
+				game, clan, owner, players, memberships, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				memberships[0].Level = "CoLeader"
+				_, err = testDb.Update(memberships[0])
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, owner.PublicID, clan.PublicID, players[0].PublicID, 0)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Player %s cannot %s membership for player %s and clan %s", players[0].PublicID, "ban", owner.PublicID, clan.PublicID)))
+			})
+
+			It("If requestor does not have enough level", func() {
+				game, clan, _, players, _, err := GetClanWithMemberships(testDb, 2, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, players[1].PublicID, 0)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Player %s cannot %s membership for player %s and clan %s", players[1].PublicID, "ban", players[0].PublicID, clan.PublicID)))
+			})
+		})
+
+		Describe("Banned players", func() {
+			It("Should not be able to apply for membership", func() {
+				game, clan, owner, players, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				clan.AllowApplication = true
+				_, err = testDb.Update(clan)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID, 0)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = CreateMembership(testDb, game, clan.GameID, "Member", players[0].PublicID, clan.PublicID, players[0].PublicID, "Please accept me")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Player %s is banned from clan %s.", players[0].PublicID, clan.PublicID)))
+			})
+
+			It("Should not be invited", func() {
+				game, clan, owner, players, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				membership, err := BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID, 3600)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = CreateMembership(testDb, game, clan.GameID, "Member", players[0].PublicID, clan.PublicID, owner.PublicID, "")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Player %s is banned from clan %s until %d.", players[0].PublicID, clan.PublicID, membership.BanExpiresAt)))
+			})
+
+			It("Should be able to apply again after the ban expires", func() {
+				game, clan, owner, players, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				clan.AllowApplication = true
+				_, err = testDb.Update(clan)
+				Expect(err).NotTo(HaveOccurred())
+
+				membership, err := BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID, 60)
+				Expect(err).NotTo(HaveOccurred())
+				membership.BanExpiresAt = util.NowMilli() - 1000
+				_, err = testDb.Update(membership)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = CreateMembership(testDb, game, clan.GameID, "Member", players[0].PublicID, clan.PublicID, players[0].PublicID, "Please accept me")
+				Expect(err).NotTo(HaveOccurred())
+
+				dbMembership, err := GetMembershipByID(testDb, membership.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbMembership.Banned).To(BeFalse())
+			})
+		})
+
+		Describe("Should unban a player with UnbanMember", func() {
+			It("If requestor is the owner", func() {
+				game, clan, owner, players, memberships, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID, 0)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = UnbanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbMembership, err := GetMembershipByID(testDb, memberships[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbMembership.Banned).To(BeFalse())
+				Expect(dbMembership.DeletedBy).To(Equal(owner.ID))
+				Expect(dbMembership.DeletedAt).To(BeNumerically(">", util.NowMilli()-1000))
+			})
+		})
+
+		Describe("Should not unban a player with UnbanMember", func() {
+			It("If player is not banned", func() {
+				game, clan, owner, players, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = UnbanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Ban was not found with id: %s", players[0].PublicID)))
+			})
+
+			It("If requestor does not have enough level", func() {
+				game, clan, owner, players, _, err := GetClanWithMemberships(testDb, 2, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID, 0)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = UnbanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, players[1].PublicID)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Player %s cannot %s membership for player %s and clan %s", players[1].PublicID, "unban", players[0].PublicID, clan.PublicID)))
+			})
+		})
 	})
 })
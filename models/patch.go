@@ -0,0 +1,271 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/topfreegames/khan/util"
+)
+
+// MetadataPatch describes a partial update of the metadata of a game, player or clan
+type MetadataPatch struct {
+	// Merge is a JSON merge patch (RFC 7396) applied to the metadata
+	Merge map[string]interface{}
+	// Increment maps top-level metadata fields to the amount they are atomically incremented by
+	Increment map[string]float64
+}
+
+// PlayerPatch describes a partial update of a player. Nil fields are not changed
+type PlayerPatch struct {
+	Name     *string
+	Metadata *MetadataPatch
+}
+
+// ClanPatch describes a partial update of a clan. Nil fields are not changed
+type ClanPatch struct {
+	Name             *string
+	AllowApplication *bool
+	AutoJoin         *bool
+	Metadata         *MetadataPatch
+}
+
+// GamePatch describes a partial update of a game. Nil fields are not changed
+type GamePatch struct {
+	Name     *string
+	Metadata *MetadataPatch
+}
+
+// patchQuery accumulates the SET clauses, conditions and arguments of a patch UPDATE query
+type patchQuery struct {
+	sets       []string
+	conditions []string
+	args       []interface{}
+}
+
+func (q *patchQuery) arg(value interface{}) string {
+	q.args = append(q.args, value)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+func (q *patchQuery) set(column string, value interface{}) {
+	q.sets = append(q.sets, fmt.Sprintf("%s=%s", column, q.arg(value)))
+}
+
+func (q *patchQuery) where(condition string, values ...interface{}) {
+	placeholders := make([]interface{}, len(values))
+	for i, value := range values {
+		placeholders[i] = q.arg(value)
+	}
+	q.conditions = append(q.conditions, fmt.Sprintf(condition, placeholders...))
+}
+
+// setMetadata sets the metadata column to the result of applying the patch to its current value and
+// requires all incremented fields to be either numbers or missing, so they can't be overwritten.
+// Increments are applied to the merged metadata, so a field that is both merged and incremented
+// is incremented from its merged value
+func (q *patchQuery) setMetadata(patch *MetadataPatch) error {
+	if patch == nil {
+		return nil
+	}
+
+	expression := "metadata"
+	if patch.Merge != nil {
+		merge, err := json.Marshal(patch.Merge)
+		if err != nil {
+			return err
+		}
+		expression = fmt.Sprintf("jsonb_merge_patch(%s, %s::jsonb)", expression, q.arg(string(merge)))
+	}
+
+	if len(patch.Increment) > 0 {
+		fields := make([]string, 0, len(patch.Increment))
+		for field := range patch.Increment {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		merged := expression
+		increments := make([]string, 0, len(fields))
+		for _, field := range fields {
+			key := q.arg(field)
+			increments = append(increments, fmt.Sprintf(
+				"%s::text, COALESCE((%s->>(%s::text))::numeric, 0) + %s::numeric",
+				key, merged, key, q.arg(patch.Increment[field]),
+			))
+			q.conditions = append(q.conditions, fmt.Sprintf(
+				"COALESCE(jsonb_typeof(%s->(%s::text)), 'number')='number'", merged, key,
+			))
+		}
+		expression = fmt.Sprintf("%s || jsonb_build_object(%s)", expression, strings.Join(increments, ", "))
+	}
+
+	q.sets = append(q.sets, fmt.Sprintf("metadata=%s", expression))
+	return nil
+}
+
+// exec runs the UPDATE query on the given table and returns the ids of the updated rows
+func (q *patchQuery) exec(db DB, table string) ([]int64, error) {
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s WHERE %s RETURNING id",
+		table, strings.Join(q.sets, ", "), strings.Join(q.conditions, " AND "),
+	)
+	var ids []int64
+	_, err := db.Select(&ids, query, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// getMetadataIncrementFields returns the sorted incremented fields of a patch
+func getMetadataIncrementFields(patch *MetadataPatch) []string {
+	fields := []string{}
+	if patch != nil {
+		for field := range patch.Increment {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// PatchPlayer partially updates an existing player, only if its current version is the expected one.
+// If version is 0 the player is updated regardless of its version
+func PatchPlayer(db DB, game *Game, gameID, publicID string, patch *PlayerPatch, version int64) (*Player, error) {
+	q := &patchQuery{}
+	q.where("game_id=%s", gameID)
+	q.where("public_id=%s", publicID)
+	if version != 0 {
+		q.where("version=%s", version)
+	}
+	if patch.Name != nil {
+		q.set("name", *patch.Name)
+	}
+	if err := q.setMetadata(patch.Metadata); err != nil {
+		return nil, err
+	}
+	q.set("updated_at", util.NowMilli())
+	q.sets = append(q.sets, "version=version+1")
+
+	ids, err := q.exec(db, "players")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		player, err := GetPlayerByPublicID(db, gameID, publicID)
+		if err != nil {
+			return nil, err
+		}
+		if version != 0 && player.Version != version {
+			return nil, &VersionConflictError{"Player", publicID, player.Version}
+		}
+		return nil, &InvalidMetadataIncrementError{"Player", publicID, getMetadataIncrementFields(patch.Metadata)}
+	}
+	if patch.Metadata != nil {
+		err = RefreshPlayerClanScores(db, game, ids[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return GetPlayerByID(db, ids[0])
+}
+
+// PatchClan partially updates an existing clan, only if its current version is the expected one.
+// If version is 0 the clan is updated regardless of its version. The patch is applied with a custom
+// query inside the caller's transaction, so the caller must
+// index the clan into ElasticSearch and MongoDB, as clan.PostUpdate() does, once it commits
+func PatchClan(db DB, game *Game, gameID, publicID, ownerPublicID string, patch *ClanPatch, version int64) (*Clan, error) {
+	clan, err := GetClanByPublicIDAndOwnerPublicID(db, gameID, publicID, ownerPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &patchQuery{}
+	q.where("id=%s", clan.ID)
+	if version != 0 {
+		q.where("version=%s", version)
+	}
+	if patch.Name != nil {
+		q.set("name", *patch.Name)
+	}
+	if patch.AllowApplication != nil {
+		q.set("allow_application", *patch.AllowApplication)
+	}
+	if patch.AutoJoin != nil {
+		q.set("auto_join", *patch.AutoJoin)
+	}
+	if err := q.setMetadata(patch.Metadata); err != nil {
+		return nil, err
+	}
+	q.set("updated_at", util.NowMilli())
+	q.sets = append(q.sets, "version=version+1")
+
+	ids, err := q.exec(db, "clans")
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := GetClanByID(db, clan.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		if version != 0 && current.Version != version {
+			return nil, &VersionConflictError{"Clan", publicID, current.Version}
+		}
+		return nil, &InvalidMetadataIncrementError{"Clan", publicID, getMetadataIncrementFields(patch.Metadata)}
+	}
+
+	err = RefreshClanScores(db, game, current.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = createAuditEntry(
+		db, gameID, ClanUpdatedAction, ownerPublicID, publicID, ownerPublicID,
+		getClanAuditValues(clan), getClanAuditValues(current),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+// PatchGame partially updates an existing game
+func PatchGame(db DB, publicID string, patch *GamePatch) (*Game, error) {
+	q := &patchQuery{}
+	q.where("public_id=%s", publicID)
+	if patch.Name != nil {
+		q.set("name", *patch.Name)
+	}
+	if err := q.setMetadata(patch.Metadata); err != nil {
+		return nil, err
+	}
+	q.set("updated_at", util.NowMilli())
+
+	ids, err := q.exec(db, "games")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		_, err := GetGameByPublicID(db, publicID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &InvalidMetadataIncrementError{"Game", publicID, getMetadataIncrementFields(patch.Metadata)}
+	}
+
+	return GetGameByID(db, int(ids[0]))
+}
@@ -0,0 +1,202 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Patch Model", func() {
+	var testDb DB
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Patch Player", func() {
+		It("Should apply a merge patch to the player metadata", func() {
+			game, player, err := CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = UpdatePlayer(testDb, game, player.GameID, player.PublicID, player.Name, map[string]interface{}{
+				"a": "b",
+				"c": map[string]interface{}{"d": "e", "f": "g"},
+				"h": []interface{}{"i"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			name := "new name"
+			updPlayer, err := PatchPlayer(testDb, game, player.GameID, player.PublicID, &PlayerPatch{
+				Name: &name,
+				Metadata: &MetadataPatch{
+					Merge: map[string]interface{}{
+						"a": nil,
+						"c": map[string]interface{}{"f": nil, "j": "k"},
+						"h": []interface{}{"l"},
+					},
+				},
+			}, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updPlayer.ID).To(Equal(player.ID))
+			Expect(updPlayer.Name).To(Equal(name))
+			Expect(updPlayer.Metadata).To(Equal(map[string]interface{}{
+				"c": map[string]interface{}{"d": "e", "j": "k"},
+				"h": []interface{}{"l"},
+			}))
+		})
+
+		It("Should not lose concurrent increments", func() {
+			game, player, err := CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			var wg sync.WaitGroup
+			errs := make(chan error, 10)
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := PatchPlayer(testDb, game, player.GameID, player.PublicID, &PlayerPatch{
+						Metadata: &MetadataPatch{Increment: map[string]float64{"score": 1.5}},
+					}, 0)
+					errs <- err
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			dbPlayer, err := GetPlayerByPublicID(testDb, player.GameID, player.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbPlayer.Metadata["score"]).To(BeEquivalentTo(15))
+			Expect(dbPlayer.Version).To(Equal(player.Version + 10))
+		})
+
+		It("Should not patch the player if version does not match", func() {
+			game, player, err := CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			name := "new name"
+			_, err = PatchPlayer(testDb, game, player.GameID, player.PublicID, &PlayerPatch{Name: &name}, player.Version+1)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&VersionConflictError{}))
+
+			dbPlayer, err := GetPlayerByPublicID(testDb, player.GameID, player.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbPlayer.Name).To(Equal(player.Name))
+		})
+
+		It("Should not patch a player that does not exist", func() {
+			game, player, err := CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			name := "new name"
+			_, err = PatchPlayer(testDb, game, player.GameID, "invalid-player", &PlayerPatch{Name: &name}, 0)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Player was not found with id: invalid-player"))
+		})
+	})
+
+	Describe("Patch Clan", func() {
+		It("Should patch only the given clan fields", func() {
+			player, clans, err := GetTestClans(testDb, "", "", 1)
+			Expect(err).NotTo(HaveOccurred())
+			clan, err := GetClanByPublicID(testDb, clans[0].GameID, clans[0].PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			game, err := GetGameByPublicID(testDb, clan.GameID)
+			Expect(err).NotTo(HaveOccurred())
+
+			allowApplication := !clan.AllowApplication
+			updClan, err := PatchClan(testDb, game, clan.GameID, clan.PublicID, player.PublicID, &ClanPatch{
+				AllowApplication: &allowApplication,
+				Metadata: &MetadataPatch{
+					Merge:     map[string]interface{}{"new": "field"},
+					Increment: map[string]float64{"score": -2},
+				},
+			}, clan.Version)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updClan.Name).To(Equal(clan.Name))
+			Expect(updClan.AutoJoin).To(Equal(clan.AutoJoin))
+			Expect(updClan.AllowApplication).To(Equal(allowApplication))
+			Expect(updClan.Metadata["new"]).To(Equal("field"))
+			Expect(updClan.Metadata["score"]).To(BeEquivalentTo(-2))
+			Expect(updClan.Version).To(Equal(clan.Version + 1))
+		})
+
+		It("Should increment metadata fields from their merged value", func() {
+			player, clans, err := GetTestClans(testDb, "", "", 1)
+			Expect(err).NotTo(HaveOccurred())
+			clan := clans[0]
+			game, err := GetGameByPublicID(testDb, clan.GameID)
+			Expect(err).NotTo(HaveOccurred())
+
+			updClan, err := PatchClan(testDb, game, clan.GameID, clan.PublicID, player.PublicID, &ClanPatch{
+				Metadata: &MetadataPatch{
+					Merge:     map[string]interface{}{"score": 5},
+					Increment: map[string]float64{"score": 2},
+				},
+			}, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updClan.Metadata["score"]).To(BeEquivalentTo(7))
+
+			_, err = PatchClan(testDb, game, clan.GameID, clan.PublicID, player.PublicID, &ClanPatch{
+				Metadata: &MetadataPatch{
+					Merge:     map[string]interface{}{"score": "high"},
+					Increment: map[string]float64{"score": 2},
+				},
+			}, 0)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&InvalidMetadataIncrementError{}))
+		})
+
+		It("Should not increment metadata fields that are not numbers", func() {
+			player, clans, err := GetTestClans(testDb, "", "", 1)
+			Expect(err).NotTo(HaveOccurred())
+			clan := clans[0]
+			game, err := GetGameByPublicID(testDb, clan.GameID)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = UpdateClan(
+				testDb, game, clan.GameID, clan.PublicID, clan.Name, player.PublicID,
+				map[string]interface{}{"score": map[string]interface{}{}}, clan.AllowApplication, clan.AutoJoin,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = PatchClan(testDb, game, clan.GameID, clan.PublicID, player.PublicID, &ClanPatch{
+				Metadata: &MetadataPatch{Increment: map[string]float64{"score": 1}},
+			}, 0)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&InvalidMetadataIncrementError{}))
+		})
+	})
+
+	Describe("Patch Game", func() {
+		It("Should patch the game name and metadata", func() {
+			game := GameFactory.MustCreateWithOption(map[string]interface{}{
+				"Metadata": map[string]interface{}{"x": "a"},
+			}).(*Game)
+			err := testDb.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			name := "new name"
+			updGame, err := PatchGame(testDb, game.PublicID, &GamePatch{
+				Name:     &name,
+				Metadata: &MetadataPatch{Merge: map[string]interface{}{"y": "b"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updGame.Name).To(Equal(name))
+			Expect(updGame.Metadata).To(Equal(map[string]interface{}{"x": "a", "y": "b"}))
+			Expect(updGame.MaxMembers).To(Equal(game.MaxMembers))
+		})
+	})
+})
@@ -10,7 +10,10 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/spf13/viper"
 	"github.com/topfreegames/khan/util"
 
 	"github.com/go-gorp/gorp"
@@ -27,18 +30,21 @@ type Player struct {
 	OwnershipCount  int                    `db:"ownership_count"`
 	CreatedAt       int64                  `db:"created_at"`
 	UpdatedAt       int64                  `db:"updated_at"`
+	Version         int64                  `db:"version"`
 }
 
 // PreInsert populates fields before inserting a new player
 func (p *Player) PreInsert(s gorp.SqlExecutor) error {
 	p.CreatedAt = util.NowMilli()
 	p.UpdatedAt = p.CreatedAt
+	p.Version = 1
 	return nil
 }
 
 // PreUpdate populates fields before updating a player
 func (p *Player) PreUpdate(s gorp.SqlExecutor) error {
 	p.UpdatedAt = util.NowMilli()
+	p.Version++
 	return nil
 }
 
@@ -89,7 +95,7 @@ func UpdatePlayerOwnershipCount(db DB, id int64) error {
 	FROM (
 		SELECT COUNT(*) as count
 		FROM clans c
-		WHERE c.owner_id = $1
+		WHERE c.owner_id = $1 AND c.deleted_at = 0
 	) as ownership
 	WHERE players.id=$1
 	`
@@ -135,8 +141,9 @@ func GetPlayerByPublicID(db DB, gameID string, publicID string) (*Player, error)
 	return players[0], nil
 }
 
-// CreatePlayer creates a new player
-func CreatePlayer(db DB, gameID, publicID, name string, metadata map[string]interface{}, upsert bool) (*Player, error) {
+// CreatePlayer creates a new player. When upserting, the rankings of the player's clans in the given
+// game are refreshed, otherwise the game may be nil
+func CreatePlayer(db DB, game *Game, gameID, publicID, name string, metadata map[string]interface{}, upsert bool) (*Player, error) {
 	metadataJSON, err := json.Marshal(metadata)
 	if err != nil {
 		return nil, err
@@ -146,7 +153,7 @@ func CreatePlayer(db DB, gameID, publicID, name string, metadata map[string]inte
 			INSERT INTO players(game_id, public_id, name, metadata, created_at, updated_at)
 						VALUES($1, $2, $3, $4, $5, $5)%s RETURNING id`
 	onConflict := ` ON CONFLICT (game_id, public_id)
-			DO UPDATE set name=$3, metadata=$4, updated_at=$5
+			DO UPDATE set name=$3, metadata=$4, updated_at=$5, version=players.version+1
 			WHERE players.game_id=$1 and players.public_id=$2`
 
 	if upsert {
@@ -161,12 +168,54 @@ func CreatePlayer(db DB, gameID, publicID, name string, metadata map[string]inte
 	if err != nil {
 		return nil, err
 	}
+	if upsert {
+		err = RefreshPlayerClanScores(db, game, lastID)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return GetPlayerByID(db, lastID)
 }
 
 // UpdatePlayer updates an existing player
-func UpdatePlayer(db DB, gameID, publicID, name string, metadata map[string]interface{}) (*Player, error) {
-	return CreatePlayer(db, gameID, publicID, name, metadata, true)
+func UpdatePlayer(db DB, game *Game, gameID, publicID, name string, metadata map[string]interface{}) (*Player, error) {
+	return CreatePlayer(db, game, gameID, publicID, name, metadata, true)
+}
+
+// UpdatePlayerWithVersion updates an existing player only if its current version is the expected one.
+// If version is 0 the player is upserted regardless of its version, as in UpdatePlayer
+func UpdatePlayerWithVersion(db DB, game *Game, gameID, publicID, name string, metadata map[string]interface{}, version int64) (*Player, error) {
+	if version == 0 {
+		return UpdatePlayer(db, game, gameID, publicID, name, metadata)
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+	UPDATE players SET name=$3, metadata=$4, updated_at=$5, version=version+1
+	WHERE game_id=$1 AND public_id=$2 AND version=$6
+	RETURNING id`
+	var ids []int64
+	_, err = db.Select(&ids, query, gameID, publicID, name, metadataJSON, util.NowMilli(), version)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		player, err := GetPlayerByPublicID(db, gameID, publicID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &VersionConflictError{"Player", publicID, player.Version}
+	}
+	err = RefreshPlayerClanScores(db, game, ids[0])
+	if err != nil {
+		return nil, err
+	}
+	return GetPlayerByID(db, ids[0])
 }
 
 // GetPlayerOwnershipDetails returns detailed information about a player owned clans
@@ -174,7 +223,7 @@ func GetPlayerOwnershipDetails(db DB, gameID, publicID string) (map[string]inter
 	query := `
 	SELECT c.*
 	FROM players p
-	INNER JOIN clans c ON c.owner_id=p.id
+	INNER JOIN clans c ON c.owner_id=p.id AND c.deleted_at=0
 	WHERE p.game_id=$1 AND p.public_id=$2 
 	`
 
@@ -240,7 +289,7 @@ func GetPlayerMembershipDetails(db DB, gameID, publicID string) (map[string]inte
 	query := `
 	SELECT
 		p.id PlayerID, p.name PlayerName, p.metadata PlayerMetadata, p.public_id PlayerPublicID,
-		p.created_at PlayerCreatedAt, p.updated_at PlayerUpdatedAt,
+		p.created_at PlayerCreatedAt, p.updated_at PlayerUpdatedAt, p.version PlayerVersion,
 		m.membership_level MembershipLevel,
 		m.approved MembershipApproved, m.denied MembershipDenied, m.banned MembershipBanned,
 		c.public_id ClanPublicID, c.name ClanName, c.metadata DBClanMetadata, c.owner_id ClanOwnerID,
@@ -253,13 +302,14 @@ func GetPlayerMembershipDetails(db DB, gameID, publicID string) (map[string]inte
 		m.updated_at MembershipUpdatedAt,
 		m.deleted_at MembershipDeletedAt,
 		m.approved_at MembershipApprovedAt, m.denied_at MembershipDeniedAt,
+		m.expires_at MembershipExpiresAt,
 		m.message MembershipMessage,
 		d.name DeletedByName, d.public_id DeletedByPublicID
 	FROM players p
 		LEFT OUTER JOIN (
 			SELECT * FROM memberships im WHERE im.player_id=$2 AND (im.approved=true OR im.denied=true OR im.banned=true)
 			UNION
-			(SELECT * FROM memberships im WHERE im.player_id=$2 AND im.deleted_at=0 AND im.approved=false AND im.denied=false AND im.banned=false ORDER BY updated_at DESC LIMIT $3)
+			(SELECT * FROM memberships im WHERE im.player_id=$2 AND im.deleted_at=0 AND im.approved=false AND im.denied=false AND im.banned=false AND (im.expires_at=0 OR im.expires_at>$4) ORDER BY updated_at DESC LIMIT $3)
 		) m ON p.id = m.player_id
 		LEFT OUTER JOIN clans c on c.id=m.clan_id
 		LEFT OUTER JOIN players d on d.id=m.deleted_by
@@ -270,7 +320,7 @@ func GetPlayerMembershipDetails(db DB, gameID, publicID string) (map[string]inte
 		p.game_id=$1 and p.id=$2`
 
 	var details []playerDetailsDAO
-	_, err = db.Select(&details, query, gameID, player.ID, 5)
+	_, err = db.Select(&details, query, gameID, player.ID, 5, util.NowMilli())
 	if err != nil {
 		return nil, err
 	}
@@ -286,6 +336,7 @@ func GetPlayerMembershipDetails(db DB, gameID, publicID string) (map[string]inte
 	result["publicID"] = details[0].PlayerPublicID
 	result["createdAt"] = details[0].PlayerCreatedAt
 	result["updatedAt"] = details[0].PlayerUpdatedAt
+	result["version"] = details[0].PlayerVersion
 
 	if details[0].MembershipLevel.Valid {
 		// Player has memberships
@@ -354,6 +405,167 @@ func GetPlayerMembershipDetails(db DB, gameID, publicID string) (map[string]inte
 	return result, nil
 }
 
+// GetPlayerMembershipsOptions holds the pagination and filtering options of GetPlayerMembershipsPage()
+type GetPlayerMembershipsOptions struct {
+	Limit        int
+	Cursor       string
+	Statuses     []string
+	ClanPublicID string
+	Order        string
+}
+
+// PlayerMembershipsDefaultLimitKey is string constant
+const PlayerMembershipsDefaultLimitKey string = "playerMemberships.defaultLimit"
+
+// PlayerMembershipsMaxLimitKey is string constant
+const PlayerMembershipsMaxLimitKey string = "playerMemberships.maxLimit"
+
+// membershipStatusConditions holds the SQL condition of each membership status, where :now is the current time
+var membershipStatusConditions = map[string]string{
+	"approved":           "m.deleted_at=0 AND m.approved=true",
+	"pendingApplication": "m.deleted_at=0 AND m.approved=false AND m.denied=false AND m.banned=false AND m.requestor_id=m.player_id AND (m.expires_at=0 OR m.expires_at>:now)",
+	"pendingInvite":      "m.deleted_at=0 AND m.approved=false AND m.denied=false AND m.banned=false AND m.requestor_id<>m.player_id AND (m.expires_at=0 OR m.expires_at>:now)",
+	"expired":            "m.deleted_at=0 AND m.approved=false AND m.denied=false AND m.banned=false AND m.expires_at>0 AND m.expires_at<=:now",
+	"denied":             "m.deleted_at=0 AND m.denied=true",
+	"banned":             "m.banned=true",
+	"deleted":            "m.deleted_at>0 AND m.banned=false",
+}
+
+// IsValidMembershipStatus returns whether memberships can be filtered by the given status
+func IsValidMembershipStatus(status string) bool {
+	_, ok := membershipStatusConditions[status]
+	return ok
+}
+
+// NewDefaultGetPlayerMembershipsOptions returns a new options structure with default values for GetPlayerMembershipsPage()
+func NewDefaultGetPlayerMembershipsOptions(config *viper.Viper) *GetPlayerMembershipsOptions {
+	return &GetPlayerMembershipsOptions{
+		Limit: config.GetInt(PlayerMembershipsDefaultLimitKey),
+		Order: Descending,
+	}
+}
+
+func getMembershipStatus(detail *playerDetailsDAO, now int64) string {
+	expiresAt := nullOrInt(detail.MembershipExpiresAt)
+	switch {
+	case nullOrBool(detail.MembershipBanned):
+		return "banned"
+	case nullOrInt(detail.MembershipDeletedAt) > 0:
+		return "deleted"
+	case nullOrBool(detail.MembershipApproved):
+		return "approved"
+	case nullOrBool(detail.MembershipDenied):
+		return "denied"
+	case expiresAt > 0 && expiresAt <= now:
+		return "expired"
+	case nullOrString(detail.RequestorPublicID) == detail.PlayerPublicID:
+		return "pendingApplication"
+	default:
+		return "pendingInvite"
+	}
+}
+
+// GetPlayerMembershipsPage returns a page of the memberships of a player, newest updates first by default,
+// filtered by status and clan, and the cursor of the next page ("" if there are no more memberships)
+func GetPlayerMembershipsPage(db DB, gameID, publicID string, options *GetPlayerMembershipsOptions) (map[string]interface{}, error) {
+	player, err := GetPlayerByPublicID(db, gameID, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []interface{}{player.ID}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	now := util.NowMilli()
+	conditions := []string{"m.player_id=$1"}
+	if len(options.Statuses) > 0 {
+		nowArg := arg(now)
+		statusConditions := make([]string, len(options.Statuses))
+		for i, status := range options.Statuses {
+			statusConditions[i] = fmt.Sprintf("(%s)", strings.Replace(membershipStatusConditions[status], ":now", nowArg, -1))
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s)", strings.Join(statusConditions, " OR ")))
+	}
+	if options.ClanPublicID != "" {
+		conditions = append(conditions, fmt.Sprintf("c.public_id=%s", arg(options.ClanPublicID)))
+	}
+
+	direction, comparison := "DESC", "<"
+	if options.Order == Ascending {
+		direction, comparison = "ASC", ">"
+	}
+	if options.Cursor != "" {
+		value, id, err := decodeCursor(options.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		updatedAt, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, &InvalidCursorError{options.Cursor}
+		}
+		conditions = append(conditions, fmt.Sprintf("(m.updated_at, m.id)%s(%s, %s)", comparison, arg(updatedAt), arg(id)))
+	}
+
+	// fetch one extra membership to find out whether there is a next page
+	query := fmt.Sprintf(`
+	SELECT
+		p.public_id PlayerPublicID,
+		m.id MembershipID, m.membership_level MembershipLevel,
+		m.approved MembershipApproved, m.denied MembershipDenied, m.banned MembershipBanned,
+		c.public_id ClanPublicID, c.name ClanName, c.metadata DBClanMetadata, c.owner_id ClanOwnerID,
+		c.membership_count ClanMembershipCount,
+		NULL RequestorMembershipLevel,
+		r.name RequestorName, r.public_id RequestorPublicID, r.metadata DBRequestorMetadata,
+		a.name ApproverName, a.public_id ApproverPublicID, a.metadata DBApproverMetadata,
+		y.name DenierName, y.public_id DenierPublicID, y.metadata DBDenierMetadata,
+		m.created_at MembershipCreatedAt,
+		m.updated_at MembershipUpdatedAt,
+		m.deleted_at MembershipDeletedAt,
+		m.approved_at MembershipApprovedAt, m.denied_at MembershipDeniedAt,
+		m.expires_at MembershipExpiresAt,
+		m.message MembershipMessage,
+		d.name DeletedByName, d.public_id DeletedByPublicID
+	FROM memberships m
+		INNER JOIN players p on p.id=m.player_id
+		INNER JOIN clans c on c.id=m.clan_id
+		LEFT OUTER JOIN players d on d.id=m.deleted_by
+		LEFT OUTER JOIN players r on r.id=m.requestor_id
+		LEFT OUTER JOIN players a on a.id=m.approver_id
+		LEFT OUTER JOIN players y on y.id=m.denier_id
+	WHERE %s
+	ORDER BY m.updated_at %s, m.id %s
+	LIMIT %s`,
+		strings.Join(conditions, " AND "), direction, direction, arg(options.Limit+1),
+	)
+
+	var details []playerDetailsDAO
+	_, err = db.Select(&details, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	nextCursor := ""
+	if len(details) > options.Limit {
+		details = details[:options.Limit]
+		last := details[len(details)-1]
+		nextCursor = encodeCursor(strconv.FormatInt(nullOrInt(last.MembershipUpdatedAt), 10), nullOrInt(last.MembershipID))
+	}
+
+	memberships := make([]map[string]interface{}, len(details))
+	for i := range details {
+		memberships[i] = details[i].Serialize()
+		memberships[i]["status"] = getMembershipStatus(&details[i], now)
+	}
+
+	return map[string]interface{}{
+		"memberships": memberships,
+		"nextCursor":  nextCursor,
+	}, nil
+}
+
 // GetPlayerDetails returns detailed information about a player and their memberships
 func GetPlayerDetails(db DB, gameID, publicID string) (map[string]interface{}, error) {
 	result, err := GetPlayerMembershipDetails(db, gameID, publicID)
@@ -99,6 +99,7 @@ var _ = Describe("Player Model", func() {
 				playerID := uuid.NewV4().String()
 				player, err := CreatePlayer(
 					testDb,
+					game,
 					game.PublicID,
 					playerID,
 					"player-name",
@@ -118,12 +119,13 @@ var _ = Describe("Player Model", func() {
 
 		Describe("Update Player", func() {
 			It("Should update a Player with UpdatePlayer", func() {
-				_, player, err := CreatePlayerFactory(testDb, "")
+				game, player, err := CreatePlayerFactory(testDb, "")
 				Expect(err).NotTo(HaveOccurred())
 
 				metadata := map[string]interface{}{"x": "a"}
 				updPlayer, err := UpdatePlayer(
 					testDb,
+					game,
 					player.GameID,
 					player.PublicID,
 					player.Name,
@@ -139,6 +141,58 @@ var _ = Describe("Player Model", func() {
 				Expect(dbPlayer.Metadata["x"]).To(BeEquivalentTo(metadata["x"]))
 			})
 
+			It("Should increment the Player version with UpdatePlayerWithVersion", func() {
+				game, player, err := CreatePlayerFactory(testDb, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(player.Version).To(BeEquivalentTo(1))
+
+				updPlayer, err := UpdatePlayerWithVersion(
+					testDb,
+					game,
+					player.GameID,
+					player.PublicID,
+					player.Name,
+					map[string]interface{}{"x": "a"},
+					player.Version,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(updPlayer.ID).To(Equal(player.ID))
+				Expect(updPlayer.Version).To(Equal(player.Version + 1))
+				Expect(updPlayer.Metadata["x"]).To(Equal("a"))
+			})
+
+			It("Should not update a Player if version does not match with UpdatePlayerWithVersion", func() {
+				game, player, err := CreatePlayerFactory(testDb, "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = UpdatePlayerWithVersion(
+					testDb,
+					game,
+					player.GameID,
+					player.PublicID,
+					player.Name,
+					map[string]interface{}{"x": "a"},
+					player.Version+1,
+				)
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(&VersionConflictError{}))
+				Expect(err.(*VersionConflictError).Version).To(Equal(player.Version))
+
+				dbPlayer, err := GetPlayerByPublicID(testDb, player.GameID, player.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbPlayer.Metadata).To(Equal(player.Metadata))
+				Expect(dbPlayer.Version).To(Equal(player.Version))
+			})
+
+			It("Should increment the Player version with UpdatePlayer", func() {
+				game, player, err := CreatePlayerFactory(testDb, "")
+				Expect(err).NotTo(HaveOccurred())
+
+				updPlayer, err := UpdatePlayer(testDb, game, player.GameID, player.PublicID, player.Name, player.Metadata)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(updPlayer.Version).To(Equal(player.Version + 1))
+			})
+
 			It("Should create Player with UpdatePlayer if player does not exist", func() {
 				game := GameFactory.MustCreate().(*Game)
 				err := testDb.Insert(game)
@@ -150,6 +204,7 @@ var _ = Describe("Player Model", func() {
 				metadata := map[string]interface{}{"x": "1"}
 				updPlayer, err := UpdatePlayer(
 					testDb,
+					game,
 					gameID,
 					publicID,
 					publicID,
@@ -168,6 +223,7 @@ var _ = Describe("Player Model", func() {
 			It("Should not update a Player with Invalid Data with UpdatePlayer", func() {
 				_, err := UpdatePlayer(
 					testDb,
+					&Game{PublicID: "-1"},
 					"-1",
 					"qwe",
 					"some player name",
@@ -313,6 +369,7 @@ var _ = Describe("Player Model", func() {
 
 				c, err := CreateClan(
 					testDb,
+					game,
 					game.PublicID,
 					"johns-bug-clan",
 					"johns-bug-clan",
@@ -473,6 +530,113 @@ var _ = Describe("Player Model", func() {
 			})
 		})
 
+		Describe("Get Player Memberships Page", func() {
+			var options *GetPlayerMembershipsOptions
+
+			BeforeEach(func() {
+				options = &GetPlayerMembershipsOptions{
+					Limit: 100,
+					Order: Descending,
+				}
+			})
+
+			It("Should get all memberships with their status", func() {
+				player, err := GetTestPlayerWithMemberships(testDb, "", 2, 1, 1, 3)
+				Expect(err).NotTo(HaveOccurred())
+
+				page, err := GetPlayerMembershipsPage(testDb, player.GameID, player.PublicID, options)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(page["nextCursor"]).To(Equal(""))
+
+				statuses := map[string]int{}
+				for _, membership := range page["memberships"].([]map[string]interface{}) {
+					statuses[membership["status"].(string)]++
+				}
+				Expect(statuses).To(Equal(map[string]int{
+					"approved":      2,
+					"denied":        1,
+					"banned":        1,
+					"pendingInvite": 3,
+				}))
+			})
+
+			It("Should return expired applications and invitations only as expired", func() {
+				player, err := GetTestPlayerWithMemberships(testDb, "", 0, 0, 0, 3)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = testDb.Exec(`
+				UPDATE memberships SET expires_at=$2 WHERE id=(
+					SELECT id FROM memberships WHERE player_id=$1 AND approved=false ORDER BY id LIMIT 1
+				)`, player.ID, util.NowMilli()-1000)
+				Expect(err).NotTo(HaveOccurred())
+
+				statuses := func() map[string]int {
+					page, err := GetPlayerMembershipsPage(testDb, player.GameID, player.PublicID, options)
+					Expect(err).NotTo(HaveOccurred())
+					result := map[string]int{}
+					for _, membership := range page["memberships"].([]map[string]interface{}) {
+						result[membership["status"].(string)]++
+					}
+					return result
+				}
+
+				Expect(statuses()).To(Equal(map[string]int{"expired": 1, "pendingInvite": 2}))
+
+				options.Statuses = []string{"pendingInvite"}
+				Expect(statuses()).To(Equal(map[string]int{"pendingInvite": 2}))
+
+				options.Statuses = []string{"expired"}
+				Expect(statuses()).To(Equal(map[string]int{"expired": 1}))
+			})
+
+			It("Should page through memberships newest updates first", func() {
+				player, err := GetTestPlayerWithMemberships(testDb, "", 3, 0, 0, 0)
+				Expect(err).NotTo(HaveOccurred())
+
+				options.Limit = 2
+				page, err := GetPlayerMembershipsPage(testDb, player.GameID, player.PublicID, options)
+				Expect(err).NotTo(HaveOccurred())
+				memberships := page["memberships"].([]map[string]interface{})
+				Expect(memberships).To(HaveLen(2))
+				Expect(memberships[0]["updatedAt"].(int64)).To(BeNumerically(">=", memberships[1]["updatedAt"].(int64)))
+				Expect(page["nextCursor"]).NotTo(BeEmpty())
+
+				options.Cursor = page["nextCursor"].(string)
+				page, err = GetPlayerMembershipsPage(testDb, player.GameID, player.PublicID, options)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(page["memberships"]).To(HaveLen(1))
+				Expect(page["nextCursor"]).To(Equal(""))
+			})
+
+			It("Should filter memberships by status and clan", func() {
+				player, err := GetTestPlayerWithMemberships(testDb, "", 2, 1, 0, 2)
+				Expect(err).NotTo(HaveOccurred())
+
+				options.Statuses = []string{"approved", "denied"}
+				page, err := GetPlayerMembershipsPage(testDb, player.GameID, player.PublicID, options)
+				Expect(err).NotTo(HaveOccurred())
+				memberships := page["memberships"].([]map[string]interface{})
+				Expect(memberships).To(HaveLen(3))
+				for _, membership := range memberships {
+					Expect(membership["status"]).To(SatisfyAny(Equal("approved"), Equal("denied")))
+				}
+
+				clanPublicID := memberships[0]["clan"].(map[string]interface{})["publicID"].(string)
+				options.Statuses = nil
+				options.ClanPublicID = clanPublicID
+				page, err = GetPlayerMembershipsPage(testDb, player.GameID, player.PublicID, options)
+				Expect(err).NotTo(HaveOccurred())
+				memberships = page["memberships"].([]map[string]interface{})
+				Expect(memberships).To(HaveLen(1))
+				Expect(memberships[0]["clan"].(map[string]interface{})["publicID"]).To(Equal(clanPublicID))
+			})
+
+			It("Should return error if Player does not exist", func() {
+				_, err := GetPlayerMembershipsPage(testDb, "game-id", "invalid-player-id", options)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Player was not found with id: invalid-player-id"))
+			})
+		})
+
 		Describe("Update Player Membership Count", func() {
 			It("Should work if membership is created", func() {
 				prevMemberships := 5
@@ -21,16 +21,22 @@ type PruneStats struct {
 	PendingInvitesPruned      int
 	DeniedMembershipsPruned   int
 	DeletedMembershipsPruned  int
+	IdempotencyKeysPruned     int
+	AuditEntriesPruned        int
+	HookDeliveriesPruned      int
 }
 
 //GetStats returns a formatted message
 func (ps *PruneStats) GetStats() string {
 	return fmt.Sprintf(
-		"-Pending Applications: %d\n-Pending Invites: %d\n-Denied Memberships: %d\n-Deleted Memberships: %d\n",
+		"-Pending Applications: %d\n-Pending Invites: %d\n-Denied Memberships: %d\n-Deleted Memberships: %d\n-Idempotency Keys: %d\n-Audit Entries: %d\n-Hook Deliveries: %d\n",
 		ps.PendingApplicationsPruned,
 		ps.PendingInvitesPruned,
 		ps.DeniedMembershipsPruned,
 		ps.DeletedMembershipsPruned,
+		ps.IdempotencyKeysPruned,
+		ps.AuditEntriesPruned,
+		ps.HookDeliveriesPruned,
 	)
 }
 
@@ -58,6 +64,7 @@ func prunePendingApplications(options *PruneOptions, db DB, logger zap.Logger) (
 		m.deleted_at=0 AND
 		m.approved=FALSE AND
 		m.denied=FALSE AND
+		m.banned=FALSE AND
 		m.requestor_id=m.player_id AND
 		m.updated_at < $2`
 
@@ -71,6 +78,7 @@ func prunePendingInvites(options *PruneOptions, db DB, logger zap.Logger) (int,
 		m.deleted_at=0 AND
 		m.approved=FALSE AND
 		m.denied=FALSE AND
+		m.banned=FALSE AND
 		m.requestor_id != m.player_id AND
 		m.updated_at < $2`
 
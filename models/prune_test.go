@@ -57,6 +57,62 @@ var _ = Describe("Prune Stale Data Model", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(int(count)).To(Equal(52))
 			})
+
+			It("Should not remove bans", func() {
+				gameID, err := GetTestClanWithStaleData(testDb, 5, 6, 7, 8)
+				Expect(err).NotTo(HaveOccurred())
+
+				var clan Clan
+				err = testDb.SelectOne(&clan, `SELECT * FROM clans WHERE game_id=$1`, gameID)
+				Expect(err).NotTo(HaveOccurred())
+
+				// bans requested by the banner and bans of former applicants
+				updatedAt := time.Now().Add(-3*time.Hour).UnixNano() / 1000000
+				var banIDs []int64
+				for _, application := range []bool{false, true} {
+					player := PlayerFactory.MustCreateWithOption(map[string]interface{}{
+						"GameID": gameID,
+					}).(*Player)
+					err = testDb.Insert(player)
+					Expect(err).NotTo(HaveOccurred())
+
+					requestorID := clan.OwnerID
+					if application {
+						requestorID = player.ID
+					}
+					ban := MembershipFactory.MustCreateWithOption(map[string]interface{}{
+						"GameID":      gameID,
+						"PlayerID":    player.ID,
+						"ClanID":      clan.ID,
+						"RequestorID": requestorID,
+						"Approved":    false,
+						"Denied":      false,
+						"Banned":      true,
+					}).(*Membership)
+					err = testDb.Insert(ban)
+					Expect(err).NotTo(HaveOccurred())
+					_, err = testDb.Exec(`UPDATE memberships SET updated_at=$1, created_at=$1 WHERE id=$2`, updatedAt, ban.ID)
+					Expect(err).NotTo(HaveOccurred())
+					banIDs = append(banIDs, ban.ID)
+				}
+
+				expiration := int((2 * time.Hour).Seconds())
+				options := &PruneOptions{
+					GameID:                        gameID,
+					PendingApplicationsExpiration: expiration,
+					PendingInvitesExpiration:      expiration,
+					DeniedMembershipsExpiration:   expiration,
+					DeletedMembershipsExpiration:  expiration,
+				}
+				pruneStats, err := PruneStaleData(options, testDb, logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pruneStats.PendingApplicationsPruned).To(Equal(5))
+				Expect(pruneStats.PendingInvitesPruned).To(Equal(6))
+
+				count, err := testDb.SelectInt(`SELECT COUNT(*) FROM memberships WHERE banned=TRUE AND id IN ($1, $2)`, banIDs[0], banIDs[1])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(int(count)).To(Equal(2))
+			})
 		})
 	})
 })
@@ -0,0 +1,349 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+	"github.com/topfreegames/khan/util"
+)
+
+// ClanRankingDimensionsKey is the game metadata key where the clan ranking dimensions are declared
+const ClanRankingDimensionsKey string = "clanRankingDimensions"
+
+// TopClansDefaultLimitKey is string constant
+const TopClansDefaultLimitKey string = "topClans.defaultLimit"
+
+// TopClansMaxLimitKey is string constant
+const TopClansMaxLimitKey string = "topClans.maxLimit"
+
+// ClanMetadataDimension scores clans by a numeric field of their metadata
+const ClanMetadataDimension string = "clanMetadata"
+
+// MembershipCountDimension scores clans by their number of members
+const MembershipCountDimension string = "membershipCount"
+
+// PlayerMetadataDimension scores clans by aggregating a numeric field of their members' metadata
+const PlayerMetadataDimension string = "playerMetadata"
+
+var clanRankingAggregations = map[string]string{
+	"sum": "SUM",
+	"avg": "AVG",
+	"min": "MIN",
+	"max": "MAX",
+}
+
+// ClanRankingDimension describes how the clans of a game are scored in a ranking
+type ClanRankingDimension struct {
+	Name        string
+	Type        string
+	Field       string
+	Aggregation string
+}
+
+// ClanRank is the position of a clan in a ranking
+type ClanRank struct {
+	Rank            int                    `db:"-"`
+	Score           float64                `db:"score"`
+	ClanID          int64                  `db:"clan_id"`
+	PublicID        string                 `db:"public_id"`
+	Name            string                 `db:"name"`
+	MembershipCount int                    `db:"membership_count"`
+	Metadata        map[string]interface{} `db:"metadata"`
+}
+
+// GetClanRankingDimensions returns the valid clan ranking dimensions declared in the game metadata, by name.
+// Dimensions are declared as {"<name>": {"type": "clanMetadata|membershipCount|playerMetadata", "field": "<metadata field>", "aggregation": "sum|avg|min|max"}}
+func GetClanRankingDimensions(game *Game) map[string]*ClanRankingDimension {
+	dimensions := map[string]*ClanRankingDimension{}
+	declared, ok := game.Metadata[ClanRankingDimensionsKey].(map[string]interface{})
+	if !ok {
+		return dimensions
+	}
+
+	for name, value := range declared {
+		config, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		dimension := &ClanRankingDimension{Name: name}
+		dimension.Type, _ = config["type"].(string)
+		dimension.Field, _ = config["field"].(string)
+		dimension.Aggregation, _ = config["aggregation"].(string)
+		if dimension.Aggregation == "" {
+			dimension.Aggregation = "sum"
+		}
+
+		switch dimension.Type {
+		case MembershipCountDimension:
+		case ClanMetadataDimension:
+			if dimension.Field == "" {
+				continue
+			}
+		case PlayerMetadataDimension:
+			if _, ok := clanRankingAggregations[dimension.Aggregation]; !ok || dimension.Field == "" {
+				continue
+			}
+		default:
+			continue
+		}
+		dimensions[name] = dimension
+	}
+	return dimensions
+}
+
+// rankingQuery accumulates the arguments of a ranking query
+type rankingQuery struct {
+	args []interface{}
+}
+
+func (q *rankingQuery) arg(value interface{}) string {
+	q.args = append(q.args, value)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// getScoresQuery returns the query that computes the score of the clans matching clanCondition in the given dimension
+func (q *rankingQuery) getScoresQuery(dimension *ClanRankingDimension, clanCondition string) string {
+	switch dimension.Type {
+	case ClanMetadataDimension:
+		field := q.arg(dimension.Field)
+		return fmt.Sprintf(`
+			SELECT c.id clan_id, (c.metadata->>(%s::text))::numeric score
+			FROM clans c
+			WHERE %s AND jsonb_typeof(c.metadata->(%s::text))='number'
+		`, field, clanCondition, field)
+	case PlayerMetadataDimension:
+		field := q.arg(dimension.Field)
+		return fmt.Sprintf(`
+			SELECT c.id clan_id, %s((p.metadata->>(%s::text))::numeric) score
+			FROM clans c
+			CROSS JOIN LATERAL (
+				SELECT c.owner_id player_id
+				UNION
+				SELECT m.player_id FROM memberships m
+				WHERE
+					m.clan_id=c.id AND m.deleted_at=0 AND m.approved=true AND
+					m.denied=false AND m.banned=false
+			) cm
+			INNER JOIN players p ON p.id=cm.player_id
+			WHERE %s AND jsonb_typeof(p.metadata->(%s::text))='number'
+			GROUP BY c.id
+		`, clanRankingAggregations[dimension.Aggregation], field, clanCondition, field)
+	default:
+		return fmt.Sprintf(`
+			SELECT c.id clan_id, c.membership_count::numeric score
+			FROM clans c
+			WHERE %s
+		`, clanCondition)
+	}
+}
+
+// refreshClanScores recomputes the scores of the given clans in the given dimensions.
+// If clanIDs is nil the scores of all the clans of the game are recomputed
+func refreshClanScores(db DB, gameID string, dimensions map[string]*ClanRankingDimension, clanIDs []int64) error {
+	names := make([]string, 0, len(dimensions))
+	for name := range dimensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		q := &rankingQuery{}
+		gameIDArg := q.arg(gameID)
+		dimensionArg := q.arg(name)
+		updatedAtArg := q.arg(util.NowMilli())
+
+		clanCondition := fmt.Sprintf("c.game_id=%s AND c.deleted_at=0", gameIDArg)
+		scoreCondition := ""
+		if clanIDs != nil {
+			placeholders := make([]string, len(clanIDs))
+			for i, clanID := range clanIDs {
+				placeholders[i] = q.arg(clanID)
+			}
+			ids := strings.Join(placeholders, ", ")
+			clanCondition = fmt.Sprintf("%s AND c.id IN (%s)", clanCondition, ids)
+			scoreCondition = fmt.Sprintf("AND cs.clan_id IN (%s)", ids)
+		}
+
+		// stale scores are removed and current ones upserted in a single statement,
+		// so the ranking is never seen without the refreshed clans
+		query := fmt.Sprintf(`
+			WITH scores AS (%s),
+			deleted AS (
+				DELETE FROM clan_scores cs
+				WHERE
+					cs.game_id=%s AND cs.dimension=%s %s AND
+					NOT EXISTS (SELECT 1 FROM scores s WHERE s.clan_id=cs.clan_id AND s.score IS NOT NULL)
+			)
+			INSERT INTO clan_scores (game_id, dimension, clan_id, score, updated_at)
+			SELECT %s, %s, s.clan_id, s.score, %s FROM scores s WHERE s.score IS NOT NULL
+			ON CONFLICT (game_id, dimension, clan_id)
+			DO UPDATE SET score=excluded.score, updated_at=excluded.updated_at
+		`,
+			q.getScoresQuery(dimensions[name], clanCondition),
+			gameIDArg, dimensionArg, scoreCondition,
+			gameIDArg, dimensionArg, updatedAtArg,
+		)
+		_, err := db.Exec(query, q.args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefreshClanScores recomputes the scores of the given clans in all the ranking dimensions of their game
+func RefreshClanScores(db DB, game *Game, clanIDs ...int64) error {
+	if len(clanIDs) == 0 {
+		return nil
+	}
+	dimensions := GetClanRankingDimensions(game)
+	if len(dimensions) == 0 {
+		return nil
+	}
+	return refreshClanScores(db, game.PublicID, dimensions, clanIDs)
+}
+
+// deleteClanScores removes a clan from all the rankings of its game
+func deleteClanScores(db gorp.SqlExecutor, clanID int64) error {
+	_, err := db.Exec("DELETE FROM clan_scores WHERE clan_id=$1", clanID)
+	return err
+}
+
+// RefreshPlayerClanScores recomputes the scores of the clans the player owns or is a member of
+// in the ranking dimensions that aggregate player metadata
+func RefreshPlayerClanScores(db DB, game *Game, playerID int64) error {
+	dimensions := GetClanRankingDimensions(game)
+	for name, dimension := range dimensions {
+		if dimension.Type != PlayerMetadataDimension {
+			delete(dimensions, name)
+		}
+	}
+	if len(dimensions) == 0 {
+		return nil
+	}
+
+	query := `
+	SELECT id FROM clans WHERE owner_id=$1 AND deleted_at=0
+	UNION
+	SELECT clan_id FROM memberships
+	WHERE
+		player_id=$1 AND deleted_at=0 AND approved=true AND
+		denied=false AND banned=false
+	`
+	var clanIDs []int64
+	_, err := db.Select(&clanIDs, query, playerID)
+	if err != nil {
+		return err
+	}
+	if len(clanIDs) == 0 {
+		return nil
+	}
+	return refreshClanScores(db, game.PublicID, dimensions, clanIDs)
+}
+
+// RebuildClanScores recomputes the scores of all the clans of a game and removes
+// the scores of dimensions that are not declared anymore
+func RebuildClanScores(db DB, game *Game) error {
+	dimensions := GetClanRankingDimensions(game)
+
+	q := &rankingQuery{}
+	conditions := []string{fmt.Sprintf("game_id=%s", q.arg(game.PublicID))}
+	if len(dimensions) > 0 {
+		placeholders := []string{}
+		for name := range dimensions {
+			placeholders = append(placeholders, q.arg(name))
+		}
+		conditions = append(conditions, fmt.Sprintf("dimension NOT IN (%s)", strings.Join(placeholders, ", ")))
+	}
+	_, err := db.Exec(fmt.Sprintf("DELETE FROM clan_scores WHERE %s", strings.Join(conditions, " AND ")), q.args...)
+	if err != nil {
+		return err
+	}
+
+	return refreshClanScores(db, game.PublicID, dimensions, nil)
+}
+
+// ClanRankingDimensionsChanged returns whether the clan ranking dimensions of a game are not the previous ones.
+// The previous game is nil if the game did not exist
+func ClanRankingDimensionsChanged(previous, game *Game) bool {
+	dimensions := map[string]*ClanRankingDimension{}
+	if previous != nil {
+		dimensions = GetClanRankingDimensions(previous)
+	}
+	return !reflect.DeepEqual(GetClanRankingDimensions(game), dimensions)
+}
+
+// GetTopClans returns the top clans of a game in the given ranking dimension, ordered by rank.
+// Clans with the same score share the same rank
+func GetTopClans(db DB, game *Game, dimension string, limit int) ([]*ClanRank, error) {
+	if _, ok := GetClanRankingDimensions(game)[dimension]; !ok {
+		return nil, &ModelNotFoundError{"ClanRankingDimension", dimension}
+	}
+
+	query := `
+	SELECT cs.clan_id, cs.score, c.public_id, c.name, c.membership_count, c.metadata
+	FROM clan_scores cs
+	INNER JOIN clans c ON c.id=cs.clan_id
+	WHERE cs.game_id=$1 AND cs.dimension=$2 AND c.deleted_at=0
+	ORDER BY cs.score DESC, cs.clan_id
+	LIMIT $3
+	`
+	var ranks []*ClanRank
+	_, err := db.Select(&ranks, query, game.PublicID, dimension, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, rank := range ranks {
+		if i > 0 && ranks[i-1].Score == rank.Score {
+			rank.Rank = ranks[i-1].Rank
+		} else {
+			rank.Rank = i + 1
+		}
+	}
+	return ranks, nil
+}
+
+// GetClanRank returns the rank and score of a clan in the given ranking dimension
+func GetClanRank(db DB, game *Game, dimension, clanPublicID string) (*ClanRank, error) {
+	if _, ok := GetClanRankingDimensions(game)[dimension]; !ok {
+		return nil, &ModelNotFoundError{"ClanRankingDimension", dimension}
+	}
+
+	query := `
+	SELECT cs.clan_id, cs.score, c.public_id, c.name, c.membership_count, c.metadata
+	FROM clan_scores cs
+	INNER JOIN clans c ON c.id=cs.clan_id
+	WHERE cs.game_id=$1 AND cs.dimension=$2 AND c.public_id=$3 AND c.deleted_at=0
+	`
+	var ranks []*ClanRank
+	_, err := db.Select(&ranks, query, game.PublicID, dimension, clanPublicID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ranks) == 0 {
+		return nil, &ModelNotFoundError{"ClanRank", clanPublicID}
+	}
+	rank := ranks[0]
+
+	// uses the ranking index to count the clans ahead of this one
+	ahead, err := db.SelectInt(
+		"SELECT COUNT(*) FROM clan_scores WHERE game_id=$1 AND dimension=$2 AND score>$3::numeric",
+		game.PublicID, dimension, rank.Score,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rank.Rank = int(ahead) + 1
+	return rank, nil
+}
@@ -0,0 +1,248 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Ranking Model", func() {
+	var testDb DB
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	createGameWithDimensions := func(dimensions map[string]interface{}) *Game {
+		game := GameFactory.MustCreateWithOption(map[string]interface{}{
+			"Metadata": map[string]interface{}{ClanRankingDimensionsKey: dimensions},
+		}).(*Game)
+		err := testDb.Insert(game)
+		Expect(err).NotTo(HaveOccurred())
+		return game
+	}
+
+	Describe("Get Clan Ranking Dimensions", func() {
+		It("Should return only the valid dimensions", func() {
+			game := GameFactory.MustCreateWithOption(map[string]interface{}{
+				"Metadata": map[string]interface{}{
+					ClanRankingDimensionsKey: map[string]interface{}{
+						"trophies":   map[string]interface{}{"type": "clanMetadata", "field": "trophies"},
+						"members":    map[string]interface{}{"type": "membershipCount"},
+						"bestPlayer": map[string]interface{}{"type": "playerMetadata", "field": "score", "aggregation": "max"},
+						"noField":    map[string]interface{}{"type": "clanMetadata"},
+						"badType":    map[string]interface{}{"type": "invalid"},
+						"badAgg":     map[string]interface{}{"type": "playerMetadata", "field": "score", "aggregation": "median"},
+						"notAMap":    "trophies",
+					},
+				},
+			}).(*Game)
+
+			dimensions := GetClanRankingDimensions(game)
+			Expect(dimensions).To(HaveLen(3))
+			Expect(dimensions["trophies"]).To(Equal(&ClanRankingDimension{
+				Name: "trophies", Type: ClanMetadataDimension, Field: "trophies", Aggregation: "sum",
+			}))
+			Expect(dimensions["members"].Type).To(Equal(MembershipCountDimension))
+			Expect(dimensions["bestPlayer"].Aggregation).To(Equal("max"))
+		})
+	})
+
+	Describe("Clan Metadata Dimension", func() {
+		It("Should rank clans by the metadata field when the dimension is declared", func() {
+			player, clans, err := GetTestClans(testDb, "", "", 4)
+			Expect(err).NotTo(HaveOccurred())
+			game, err := GetGameByPublicID(testDb, player.GameID)
+			Expect(err).NotTo(HaveOccurred())
+			trophies := []interface{}{10, 30, 10, "many"}
+			for i, clan := range clans {
+				_, err = UpdateClan(
+					testDb, game, clan.GameID, clan.PublicID, clan.Name, player.PublicID,
+					map[string]interface{}{"trophies": trophies[i]}, clan.AllowApplication, clan.AutoJoin,
+				)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			previous := game
+			game, err = PatchGame(testDb, player.GameID, &GamePatch{
+				Metadata: &MetadataPatch{Merge: map[string]interface{}{
+					ClanRankingDimensionsKey: map[string]interface{}{
+						"trophies": map[string]interface{}{"type": "clanMetadata", "field": "trophies"},
+					},
+				}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ClanRankingDimensionsChanged(previous, game)).To(BeTrue())
+
+			// the existing clans are only ranked once the scores are rebuilt
+			ranks, err := GetTopClans(testDb, game, "trophies", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ranks).To(BeEmpty())
+			err = RebuildClanScores(testDb, game)
+			Expect(err).NotTo(HaveOccurred())
+
+			ranks, err = GetTopClans(testDb, game, "trophies", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ranks).To(HaveLen(3))
+			Expect(ranks[0].PublicID).To(Equal(clans[1].PublicID))
+			Expect(ranks[0].Rank).To(Equal(1))
+			Expect(ranks[0].Score).To(BeEquivalentTo(30))
+			Expect(ranks[1].Rank).To(Equal(2))
+			Expect(ranks[2].Rank).To(Equal(2))
+			Expect(ranks[2].Score).To(BeEquivalentTo(10))
+
+			_, err = UpdateClan(
+				testDb, game, clans[0].GameID, clans[0].PublicID, clans[0].Name, player.PublicID,
+				map[string]interface{}{"trophies": 50}, clans[0].AllowApplication, clans[0].AutoJoin,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			ranks, err = GetTopClans(testDb, game, "trophies", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ranks).To(HaveLen(1))
+			Expect(ranks[0].PublicID).To(Equal(clans[0].PublicID))
+
+			rank, err := GetClanRank(testDb, game, "trophies", clans[2].PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rank.Rank).To(Equal(3))
+			Expect(rank.Score).To(BeEquivalentTo(10))
+
+			_, err = GetClanRank(testDb, game, "trophies", clans[3].PublicID)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&ModelNotFoundError{}))
+		})
+
+		It("Should remove deleted clans from the ranking", func() {
+			game := createGameWithDimensions(map[string]interface{}{
+				"trophies": map[string]interface{}{"type": "clanMetadata", "field": "trophies"},
+			})
+			_, player, err := CreatePlayerFactory(testDb, game.PublicID, true)
+			Expect(err).NotTo(HaveOccurred())
+			clan, err := CreateClan(
+				testDb, game, game.PublicID, "ranked-clan", "ranked clan", player.PublicID,
+				map[string]interface{}{"trophies": 10}, true, false, 1,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			ranks, err := GetTopClans(testDb, game, "trophies", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ranks).To(HaveLen(1))
+			Expect(ranks[0].PublicID).To(Equal(clan.PublicID))
+
+			_, _, err = DeleteClan(testDb, game.PublicID, clan.PublicID, player.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+
+			ranks, err = GetTopClans(testDb, game, "trophies", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ranks).To(BeEmpty())
+			count, err := testDb.SelectInt("SELECT COUNT(*) FROM clan_scores WHERE clan_id=$1", clan.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeEquivalentTo(0))
+		})
+	})
+
+	Describe("Membership Count Dimension", func() {
+		It("Should keep the clan score in sync with its members", func() {
+			game := createGameWithDimensions(map[string]interface{}{
+				"members": map[string]interface{}{"type": "membershipCount"},
+			})
+			_, clan, owner, players, _, err := GetClanWithMemberships(testDb, 2, 0, 0, 0, game.PublicID, "", true)
+			Expect(err).NotTo(HaveOccurred())
+
+			rank, err := GetClanRank(testDb, game, "members", clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rank.Score).To(BeEquivalentTo(3))
+
+			_, err = DeleteMembership(testDb, game, game.PublicID, players[0].PublicID, clan.PublicID, owner.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+
+			rank, err = GetClanRank(testDb, game, "members", clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rank.Score).To(BeEquivalentTo(2))
+		})
+	})
+
+	Describe("Player Metadata Dimension", func() {
+		It("Should aggregate the metadata of approved members and the owner", func() {
+			game := createGameWithDimensions(map[string]interface{}{
+				"totalScore": map[string]interface{}{"type": "playerMetadata", "field": "score"},
+				"bestScore":  map[string]interface{}{"type": "playerMetadata", "field": "score", "aggregation": "max"},
+			})
+			_, clan, owner, players, _, err := GetClanWithMemberships(testDb, 2, 0, 0, 1, game.PublicID, "", true)
+			Expect(err).NotTo(HaveOccurred())
+
+			scores := map[*Player]interface{}{owner: 10, players[0]: 5, players[1]: "high", players[2]: 100}
+			for player, score := range scores {
+				_, err = UpdatePlayer(testDb, game, game.PublicID, player.PublicID, player.Name, map[string]interface{}{"score": score})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			rank, err := GetClanRank(testDb, game, "totalScore", clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rank.Score).To(BeEquivalentTo(15))
+
+			_, err = PatchPlayer(testDb, game, game.PublicID, players[0].PublicID, &PlayerPatch{
+				Metadata: &MetadataPatch{Increment: map[string]float64{"score": 20}},
+			}, 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			rank, err = GetClanRank(testDb, game, "totalScore", clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rank.Score).To(BeEquivalentTo(35))
+			rank, err = GetClanRank(testDb, game, "bestScore", clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rank.Score).To(BeEquivalentTo(25))
+		})
+
+		It("Should not query the clans of players of games without player metadata dimensions", func() {
+			game := GameFactory.MustCreateWithOption(map[string]interface{}{
+				"Metadata": map[string]interface{}{ClanRankingDimensionsKey: map[string]interface{}{
+					"trophies": map[string]interface{}{"type": "clanMetadata", "field": "trophies"},
+				}},
+			}).(*Game)
+
+			// a nil db fails any query
+			err := RefreshPlayerClanScores(nil, game, 1)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("Clan Ranking Dimensions Changed", func() {
+		It("Should compare the declared dimensions of the games", func() {
+			dimensions := map[string]interface{}{
+				"trophies": map[string]interface{}{"type": "clanMetadata", "field": "trophies"},
+			}
+			game := GameFactory.MustCreateWithOption(map[string]interface{}{
+				"Metadata": map[string]interface{}{ClanRankingDimensionsKey: dimensions},
+			}).(*Game)
+			sameDimensions := GameFactory.MustCreateWithOption(map[string]interface{}{
+				"Metadata": map[string]interface{}{ClanRankingDimensionsKey: dimensions, "other": "field"},
+			}).(*Game)
+			noDimensions := GameFactory.MustCreate().(*Game)
+
+			Expect(ClanRankingDimensionsChanged(game, sameDimensions)).To(BeFalse())
+			Expect(ClanRankingDimensionsChanged(noDimensions, game)).To(BeTrue())
+			Expect(ClanRankingDimensionsChanged(game, noDimensions)).To(BeTrue())
+			Expect(ClanRankingDimensionsChanged(nil, game)).To(BeTrue())
+			Expect(ClanRankingDimensionsChanged(nil, noDimensions)).To(BeFalse())
+		})
+	})
+
+	Describe("Get Top Clans", func() {
+		It("Should fail if the dimension is not declared", func() {
+			game := createGameWithDimensions(map[string]interface{}{})
+			_, err := GetTopClans(testDb, game, "trophies", 10)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("ClanRankingDimension was not found with id: trophies"))
+		})
+	})
+})
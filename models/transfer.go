@@ -0,0 +1,600 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/topfreegames/extensions/gorp/interfaces"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/util"
+	"github.com/uber-go/zap"
+)
+
+// Record types of a game import/export stream
+const (
+	TransferPlayerType     = "player"
+	TransferClanType       = "clan"
+	TransferMembershipType = "membership"
+)
+
+// DefaultTransferBatchSize is the number of records read or written per query/transaction
+const DefaultTransferBatchSize = 500
+
+// maxTransferLineSize is the largest JSON line accepted by the importer
+const maxTransferLineSize = 1024 * 1024
+
+// TransferRecord is a single line of a game import/export stream
+type TransferRecord struct {
+	Type              string                 `json:"type"`
+	PublicID          string                 `json:"publicID,omitempty"`
+	Name              string                 `json:"name,omitempty"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+	OwnerPublicID     string                 `json:"ownerPublicID,omitempty"`
+	AllowApplication  bool                   `json:"allowApplication,omitempty"`
+	AutoJoin          bool                   `json:"autoJoin,omitempty"`
+	PlayerPublicID    string                 `json:"playerPublicID,omitempty"`
+	ClanPublicID      string                 `json:"clanPublicID,omitempty"`
+	RequestorPublicID string                 `json:"requestorPublicID,omitempty"`
+	Level             string                 `json:"level,omitempty"`
+	Approved          bool                   `json:"approved,omitempty"`
+	Denied            bool                   `json:"denied,omitempty"`
+	Banned            bool                   `json:"banned,omitempty"`
+	BanExpiresAt      int64                  `json:"banExpiresAt,omitempty"`
+	Message           string                 `json:"message,omitempty"`
+	DeletedAt         int64                  `json:"deletedAt,omitempty"`
+	DeletedByPublicID string                 `json:"deletedByPublicID,omitempty"`
+}
+
+// TransferStats show stats about what has been imported or exported
+type TransferStats struct {
+	Players     int
+	Clans       int
+	Memberships int
+	// Lines is the number of lines of the stream already processed
+	Lines int
+}
+
+// GetStats returns a formatted message
+func (ts *TransferStats) GetStats() string {
+	return fmt.Sprintf(
+		"-Players: %d\n-Clans: %d\n-Memberships: %d\n-Lines: %d\n",
+		ts.Players,
+		ts.Clans,
+		ts.Memberships,
+		ts.Lines,
+	)
+}
+
+// ImportOptions has the batching and resuming configuration of a game import
+type ImportOptions struct {
+	BatchSize int
+	// Checkpoint is the number of lines committed by a previous run, those lines are skipped
+	Checkpoint int
+	// OnCheckpoint is called with the number of committed lines after each batch is committed
+	OnCheckpoint func(lines int) error
+}
+
+// ExportGame writes all players, clans and memberships of a game to w as JSON Lines.
+// Players come before clans and clans before memberships, so the stream can be imported in order.
+// Deleted clans and memberships are only exported, with their deletedAt, if includeDeleted is true
+func ExportGame(db DB, gameID string, w io.Writer, batchSize int, includeDeleted bool) (*TransferStats, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultTransferBatchSize
+	}
+	encoder := json.NewEncoder(w)
+	stats := &TransferStats{}
+	clansFilter, membershipsFilter := "AND c.deleted_at=0", "AND m.deleted_at=0"
+	if includeDeleted {
+		clansFilter, membershipsFilter = "", ""
+	}
+
+	var lastID int64
+	for {
+		var players []*Player
+		_, err := db.Select(&players, `
+			SELECT * FROM players
+			WHERE game_id=$1 AND id>$2
+			ORDER BY id LIMIT $3`, gameID, lastID, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, player := range players {
+			err = encoder.Encode(&TransferRecord{
+				Type:     TransferPlayerType,
+				PublicID: player.PublicID,
+				Name:     player.Name,
+				Metadata: player.Metadata,
+			})
+			if err != nil {
+				return nil, err
+			}
+			lastID = player.ID
+		}
+		stats.Players += len(players)
+		stats.Lines += len(players)
+		if len(players) < batchSize {
+			break
+		}
+	}
+
+	lastID = 0
+	for {
+		var clans []transferClanDAO
+		_, err := db.Select(&clans, `
+			SELECT
+				c.id ID, c.public_id PublicID, c.name Name, c.metadata Metadata,
+				c.allow_application AllowApplication, c.auto_join AutoJoin,
+				o.public_id OwnerPublicID, c.deleted_at DeletedAt
+			FROM clans c
+				INNER JOIN players o ON o.id=c.owner_id
+			WHERE c.game_id=$1 AND c.id>$2 `+clansFilter+`
+			ORDER BY c.id LIMIT $3`, gameID, lastID, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, clan := range clans {
+			err = encoder.Encode(&TransferRecord{
+				Type:             TransferClanType,
+				PublicID:         clan.PublicID,
+				Name:             clan.Name,
+				Metadata:         clan.Metadata,
+				OwnerPublicID:    clan.OwnerPublicID,
+				AllowApplication: clan.AllowApplication,
+				AutoJoin:         clan.AutoJoin,
+				DeletedAt:        clan.DeletedAt,
+			})
+			if err != nil {
+				return nil, err
+			}
+			lastID = clan.ID
+		}
+		stats.Clans += len(clans)
+		stats.Lines += len(clans)
+		if len(clans) < batchSize {
+			break
+		}
+	}
+
+	lastID = 0
+	for {
+		var memberships []transferMembershipDAO
+		_, err := db.Select(&memberships, `
+			SELECT
+				m.id ID, p.public_id PlayerPublicID, c.public_id ClanPublicID,
+				r.public_id RequestorPublicID, m.membership_level Level,
+				m.approved Approved, m.denied Denied, m.banned Banned,
+				m.ban_expires_at BanExpiresAt, m.message Message,
+				m.deleted_at DeletedAt, d.public_id DeletedByPublicID
+			FROM memberships m
+				INNER JOIN players p ON p.id=m.player_id
+				INNER JOIN clans c ON c.id=m.clan_id
+				INNER JOIN players r ON r.id=m.requestor_id
+				LEFT OUTER JOIN players d ON d.id=m.deleted_by
+			WHERE m.game_id=$1 AND m.id>$2 `+clansFilter+` `+membershipsFilter+`
+			ORDER BY m.id LIMIT $3`, gameID, lastID, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, membership := range memberships {
+			err = encoder.Encode(&TransferRecord{
+				Type:              TransferMembershipType,
+				PlayerPublicID:    membership.PlayerPublicID,
+				ClanPublicID:      membership.ClanPublicID,
+				RequestorPublicID: membership.RequestorPublicID,
+				Level:             membership.Level,
+				Approved:          membership.Approved,
+				Denied:            membership.Denied,
+				Banned:            membership.Banned,
+				BanExpiresAt:      membership.BanExpiresAt,
+				Message:           membership.Message,
+				DeletedAt:         membership.DeletedAt,
+				DeletedByPublicID: nullOrString(membership.DeletedByPublicID),
+			})
+			if err != nil {
+				return nil, err
+			}
+			lastID = membership.ID
+		}
+		stats.Memberships += len(memberships)
+		stats.Lines += len(memberships)
+		if len(memberships) < batchSize {
+			break
+		}
+	}
+
+	return stats, nil
+}
+
+// ImportGame reads players, clans and memberships as JSON Lines from r and upserts them into the game.
+// Records are validated against the game levels and limits and written in batched transactions.
+// After all lines are imported the membership and ownership counts of the game are recomputed.
+// The returned stats only count the records of committed batches. Archived games can't be imported into
+func ImportGame(db interfaces.Database, game *Game, r io.Reader, options *ImportOptions, logger zap.Logger) (*TransferStats, error) {
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultTransferBatchSize
+	}
+
+	l := logger.With(
+		zap.String("source", "import"),
+		zap.String("operation", "ImportGame"),
+		zap.String("gameID", game.PublicID),
+	)
+
+	stats := &TransferStats{Lines: options.Checkpoint}
+	if game.ArchivedAt > 0 {
+		return stats, &GameArchivedError{game.PublicID}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxTransferLineSize)
+
+	var tx interfaces.Transaction
+	// batch counts the records of the current transaction, which are only
+	// added to stats once it commits
+	batch := &TransferStats{}
+	pending := 0
+	line := 0
+
+	commit := func() error {
+		if tx == nil {
+			return nil
+		}
+		err := tx.Commit()
+		tx = nil
+		if err != nil {
+			return err
+		}
+		stats.Lines = line
+		stats.Players += batch.Players
+		stats.Clans += batch.Clans
+		stats.Memberships += batch.Memberships
+		batch = &TransferStats{}
+		pending = 0
+		log.I(l, "Import batch committed.", func(cm log.CM) {
+			cm.Write(zap.Int("lines", line))
+		})
+		if options.OnCheckpoint != nil {
+			return options.OnCheckpoint(line)
+		}
+		return nil
+	}
+	rollback := func() {
+		if tx != nil {
+			tx.Rollback()
+			tx = nil
+		}
+	}
+
+	for scanner.Scan() {
+		line++
+		if line <= options.Checkpoint {
+			continue
+		}
+		data := scanner.Bytes()
+		if len(data) == 0 {
+			continue
+		}
+
+		var record TransferRecord
+		err := json.Unmarshal(data, &record)
+		if err != nil {
+			rollback()
+			return stats, &InvalidImportRecordError{line, err.Error()}
+		}
+		// deleted clans and memberships are only exported to keep the game history
+		if record.DeletedAt > 0 {
+			continue
+		}
+
+		if tx == nil {
+			tx, err = db.Begin()
+			if err != nil {
+				return stats, err
+			}
+		}
+
+		var count *int
+		switch record.Type {
+		case TransferPlayerType:
+			err = importPlayer(tx, game, &record)
+			count = &batch.Players
+		case TransferClanType:
+			err = importClan(tx, game, &record)
+			count = &batch.Clans
+		case TransferMembershipType:
+			err = importMembership(tx, game, &record)
+			count = &batch.Memberships
+		default:
+			err = fmt.Errorf("unknown record type %q", record.Type)
+		}
+		if err != nil {
+			rollback()
+			return stats, &InvalidImportRecordError{line, err.Error()}
+		}
+		*count++
+
+		pending++
+		if pending >= batchSize {
+			err = commit()
+			if err != nil {
+				rollback()
+				return stats, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		rollback()
+		return stats, err
+	}
+	err := commit()
+	if err != nil {
+		rollback()
+		return stats, err
+	}
+
+	log.I(l, "Recomputing membership and ownership counts...")
+	err = RecomputeGameCounts(db, game)
+	if err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// RecomputeGameCounts updates the membership and ownership counts of every player and clan in a game
+func RecomputeGameCounts(db DB, game *Game) error {
+	query := `
+	UPDATE players p SET
+		membership_count=(
+			SELECT COUNT(*) FROM memberships m
+			WHERE
+				m.player_id=p.id AND m.deleted_at=0 AND m.approved=true AND
+				m.denied=false AND m.banned=false
+		),
+		ownership_count=(
+			SELECT COUNT(*) FROM clans c
+			WHERE c.owner_id=p.id AND c.deleted_at=0
+		)
+	WHERE p.game_id=$1
+	`
+	_, err := db.Exec(query, game.PublicID)
+	if err != nil {
+		return err
+	}
+
+	var clanIDs []int64
+	_, err = db.Select(&clanIDs, "SELECT id FROM clans WHERE game_id=$1 AND deleted_at=0", game.PublicID)
+	if err != nil {
+		return err
+	}
+	for _, clanID := range clanIDs {
+		err = UpdateClanMembershipCount(db, game, clanID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func importPlayer(db DB, game *Game, record *TransferRecord) error {
+	if record.PublicID == "" || record.Name == "" {
+		return fmt.Errorf("player publicID and name are required")
+	}
+	metadata := record.Metadata
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+
+	player, err := GetPlayerByPublicID(db, game.PublicID, record.PublicID)
+	if err != nil {
+		if _, ok := err.(*ModelNotFoundError); !ok {
+			return err
+		}
+		return db.Insert(&Player{
+			GameID:   game.PublicID,
+			PublicID: record.PublicID,
+			Name:     record.Name,
+			Metadata: metadata,
+		})
+	}
+
+	player.Name = record.Name
+	player.Metadata = metadata
+	_, err = db.Update(player)
+	return err
+}
+
+func importClan(db DB, game *Game, record *TransferRecord) error {
+	if record.PublicID == "" || record.Name == "" || record.OwnerPublicID == "" {
+		return fmt.Errorf("clan publicID, name and ownerPublicID are required")
+	}
+	metadata := record.Metadata
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+
+	owner, err := GetPlayerByPublicID(db, game.PublicID, record.OwnerPublicID)
+	if err != nil {
+		return err
+	}
+
+	var clans []*Clan
+	_, err = db.Select(&clans, "SELECT * FROM clans WHERE game_id=$1 AND public_id=$2", game.PublicID, record.PublicID)
+	if err != nil {
+		return err
+	}
+
+	var clan *Clan
+	if len(clans) > 0 {
+		clan = clans[0]
+	}
+	if clan == nil || clan.DeletedAt != 0 || clan.OwnerID != owner.ID {
+		err = importPlayerReachedMaxClans(db, game, owner)
+		if err != nil {
+			return err
+		}
+	}
+
+	if clan == nil {
+		return db.Insert(&Clan{
+			GameID:           game.PublicID,
+			PublicID:         record.PublicID,
+			Name:             record.Name,
+			OwnerID:          owner.ID,
+			MembershipCount:  1,
+			Metadata:         metadata,
+			AllowApplication: record.AllowApplication,
+			AutoJoin:         record.AutoJoin,
+		})
+	}
+
+	clan.Name = record.Name
+	clan.OwnerID = owner.ID
+	clan.Metadata = metadata
+	clan.AllowApplication = record.AllowApplication
+	clan.AutoJoin = record.AutoJoin
+	clan.DeletedAt = 0
+	_, err = db.Update(clan)
+	return err
+}
+
+func importMembership(db DB, game *Game, record *TransferRecord) error {
+	if record.PlayerPublicID == "" || record.ClanPublicID == "" {
+		return fmt.Errorf("membership playerPublicID and clanPublicID are required")
+	}
+	if _, ok := game.MembershipLevels[record.Level]; !ok {
+		return &InvalidLevelForGameError{game.PublicID, record.Level}
+	}
+	states := 0
+	for _, state := range []bool{record.Approved, record.Denied, record.Banned} {
+		if state {
+			states++
+		}
+	}
+	if states > 1 {
+		return fmt.Errorf("membership can only be one of approved, denied or banned")
+	}
+
+	player, err := GetPlayerByPublicID(db, game.PublicID, record.PlayerPublicID)
+	if err != nil {
+		return err
+	}
+	clan, err := GetClanByPublicID(db, game.PublicID, record.ClanPublicID)
+	if err != nil {
+		return err
+	}
+	if clan.OwnerID == player.ID {
+		return fmt.Errorf("player %s owns clan %s and can't be a member of it", player.PublicID, clan.PublicID)
+	}
+	requestor := player
+	if record.RequestorPublicID != "" && record.RequestorPublicID != player.PublicID {
+		requestor, err = GetPlayerByPublicID(db, game.PublicID, record.RequestorPublicID)
+		if err != nil {
+			return err
+		}
+	}
+
+	var memberships []*Membership
+	_, err = db.Select(&memberships, "SELECT * FROM memberships WHERE player_id=$1 AND clan_id=$2", player.ID, clan.ID)
+	if err != nil {
+		return err
+	}
+	var membership *Membership
+	if len(memberships) > 0 {
+		membership = memberships[0]
+	}
+
+	wasMember := membership != nil && membership.DeletedAt == 0 && membership.Approved &&
+		!membership.Denied && !membership.Banned
+	if record.Approved && !wasMember {
+		err = importClanReachedMaxMembers(db, game, clan)
+		if err != nil {
+			return err
+		}
+		err = importPlayerReachedMaxClans(db, game, player)
+		if err != nil {
+			return err
+		}
+	}
+
+	if membership == nil {
+		membership = &Membership{
+			GameID:   game.PublicID,
+			PlayerID: player.ID,
+			ClanID:   clan.ID,
+		}
+	}
+
+	now := util.NowMilli()
+	membership.Level = record.Level
+	membership.Approved = record.Approved
+	membership.Denied = record.Denied
+	membership.Banned = record.Banned
+	membership.RequestorID = requestor.ID
+	membership.Message = record.Message
+	membership.DeletedAt = 0
+	membership.DeletedBy = 0
+	membership.ExpiresAt = 0
+	membership.BanExpiresAt = 0
+	membership.BannerID = sql.NullInt64{}
+	if record.Approved && membership.ApprovedAt == 0 {
+		membership.ApprovedAt = now
+	}
+	if record.Denied && membership.DeniedAt == 0 {
+		membership.DeniedAt = now
+	}
+	if record.Banned {
+		membership.BannedAt = now
+		membership.BanExpiresAt = record.BanExpiresAt
+	}
+
+	if membership.ID == 0 {
+		return db.Insert(membership)
+	}
+	_, err = db.Update(membership)
+	return err
+}
+
+func importPlayerReachedMaxClans(db DB, game *Game, player *Player) error {
+	count, err := db.SelectInt(`
+		SELECT
+			(SELECT COUNT(*) FROM clans c WHERE c.owner_id=$1 AND c.deleted_at=0) +
+			(SELECT COUNT(*) FROM memberships m
+				WHERE
+					m.player_id=$1 AND m.deleted_at=0 AND m.approved=true AND
+					m.denied=false AND m.banned=false)
+	`, player.ID)
+	if err != nil {
+		return err
+	}
+	if int(count) >= game.MaxClansPerPlayer {
+		return &PlayerReachedMaxClansError{player.PublicID}
+	}
+	return nil
+}
+
+func importClanReachedMaxMembers(db DB, game *Game, clan *Clan) error {
+	count, err := db.SelectInt(`
+		SELECT COUNT(*) + 1 FROM memberships m
+		WHERE
+			m.clan_id=$1 AND m.deleted_at=0 AND m.approved=true AND
+			m.denied=false AND m.banned=false
+	`, clan.ID)
+	if err != nil {
+		return err
+	}
+	if int(count) >= game.MaxMembers {
+		return &ClanReachedMaxMembersError{clan.PublicID}
+	}
+	return nil
+}
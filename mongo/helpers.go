@@ -23,3 +23,10 @@ func GetClanNameTextIndexCommand(gameID string, background bool) bson.D {
 		}},
 	}
 }
+
+// GetDropClansCollectionCommand returns a mongo command to drop the clans collection of a game.
+func GetDropClansCollectionCommand(gameID string) bson.D {
+	return bson.D{
+		{Name: "drop", Value: fmt.Sprintf("clans_%s", gameID)},
+	}
+}
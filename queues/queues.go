@@ -8,3 +8,9 @@ const KhanESQueue = "khan_es_updater"
 
 // KhanMongoQueue is the queue that will receive Mongo updates
 const KhanMongoQueue = "khan_mongo_updater"
+
+// KhanDeadLetterQueue is the queue that will receive webhook deliveries that ran out of retries
+const KhanDeadLetterQueue = "khan_webhooks_dead"
+
+// KhanRankingQueue is the queue that will receive clan ranking rebuilds
+const KhanRankingQueue = "khan_ranking_updater"
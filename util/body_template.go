@@ -0,0 +1,61 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var bodyTemplateTag = regexp.MustCompile(`{{\s*([A-Za-z0-9_.]+)\s*}}`)
+
+// RenderHookBody builds a webhook body out of template, a JSON value in which
+// strings can reference payload keys as {{key}} or {{dot.separated.key}}.
+// A string that is only a tag is replaced by the payload value, keeping its
+// type, while tags inside longer strings are replaced by their text.
+// Keys that are not in the payload are rendered as null or an empty string
+func RenderHookBody(template interface{}, payload map[string]interface{}) interface{} {
+	switch t := template.(type) {
+	case map[string]interface{}:
+		rendered := make(map[string]interface{}, len(t))
+		for key, value := range t {
+			rendered[key] = RenderHookBody(value, payload)
+		}
+		return rendered
+	case []interface{}:
+		rendered := make([]interface{}, len(t))
+		for i, value := range t {
+			rendered[i] = RenderHookBody(value, payload)
+		}
+		return rendered
+	case string:
+		if match := bodyTemplateTag.FindStringSubmatch(t); match != nil && match[0] == t {
+			return GetPayloadPath(payload, strings.Split(match[1], "."))
+		}
+		return bodyTemplateTag.ReplaceAllStringFunc(t, func(tag string) string {
+			key := bodyTemplateTag.FindStringSubmatch(tag)[1]
+			return bodyTemplateText(GetPayloadPath(payload, strings.Split(key, ".")))
+		})
+	}
+	return template
+}
+
+func bodyTemplateText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,69 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package util_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/khan/util"
+)
+
+var _ = Describe("Hook Body Template", func() {
+	payload := map[string]interface{}{
+		"level": 3,
+		"clan": map[string]interface{}{
+			"name":     "clan \"one\"",
+			"metadata": map[string]interface{}{"region": "eu"},
+			"tags":     []interface{}{"a", "b"},
+		},
+	}
+
+	Describe("RenderHookBody", func() {
+		cases := []struct {
+			description string
+			template    interface{}
+			expected    interface{}
+		}{
+			{"tags keeping their type", "{{level}}", 3},
+			{"tags with spaces", "{{ clan.name }}", "clan \"one\""},
+			{"nested objects", "{{clan.metadata}}", map[string]interface{}{"region": "eu"}},
+			{"tags inside text", "level {{level}} in {{clan.metadata.region}}", "level 3 in eu"},
+			{"quotes inside text unescaped", "name: {{clan.name}}", "name: clan \"one\""},
+			{"objects inside text as JSON", "tags: {{clan.tags}}", `tags: ["a","b"]`},
+			{"missing tags as null", "{{clan.missing}}", nil},
+			{"missing tags inside text as empty", "[{{missing.key}}]", "[]"},
+			{"keys inside non objects as null", "{{level.value}}", nil},
+			{"strings without tags", "level", "level"},
+			{"invalid tags as text", "{{clan name}}", "{{clan name}}"},
+			{"non string values", 1.5, 1.5},
+			{
+				"objects and arrays",
+				map[string]interface{}{
+					"text":   "{{clan.metadata.region}}",
+					"values": []interface{}{"{{level}}", true, map[string]interface{}{"tags": "{{clan.tags}}"}},
+				},
+				map[string]interface{}{
+					"text":   "eu",
+					"values": []interface{}{3, true, map[string]interface{}{"tags": []interface{}{"a", "b"}}},
+				},
+			},
+		}
+
+		for _, tc := range cases {
+			tc := tc
+			It("Should render "+tc.description, func() {
+				rendered := RenderHookBody(tc.template, payload)
+				if tc.expected == nil {
+					Expect(rendered).To(BeNil())
+					return
+				}
+				Expect(rendered).To(Equal(tc.expected))
+			})
+		}
+	})
+})
@@ -0,0 +1,389 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// HookFilter is a compiled filter expression that can be matched against webhook payloads.
+// Expressions compare payload keys with literals, e.g. `clan.metadata.region == "eu" && level >= 2`.
+// Supported operators are ==, !=, <, <=, >, >=, &&, || and !, and parentheses can be used for grouping.
+// Literals can be strings, numbers, true, false and null. Keys that are not in the payload are null.
+type HookFilter struct {
+	expression string
+	root       filterNode
+}
+
+// CompileHookFilter parses expression into a HookFilter
+func CompileHookFilter(expression string) (*HookFilter, error) {
+	tokens, err := tokenizeFilter(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("invalid filter %q: unexpected %q", expression, p.peek().value)
+	}
+	return &HookFilter{expression: expression, root: root}, nil
+}
+
+// Match returns whether payload satisfies the filter
+func (f *HookFilter) Match(payload map[string]interface{}) bool {
+	return isTruthy(f.root.eval(payload))
+}
+
+// String returns the filter expression
+func (f *HookFilter) String() string {
+	return f.expression
+}
+
+type filterTokenKind int
+
+const (
+	filterTokenOperator filterTokenKind = iota
+	filterTokenString
+	filterTokenNumber
+	filterTokenIdentifier
+)
+
+type filterToken struct {
+	kind  filterTokenKind
+	value string
+}
+
+var filterOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")"}
+
+func tokenizeFilter(expression string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '"'; j++ {
+				if runes[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("invalid filter %q: unterminated string", expression)
+			}
+			value, err := strconv.Unquote(string(runes[i : j+1]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter %q: %s", expression, err.Error())
+			}
+			tokens = append(tokens, filterToken{filterTokenString, value})
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for ; j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.'); j++ {
+			}
+			tokens = append(tokens, filterToken{filterTokenNumber, string(runes[i:j])})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for ; j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '.'); j++ {
+			}
+			tokens = append(tokens, filterToken{filterTokenIdentifier, string(runes[i:j])})
+			i = j
+		default:
+			matched := false
+			for _, op := range filterOperators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, filterToken{filterTokenOperator, op})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("invalid filter %q: unexpected %q", expression, string(r))
+			}
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *filterParser) peek() filterToken {
+	if p.done() {
+		return filterToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) acceptOperator(ops ...string) (string, bool) {
+	token := p.peek()
+	if p.done() || token.kind != filterTokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if token.value == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOperator("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterLogicalNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOperator("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterLogicalNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if _, ok := p.acceptOperator("!"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &filterNotNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.acceptOperator("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return &filterComparisonNode{op: op, left: left, right: right}, nil
+}
+
+func (p *filterParser) parseOperand() (filterNode, error) {
+	if p.done() {
+		return nil, fmt.Errorf("invalid filter: unexpected end of expression")
+	}
+	if _, ok := p.acceptOperator("("); ok {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.acceptOperator(")"); !ok {
+			return nil, fmt.Errorf("invalid filter: missing )")
+		}
+		return node, nil
+	}
+
+	token := p.peek()
+	p.pos++
+	switch token.kind {
+	case filterTokenString:
+		return &filterLiteralNode{value: token.value}, nil
+	case filterTokenNumber:
+		number, err := strconv.ParseFloat(token.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter: invalid number %q", token.value)
+		}
+		return &filterLiteralNode{value: number}, nil
+	case filterTokenIdentifier:
+		switch token.value {
+		case "true":
+			return &filterLiteralNode{value: true}, nil
+		case "false":
+			return &filterLiteralNode{value: false}, nil
+		case "null":
+			return &filterLiteralNode{value: nil}, nil
+		}
+		return &filterPathNode{path: strings.Split(token.value, ".")}, nil
+	}
+	return nil, fmt.Errorf("invalid filter: unexpected %q", token.value)
+}
+
+type filterNode interface {
+	eval(payload map[string]interface{}) interface{}
+}
+
+type filterLiteralNode struct {
+	value interface{}
+}
+
+func (n *filterLiteralNode) eval(payload map[string]interface{}) interface{} {
+	return n.value
+}
+
+type filterPathNode struct {
+	path []string
+}
+
+func (n *filterPathNode) eval(payload map[string]interface{}) interface{} {
+	return GetPayloadPath(payload, n.path)
+}
+
+type filterNotNode struct {
+	operand filterNode
+}
+
+func (n *filterNotNode) eval(payload map[string]interface{}) interface{} {
+	return !isTruthy(n.operand.eval(payload))
+}
+
+type filterLogicalNode struct {
+	op          string
+	left, right filterNode
+}
+
+func (n *filterLogicalNode) eval(payload map[string]interface{}) interface{} {
+	left := isTruthy(n.left.eval(payload))
+	if n.op == "&&" {
+		return left && isTruthy(n.right.eval(payload))
+	}
+	return left || isTruthy(n.right.eval(payload))
+}
+
+type filterComparisonNode struct {
+	op          string
+	left, right filterNode
+}
+
+func (n *filterComparisonNode) eval(payload map[string]interface{}) interface{} {
+	left := normalizeFilterValue(n.left.eval(payload))
+	right := normalizeFilterValue(n.right.eval(payload))
+
+	switch n.op {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	}
+
+	if l, ok := left.(float64); ok {
+		if r, ok := right.(float64); ok {
+			return compareFilterOrder(n.op, l < r, l > r)
+		}
+	}
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			return compareFilterOrder(n.op, l < r, l > r)
+		}
+	}
+	return false
+}
+
+func compareFilterOrder(op string, less, greater bool) bool {
+	switch op {
+	case "<":
+		return less
+	case "<=":
+		return !greater
+	case ">":
+		return greater
+	case ">=":
+		return !less
+	}
+	return false
+}
+
+// normalizeFilterValue converts numbers to float64 so they can be compared
+// and any other non-comparable value to its JSON representation
+func normalizeFilterValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, string, float64:
+		return v
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+func isTruthy(value interface{}) bool {
+	switch v := normalizeFilterValue(value).(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	}
+	return true
+}
+
+// GetPayloadPath returns the value at path inside payload, or nil if any of the keys is missing
+func GetPayloadPath(payload map[string]interface{}, path []string) interface{} {
+	var item interface{} = payload
+	for _, piece := range path {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		item = m[piece]
+	}
+	return item
+}
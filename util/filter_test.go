@@ -0,0 +1,100 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package util_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/khan/util"
+)
+
+var _ = Describe("Hook Filter", func() {
+	payload := map[string]interface{}{
+		"level":   json.Number("3"),
+		"members": 10,
+		"name":    `say "hi" && bye`,
+		"clan": map[string]interface{}{
+			"name": "ação",
+			"metadata": map[string]interface{}{
+				"region": "eu",
+				"open":   true,
+			},
+		},
+	}
+
+	Describe("Match", func() {
+		cases := []struct {
+			description string
+			expression  string
+			expected    bool
+		}{
+			{"equal strings", `clan.metadata.region == "eu"`, true},
+			{"different strings", `clan.metadata.region != "eu"`, false},
+			{"unicode strings", `clan.name == "ação"`, true},
+			{"escaped quotes and operators inside strings", `name == "say \"hi\" && bye"`, true},
+			{"json numbers and literals", `level == 3`, true},
+			{"int and float numbers", `members >= 9.5`, true},
+			{"negative numbers", `level > -1`, true},
+			{"string ordering", `clan.metadata.region < "fr"`, true},
+			{"booleans", `clan.metadata.open == true`, true},
+			{"truthy keys", `clan.metadata.open`, true},
+			{"&& before ||", `level == 1 || level == 3 && members == 10`, true},
+			{"&& before || on the left", `level == 3 && members == 1 || level == 1`, false},
+			{"parentheses over precedence", `(level == 1 || level == 3) && members == 1`, false},
+			{"! over comparison", `!level == 1`, true},
+			{"double !", `!!clan.metadata.open`, true},
+			{"missing keys are null", `clan.metadata.missing == null`, true},
+			{"missing keys are not equal to values", `missing == "eu"`, false},
+			{"missing keys are different from values", `missing != "eu"`, true},
+			{"missing keys are not ordered", `missing > 1`, false},
+			{"missing keys are falsy", `missing`, false},
+			{"keys inside non objects are null", `clan.name.first == null`, true},
+			{"values of different types are not ordered", `clan.name > 1`, false},
+		}
+
+		for _, tc := range cases {
+			tc := tc
+			It("Should match "+tc.description, func() {
+				filter, err := CompileHookFilter(tc.expression)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(filter.String()).To(Equal(tc.expression))
+				Expect(filter.Match(payload)).To(Equal(tc.expected))
+			})
+		}
+	})
+
+	Describe("CompileHookFilter", func() {
+		cases := []struct {
+			description string
+			expression  string
+		}{
+			{"empty expressions", ``},
+			{"unterminated strings", `name == "eu`},
+			{"invalid escapes", `name == "\q"`},
+			{"unknown characters", `level # 1`},
+			{"missing operands", `level >=`},
+			{"leading operators", `== 1`},
+			{"dangling logical operators", `level == 1 &&`},
+			{"missing )", `(level == 1`},
+			{"unexpected )", `level == 1)`},
+			{"chained comparisons", `1 < level < 5`},
+			{"invalid numbers", `level == 1.2.3`},
+		}
+
+		for _, tc := range cases {
+			tc := tc
+			It("Should not compile "+tc.description, func() {
+				filter, err := CompileHookFilter(tc.expression)
+				Expect(err).To(HaveOccurred())
+				Expect(filter).To(BeNil())
+			})
+		}
+	})
+})
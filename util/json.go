@@ -23,7 +23,7 @@ type TypeConverter struct{}
 // ToDb converts val from json to string
 func (tc TypeConverter) ToDb(val interface{}) (interface{}, error) {
 	switch val.(type) {
-	case map[string]interface{}:
+	case map[string]interface{}, []int, []string:
 		return json.Marshal(val)
 	}
 	return val, nil
@@ -32,7 +32,7 @@ func (tc TypeConverter) ToDb(val interface{}) (interface{}, error) {
 // FromDb converts target from string to json
 func (tc TypeConverter) FromDb(target interface{}) (gorp.CustomScanner, bool) {
 	switch target.(type) {
-	case *map[string]interface{}:
+	case *map[string]interface{}, *[]int, *[]string:
 		binder := func(holder, target interface{}) error {
 			s, ok := holder.(*string)
 			if !ok {
@@ -0,0 +1,48 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// HookSignatureHeader is the header that holds the signature of a webhook body
+const HookSignatureHeader = "X-Khan-Signature"
+
+// HookTimestampHeader is the header that holds the unix timestamp a webhook was signed at
+const HookTimestampHeader = "X-Khan-Timestamp"
+
+// DefaultHookSignatureTolerance is how old a signed webhook can be before receivers should reject it
+const DefaultHookSignatureTolerance = 5 * time.Minute
+
+// SignHookPayload returns the HMAC-SHA256 signature of body signed at timestamp with secret
+func SignHookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
+
+// VerifyHookSignature checks that signature matches body and that timestamp is within tolerance of now
+func VerifyHookSignature(secret, signature string, timestamp int64, body []byte, tolerance time.Duration) error {
+	signedAt := time.Unix(timestamp, 0)
+	if age := time.Since(signedAt); age > tolerance || age < -tolerance {
+		return fmt.Errorf("webhook signed at %s is outside the replay window", signedAt.Format(time.RFC3339))
+	}
+	expected := SignHookPayload(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("webhook signature does not match")
+	}
+	return nil
+}
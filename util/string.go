@@ -0,0 +1,23 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package util
+
+import "unicode/utf8"
+
+// TruncateString returns the first maxBytes bytes of value without splitting a multi-byte rune,
+// so the result is shorter when the limit falls in the middle of one
+func TruncateString(value string, maxBytes int) string {
+	if len(value) <= maxBytes {
+		return value
+	}
+	end := maxBytes
+	for end > 0 && !utf8.RuneStart(value[end]) {
+		end--
+	}
+	return value[:end]
+}